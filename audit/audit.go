@@ -0,0 +1,52 @@
+// Package audit carries who is acting through a request's context, so that
+// the audit events recorded while handling it say who did what from where
+// without every call having to pass it along.
+package audit
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Actor is who, and from where, an action was taken. UserID and Email are
+// blank until someone has logged in.
+type Actor struct {
+	UserID uuid.UUID
+	Email  string
+	// ImpersonatorID is the admin acting as the user, if there is one.
+	ImpersonatorID uuid.UUID
+	IP             string
+	UserAgent      string
+}
+
+type contextKey struct{}
+
+// WithActor returns a copy of the context carrying the actor.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, contextKey{}, actor)
+}
+
+// ActorFromContext returns the actor the context carries, which is blank if
+// there is none, such as for a command run by an operator.
+func ActorFromContext(ctx context.Context) Actor {
+	actor, _ := ctx.Value(contextKey{}).(Actor)
+	return actor
+}
+
+// WithUser returns a copy of the context whose actor is the user, keeping
+// where they are acting from.
+func WithUser(ctx context.Context, userID uuid.UUID, email string) context.Context {
+	actor := ActorFromContext(ctx)
+	actor.UserID = userID
+	actor.Email = email
+	return WithActor(ctx, actor)
+}
+
+// WithImpersonator returns a copy of the context whose actor is being
+// impersonated by the admin.
+func WithImpersonator(ctx context.Context, adminID uuid.UUID) context.Context {
+	actor := ActorFromContext(ctx)
+	actor.ImpersonatorID = adminID
+	return WithActor(ctx, actor)
+}
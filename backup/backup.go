@@ -0,0 +1,123 @@
+// Package backup writes and reads archives of a single user's rows, so that a
+// user can be rolled back to an earlier state or moved to another database.
+//
+// An archive is gzipped JSON holding the rows along with a checksum of them,
+// so that an archive which has been damaged or edited is refused rather than
+// partly restored.
+package backup
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"emailaddress.horse/thousand/repository"
+)
+
+// Version is the version of the archive format written. Archives of other
+// versions can't be read.
+const Version = 1
+
+const checksumPrefix = "sha256:"
+
+var (
+	// ErrChecksumMismatch is returned when reading an archive whose rows don't
+	// match its checksum.
+	ErrChecksumMismatch = errors.New("backup checksum does not match")
+
+	// ErrUnsupportedVersion is returned when reading an archive written in
+	// another format.
+	ErrUnsupportedVersion = errors.New("unsupported backup version")
+)
+
+// Archive is the envelope around the rows. Data is kept as written so that
+// the checksum is of exactly those bytes.
+type Archive struct {
+	Version   int             `json:"version"`
+	CreatedAt time.Time       `json:"created_at"`
+	Checksum  string          `json:"checksum"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Write writes an archive of the rows to w.
+func Write(w io.Writer, data repository.UserBackup) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	archive := Archive{
+		Version:   Version,
+		CreatedAt: time.Now().UTC(),
+		Checksum:  checksum(raw),
+		Data:      raw,
+	}
+
+	gz := gzip.NewWriter(w)
+
+	if err := json.NewEncoder(gz).Encode(archive); err != nil {
+		return err
+	}
+
+	return gz.Close()
+}
+
+// Read reads an archive from r, checking its version and checksum.
+func Read(r io.Reader) (repository.UserBackup, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return repository.UserBackup{}, fmt.Errorf("error decompressing backup: %w", err)
+	}
+	defer gz.Close()
+
+	var archive Archive
+	if err := json.NewDecoder(gz).Decode(&archive); err != nil {
+		return repository.UserBackup{}, fmt.Errorf("error decoding backup: %w", err)
+	}
+
+	if archive.Version != Version {
+		return repository.UserBackup{}, fmt.Errorf("%w %d: expected %d", ErrUnsupportedVersion, archive.Version, Version)
+	}
+
+	if archive.Checksum != checksum(archive.Data) {
+		return repository.UserBackup{}, ErrChecksumMismatch
+	}
+
+	var data repository.UserBackup
+	if err := json.Unmarshal(archive.Data, &data); err != nil {
+		return repository.UserBackup{}, fmt.Errorf("error decoding backup: %w", err)
+	}
+
+	return data, nil
+}
+
+// User writes an archive of the user with the email to w.
+func User(ctx context.Context, repo *repository.Repository, email string, w io.Writer) (repository.UserBackup, error) {
+	data, err := repo.BackupUser(ctx, email)
+	if err != nil {
+		return repository.UserBackup{}, err
+	}
+
+	return data, Write(w, data)
+}
+
+// Restore restores the archive read from r.
+func Restore(ctx context.Context, repo *repository.Repository, r io.Reader, opts repository.RestoreOptions) (repository.RestoreResult, error) {
+	data, err := Read(r)
+	if err != nil {
+		return repository.RestoreResult{}, err
+	}
+
+	return repo.RestoreUser(ctx, data, opts)
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return checksumPrefix + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,250 @@
+package backup_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"emailaddress.horse/thousand/backup"
+	"emailaddress.horse/thousand/form"
+	"emailaddress.horse/thousand/repository"
+	"emailaddress.horse/thousand/repository/queries"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func newTestRepository(t *testing.T) *repository.Repository {
+	t.Helper()
+
+	var databaseURL = "postgres://localhost:5432/thousand_test?sslmode=disable"
+
+	if os.Getenv("DATABASE_URL") != "" {
+		databaseURL = os.Getenv("DATABASE_URL")
+	}
+
+	repo, err := repository.New(repository.Options{
+		DatabaseURL: databaseURL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo, tx, err := repo.WithTx(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		if err := tx.Rollback(context.Background()); err != nil {
+			t.Fatalf("Error attempting to rollback - DB may have unexpected contents: %s", err)
+		}
+	})
+
+	return repo
+}
+
+func testData() repository.UserBackup {
+	userID := uuid.New()
+	vampireID := uuid.New()
+	createdAt := time.Date(2022, 1, 2, 3, 4, 5, 6000, time.UTC)
+
+	return repository.UserBackup{
+		User: queries.User{ID: userID, Email: "agnes@example.com", CreatedAt: createdAt},
+		Vampires: []queries.Vampire{
+			{ID: vampireID, Name: "Agnes <the> Pale & Quiet", CreatedAt: createdAt, UserID: uuid.NullUUID{UUID: userID, Valid: true}},
+		},
+		Skills: []queries.Skill{
+			{ID: uuid.New(), VampireID: vampireID, Description: "Reading Latin", CreatedAt: createdAt},
+		},
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	t.Parallel()
+
+	data := testData()
+
+	var buf bytes.Buffer
+	if err := backup.Write(&buf, data); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := backup.Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(data, read); diff != "" {
+		t.Errorf("read mismatch (-written +read):\n%s", diff)
+	}
+}
+
+func TestRead_Errors(t *testing.T) {
+	t.Parallel()
+
+	// rewrite decompresses an archive, changes it and compresses it again
+	rewrite := func(t *testing.T, change func(*backup.Archive)) io.Reader {
+		var buf bytes.Buffer
+		if err := backup.Write(&buf, testData()); err != nil {
+			t.Fatal(err)
+		}
+
+		gz, err := gzip.NewReader(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var archive backup.Archive
+		if err := json.NewDecoder(gz).Decode(&archive); err != nil {
+			t.Fatal(err)
+		}
+
+		change(&archive)
+
+		var out bytes.Buffer
+		w := gzip.NewWriter(&out)
+		if err := json.NewEncoder(w).Encode(archive); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		return &out
+	}
+
+	tests := []struct {
+		name     string
+		archive  func(*testing.T) io.Reader
+		expected error
+		message  string
+	}{
+		{
+			name: "edited",
+			archive: func(t *testing.T) io.Reader {
+				return rewrite(t, func(a *backup.Archive) {
+					a.Data = json.RawMessage(strings.Replace(string(a.Data), "Reading Latin", "Reading Greek", 1))
+				})
+			},
+			expected: backup.ErrChecksumMismatch,
+		},
+		{
+			name: "other version",
+			archive: func(t *testing.T) io.Reader {
+				return rewrite(t, func(a *backup.Archive) { a.Version = backup.Version + 1 })
+			},
+			expected: backup.ErrUnsupportedVersion,
+		},
+		{
+			name: "not gzipped",
+			archive: func(t *testing.T) io.Reader {
+				return strings.NewReader(`{"version": 1}`)
+			},
+			message: "error decompressing backup",
+		},
+		{
+			name: "truncated",
+			archive: func(t *testing.T) io.Reader {
+				var buf bytes.Buffer
+				if err := backup.Write(&buf, testData()); err != nil {
+					t.Fatal(err)
+				}
+				return bytes.NewReader(buf.Bytes()[:buf.Len()/2])
+			},
+			message: "error decoding backup",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := backup.Read(tt.archive(t))
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+
+			if tt.expected != nil && !errors.Is(err, tt.expected) {
+				t.Errorf("expected %q; received %q", tt.expected, err)
+			}
+
+			if tt.message != "" && !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("expected error containing %q; received %q", tt.message, err)
+			}
+		})
+	}
+}
+
+// TestUserRestore backs up a user through an archive and restores them over
+// themselves after they've been changed. The user is an admin and disabled so
+// that both survive the restore.
+func TestUserRestore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := newTestRepository(t)
+
+	email := fmt.Sprintf("%s@example.com", uuid.New())
+	user, err := repo.CreateUser(ctx, form.NewUser(email, "password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.GrantAdmin(ctx, email); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.SetUserDisabled(ctx, user.ID, true); err != nil {
+		t.Fatal(err)
+	}
+
+	vampire, err := repo.CreateVampire(ctx, user.ID, "Agnes the Pale")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.CreateExperience(ctx, vampire.ID, vampire.Memories[0].ID, "I fed on Osric at the river ford."); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	data, err := backup.User(ctx, repo, email, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.CreateVampire(ctx, user.ID, "Made by mistake"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := backup.Restore(ctx, repo, &buf, repository.RestoreOptions{Replace: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := repo.BackupUser(ctx, email)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !restored.User.IsAdmin {
+		t.Error("expected restored user to be an admin")
+	}
+
+	if !restored.User.DisabledAt.Valid {
+		t.Error("expected restored user to be disabled")
+	}
+
+	if diff := cmp.Diff(data, restored); diff != "" {
+		t.Errorf("restored rows mismatch (-backed up +restored):\n%s", diff)
+	}
+}
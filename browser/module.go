@@ -7,9 +7,11 @@ import (
 	"emailaddress.horse/thousand/handlers"
 	"emailaddress.horse/thousand/health"
 	"emailaddress.horse/thousand/middleware"
+	"emailaddress.horse/thousand/registry"
 	"emailaddress.horse/thousand/repository"
 	"emailaddress.horse/thousand/server"
 	"emailaddress.horse/thousand/session"
+	"emailaddress.horse/thousand/streams"
 	"emailaddress.horse/thousand/templates"
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/fx"
@@ -29,12 +31,15 @@ type params struct {
 var testModule = fx.Options(
 	fx.Provide(fx.Annotate(chi.NewMux, fx.As(new(chi.Router)))),
 
+	fx.Provide(registry.NewGame),
+
 	middleware.Module,
 
 	handlers.Module,
 	health.Module,
 	server.Module,
 	session.Module,
+	streams.Module,
 	templates.Module,
 
 	fx.Provide(func(t *testing.T) *zap.Logger {
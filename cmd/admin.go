@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"emailaddress.horse/thousand/models"
+	"github.com/urfave/cli/v2"
+)
+
+// grantAdmin makes a user an admin, which is how the first admin is made since
+// only admins can see the admin interface.
+func grantAdmin(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return cli.Exit("expected the EMAIL of the user to make an admin", 1)
+	}
+
+	repo, err := openRepository(c)
+	if err != nil {
+		return err
+	}
+	defer repo.Close()
+
+	user, err := repo.GrantAdmin(operatorContext(c, "admin grant").Context, c.Args().First())
+	if errors.Is(err, models.ErrNotFound) {
+		return cli.Exit(fmt.Sprintf("no user with email %q", c.Args().First()), 1)
+	} else if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "%s is an admin\n", user.Email)
+	return nil
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"emailaddress.horse/thousand/audit"
+	"emailaddress.horse/thousand/models"
+	"github.com/google/uuid"
+	"github.com/urfave/cli/v2"
+)
+
+// operatorContext returns the command's context with an actor saying which
+// command made any changes, since nobody is logged in to be the actor.
+func operatorContext(c *cli.Context, command string) *cli.Context {
+	c.Context = audit.WithActor(c.Context, audit.Actor{UserAgent: "thousand " + command})
+	return c
+}
+
+// queryAudit prints the audit events matching the flags, most recent first.
+func queryAudit(c *cli.Context) error {
+	query := models.AuditEventQuery{
+		ActorEmail: c.String("actor"),
+		Action:     models.AuditAction(c.String("action")),
+		Since:      c.Duration("since"),
+		Limit:      c.Int("limit"),
+	}
+
+	if target := c.String("target"); target != "" {
+		id, err := uuid.Parse(target)
+		if err != nil {
+			return cli.Exit(fmt.Sprintf("target must be an ID: %s", err), 1)
+		}
+		query.TargetID = id
+	}
+
+	if query.Since < 0 {
+		return cli.Exit("since must not be negative", 1)
+	}
+
+	repo, err := openRepository(c)
+	if err != nil {
+		return err
+	}
+	defer repo.Close()
+
+	events, err := repo.GetAuditEvents(c.Context, query)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tACTION\tACTOR\tIMPERSONATOR\tIP\tTARGET\tCHANGES\tUSER AGENT")
+
+	for _, event := range events {
+		target := "-"
+		if event.TargetID != (uuid.UUID{}) {
+			target = fmt.Sprintf("%s:%s", event.TargetType, event.TargetID)
+		}
+
+		impersonator := "-"
+		if event.Impersonated() {
+			impersonator = event.ImpersonatorID.String()
+		}
+
+		changes := "-"
+		if len(event.Changes) > 0 {
+			b, err := json.Marshal(event.Changes)
+			if err != nil {
+				return err
+			}
+			changes = string(b)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			event.CreatedAt.Format("2006-01-02 15:04:05"),
+			event.Action,
+			valueOrDash(event.ActorEmail),
+			impersonator,
+			valueOrDash(event.IP),
+			target,
+			changes,
+			valueOrDash(event.UserAgent),
+		)
+	}
+
+	return w.Flush()
+}
+
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"emailaddress.horse/thousand/backup"
+	"emailaddress.horse/thousand/config"
+	"emailaddress.horse/thousand/models"
+	"emailaddress.horse/thousand/repository"
+	"github.com/urfave/cli/v2"
+)
+
+// backupUser writes an archive of a user to the output file or, by default,
+// stdout so that it can be piped from a remote shell.
+func backupUser(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return cli.Exit("expected the EMAIL of the user to back up", 1)
+	}
+
+	repo, err := openRepository(c)
+	if err != nil {
+		return err
+	}
+	defer repo.Close()
+
+	var w io.Writer = os.Stdout
+	if output := c.String("output"); output != "-" {
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	data, err := backup.User(c.Context, repo, c.Args().First(), w)
+	if errors.Is(err, models.ErrNotFound) {
+		return cli.Exit(fmt.Sprintf("no user with email %q", c.Args().First()), 1)
+	} else if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Backed up %s with %d vampires\n", data.User.Email, len(data.Vampires))
+	return nil
+}
+
+// restoreUser restores an archive from the file or, if none is given, stdin.
+func restoreUser(c *cli.Context) error {
+	if c.NArg() > 1 {
+		return cli.Exit("expected at most one PATH to restore from", 1)
+	}
+
+	var r io.Reader = os.Stdin
+	if path := c.Args().First(); path != "" && path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	repo, err := openRepository(c)
+	if err != nil {
+		return err
+	}
+	defer repo.Close()
+
+	result, err := backup.Restore(operatorContext(c, "restore").Context, repo, r, repository.RestoreOptions{
+		Replace: c.Bool("replace"),
+	})
+	if errors.Is(err, models.ErrEmailAlreadyInUse) {
+		return cli.Exit(fmt.Sprintf("%s; use --replace to replace them", err), 1)
+	} else if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Restored user %s, remapping %d IDs and skipping %d memberships and invitations\n", result.UserID, result.Remapped, result.Skipped)
+	return nil
+}
+
+func openRepository(c *cli.Context) (*repository.Repository, error) {
+	cfg, err := config.Load(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.New(repository.Options{
+		DatabaseURL:    cfg.Database.URL,
+		TrashRetention: cfg.Database.TrashRetention,
+	})
+}
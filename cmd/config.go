@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"emailaddress.horse/thousand/config"
+	"github.com/urfave/cli/v2"
+)
+
+// loadDatabaseURL loads the config for commands which only need to connect to
+// the database, so don't need the rest of it to be valid.
+func loadDatabaseURL(c *cli.Context) (string, error) {
+	cfg, err := config.Load(c)
+	if err != nil {
+		return "", err
+	}
+
+	return cfg.Database.URL, nil
+}
+
+// printConfig writes the effective config with its secrets redacted, then
+// reports whether it's valid so problems can be seen alongside the values.
+func printConfig(c *cli.Context) error {
+	cfg, err := config.Load(c)
+	if err != nil {
+		return err
+	}
+
+	if err := cfg.Redacted().Encode(os.Stdout, c.String("format")); err != nil {
+		return err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cli.Exit(fmt.Sprintf("\n%s", err), 1)
+	}
+
+	return nil
+}
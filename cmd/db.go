@@ -5,12 +5,17 @@ import (
 	"fmt"
 	"net/url"
 
+	"emailaddress.horse/thousand/config"
+	"emailaddress.horse/thousand/seed"
 	"github.com/jackc/pgx/v4"
 	"github.com/urfave/cli/v2"
 )
 
 func createDatabase(c *cli.Context) error {
-	databaseURL := c.String("database-url")
+	databaseURL, err := loadDatabaseURL(c)
+	if err != nil {
+		return err
+	}
 
 	dbName, err := getDatabaseName(databaseURL)
 	if err != nil {
@@ -27,7 +32,10 @@ func createDatabase(c *cli.Context) error {
 }
 
 func dropDatabase(c *cli.Context) error {
-	databaseURL := c.String("database-url")
+	databaseURL, err := loadDatabaseURL(c)
+	if err != nil {
+		return err
+	}
 
 	dbName, err := getDatabaseName(databaseURL)
 	if err != nil {
@@ -74,3 +82,100 @@ func execOnPostgresDB(ctx context.Context, databaseURL, sqlString string) error
 
 	return err
 }
+
+func seedDatabase(c *cli.Context) error {
+	repo, err := openRepository(c)
+	if err != nil {
+		return err
+	}
+	defer repo.Close()
+
+	result, err := seed.Run(c.Context, repo, seed.Options{
+		Users:    c.Int("users"),
+		Vampires: c.Int("vampires"),
+		Seed:     c.Int64("seed"),
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Seeded %d users, skipped %d already seeded; log in with password %q\n", result.Created, result.Skipped, seed.Password)
+	return nil
+}
+
+// purgeTrash permanently deletes the vampires which have been in the trash
+// for longer than the retention period.
+func purgeTrash(c *cli.Context) error {
+	repo, err := openRepository(c)
+	if err != nil {
+		return err
+	}
+	defer repo.Close()
+
+	count, err := repo.PurgeTrashedVampires(operatorContext(c, "db purge").Context)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Purged %d vampires from the trash\n", count)
+	return nil
+}
+
+// resetDatabase drops and recreates the database, migrates it and seeds it.
+// It refuses to run against production.
+func resetDatabase(c *cli.Context) error {
+	cfg, err := config.Load(c)
+	if err != nil {
+		return err
+	}
+
+	if cfg.Environment == config.EnvironmentProduction {
+		return cli.Exit("refusing to reset the database in production", 1)
+	}
+
+	dbName, err := getDatabaseName(cfg.Database.URL)
+	if err != nil {
+		return err
+	}
+
+	err = execOnPostgresDB(c.Context, cfg.Database.URL, fmt.Sprintf("DROP database IF EXISTS %s", dbName))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Dropped database: %q\n", dbName)
+
+	if err := createDatabase(c); err != nil {
+		return err
+	}
+
+	if err := runMigrations(c); err != nil {
+		return err
+	}
+
+	if c.Int("users") == 0 {
+		return nil
+	}
+
+	return seedDatabase(c)
+}
+
+func seedFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.IntFlag{
+			Name:  "users",
+			Usage: "how many users to seed",
+			Value: 3,
+		},
+		&cli.IntFlag{
+			Name:  "vampires",
+			Usage: "how many vampires each seeded user has",
+			Value: 2,
+		},
+		&cli.Int64Flag{
+			Name:  "seed",
+			Usage: "`SEED` for generating data; a different seed adds different users",
+			Value: 1,
+		},
+	}
+}
@@ -2,16 +2,24 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 
+	"emailaddress.horse/thousand/config"
 	"emailaddress.horse/thousand/handlers"
 	"emailaddress.horse/thousand/health"
 	"emailaddress.horse/thousand/logger"
 	"emailaddress.horse/thousand/middleware"
+	"emailaddress.horse/thousand/migrate"
+	"emailaddress.horse/thousand/models"
+	"emailaddress.horse/thousand/monitoring"
+	"emailaddress.horse/thousand/openid"
 	"emailaddress.horse/thousand/registry"
 	"emailaddress.horse/thousand/repository"
 	"emailaddress.horse/thousand/server"
 	"emailaddress.horse/thousand/session"
+	"emailaddress.horse/thousand/streams"
 	"emailaddress.horse/thousand/templates"
+	"emailaddress.horse/thousand/tracing"
 	"github.com/go-chi/chi/v5"
 	"github.com/urfave/cli/v2"
 	"go.uber.org/fx"
@@ -22,64 +30,44 @@ func BuildCLIApp() *cli.App {
 	return &cli.App{
 		Name:  "thousand",
 		Usage: "I forget why I made this...",
-		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:    "database-url",
-				Usage:   "override the default DB connection",
-				Value:   "postgres://localhost:5432/thousand_development?sslmode=disable",
-				EnvVars: []string{"DATABASE_URL"},
-			},
-			&cli.StringFlag{
-				Name:    "log-format",
-				Usage:   "format for logs: `prod` uses structured logging; `dev` uses readable logging",
-				Value:   "dev",
-				EnvVars: []string{"LOG_FORMAT"},
-			},
-			&cli.IntFlag{
-				Name:    "port",
-				Usage:   "port to run the server on",
-				Value:   4000,
-				EnvVars: []string{"PORT"},
-			},
-			&cli.StringFlag{
-				Name:    "secret-key",
-				Usage:   "secret key for encrypting session",
-				Value:   "secret",
-				EnvVars: []string{"SECRET_KEY"},
-			},
-		},
+		Flags: config.Flags(),
 		Action: func(c *cli.Context) error {
+			cfg, err := config.Load(c)
+			if err != nil {
+				return err
+			}
+
+			if err := cfg.Validate(); err != nil {
+				return err
+			}
+
 			a := fx.New(
-				fx.Supply(
-					struct {
-						fx.Out
-
-						DatabaseURL string `name:"databaseURL"`
-						Host        string `name:"host" optional:"true"`
-						LogFormat   string `name:"logFormat" optional:"true"`
-						Port        int    `name:"port"`
-						SecretKey   string `name:"secretKey"`
-					}{
-						DatabaseURL: c.String("database-url"),
-						LogFormat:   c.String("log-format"),
-						Port:        c.Int("port"),
-						SecretKey:   c.String("secret-key"),
-					},
-				),
+				fx.Supply(cfg),
+				config.Module,
 
 				fx.Provide(fx.Annotate(chi.NewMux, fx.As(new(chi.Router)))),
 				middleware.Module,
 
+				// Migrations are checked before anything else starts, so the
+				// server never listens with pending migrations
+				migrate.Module,
+
 				handlers.Module,
 				health.Module,
 				logger.Module,
+				monitoring.Module,
+				openid.Module,
 				registry.Module,
 				repository.Module,
 				server.Module,
 				session.Module,
+				streams.Module,
 				templates.Module,
+				tracing.Module,
 
 				fx.Invoke(func(s *server.Server) {}),
+
+				fx.StopTimeout(cfg.Server.ShutdownTimeout),
 			)
 
 			a.Run()
@@ -95,8 +83,12 @@ func BuildCLIApp() *cli.App {
 						fx.Provide(fx.Annotate(chi.NewMux, fx.As(new(chi.Router)))),
 
 						fx.Provide(func() *health.Health { return nil }),
+						fx.Provide(func() *openid.Provider { return openid.New(openid.Options{}) }),
+						fx.Provide(func() *registry.Game { return nil }),
 						fx.Provide(func() *repository.Repository { return nil }),
 						fx.Provide(func() *session.Store { return nil }),
+						fx.Provide(func() *streams.Broadcaster { return nil }),
+						fx.Provide(func() *streams.Hub { return nil }),
 						fx.Provide(func() *templates.Renderer { return nil }),
 						fx.Provide(func() *zap.Logger { return nil }),
 
@@ -110,6 +102,96 @@ func BuildCLIApp() *cli.App {
 					return a.Start(context.Background())
 				},
 			},
+			{
+				Name:  "backup",
+				Usage: "back up data from the database",
+				Subcommands: []*cli.Command{
+					{
+						Name:      "user",
+						Usage:     "write a gzipped archive of a user and all their vampires",
+						ArgsUsage: "EMAIL",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:    "output",
+								Aliases: []string{"o"},
+								Usage:   "`PATH` to write the archive to; - for stdout",
+								Value:   "-",
+							},
+						},
+						Action: backupUser,
+					},
+				},
+			},
+			{
+				Name:      "restore",
+				Usage:     "restore a user from an archive written by backup user, read from PATH or stdin",
+				ArgsUsage: "[PATH]",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "replace",
+						Usage: "replace the user and everything of theirs if they already exist",
+					},
+				},
+				Action: restoreUser,
+			},
+			{
+				Name:  "admin",
+				Usage: "manage who can use the admin interface",
+				Subcommands: []*cli.Command{
+					{
+						Name:      "grant",
+						Usage:     "make the user with EMAIL an admin",
+						ArgsUsage: "EMAIL",
+						Action:    grantAdmin,
+					},
+				},
+			},
+			{
+				Name:  "audit",
+				Usage: "list audit events, most recent first",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "actor",
+						Usage: "only events by the user with `EMAIL`, including failed logins with it",
+					},
+					&cli.StringFlag{
+						Name:  "action",
+						Usage: "only events with `ACTION`, such as session.login_failed",
+					},
+					&cli.StringFlag{
+						Name:  "target",
+						Usage: "only events about the user, vampire or other thing with `ID`",
+					},
+					&cli.DurationFlag{
+						Name:  "since",
+						Usage: "only events from within `DURATION` before now, such as 24h",
+					},
+					&cli.IntFlag{
+						Name:  "limit",
+						Value: models.DefaultAuditEventLimit,
+						Usage: fmt.Sprintf("list at most `N` events, up to %d", models.MaxAuditEventLimit),
+					},
+				},
+				Action: queryAudit,
+			},
+			{
+				Name:  "config",
+				Usage: "inspect the app's settings",
+				Subcommands: []*cli.Command{
+					{
+						Name:  "print",
+						Usage: "print the effective settings with secrets redacted",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "format",
+								Usage: "`FORMAT` to print in: toml or yaml",
+								Value: "toml",
+							},
+						},
+						Action: printConfig,
+					},
+				},
+			},
 			{
 				Name:  "db",
 				Usage: "setup and migrate the database",
@@ -124,6 +206,23 @@ func BuildCLIApp() *cli.App {
 						Usage:  "drop the database",
 						Action: dropDatabase,
 					},
+					{
+						Name:   "purge",
+						Usage:  "permanently delete vampires which have been in the trash for longer than --trash-retention",
+						Action: purgeTrash,
+					},
+					{
+						Name:   "reset",
+						Usage:  "drop, create, migrate and seed the database; seeding is skipped if --users is 0",
+						Flags:  seedFlags(),
+						Action: resetDatabase,
+					},
+					{
+						Name:   "seed",
+						Usage:  "add generated users and vampires, skipping any already seeded",
+						Flags:  seedFlags(),
+						Action: seedDatabase,
+					},
 				},
 			},
 			{
@@ -131,20 +230,36 @@ func BuildCLIApp() *cli.App {
 				Usage: "manage migrations",
 				Subcommands: []*cli.Command{
 					{
-						Name:   "create",
-						Usage:  "create new SQL migration",
-						Action: createMigration,
+						Name:      "create",
+						Usage:     "create new SQL migration in the migrations directory",
+						ArgsUsage: "NAME",
+						Action:    createMigration,
 					},
 					{
 						Name:   "run",
 						Usage:  "run pending migrations",
+						Flags:  dryRunFlags(),
 						Action: runMigrations,
 					},
 					{
 						Name:   "rollback",
 						Usage:  "rollback latest migration",
+						Flags:  dryRunFlags(),
 						Action: rollbackMigrations,
 					},
+					{
+						Name:   "redo",
+						Usage:  "rollback latest migration and run it again",
+						Flags:  dryRunFlags(),
+						Action: redoMigration,
+					},
+					{
+						Name:      "to",
+						Usage:     "run or rollback migrations until VERSION is the latest applied; 0 rolls back everything",
+						ArgsUsage: "VERSION",
+						Flags:     dryRunFlags(),
+						Action:    migrateToVersion,
+					},
 					{
 						Name:   "status",
 						Usage:  "report current status of migrations",
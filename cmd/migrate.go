@@ -1,54 +1,127 @@
 package cmd
 
 import (
-	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
 
-	"emailaddress.horse/thousand/db"
+	"emailaddress.horse/thousand/config"
+	"emailaddress.horse/thousand/migrate"
 	"github.com/pressly/goose/v3"
 	"github.com/urfave/cli/v2"
 )
 
-func init() {
-	goose.SetBaseFS(db.Migrations)
-}
+func createMigration(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return cli.Exit("expected the NAME of the migration", 1)
+	}
 
-const (
-	migrationsPath = "./db/migrations"
-)
+	cfg, err := config.Load(c)
+	if err != nil {
+		return err
+	}
 
-func createMigration(c *cli.Context) error {
-	return goose.Create(nil, migrationsPath, c.Args().Get(0), "sql")
+	return goose.Create(nil, cfg.Database.MigrationsDir, c.Args().First(), "sql")
 }
 
 func runMigrations(c *cli.Context) error {
-	databaseURL := c.String("database-url")
+	return migrateTo(c, migrate.Latest())
+}
+
+func rollbackMigrations(c *cli.Context) error {
+	return migrateTo(c, migrate.Rollback())
+}
+
+func redoMigration(c *cli.Context) error {
+	return migrateTo(c, migrate.Redo())
+}
 
-	conn, err := sql.Open("pgx", databaseURL)
+func migrateToVersion(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return cli.Exit("expected the VERSION to migrate to", 1)
+	}
+
+	version, err := strconv.ParseInt(c.Args().First(), 10, 64)
 	if err != nil {
-		return err
+		return cli.Exit(fmt.Sprintf("invalid version %q", c.Args().First()), 1)
 	}
 
-	return goose.Up(conn, db.FSMigrationsPath)
+	return migrateTo(c, migrate.To(version))
 }
 
-func rollbackMigrations(c *cli.Context) error {
-	databaseURL := c.String("database-url")
+func migrationsStatus(c *cli.Context) error {
+	m, err := openMigrator(c)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
 
-	conn, err := sql.Open("pgx", databaseURL)
+	return m.Status()
+}
+
+// migrateTo applies or, with --dry-run, prints the steps to the target.
+func migrateTo(c *cli.Context, target migrate.Target) error {
+	m, err := openMigrator(c)
 	if err != nil {
 		return err
 	}
+	defer m.Close()
+
+	if c.Bool("dry-run") {
+		return printMigrationPlan(c, m, target)
+	}
+
+	_, err = m.Apply(c.Context, target)
+	if errors.Is(err, migrate.ErrNoMigrationsApplied) {
+		return cli.Exit(err.Error(), 1)
+	}
 
-	return goose.Down(conn, db.FSMigrationsPath)
+	return err
 }
 
-func migrationsStatus(c *cli.Context) error {
-	databaseURL := c.String("database-url")
+func printMigrationPlan(c *cli.Context, m *migrate.Migrator, target migrate.Target) error {
+	steps, err := m.Plan(c.Context, target)
+	if errors.Is(err, migrate.ErrNoMigrationsApplied) {
+		return cli.Exit(err.Error(), 1)
+	} else if err != nil {
+		return err
+	}
+
+	if len(steps) == 0 {
+		fmt.Println("-- nothing to migrate")
+		return nil
+	}
 
-	conn, err := sql.Open("pgx", databaseURL)
+	for i, step := range steps {
+		sql, err := step.SQL()
+		if err != nil {
+			return err
+		}
+
+		if i > 0 {
+			fmt.Println()
+		}
+
+		fmt.Printf("-- %s\n%s\n", step, sql)
+	}
+
+	return nil
+}
+
+func openMigrator(c *cli.Context) (*migrate.Migrator, error) {
+	databaseURL, err := loadDatabaseURL(c)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return goose.Status(conn, db.FSMigrationsPath)
+	return migrate.Open(migrate.Options{DatabaseURL: databaseURL})
+}
+
+func dryRunFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "print the SQL which would run without running it",
+		},
+	}
 }
@@ -0,0 +1,247 @@
+// Package config loads the app's settings. Each setting is read from, in
+// increasing order of precedence, its default, a TOML or YAML file,
+// environment variables and command line flags.
+//
+// Settings are fields of Config tagged with the names they take in each
+// source, so adding one only needs a field here and, if a module uses it, a
+// value in Values.
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"emailaddress.horse/thousand/db"
+	"emailaddress.horse/thousand/health"
+	"emailaddress.horse/thousand/models"
+	"emailaddress.horse/thousand/server"
+	"emailaddress.horse/thousand/tracing"
+	"go.uber.org/fx"
+)
+
+const (
+	EnvironmentDevelopment = "development"
+	EnvironmentProduction  = "production"
+
+	// DefaultSecretKey is only fit for development, so it's rejected in
+	// production.
+	DefaultSecretKey = "secret"
+)
+
+// Config is every setting the app can be given. Fields are tagged with:
+//
+//   - toml and yaml: the key in a config file, nested by section
+//   - flag: the command line flag
+//   - env: the environment variable
+//   - usage: the help text shown for the flag
+//   - secret: true if the value is redacted when printed, or password if only
+//     the password in the URL is
+type Config struct {
+	Environment string `toml:"environment" yaml:"environment" flag:"environment" env:"ENVIRONMENT" usage:"environment the app is running in: development or production"`
+
+	Admin    Admin    `toml:"admin" yaml:"admin"`
+	Database Database `toml:"database" yaml:"database"`
+	Health   Health   `toml:"health" yaml:"health"`
+	Log      Log      `toml:"log" yaml:"log"`
+	OIDC     OIDC     `toml:"oidc" yaml:"oidc"`
+	Server   Server   `toml:"server" yaml:"server"`
+	Session  Session  `toml:"session" yaml:"session"`
+	Tracing  Tracing  `toml:"tracing" yaml:"tracing"`
+}
+
+type Admin struct {
+	Address   string `toml:"address" yaml:"address" flag:"admin-address" env:"ADMIN_ADDRESS" usage:"host and port to serve metrics, health checks and profiles on; disabled if blank"`
+	BasicAuth string `toml:"basic_auth" yaml:"basic_auth" flag:"admin-basic-auth" env:"ADMIN_BASIC_AUTH" usage:"USERNAME:PASSWORD required to use the admin listener" secret:"true"`
+	Pprof     bool   `toml:"pprof" yaml:"pprof" flag:"admin-pprof" env:"ADMIN_PPROF" usage:"serve runtime profiles under /debug/pprof on the admin listener"`
+	Token     string `toml:"token" yaml:"token" flag:"admin-token" env:"ADMIN_TOKEN" usage:"bearer TOKEN required to use the admin listener" secret:"true"`
+}
+
+type Database struct {
+	URL                    string        `toml:"url" yaml:"url" flag:"database-url" env:"DATABASE_URL" usage:"override the default DB connection" secret:"password"`
+	MigrateOnStart         bool          `toml:"migrate_on_start" yaml:"migrate_on_start" flag:"migrate-on-start" env:"MIGRATE_ON_START" usage:"apply pending migrations before serving, taking turns with other instances"`
+	AllowPendingMigrations bool          `toml:"allow_pending_migrations" yaml:"allow_pending_migrations" flag:"allow-pending-migrations" env:"ALLOW_PENDING_MIGRATIONS" usage:"serve even though migrations are pending, rather than refusing to start"`
+	MigrationsDir          string        `toml:"migrations_dir" yaml:"migrations_dir" flag:"migrations-dir" env:"MIGRATIONS_DIR" usage:"PATH of the directory migrate create writes new migrations to"`
+	TrashRetention         time.Duration `toml:"trash_retention" yaml:"trash_retention" flag:"trash-retention" env:"TRASH_RETENTION" usage:"how long trashed vampires can be restored before db purge deletes them"`
+}
+
+type Health struct {
+	CacheTTL time.Duration `toml:"cache_ttl" yaml:"cache_ttl" flag:"health-cache-ttl" env:"HEALTH_CACHE_TTL" usage:"how long readiness check results are reused before checking again"`
+	Timeout  time.Duration `toml:"timeout" yaml:"timeout" flag:"health-timeout" env:"HEALTH_TIMEOUT" usage:"how long each readiness check may run before it fails"`
+}
+
+type Log struct {
+	Format string `toml:"format" yaml:"format" flag:"log-format" env:"LOG_FORMAT" usage:"format for logs: prod uses structured logging; dev uses readable logging"`
+}
+
+type OIDC struct {
+	ClientID     string `toml:"client_id" yaml:"client_id" flag:"oidc-client-id" env:"OIDC_CLIENT_ID" usage:"client ID registered with the OpenID Connect provider"`
+	ClientSecret string `toml:"client_secret" yaml:"client_secret" flag:"oidc-client-secret" env:"OIDC_CLIENT_SECRET" usage:"client secret registered with the OpenID Connect provider" secret:"true"`
+	Issuer       string `toml:"issuer" yaml:"issuer" flag:"oidc-issuer" env:"OIDC_ISSUER" usage:"issuer URL of an OpenID Connect provider to allow logging in with; disabled if blank"`
+	Name         string `toml:"name" yaml:"name" flag:"oidc-name" env:"OIDC_NAME" usage:"name of the OpenID Connect provider shown to users"`
+	RedirectURL  string `toml:"redirect_url" yaml:"redirect_url" flag:"oidc-redirect-url" env:"OIDC_REDIRECT_URL" usage:"URL of /session/oidc/callback registered with the OpenID Connect provider"`
+}
+
+type Server struct {
+	Host              string        `toml:"host" yaml:"host" flag:"host" env:"HOST" usage:"host to run the server on; all interfaces if blank"`
+	Port              int           `toml:"port" yaml:"port" flag:"port" env:"PORT" usage:"port to run the server on"`
+	Socket            string        `toml:"socket" yaml:"socket" flag:"socket" env:"SOCKET" usage:"PATH of a Unix domain socket to run the server on instead of the host and port"`
+	SocketActivation  bool          `toml:"socket_activation" yaml:"socket_activation" flag:"socket-activation" env:"SOCKET_ACTIVATION" usage:"run the server on the socket passed down by systemd instead of the host and port"`
+	TLSCertFile       string        `toml:"tls_cert_file" yaml:"tls_cert_file" flag:"tls-cert-file" env:"TLS_CERT_FILE" usage:"PATH of the PEM certificate to serve HTTPS with; reloaded when changed"`
+	TLSKeyFile        string        `toml:"tls_key_file" yaml:"tls_key_file" flag:"tls-key-file" env:"TLS_KEY_FILE" usage:"PATH of the PEM key to serve HTTPS with; reloaded when changed"`
+	ReadHeaderTimeout time.Duration `toml:"read_header_timeout" yaml:"read_header_timeout" flag:"read-header-timeout" env:"READ_HEADER_TIMEOUT" usage:"how long clients have to send request headers"`
+	IdleTimeout       time.Duration `toml:"idle_timeout" yaml:"idle_timeout" flag:"idle-timeout" env:"IDLE_TIMEOUT" usage:"how long keep-alive connections are held open between requests"`
+	WriteTimeout      time.Duration `toml:"write_timeout" yaml:"write_timeout" flag:"write-timeout" env:"WRITE_TIMEOUT" usage:"how long a response may take to write; disabled if zero, which streams need"`
+	DrainPeriod       time.Duration `toml:"drain_period" yaml:"drain_period" flag:"drain-period" env:"DRAIN_PERIOD" usage:"how long to keep serving after reporting not ready when shutting down"`
+	ShutdownTimeout   time.Duration `toml:"shutdown_timeout" yaml:"shutdown_timeout" flag:"shutdown-timeout" env:"SHUTDOWN_TIMEOUT" usage:"how long shutting down, including the drain period, may take before giving up"`
+}
+
+type Session struct {
+	SecretKey string `toml:"secret_key" yaml:"secret_key" flag:"secret-key" env:"SECRET_KEY" usage:"secret key for encrypting session" secret:"true"`
+}
+
+type Tracing struct {
+	Exporter     string `toml:"exporter" yaml:"exporter" flag:"trace-exporter" env:"TRACE_EXPORTER" usage:"EXPORTER to send traces to: otlp sends them to a collector; stdout and file write them locally; disabled if blank"`
+	File         string `toml:"file" yaml:"file" flag:"trace-file" env:"TRACE_FILE" usage:"PATH of the file the file trace exporter appends traces to"`
+	OTLPEndpoint string `toml:"otlp_endpoint" yaml:"otlp_endpoint" flag:"otlp-endpoint" env:"OTLP_ENDPOINT" usage:"host and port of the OpenTelemetry collector the otlp trace exporter sends to"`
+	OTLPInsecure bool   `toml:"otlp_insecure" yaml:"otlp_insecure" flag:"otlp-insecure" env:"OTLP_INSECURE" usage:"send spans to the OpenTelemetry collector without TLS"`
+}
+
+// Default returns the settings used when nothing else is given, which suit
+// running the app locally.
+func Default() Config {
+	return Config{
+		Environment: EnvironmentDevelopment,
+		Admin: Admin{
+			Address: ":9091",
+		},
+		Database: Database{
+			URL:            "postgres://localhost:5432/thousand_development?sslmode=disable",
+			MigrationsDir:  db.AppMigrationsPath,
+			TrashRetention: models.DefaultTrashRetention,
+		},
+		Health: Health{
+			CacheTTL: health.DefaultTTL,
+			Timeout:  health.DefaultTimeout,
+		},
+		Log: Log{
+			Format: "dev",
+		},
+		OIDC: OIDC{
+			Name: "OpenID Connect",
+		},
+		Server: Server{
+			Port:              4000,
+			ReadHeaderTimeout: server.DefaultReadHeaderTimeout,
+			IdleTimeout:       server.DefaultIdleTimeout,
+			ShutdownTimeout:   fx.DefaultTimeout,
+		},
+		Session: Session{
+			SecretKey: DefaultSecretKey,
+		},
+		Tracing: Tracing{
+			File:         "traces.json",
+			OTLPEndpoint: "localhost:4318",
+		},
+	}
+}
+
+// ValidationError lists every problem found with a config.
+type ValidationError []string
+
+func (e ValidationError) Error() string {
+	return "invalid config: " + strings.Join(e, "; ")
+}
+
+// Validate checks the settings make sense together, returning a
+// ValidationError listing every problem found.
+func (c Config) Validate() error {
+	var problems ValidationError
+
+	problem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	switch c.Environment {
+	case EnvironmentDevelopment:
+	case EnvironmentProduction:
+		if c.Session.SecretKey == DefaultSecretKey {
+			problem("secret key must be changed from the default in production")
+		}
+	default:
+		problem("environment must be %s or %s; got %q", EnvironmentDevelopment, EnvironmentProduction, c.Environment)
+	}
+
+	if c.Session.SecretKey == "" {
+		problem("secret key must be set")
+	}
+
+	if c.Admin.BasicAuth != "" && !strings.Contains(c.Admin.BasicAuth, ":") {
+		problem("admin basic auth must be a username and password separated by a colon")
+	}
+
+	switch c.Log.Format {
+	case "dev", "prod":
+	default:
+		problem("log format must be dev or prod; got %q", c.Log.Format)
+	}
+
+	if c.OIDC.Issuer != "" && (c.OIDC.ClientID == "" || c.OIDC.RedirectURL == "") {
+		problem("OpenID Connect needs a client ID and redirect URL when an issuer is set")
+	}
+
+	if c.Server.Port < 0 || c.Server.Port > 65535 {
+		problem("port must be between 0 and 65535; got %d", c.Server.Port)
+	}
+
+	if c.Server.Socket != "" && c.Server.SocketActivation {
+		problem("socket and socket activation can't both be used")
+	}
+
+	if (c.Server.TLSCertFile == "") != (c.Server.TLSKeyFile == "") {
+		problem("TLS needs both a certificate and a key file")
+	}
+
+	for _, d := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"health cache TTL", c.Health.CacheTTL},
+		{"health timeout", c.Health.Timeout},
+		{"read header timeout", c.Server.ReadHeaderTimeout},
+		{"idle timeout", c.Server.IdleTimeout},
+		{"write timeout", c.Server.WriteTimeout},
+		{"drain period", c.Server.DrainPeriod},
+		{"shutdown timeout", c.Server.ShutdownTimeout},
+	} {
+		if d.value < 0 {
+			problem("%s must not be negative; got %s", d.name, d.value)
+		}
+	}
+
+	if c.Database.TrashRetention <= 0 {
+		problem("trash retention must be positive; got %s", c.Database.TrashRetention)
+	} else if c.Database.TrashRetention > models.MaxTrashRetention {
+		problem("trash retention must be at most %s; got %s", models.MaxTrashRetention, c.Database.TrashRetention)
+	}
+
+	if c.Server.DrainPeriod > 0 && c.Server.DrainPeriod >= c.Server.ShutdownTimeout {
+		problem("drain period must be shorter than the shutdown timeout")
+	}
+
+	switch c.Tracing.Exporter {
+	case tracing.ExporterNone, tracing.ExporterOTLP, tracing.ExporterStdout:
+	case tracing.ExporterFile:
+		if c.Tracing.File == "" {
+			problem("trace file must be set to use the file trace exporter")
+		}
+	default:
+		problem("trace exporter must be blank, otlp, stdout or file; got %q", c.Tracing.Exporter)
+	}
+
+	if len(problems) > 0 {
+		return problems
+	}
+
+	return nil
+}
@@ -0,0 +1,5 @@
+package config
+
+// LoadFrom exposes load so that tests can give the environment rather than
+// change the process's.
+var LoadFrom = load
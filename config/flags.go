@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/urfave/cli/v2"
+)
+
+// Flags returns a flag for every setting, plus FileFlag. Flags have no values
+// of their own, so that only those given on the command line override the
+// file and environment, but show the defaults and environment variables in
+// their help text.
+func Flags() []cli.Flag {
+	flags := []cli.Flag{
+		&cli.StringFlag{
+			Name:    FileFlag,
+			Usage:   "`PATH` of a TOML or YAML file to read settings from",
+			EnvVars: []string{"CONFIG_FILE"},
+		},
+	}
+
+	defaults := Default()
+
+	// Walking the defaults can't fail as the callback never returns an error
+	_ = eachSetting(&defaults, func(field reflect.StructField, value reflect.Value) error {
+		name := field.Tag.Get("flag")
+		if name == "" {
+			return nil
+		}
+
+		usage := field.Tag.Get("usage")
+		if env := field.Tag.Get("env"); env != "" {
+			usage = fmt.Sprintf("%s [$%s]", usage, env)
+		}
+
+		defaultText := fmt.Sprint(value.Interface())
+		if field.Tag.Get("secret") == "true" && !value.IsZero() {
+			defaultText = redacted
+		}
+
+		switch {
+		case value.Type() == durationType:
+			flags = append(flags, &cli.DurationFlag{Name: name, Usage: usage, DefaultText: defaultText})
+		case value.Kind() == reflect.String:
+			flags = append(flags, &cli.StringFlag{Name: name, Usage: usage, DefaultText: defaultText})
+		case value.Kind() == reflect.Int:
+			flags = append(flags, &cli.IntFlag{Name: name, Usage: usage, DefaultText: defaultText})
+		case value.Kind() == reflect.Bool:
+			flags = append(flags, &cli.BoolFlag{Name: name, Usage: usage})
+		}
+
+		return nil
+	})
+
+	return flags
+}
@@ -0,0 +1,188 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// FileFlag is the flag giving the path of the config file, which can't itself
+// be set in the file.
+const FileFlag = "config"
+
+// ErrUnknownFileFormat is returned for config files which aren't TOML or YAML
+// by their extension.
+var ErrUnknownFileFormat = errors.New("config file must be .toml, .yaml or .yml")
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Load builds the config from the defaults, the file given by FileFlag, the
+// environment and the flags, each overriding the last. It doesn't validate
+// the result.
+func Load(c *cli.Context) (Config, error) {
+	return load(c.String(FileFlag), os.LookupEnv, c)
+}
+
+func load(file string, lookupEnv func(string) (string, bool), c *cli.Context) (Config, error) {
+	config := Default()
+
+	if file != "" {
+		if err := decodeFile(file, &config); err != nil {
+			return Config{}, err
+		}
+	}
+
+	if err := applyEnv(&config, lookupEnv); err != nil {
+		return Config{}, err
+	}
+
+	if c != nil {
+		if err := applyFlags(&config, c); err != nil {
+			return Config{}, err
+		}
+	}
+
+	return config, nil
+}
+
+// decodeFile reads the file over the config, failing on any keys which don't
+// match a setting so that typos aren't silently ignored.
+func decodeFile(file string, config *Config) error {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".toml":
+		md, err := toml.DecodeFile(file, config)
+		if err != nil {
+			return fmt.Errorf("error reading config file: %w", err)
+		}
+
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("error reading config file: unknown setting %q", undecoded[0].String())
+		}
+	case ".yaml", ".yml":
+		f, err := os.Open(file)
+		if err != nil {
+			return fmt.Errorf("error reading config file: %w", err)
+		}
+		defer f.Close()
+
+		dec := yaml.NewDecoder(f)
+		dec.KnownFields(true)
+
+		if err := dec.Decode(config); err != nil {
+			return fmt.Errorf("error reading config file: %w", err)
+		}
+	default:
+		return ErrUnknownFileFormat
+	}
+
+	return nil
+}
+
+func applyEnv(config *Config, lookupEnv func(string) (string, bool)) error {
+	return eachSetting(config, func(field reflect.StructField, value reflect.Value) error {
+		name := field.Tag.Get("env")
+		if name == "" {
+			return nil
+		}
+
+		raw, ok := lookupEnv(name)
+		if !ok {
+			return nil
+		}
+
+		if err := setString(value, raw); err != nil {
+			return fmt.Errorf("error reading %s: %w", name, err)
+		}
+
+		return nil
+	})
+}
+
+func applyFlags(config *Config, c *cli.Context) error {
+	return eachSetting(config, func(field reflect.StructField, value reflect.Value) error {
+		name := field.Tag.Get("flag")
+		if name == "" || !c.IsSet(name) {
+			return nil
+		}
+
+		switch {
+		case value.Type() == durationType:
+			value.SetInt(int64(c.Duration(name)))
+		case value.Kind() == reflect.String:
+			value.SetString(c.String(name))
+		case value.Kind() == reflect.Int:
+			value.SetInt(int64(c.Int(name)))
+		case value.Kind() == reflect.Bool:
+			value.SetBool(c.Bool(name))
+		default:
+			return fmt.Errorf("unsupported type %s for flag %s", value.Type(), name)
+		}
+
+		return nil
+	})
+}
+
+// setString parses raw into the setting's value.
+func setString(value reflect.Value, raw string) error {
+	switch {
+	case value.Type() == durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		value.SetInt(int64(d))
+	case value.Kind() == reflect.String:
+		value.SetString(raw)
+	case value.Kind() == reflect.Int:
+		i, err := strconv.Atoi(raw)
+		if err != nil {
+			return err
+		}
+		value.SetInt(int64(i))
+	case value.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		value.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported type %s", value.Type())
+	}
+
+	return nil
+}
+
+// eachSetting calls fn with every setting in the config, descending into each
+// section.
+func eachSetting(config *Config, fn func(reflect.StructField, reflect.Value) error) error {
+	return walk(reflect.ValueOf(config).Elem(), fn)
+}
+
+func walk(v reflect.Value, fn func(reflect.StructField, reflect.Value) error) error {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		value := v.Field(i)
+
+		if value.Kind() == reflect.Struct {
+			if err := walk(value, fn); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := fn(field, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"time"
+
+	"go.uber.org/fx"
+)
+
+// Module provides the settings from a supplied Config as the named values
+// each module takes.
+var Module = fx.Options(
+	fx.Provide(fxValues),
+)
+
+type Values struct {
+	fx.Out
+
+	AdminAddress      string        `name:"adminAddress"`
+	AdminBasicAuth    string        `name:"adminBasicAuth"`
+	AdminPprof        bool          `name:"adminPprof"`
+	AdminToken        string        `name:"adminToken"`
+	AllowPending      bool          `name:"allowPendingMigrations"`
+	DatabaseURL       string        `name:"databaseURL"`
+	DrainPeriod       time.Duration `name:"drainPeriod"`
+	HealthCacheTTL    time.Duration `name:"healthCacheTTL"`
+	HealthTimeout     time.Duration `name:"healthTimeout"`
+	Host              string        `name:"host"`
+	IdleTimeout       time.Duration `name:"idleTimeout"`
+	LogFormat         string        `name:"logFormat"`
+	MigrateOnStart    bool          `name:"migrateOnStart"`
+	OIDCClientID      string        `name:"oidcClientID"`
+	OIDCClientSecret  string        `name:"oidcClientSecret"`
+	OIDCIssuer        string        `name:"oidcIssuer"`
+	OIDCName          string        `name:"oidcName"`
+	OIDCRedirectURL   string        `name:"oidcRedirectURL"`
+	OTLPEndpoint      string        `name:"otlpEndpoint"`
+	OTLPInsecure      bool          `name:"otlpInsecure"`
+	Port              int           `name:"port"`
+	ReadHeaderTimeout time.Duration `name:"readHeaderTimeout"`
+	SecretKey         string        `name:"secretKey"`
+	Socket            string        `name:"socket"`
+	SocketActivation  bool          `name:"socketActivation"`
+	TLSCertFile       string        `name:"tlsCertFile"`
+	TLSKeyFile        string        `name:"tlsKeyFile"`
+	TraceExporter     string        `name:"traceExporter"`
+	TraceFile         string        `name:"traceFile"`
+	TrashRetention    time.Duration `name:"trashRetention"`
+	WriteTimeout      time.Duration `name:"writeTimeout"`
+}
+
+func fxValues(c Config) Values {
+	return Values{
+		AdminAddress:      c.Admin.Address,
+		AdminBasicAuth:    c.Admin.BasicAuth,
+		AdminPprof:        c.Admin.Pprof,
+		AdminToken:        c.Admin.Token,
+		AllowPending:      c.Database.AllowPendingMigrations,
+		DatabaseURL:       c.Database.URL,
+		DrainPeriod:       c.Server.DrainPeriod,
+		HealthCacheTTL:    c.Health.CacheTTL,
+		HealthTimeout:     c.Health.Timeout,
+		Host:              c.Server.Host,
+		IdleTimeout:       c.Server.IdleTimeout,
+		LogFormat:         c.Log.Format,
+		MigrateOnStart:    c.Database.MigrateOnStart,
+		OIDCClientID:      c.OIDC.ClientID,
+		OIDCClientSecret:  c.OIDC.ClientSecret,
+		OIDCIssuer:        c.OIDC.Issuer,
+		OIDCName:          c.OIDC.Name,
+		OIDCRedirectURL:   c.OIDC.RedirectURL,
+		OTLPEndpoint:      c.Tracing.OTLPEndpoint,
+		OTLPInsecure:      c.Tracing.OTLPInsecure,
+		Port:              c.Server.Port,
+		ReadHeaderTimeout: c.Server.ReadHeaderTimeout,
+		SecretKey:         c.Session.SecretKey,
+		Socket:            c.Server.Socket,
+		SocketActivation:  c.Server.SocketActivation,
+		TLSCertFile:       c.Server.TLSCertFile,
+		TLSKeyFile:        c.Server.TLSKeyFile,
+		TraceExporter:     c.Tracing.Exporter,
+		TraceFile:         c.Tracing.File,
+		TrashRetention:    c.Database.TrashRetention,
+		WriteTimeout:      c.Server.WriteTimeout,
+	}
+}
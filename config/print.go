@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "[redacted]"
+
+// Redacted returns a copy of the config with every secret which has been set
+// replaced, so that it's safe to show.
+func (c Config) Redacted() Config {
+	_ = eachSetting(&c, func(field reflect.StructField, value reflect.Value) error {
+		if value.Kind() != reflect.String || value.String() == "" {
+			return nil
+		}
+
+		switch field.Tag.Get("secret") {
+		case "true":
+			value.SetString(redacted)
+		case "password":
+			u, err := url.Parse(value.String())
+			if err != nil {
+				value.SetString(redacted)
+			} else {
+				value.SetString(u.Redacted())
+			}
+		}
+
+		return nil
+	})
+
+	return c
+}
+
+// Encode writes the config to w in the format, which is toml or yaml.
+func (c Config) Encode(w io.Writer, format string) error {
+	switch format {
+	case "toml":
+		return toml.NewEncoder(w).Encode(c)
+	case "yaml":
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(c); err != nil {
+			return err
+		}
+		return enc.Close()
+	default:
+		return fmt.Errorf("unknown config format %q: must be toml or yaml", format)
+	}
+}
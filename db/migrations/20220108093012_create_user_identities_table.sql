@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ALTER COLUMN password_hash DROP NOT NULL;
+
+CREATE TABLE user_identities (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid (),
+    user_id uuid REFERENCES users (id) NOT NULL,
+    issuer text NOT NULL,
+    subject text NOT NULL,
+    email text NOT NULL,
+    created_at timestamp NOT NULL DEFAULT NOW(),
+    updated_at timestamp,
+    UNIQUE (issuer, subject)
+);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+-- Users who only log in through an identity provider have nothing to log in
+-- with once it's gone, but deleting them would lose their vampires, so they
+-- have to be dealt with by hand first
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT
+            1
+        FROM
+            users
+        WHERE
+            password_hash IS NULL) THEN
+        RAISE EXCEPTION 'users without passwords must be given one or removed before rolling back';
+    END IF;
+END
+$$;
+
+DROP TABLE user_identities;
+
+ALTER TABLE users
+    ALTER COLUMN password_hash SET NOT NULL;
+
+-- +goose StatementEnd
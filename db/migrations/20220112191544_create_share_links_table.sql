@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE share_links (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid (),
+    vampire_id uuid REFERENCES vampires (id) NOT NULL,
+    token text NOT NULL UNIQUE DEFAULT translate(encode(gen_random_bytes(24), 'base64'), '+/', '-_'),
+    expires_at timestamp,
+    revoked_at timestamp,
+    created_at timestamp NOT NULL DEFAULT NOW(),
+    updated_at timestamp
+);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE share_links;
+
+-- +goose StatementEnd
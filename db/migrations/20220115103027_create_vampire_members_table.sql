@@ -0,0 +1,49 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE member_role AS enum (
+    'owner',
+    'editor',
+    'viewer'
+);
+
+CREATE TABLE vampire_members (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid (),
+    vampire_id uuid REFERENCES vampires (id) NOT NULL,
+    user_id uuid REFERENCES users (id) NOT NULL,
+    role member_role NOT NULL,
+    created_at timestamp NOT NULL DEFAULT NOW(),
+    updated_at timestamp,
+    UNIQUE (vampire_id, user_id)
+);
+
+INSERT INTO vampire_members (vampire_id, user_id, ROLE)
+SELECT
+    id,
+    user_id,
+    'owner'
+FROM
+    vampires
+WHERE
+    user_id IS NOT NULL;
+
+CREATE TABLE vampire_invitations (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid (),
+    vampire_id uuid REFERENCES vampires (id) NOT NULL,
+    invited_by uuid REFERENCES users (id) NOT NULL,
+    email text NOT NULL,
+    role member_role NOT NULL CHECK (ROLE <> 'owner'),
+    created_at timestamp NOT NULL DEFAULT NOW(),
+    updated_at timestamp,
+    UNIQUE (vampire_id, email)
+);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE vampire_invitations;
+
+DROP TABLE vampire_members;
+
+DROP TYPE member_role;
+
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX vampires_created_at_id_idx ON vampires (created_at, id);
+
+CREATE INDEX vampires_name_id_idx ON vampires (name, id);
+
+CREATE INDEX vampires_name_search_idx ON vampires USING GIN (to_tsvector('english', name));
+
+CREATE INDEX experiences_description_search_idx ON experiences USING GIN (to_tsvector('english', description));
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX experiences_description_search_idx;
+
+DROP INDEX vampires_name_search_idx;
+
+DROP INDEX vampires_name_id_idx;
+
+DROP INDEX vampires_created_at_id_idx;
+
+-- +goose StatementEnd
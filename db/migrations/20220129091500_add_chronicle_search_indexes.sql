@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX skills_description_search_idx ON skills USING GIN (to_tsvector('english', description));
+
+CREATE INDEX resources_description_search_idx ON resources USING GIN (to_tsvector('english', description));
+
+CREATE INDEX characters_name_search_idx ON characters USING GIN (to_tsvector('english', name));
+
+CREATE INDEX marks_description_search_idx ON marks USING GIN (to_tsvector('english', description));
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX marks_description_search_idx;
+
+DROP INDEX characters_name_search_idx;
+
+DROP INDEX resources_description_search_idx;
+
+DROP INDEX skills_description_search_idx;
+
+-- +goose StatementEnd
@@ -0,0 +1,90 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE vampires
+    ADD COLUMN deleted_at timestamp;
+
+CREATE INDEX vampires_deleted_at_idx ON vampires (deleted_at)
+WHERE
+    deleted_at IS NOT NULL;
+
+ALTER TABLE memories
+    DROP CONSTRAINT memories_vampire_id_fkey,
+    ADD CONSTRAINT memories_vampire_id_fkey FOREIGN KEY (vampire_id) REFERENCES vampires (id) ON DELETE CASCADE;
+
+ALTER TABLE experiences
+    DROP CONSTRAINT experiences_memory_id_fkey,
+    ADD CONSTRAINT experiences_memory_id_fkey FOREIGN KEY (memory_id) REFERENCES memories (id) ON DELETE CASCADE;
+
+ALTER TABLE skills
+    DROP CONSTRAINT skills_vampire_id_fkey,
+    ADD CONSTRAINT skills_vampire_id_fkey FOREIGN KEY (vampire_id) REFERENCES vampires (id) ON DELETE CASCADE;
+
+ALTER TABLE resources
+    DROP CONSTRAINT resources_vampire_id_fkey,
+    ADD CONSTRAINT resources_vampire_id_fkey FOREIGN KEY (vampire_id) REFERENCES vampires (id) ON DELETE CASCADE;
+
+ALTER TABLE characters
+    DROP CONSTRAINT characters_vampire_id_fkey,
+    ADD CONSTRAINT characters_vampire_id_fkey FOREIGN KEY (vampire_id) REFERENCES vampires (id) ON DELETE CASCADE;
+
+ALTER TABLE marks
+    DROP CONSTRAINT marks_vampire_id_fkey,
+    ADD CONSTRAINT marks_vampire_id_fkey FOREIGN KEY (vampire_id) REFERENCES vampires (id) ON DELETE CASCADE;
+
+ALTER TABLE share_links
+    DROP CONSTRAINT share_links_vampire_id_fkey,
+    ADD CONSTRAINT share_links_vampire_id_fkey FOREIGN KEY (vampire_id) REFERENCES vampires (id) ON DELETE CASCADE;
+
+ALTER TABLE vampire_members
+    DROP CONSTRAINT vampire_members_vampire_id_fkey,
+    ADD CONSTRAINT vampire_members_vampire_id_fkey FOREIGN KEY (vampire_id) REFERENCES vampires (id) ON DELETE CASCADE;
+
+ALTER TABLE vampire_invitations
+    DROP CONSTRAINT vampire_invitations_vampire_id_fkey,
+    ADD CONSTRAINT vampire_invitations_vampire_id_fkey FOREIGN KEY (vampire_id) REFERENCES vampires (id) ON DELETE CASCADE;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE vampire_invitations
+    DROP CONSTRAINT vampire_invitations_vampire_id_fkey,
+    ADD CONSTRAINT vampire_invitations_vampire_id_fkey FOREIGN KEY (vampire_id) REFERENCES vampires (id);
+
+ALTER TABLE vampire_members
+    DROP CONSTRAINT vampire_members_vampire_id_fkey,
+    ADD CONSTRAINT vampire_members_vampire_id_fkey FOREIGN KEY (vampire_id) REFERENCES vampires (id);
+
+ALTER TABLE share_links
+    DROP CONSTRAINT share_links_vampire_id_fkey,
+    ADD CONSTRAINT share_links_vampire_id_fkey FOREIGN KEY (vampire_id) REFERENCES vampires (id);
+
+ALTER TABLE marks
+    DROP CONSTRAINT marks_vampire_id_fkey,
+    ADD CONSTRAINT marks_vampire_id_fkey FOREIGN KEY (vampire_id) REFERENCES vampires (id);
+
+ALTER TABLE characters
+    DROP CONSTRAINT characters_vampire_id_fkey,
+    ADD CONSTRAINT characters_vampire_id_fkey FOREIGN KEY (vampire_id) REFERENCES vampires (id);
+
+ALTER TABLE resources
+    DROP CONSTRAINT resources_vampire_id_fkey,
+    ADD CONSTRAINT resources_vampire_id_fkey FOREIGN KEY (vampire_id) REFERENCES vampires (id);
+
+ALTER TABLE skills
+    DROP CONSTRAINT skills_vampire_id_fkey,
+    ADD CONSTRAINT skills_vampire_id_fkey FOREIGN KEY (vampire_id) REFERENCES vampires (id);
+
+ALTER TABLE experiences
+    DROP CONSTRAINT experiences_memory_id_fkey,
+    ADD CONSTRAINT experiences_memory_id_fkey FOREIGN KEY (memory_id) REFERENCES memories (id);
+
+ALTER TABLE memories
+    DROP CONSTRAINT memories_vampire_id_fkey,
+    ADD CONSTRAINT memories_vampire_id_fkey FOREIGN KEY (vampire_id) REFERENCES vampires (id);
+
+DROP INDEX vampires_deleted_at_idx;
+
+ALTER TABLE vampires
+    DROP COLUMN deleted_at;
+
+-- +goose StatementEnd
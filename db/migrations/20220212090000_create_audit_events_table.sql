@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE audit_events (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid (),
+    actor_id uuid,
+    actor_email text NOT NULL DEFAULT '',
+    ip text NOT NULL DEFAULT '',
+    user_agent text NOT NULL DEFAULT '',
+    action text NOT NULL,
+    target_type text NOT NULL DEFAULT '',
+    target_id uuid,
+    changes jsonb NOT NULL DEFAULT '{}',
+    created_at timestamp NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX audit_events_created_at_idx ON audit_events (created_at DESC, id DESC);
+
+CREATE INDEX audit_events_actor_id_idx ON audit_events (actor_id, created_at DESC);
+
+CREATE INDEX audit_events_target_id_idx ON audit_events (target_id, created_at DESC);
+
+CREATE FUNCTION audit_events_append_only ()
+    RETURNS TRIGGER
+    AS $$
+BEGIN
+    RAISE EXCEPTION
+        USING ERRCODE = 'TH002', MESSAGE = 'audit events cannot be changed or removed';
+END;
+$$
+LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_append_only
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW
+    EXECUTE PROCEDURE audit_events_append_only ();
+
+CREATE TRIGGER audit_events_no_truncate
+    BEFORE TRUNCATE ON audit_events
+    FOR EACH STATEMENT
+    EXECUTE PROCEDURE audit_events_append_only ();
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER audit_events_no_truncate ON audit_events;
+
+DROP TRIGGER audit_events_append_only ON audit_events;
+
+DROP FUNCTION audit_events_append_only ();
+
+DROP TABLE audit_events;
+
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN is_admin boolean NOT NULL DEFAULT FALSE,
+    ADD COLUMN disabled_at timestamp;
+
+CREATE INDEX users_created_at_idx ON users (created_at DESC, id DESC);
+
+CREATE TABLE password_resets (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid (),
+    user_id uuid REFERENCES users (id) ON DELETE CASCADE NOT NULL,
+    token text NOT NULL UNIQUE DEFAULT translate(encode(gen_random_bytes(24), 'base64'), '+/', '-_'),
+    expires_at timestamp NOT NULL,
+    used_at timestamp,
+    created_at timestamp NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX password_resets_user_id_idx ON password_resets (user_id);
+
+-- Whoever impersonated the actor, so that what an admin does as someone else
+-- is still theirs
+ALTER TABLE audit_events
+    ADD COLUMN impersonator_id uuid;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE audit_events
+    DROP COLUMN impersonator_id;
+
+DROP TABLE password_resets;
+
+DROP INDEX users_created_at_idx;
+
+ALTER TABLE users
+    DROP COLUMN disabled_at,
+    DROP COLUMN is_admin;
+
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE stream_messages (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid (),
+    data text NOT NULL,
+    created_at timestamp NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX stream_messages_created_at_idx ON stream_messages (created_at);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE stream_messages;
+
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE vampires
+    ADD COLUMN archived_at timestamp;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE vampires
+    DROP COLUMN archived_at;
+
+-- +goose StatementEnd
@@ -0,0 +1,55 @@
+package db
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AppliedVersionsQuery selects the versions goose has applied. Rolling back
+// adds a row rather than removing one, so only the latest row for each version
+// counts. Goose marks version 0 as applied when it creates the table, but it
+// isn't a migration.
+const AppliedVersionsQuery = `
+SELECT version_id FROM (
+  SELECT DISTINCT ON (version_id) version_id, is_applied
+  FROM goose_db_version
+  ORDER BY version_id, id DESC
+) latest
+WHERE is_applied
+  AND version_id > 0
+`
+
+// Versions returns the version of every embedded migration in ascending order.
+// Versions are the numeric prefix of each file name, as used by goose.
+func Versions() ([]int64, error) {
+	names, err := fs.Glob(Migrations, path.Join(FSMigrationsPath, "*.sql"))
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]int64, 0, len(names))
+
+	for _, name := range names {
+		base := path.Base(name)
+
+		i := strings.Index(base, "_")
+		if i < 0 {
+			return nil, fmt.Errorf("migration %s has no version", base)
+		}
+
+		version, err := strconv.ParseInt(base[:i], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing version of migration %s: %w", base, err)
+		}
+
+		versions = append(versions, version)
+	}
+
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+
+	return versions, nil
+}
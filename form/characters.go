@@ -0,0 +1,17 @@
+package form
+
+// NewCharacterForm holds what was submitted to create a character, as decoded
+// into models.CreateCharacterParams, so it can be shown again.
+type NewCharacterForm struct {
+	Name Field
+	Type Field
+}
+
+// NewCharacter returns the form for the fields decoded from a new character.
+// Fields which weren't decoded are left blank.
+func NewCharacter(fields Fields) *NewCharacterForm {
+	return &NewCharacterForm{
+		Name: fields.Get("name"),
+		Type: fields.Get("type"),
+	}
+}
@@ -0,0 +1,116 @@
+package form_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"emailaddress.horse/thousand/form"
+	"emailaddress.horse/thousand/models"
+)
+
+func TestNewCharacter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		characterName   string
+		characterType   string
+		wantResult      bool
+		wantNameMessage string
+		wantTypeMessage string
+		wantNameValue   string
+		wantParams      models.CreateCharacterParams
+	}{
+		{
+			name:          "valid",
+			characterName: "Aurelia",
+			characterType: "mortal",
+			wantResult:    true,
+			wantNameValue: "Aurelia",
+			wantParams:    models.CreateCharacterParams{Name: "Aurelia", Type: "mortal"},
+		},
+		{
+			name:          "name is trimmed",
+			characterName: "  Aurelia ",
+			characterType: "immortal",
+			wantResult:    true,
+			wantNameValue: "Aurelia",
+			wantParams:    models.CreateCharacterParams{Name: "Aurelia", Type: "immortal"},
+		},
+		{
+			name:            "name must be present",
+			characterName:   "   ",
+			characterType:   "mortal",
+			wantResult:      false,
+			wantNameMessage: "Please provide a name.",
+			wantParams:      models.CreateCharacterParams{Type: "mortal"},
+		},
+		{
+			name:            "name must not be too long",
+			characterName:   strings.Repeat("a", 101),
+			characterType:   "mortal",
+			wantResult:      false,
+			wantNameMessage: "Please keep the name to 100 characters or fewer.",
+			wantNameValue:   strings.Repeat("a", 101),
+			wantParams:      models.CreateCharacterParams{Type: "mortal"},
+		},
+		{
+			name:            "type must be present",
+			characterName:   "Aurelia",
+			characterType:   "",
+			wantResult:      false,
+			wantTypeMessage: "Please choose whether the character is mortal or immortal.",
+			wantNameValue:   "Aurelia",
+			wantParams:      models.CreateCharacterParams{Name: "Aurelia"},
+		},
+		{
+			name:            "type must be recognised",
+			characterName:   "Aurelia",
+			characterType:   "undead",
+			wantResult:      false,
+			wantTypeMessage: "Please choose whether the character is mortal or immortal.",
+			wantNameValue:   "Aurelia",
+			wantParams:      models.CreateCharacterParams{Name: "Aurelia"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			var params models.CreateCharacterParams
+			fields, err := form.Decode(url.Values{
+				"name": []string{tt.characterName},
+				"type": []string{tt.characterType},
+			}, &params)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := fields.Valid()
+
+			if tt.wantResult != result {
+				t.Errorf("expected result %t; got %t", tt.wantResult, result)
+			}
+
+			if tt.wantParams != params {
+				t.Errorf("expected params %+v; got %+v", tt.wantParams, params)
+			}
+
+			form := form.NewCharacter(fields)
+
+			if tt.wantNameMessage != form.Name.Message {
+				t.Errorf("expected name message %q; got %q", tt.wantNameMessage, form.Name.Message)
+			}
+
+			if tt.wantTypeMessage != form.Type.Message {
+				t.Errorf("expected type message %q; got %q", tt.wantTypeMessage, form.Type.Message)
+			}
+
+			if tt.wantNameValue != form.Name.Value {
+				t.Errorf("expected name value %q; got %q", tt.wantNameValue, form.Name.Value)
+			}
+		})
+	}
+}
@@ -0,0 +1,307 @@
+package form
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultTimeLayout is the layout time fields are parsed with when they don't
+// have a `layout` tag. It matches the value of a date input.
+const DefaultTimeLayout = "2006-01-02"
+
+var (
+	// ErrInvalidTarget is returned when decoding into something other than a
+	// pointer to a struct.
+	ErrInvalidTarget = errors.New("form: can only decode into a pointer to a struct")
+
+	// ErrUnsupportedType is returned when a tagged field has a type the
+	// decoder doesn't know how to fill.
+	ErrUnsupportedType = errors.New("form: unsupported field type")
+
+	// ErrInvalidRule is returned when a `validate` tag can't be understood.
+	ErrInvalidRule = errors.New("form: invalid validation rule")
+)
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Field is a single value read by Decode. It has the same shape as the fields
+// of the hand-written forms so that templates render them the same way.
+type Field struct {
+	Message string
+	Value   string
+}
+
+// Fields holds the fields read by Decode by the name they were submitted with.
+type Fields map[string]*Field
+
+// Get returns the named field, or an empty one if it wasn't decoded.
+func (fs Fields) Get(name string) Field {
+	if f, ok := fs[name]; ok {
+		return *f
+	}
+
+	return Field{}
+}
+
+// Valid returns true if none of the fields have a message.
+func (fs Fields) Valid() bool {
+	for _, f := range fs {
+		if f.Message != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Decode fills the struct dst points to from the submitted values. Each
+// exported field with a `form` tag is read from the value of that name, with
+// surrounding whitespace removed, and checked against the comma separated
+// rules in its `validate` tag:
+//
+//	required      a value must be submitted
+//	max=N, min=N  the length of a string, or the size of a number
+//	oneof=A B C   the value must be one of those listed, for enums
+//
+// Fields which can't be parsed or break a rule are given a message built from
+// their `label` tag, or their name, unless they have a `message` tag.
+//
+// Strings, bools, ints, times (parsed with the `layout` tag) and types such as
+// uuid.UUID which implement encoding.TextUnmarshaler are supported. Blank
+// values leave the field as its zero value.
+//
+// The error is only returned when dst or its tags are unusable, including when
+// a rule doesn't apply to the type of its field, never because of what was
+// submitted.
+func Decode(values url.Values, dst interface{}) (Fields, error) {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil, ErrInvalidTarget
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+
+	fields := Fields{}
+
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+
+		name := sf.Tag.Get("form")
+		if name == "" || name == "-" || sf.PkgPath != "" {
+			continue
+		}
+
+		rules, err := parseRules(sf.Tag.Get("validate"))
+		if err != nil {
+			return nil, fmt.Errorf("%w: field %s: %s", ErrInvalidRule, sf.Name, err)
+		}
+
+		label := sf.Tag.Get("label")
+		if label == "" {
+			label = name
+		}
+
+		field := &Field{Value: strings.TrimSpace(values.Get(name))}
+		fields[name] = field
+
+		if !supported(sf.Type) {
+			return nil, fmt.Errorf("%w: field %s has type %s", ErrUnsupportedType, sf.Name, sf.Type)
+		}
+
+		if err := rules.check(sf.Type); err != nil {
+			return nil, fmt.Errorf("%w: field %s: %s", ErrInvalidRule, sf.Name, err)
+		}
+
+		msg := decodeField(rv.Field(i), field.Value, sf.Tag.Get("layout"), label, rules)
+
+		if msg != "" {
+			if custom := sf.Tag.Get("message"); custom != "" {
+				msg = custom
+			}
+			field.Message = msg
+		}
+	}
+
+	return fields, nil
+}
+
+type rules struct {
+	required bool
+	max      *int64
+	min      *int64
+	oneOf    []string
+}
+
+func parseRules(tag string) (rules, error) {
+	var rs rules
+
+	if tag == "" {
+		return rs, nil
+	}
+
+	for _, rule := range strings.Split(tag, ",") {
+		parts := strings.SplitN(rule, "=", 2)
+		key := parts[0]
+		hasArg := len(parts) == 2
+
+		var arg string
+		if hasArg {
+			arg = parts[1]
+		}
+
+		switch key {
+		case "required":
+			rs.required = true
+		case "max", "min":
+			if !hasArg {
+				return rs, fmt.Errorf("%s needs a value", key)
+			}
+
+			n, err := strconv.ParseInt(arg, 10, 64)
+			if err != nil {
+				return rs, fmt.Errorf("%s: %w", key, err)
+			}
+
+			if key == "max" {
+				rs.max = &n
+			} else {
+				rs.min = &n
+			}
+		case "oneof":
+			rs.oneOf = strings.Fields(arg)
+			if len(rs.oneOf) == 0 {
+				return rs, fmt.Errorf("oneof needs at least one option")
+			}
+		default:
+			return rs, fmt.Errorf("unknown rule %q", key)
+		}
+	}
+
+	return rs, nil
+}
+
+// check returns an error if any of the rules would be ignored when decoding
+// into a field of type t, which must be supported. Only strings have a length
+// and an enum to choose from, and only strings and ints have a size.
+func (rs rules) check(t reflect.Type) error {
+	switch {
+	case t == timeType, reflect.PtrTo(t).Implements(textUnmarshalerType), t.Kind() == reflect.Bool:
+		if rs.max != nil || rs.min != nil {
+			return fmt.Errorf("max and min can't be used with %s", t)
+		}
+		if rs.oneOf != nil {
+			return fmt.Errorf("oneof can't be used with %s", t)
+		}
+	case t.Kind() != reflect.String:
+		if rs.oneOf != nil {
+			return fmt.Errorf("oneof can't be used with %s", t)
+		}
+	}
+
+	return nil
+}
+
+// decodeField sets v, which must be of a supported type, from the raw value.
+// It returns a message if the value is invalid.
+func decodeField(v reflect.Value, raw, layout, label string, rs rules) string {
+	if raw == "" {
+		if rs.required {
+			return fmt.Sprintf("Please provide a %s.", label)
+		}
+
+		return ""
+	}
+
+	invalid := fmt.Sprintf("Please provide a valid %s.", label)
+
+	switch {
+	case v.Type() == timeType:
+		if layout == "" {
+			layout = DefaultTimeLayout
+		}
+
+		t, err := time.Parse(layout, raw)
+		if err != nil {
+			return invalid
+		}
+		v.Set(reflect.ValueOf(t))
+
+	case reflect.PtrTo(v.Type()).Implements(textUnmarshalerType):
+		if err := v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw)); err != nil {
+			return invalid
+		}
+
+	case v.Kind() == reflect.String:
+		if rs.oneOf != nil && !contains(rs.oneOf, raw) {
+			return fmt.Sprintf("Please choose a valid %s.", label)
+		}
+
+		length := int64(utf8.RuneCountInString(raw))
+		if rs.max != nil && length > *rs.max {
+			return fmt.Sprintf("Please keep the %s to %d characters or fewer.", label, *rs.max)
+		}
+		if rs.min != nil && length < *rs.min {
+			return fmt.Sprintf("Please make the %s at least %d characters.", label, *rs.min)
+		}
+
+		v.SetString(raw)
+
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return invalid
+		}
+		v.SetBool(b)
+
+	case v.Kind() >= reflect.Int && v.Kind() <= reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return invalid
+		}
+
+		if rs.max != nil && n > *rs.max {
+			return fmt.Sprintf("Please provide a %s of %d or less.", label, *rs.max)
+		}
+		if rs.min != nil && n < *rs.min {
+			return fmt.Sprintf("Please provide a %s of %d or more.", label, *rs.min)
+		}
+
+		v.SetInt(n)
+	}
+
+	return ""
+}
+
+func supported(t reflect.Type) bool {
+	switch {
+	case t == timeType, reflect.PtrTo(t).Implements(textUnmarshalerType):
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+
+	return false
+}
+
+func contains(options []string, value string) bool {
+	for _, option := range options {
+		if option == value {
+			return true
+		}
+	}
+
+	return false
+}
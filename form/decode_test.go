@@ -0,0 +1,305 @@
+package form_test
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"emailaddress.horse/thousand/form"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+type testRole string
+
+type testParams struct {
+	Name     string    `form:"name" validate:"required,min=2,max=10"`
+	Role     testRole  `form:"role" validate:"oneof=owner editor viewer"`
+	Age      int       `form:"age" validate:"min=0,max=1000" label:"age in years"`
+	Turned   bool      `form:"turned"`
+	ID       uuid.UUID `form:"id"`
+	Born     time.Time `form:"born"`
+	Embraced time.Time `form:"embraced" layout:"2006-01-02T15:04"`
+	Note     string    `form:"note" message:"That isn't a note."`
+	Ignored  string
+	Skipped  string `form:"-"`
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		values       url.Values
+		wantParams   testParams
+		wantValid    bool
+		wantMessages map[string]string
+	}{
+		{
+			name: "valid",
+			values: url.Values{
+				"name":     []string{" Aurelia "},
+				"role":     []string{"editor"},
+				"age":      []string{"342"},
+				"turned":   []string{"true"},
+				"id":       []string{"11111111-1111-1111-1111-111111111111"},
+				"born":     []string{"1680-04-02"},
+				"embraced": []string{"1712-10-31T23:45"},
+				"note":     []string{"a note"},
+				"Ignored":  []string{"ignored"},
+				"-":        []string{"skipped"},
+			},
+			wantParams: testParams{
+				Name:     "Aurelia",
+				Role:     "editor",
+				Age:      342,
+				Turned:   true,
+				ID:       uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+				Born:     time.Date(1680, 4, 2, 0, 0, 0, 0, time.UTC),
+				Embraced: time.Date(1712, 10, 31, 23, 45, 0, 0, time.UTC),
+				Note:     "a note",
+			},
+			wantValid: true,
+		},
+		{
+			name: "blank values are left as zero",
+			values: url.Values{
+				"name": []string{"Aurelia"},
+			},
+			wantParams: testParams{
+				Name: "Aurelia",
+			},
+			wantValid: true,
+		},
+		{
+			name:       "required",
+			values:     url.Values{"name": []string{"   "}},
+			wantParams: testParams{},
+			wantValid:  false,
+			wantMessages: map[string]string{
+				"name": "Please provide a name.",
+			},
+		},
+		{
+			name: "string length",
+			values: url.Values{
+				"name": []string{"A"},
+			},
+			wantParams: testParams{},
+			wantValid:  false,
+			wantMessages: map[string]string{
+				"name": "Please make the name at least 2 characters.",
+			},
+		},
+		{
+			name: "string length is counted in characters",
+			values: url.Values{
+				"name": []string{strings.Repeat("é", 11)},
+			},
+			wantParams: testParams{},
+			wantValid:  false,
+			wantMessages: map[string]string{
+				"name": "Please keep the name to 10 characters or fewer.",
+			},
+		},
+		{
+			name: "enum",
+			values: url.Values{
+				"name": []string{"Aurelia"},
+				"role": []string{"owner "},
+			},
+			wantParams: testParams{
+				Name: "Aurelia",
+				Role: "owner",
+			},
+			wantValid: true,
+		},
+		{
+			name: "unknown enum value",
+			values: url.Values{
+				"name": []string{"Aurelia"},
+				"role": []string{"stranger"},
+			},
+			wantParams: testParams{
+				Name: "Aurelia",
+			},
+			wantValid: false,
+			wantMessages: map[string]string{
+				"role": "Please choose a valid role.",
+			},
+		},
+		{
+			name: "invalid values",
+			values: url.Values{
+				"name":     []string{"Aurelia"},
+				"age":      []string{"old"},
+				"turned":   []string{"perhaps"},
+				"id":       []string{"1234"},
+				"born":     []string{"02/04/1680"},
+				"embraced": []string{"1712-10-31"},
+			},
+			wantParams: testParams{
+				Name: "Aurelia",
+			},
+			wantValid: false,
+			wantMessages: map[string]string{
+				"age":      "Please provide a valid age in years.",
+				"turned":   "Please provide a valid turned.",
+				"id":       "Please provide a valid id.",
+				"born":     "Please provide a valid born.",
+				"embraced": "Please provide a valid embraced.",
+			},
+		},
+		{
+			name: "number limits",
+			values: url.Values{
+				"name": []string{"Aurelia"},
+				"age":  []string{"1001"},
+			},
+			wantParams: testParams{
+				Name: "Aurelia",
+			},
+			wantValid: false,
+			wantMessages: map[string]string{
+				"age": "Please provide a age in years of 1000 or less.",
+			},
+		},
+		{
+			name: "custom message",
+			values: url.Values{
+				"note": []string{""},
+			},
+			wantParams: testParams{},
+			wantValid:  false,
+			wantMessages: map[string]string{
+				"name": "Please provide a name.",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			var params testParams
+
+			fields, err := form.Decode(tt.values, &params)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tt.wantParams, params); diff != "" {
+				t.Errorf("unexpected params (-want +got):\n%s", diff)
+			}
+
+			if valid := fields.Valid(); tt.wantValid != valid {
+				t.Errorf("expected valid %t; got %t", tt.wantValid, valid)
+			}
+
+			messages := map[string]string{}
+			for name, field := range fields {
+				if field.Message != "" {
+					messages[name] = field.Message
+				}
+			}
+
+			if diff := cmp.Diff(tt.wantMessages, messages, cmpEmptyMapsEqual); diff != "" {
+				t.Errorf("unexpected messages (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+var cmpEmptyMapsEqual = cmp.FilterValues(func(x, y map[string]string) bool {
+	return len(x) == 0 && len(y) == 0
+}, cmp.Comparer(func(_, _ map[string]string) bool { return true }))
+
+func TestDecode_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		dst     interface{}
+		wantErr error
+	}{
+		{
+			name:    "not a pointer",
+			dst:     testParams{},
+			wantErr: form.ErrInvalidTarget,
+		},
+		{
+			name:    "not a struct",
+			dst:     new(string),
+			wantErr: form.ErrInvalidTarget,
+		},
+		{
+			name: "unsupported type",
+			dst: &struct {
+				Tags []string `form:"tags"`
+			}{},
+			wantErr: form.ErrUnsupportedType,
+		},
+		{
+			name: "unknown rule",
+			dst: &struct {
+				Name string `form:"name" validate:"shiny"`
+			}{},
+			wantErr: form.ErrInvalidRule,
+		},
+		{
+			name: "rule missing a value",
+			dst: &struct {
+				Name string `form:"name" validate:"max"`
+			}{},
+			wantErr: form.ErrInvalidRule,
+		},
+		{
+			name: "enum of a text unmarshaler",
+			dst: &struct {
+				ID uuid.UUID `form:"id" validate:"oneof=a b"`
+			}{},
+			wantErr: form.ErrInvalidRule,
+		},
+		{
+			name: "length of a text unmarshaler",
+			dst: &struct {
+				ID uuid.UUID `form:"id" validate:"max=36"`
+			}{},
+			wantErr: form.ErrInvalidRule,
+		},
+		{
+			name: "size of a time",
+			dst: &struct {
+				Born time.Time `form:"born" validate:"min=1"`
+			}{},
+			wantErr: form.ErrInvalidRule,
+		},
+		{
+			name: "size of a bool",
+			dst: &struct {
+				Turned bool `form:"turned" validate:"max=1"`
+			}{},
+			wantErr: form.ErrInvalidRule,
+		},
+		{
+			name: "enum of an int",
+			dst: &struct {
+				Age int `form:"age" validate:"oneof=1 2"`
+			}{},
+			wantErr: form.ErrInvalidRule,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := form.Decode(url.Values{}, tt.dst)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %q; got %q", tt.wantErr, err)
+			}
+		})
+	}
+}
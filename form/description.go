@@ -0,0 +1,12 @@
+package form
+
+import "fmt"
+
+// DescriptionMaxLength is the longest description, in characters, that can be
+// given to an experience, skill, resource or mark.
+const DescriptionMaxLength = 500
+
+var descriptionValidations = stringValidations{
+	stringPresent("Please provide a description."),
+	stringMaxLength(DescriptionMaxLength, fmt.Sprintf("Please keep the description to %d characters or fewer.", DescriptionMaxLength)),
+}
@@ -0,0 +1,17 @@
+package form
+
+import "strings"
+
+type NewExperienceForm struct {
+	Description stringField
+}
+
+func NewExperience(description string) *NewExperienceForm {
+	return &NewExperienceForm{
+		Description: stringField{Value: strings.TrimSpace(description)},
+	}
+}
+
+func (f *NewExperienceForm) Valid() bool {
+	return descriptionValidations.validate(&f.Description)
+}
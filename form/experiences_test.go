@@ -0,0 +1,68 @@
+package form_test
+
+import (
+	"strings"
+	"testing"
+
+	"emailaddress.horse/thousand/form"
+)
+
+func TestNewExperienceForm_Valid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                   string
+		description            string
+		wantResult             bool
+		wantDescriptionMessage string
+		wantDescriptionValue   string
+	}{
+		{
+			name:                 "valid",
+			description:          "A description",
+			wantResult:           true,
+			wantDescriptionValue: "A description",
+		},
+		{
+			name:                 "description is trimmed",
+			description:          " A description\n",
+			wantResult:           true,
+			wantDescriptionValue: "A description",
+		},
+		{
+			name:                   "description must be present",
+			description:            "  ",
+			wantResult:             false,
+			wantDescriptionMessage: "Please provide a description.",
+		},
+		{
+			name:                   "description must not be too long",
+			description:            strings.Repeat("a", form.DescriptionMaxLength+1),
+			wantResult:             false,
+			wantDescriptionMessage: "Please keep the description to 500 characters or fewer.",
+			wantDescriptionValue:   strings.Repeat("a", form.DescriptionMaxLength+1),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			form := form.NewExperience(tt.description)
+
+			result := form.Valid()
+
+			if tt.wantResult != result {
+				t.Errorf("expected result %t; got %t", tt.wantResult, result)
+			}
+
+			if tt.wantDescriptionMessage != form.Description.Message {
+				t.Errorf("expected description message %q; got %q", tt.wantDescriptionMessage, form.Description.Message)
+			}
+
+			if tt.wantDescriptionValue != form.Description.Value {
+				t.Errorf("expected description value %q; got %q", tt.wantDescriptionValue, form.Description.Value)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package form
 
-import "regexp"
+import (
+	"regexp"
+	"unicode/utf8"
+)
 
 type stringField struct {
 	Message string
@@ -31,6 +34,17 @@ func stringPresent(msg string) stringValidation {
 	}
 }
 
+func stringMaxLength(max int, msg string) stringValidation {
+	return func(f *stringField) bool {
+		if utf8.RuneCountInString(f.Value) > max {
+			f.Message = msg
+			return false
+		}
+
+		return true
+	}
+}
+
 var emailRegex = regexp.MustCompile(`(?:[a-z0-9!#$%&'*+/=?^_\x60{|}~-]+(?:\.[a-z0-9!#$%&'*+/=?^_\x60{|}~-]+)*|"(?:[\x01-\x08\x0b\x0c\x0e-\x1f\x21\x23-\x5b\x5d-\x7f]|\\[\x01-\x09\x0b\x0c\x0e-\x7f])*")@(?:(?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+[a-z0-9](?:[a-z0-9-]*[a-z0-9])?|\[(?:(?:25[0-5]|2[0-4][0-9]|[01]?[0-9][0-9]?)\.){3}(?:25[0-5]|2[0-4][0-9]|[01]?[0-9][0-9]?|[a-z0-9-]*[a-z0-9]:(?:[\x01-\x08\x0b\x0c\x0e-\x1f\x21-\x5a\x53-\x7f]|\\[\x01-\x09\x0b\x0c\x0e-\x7f])+)\])`)
 
 func stringEmailFormat(msg string) stringValidation {
@@ -0,0 +1,17 @@
+package form
+
+import "strings"
+
+type NewMarkForm struct {
+	Description stringField
+}
+
+func NewMark(description string) *NewMarkForm {
+	return &NewMarkForm{
+		Description: stringField{Value: strings.TrimSpace(description)},
+	}
+}
+
+func (f *NewMarkForm) Valid() bool {
+	return descriptionValidations.validate(&f.Description)
+}
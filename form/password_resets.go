@@ -0,0 +1,21 @@
+package form
+
+type PasswordResetForm struct {
+	Password stringField
+}
+
+var (
+	passwordResetPasswordValidations = stringValidations{
+		stringPresent("Please provide a new password."),
+	}
+)
+
+func NewPasswordReset(password string) *PasswordResetForm {
+	return &PasswordResetForm{
+		Password: stringField{Value: password},
+	}
+}
+
+func (f *PasswordResetForm) Valid() bool {
+	return passwordResetPasswordValidations.validate(&f.Password)
+}
@@ -0,0 +1,49 @@
+package form_test
+
+import (
+	"testing"
+
+	"emailaddress.horse/thousand/form"
+)
+
+func TestPasswordResetForm_Valid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                string
+		password            string
+		wantResult          bool
+		wantPasswordMessage string
+	}{
+		{
+			name:                "valid",
+			password:            "password",
+			wantResult:          true,
+			wantPasswordMessage: "",
+		},
+		{
+			name:                "password must be present",
+			password:            "",
+			wantResult:          false,
+			wantPasswordMessage: "Please provide a new password.",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			form := form.NewPasswordReset(tt.password)
+
+			result := form.Valid()
+
+			if tt.wantResult != result {
+				t.Errorf("expected result %t; got %t", tt.wantResult, result)
+			}
+
+			if tt.wantPasswordMessage != form.Password.Message {
+				t.Errorf("expected password message %q; got %q", tt.wantPasswordMessage, form.Password.Message)
+			}
+		})
+	}
+}
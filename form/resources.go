@@ -0,0 +1,17 @@
+package form
+
+// NewResourceForm holds what was submitted to create a resource, as decoded
+// into models.CreateResourceParams, so it can be shown again.
+type NewResourceForm struct {
+	Description Field
+	Stationary  Field
+}
+
+// NewResource returns the form for the fields decoded from a new resource.
+// Fields which weren't decoded are left blank.
+func NewResource(fields Fields) *NewResourceForm {
+	return &NewResourceForm{
+		Description: fields.Get("description"),
+		Stationary:  fields.Get("stationary"),
+	}
+}
@@ -0,0 +1,97 @@
+package form_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"emailaddress.horse/thousand/form"
+	"emailaddress.horse/thousand/models"
+)
+
+func TestNewResource(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                   string
+		description            string
+		stationary             string
+		wantResult             bool
+		wantDescriptionMessage string
+		wantStationaryMessage  string
+		wantParams             models.CreateResourceParams
+	}{
+		{
+			name:        "valid and stationary",
+			description: "A manor house",
+			stationary:  "true",
+			wantResult:  true,
+			wantParams:  models.CreateResourceParams{Description: "A manor house", Stationary: true},
+		},
+		{
+			name:        "valid and not stationary",
+			description: "A silver dagger",
+			stationary:  "",
+			wantResult:  true,
+			wantParams:  models.CreateResourceParams{Description: "A silver dagger"},
+		},
+		{
+			name:                   "description must be present",
+			description:            "",
+			stationary:             "true",
+			wantResult:             false,
+			wantDescriptionMessage: "Please provide a description.",
+			wantParams:             models.CreateResourceParams{Stationary: true},
+		},
+		{
+			name:                   "description must not be too long",
+			description:            strings.Repeat("a", form.DescriptionMaxLength+1),
+			stationary:             "",
+			wantResult:             false,
+			wantDescriptionMessage: "Please keep the description to 500 characters or fewer.",
+		},
+		{
+			name:                  "stationary must be a boolean",
+			description:           "A manor house",
+			stationary:            "maybe",
+			wantResult:            false,
+			wantStationaryMessage: "Please choose whether the resource is stationary.",
+			wantParams:            models.CreateResourceParams{Description: "A manor house"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			var params models.CreateResourceParams
+			fields, err := form.Decode(url.Values{
+				"description": []string{tt.description},
+				"stationary":  []string{tt.stationary},
+			}, &params)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := fields.Valid()
+
+			if tt.wantResult != result {
+				t.Errorf("expected result %t; got %t", tt.wantResult, result)
+			}
+
+			if tt.wantParams != params {
+				t.Errorf("expected params %+v; got %+v", tt.wantParams, params)
+			}
+
+			form := form.NewResource(fields)
+
+			if tt.wantDescriptionMessage != form.Description.Message {
+				t.Errorf("expected description message %q; got %q", tt.wantDescriptionMessage, form.Description.Message)
+			}
+
+			if tt.wantStationaryMessage != form.Stationary.Message {
+				t.Errorf("expected stationary message %q; got %q", tt.wantStationaryMessage, form.Stationary.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package form
+
+import "strings"
+
+type NewSkillForm struct {
+	Description stringField
+}
+
+func NewSkill(description string) *NewSkillForm {
+	return &NewSkillForm{
+		Description: stringField{Value: strings.TrimSpace(description)},
+	}
+}
+
+func (f *NewSkillForm) Valid() bool {
+	return descriptionValidations.validate(&f.Description)
+}
@@ -0,0 +1,378 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"emailaddress.horse/thousand/audit"
+	"emailaddress.horse/thousand/middleware"
+	"emailaddress.horse/thousand/models"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type showAdminRenderer interface {
+	ShowAdmin(http.ResponseWriter, *http.Request, models.AdminStats) error
+}
+
+type adminStatsGetter interface {
+	GetAdminStats(context.Context) (models.AdminStats, error)
+}
+
+func ShowAdmin(r chi.Router, l *zap.Logger, t showAdminRenderer, sg adminStatsGetter) {
+	r.Get("/admin", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		stats, err := sg.GetAdminStats(r.Context())
+		if err != nil {
+			l.Error("failed to load admin stats", zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		err = t.ShowAdmin(w, r, stats)
+		if err != nil {
+			l.Error("failed to render", zap.Error(err))
+			handleError(w, r, err)
+		}
+	})
+}
+
+type listAdminUsersRenderer interface {
+	ListAdminUsers(http.ResponseWriter, *http.Request, string, []models.AdminUser) error
+}
+
+type adminUserSearcher interface {
+	SearchUsers(context.Context, string) ([]models.AdminUser, error)
+}
+
+func ListAdminUsers(r chi.Router, l *zap.Logger, t listAdminUsersRenderer, us adminUserSearcher) {
+	r.Get("/admin/users", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		search := strings.TrimSpace(r.URL.Query().Get("q"))
+
+		users, err := us.SearchUsers(r.Context(), search)
+		if err != nil {
+			l.Error("failed to search users", zap.String("search", search), zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		err = t.ListAdminUsers(w, r, search, users)
+		if err != nil {
+			l.Error("failed to render", zap.Error(err))
+			handleError(w, r, err)
+		}
+	})
+}
+
+type showAdminUserRenderer interface {
+	ShowAdminUser(http.ResponseWriter, *http.Request, models.User, []models.AuditEvent) error
+}
+
+type adminUserGetter interface {
+	GetUser(context.Context, uuid.UUID) (models.User, error)
+	GetAuditEventsForUser(context.Context, uuid.UUID) ([]models.AuditEvent, error)
+}
+
+func ShowAdminUser(r chi.Router, l *zap.Logger, t showAdminUserRenderer, ug adminUserGetter) {
+	r.Get("/admin/users/{userID}", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		id, ok := parseAdminUserID(w, r, l)
+		if !ok {
+			return
+		}
+
+		user, err := ug.GetUser(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				err = NotFoundError.Cause(err)
+			}
+
+			l.Error("failed to get user", zap.Stringer("userID", id), zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		events, err := ug.GetAuditEventsForUser(r.Context(), id)
+		if err != nil {
+			l.Error("failed to load audit events", zap.Stringer("userID", id), zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		err = t.ShowAdminUser(w, r, user, events)
+		if err != nil {
+			l.Error("failed to render", zap.Error(err))
+			handleError(w, r, err)
+		}
+	})
+}
+
+type listAdminVampiresRenderer interface {
+	ListAdminVampires(http.ResponseWriter, *http.Request, string, []models.AdminVampire) error
+}
+
+type adminVampireSearcher interface {
+	SearchVampires(context.Context, string) ([]models.AdminVampire, error)
+}
+
+func ListAdminVampires(r chi.Router, l *zap.Logger, t listAdminVampiresRenderer, vs adminVampireSearcher) {
+	r.Get("/admin/vampires", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		search := strings.TrimSpace(r.URL.Query().Get("q"))
+
+		vampires, err := vs.SearchVampires(r.Context(), search)
+		if err != nil {
+			l.Error("failed to search vampires", zap.String("search", search), zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		err = t.ListAdminVampires(w, r, search, vampires)
+		if err != nil {
+			l.Error("failed to render", zap.Error(err))
+			handleError(w, r, err)
+		}
+	})
+}
+
+type userDisabler interface {
+	GetUser(context.Context, uuid.UUID) (models.User, error)
+	SetUserDisabled(context.Context, uuid.UUID, bool) (models.User, error)
+}
+
+func DisableUser(r chi.Router, l *zap.Logger, ud userDisabler, s flashSetter) {
+	r.Post("/admin/users/{userID}/disable", setUserDisabled(l, ud, s, true))
+}
+
+func EnableUser(r chi.Router, l *zap.Logger, ud userDisabler, s flashSetter) {
+	r.Post("/admin/users/{userID}/enable", setUserDisabled(l, ud, s, false))
+}
+
+func setUserDisabled(l *zap.Logger, ud userDisabler, s flashSetter, disabled bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		id, ok := parseAdminUserID(w, r, l)
+		if !ok {
+			return
+		}
+
+		// Admins can't lock themselves out
+		if disabled && id == middleware.CurrentUser(r.Context()).ID {
+			l.Error("admin tried to disable themselves")
+			handleError(w, r, BadRequestError)
+			return
+		}
+
+		// Nor can they lock out each other
+		if disabled {
+			user, err := ud.GetUser(r.Context(), id)
+			if err != nil {
+				if errors.Is(err, models.ErrNotFound) {
+					err = NotFoundError.Cause(err)
+				}
+
+				l.Error("failed to get user", zap.Stringer("userID", id), zap.Error(err))
+				handleError(w, r, err)
+				return
+			}
+
+			if user.IsAdmin {
+				l.Error("admin tried to disable an admin", zap.Stringer("userID", id))
+				handleError(w, r, BadRequestError)
+				return
+			}
+		}
+
+		user, err := ud.SetUserDisabled(r.Context(), id, disabled)
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				err = NotFoundError.Cause(err)
+			}
+
+			l.Error("failed to set user disabled", zap.Stringer("userID", id), zap.Bool("disabled", disabled), zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		msg := "Enabled " + user.Email + "."
+		if disabled {
+			msg = "Disabled " + user.Email + "."
+		}
+
+		redirectWithFlash(w, r, l, s, "/admin/users/"+id.String(), msg)
+	}
+}
+
+type showPasswordResetRenderer interface {
+	ShowPasswordReset(http.ResponseWriter, *http.Request, models.User, models.PasswordReset) error
+}
+
+type passwordResetCreator interface {
+	GetUser(context.Context, uuid.UUID) (models.User, error)
+	CreatePasswordReset(context.Context, uuid.UUID) (models.PasswordReset, error)
+}
+
+// CreatePasswordReset shows the admin a link to pass on to the user, since
+// nothing sends emails, which lets them choose a new password.
+func CreatePasswordReset(r chi.Router, l *zap.Logger, t showPasswordResetRenderer, rc passwordResetCreator) {
+	r.Post("/admin/users/{userID}/password_resets", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		id, ok := parseAdminUserID(w, r, l)
+		if !ok {
+			return
+		}
+
+		user, err := rc.GetUser(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				err = NotFoundError.Cause(err)
+			}
+
+			l.Error("failed to get user", zap.Stringer("userID", id), zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		// A reset link would let the admin log in as another admin
+		if user.IsAdmin {
+			l.Error("admin tried to reset an admin's password", zap.Stringer("userID", id))
+			handleError(w, r, BadRequestError)
+			return
+		}
+
+		reset, err := rc.CreatePasswordReset(r.Context(), id)
+		if err != nil {
+			l.Error("failed to create password reset", zap.Stringer("userID", id), zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		err = t.ShowPasswordReset(w, r, user, reset)
+		if err != nil {
+			l.Error("failed to render", zap.Error(err))
+			handleError(w, r, err)
+		}
+	})
+}
+
+type impersonationStarter interface {
+	StartImpersonating(*http.Request, http.ResponseWriter, uuid.UUID, uuid.UUID) error
+	flashSetter
+}
+
+func CreateImpersonation(r chi.Router, l *zap.Logger, ug userGetter, s impersonationStarter, ar auditRecorder) {
+	r.Post("/admin/users/{userID}/impersonation", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		admin := middleware.CurrentUser(r.Context())
+
+		id, ok := parseAdminUserID(w, r, l)
+		if !ok {
+			return
+		}
+
+		if id == admin.ID {
+			l.Error("admin tried to impersonate themselves")
+			handleError(w, r, BadRequestError)
+			return
+		}
+
+		user, err := ug.GetUser(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				err = NotFoundError.Cause(err)
+			}
+
+			l.Error("failed to get user", zap.Stringer("userID", id), zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		// Acting as another admin would reach the admin pages as them
+		if user.IsAdmin {
+			l.Error("admin tried to impersonate an admin", zap.Stringer("userID", id))
+			handleError(w, r, BadRequestError)
+			return
+		}
+
+		recordAuditEvent(r, l, ar, models.AuditEvent{
+			Action:     models.AuditActionImpersonationStarted,
+			TargetType: models.AuditTargetUser,
+			TargetID:   user.ID,
+		})
+
+		if err := s.StartImpersonating(r, w, admin.ID, user.ID); err != nil {
+			l.Error("failed to start impersonating", zap.Stringer("userID", id), zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		redirectWithFlash(w, r, l, s, "/vampires", "You are now acting as "+user.Email+".")
+	})
+}
+
+type impersonationStopper interface {
+	StopImpersonating(*http.Request, http.ResponseWriter) (uuid.UUID, error)
+	flashSetter
+}
+
+// DestroyImpersonation goes back to being the admin. It can't be an admin
+// route, since while impersonating the current user is whoever is being
+// impersonated.
+func DestroyImpersonation(r chi.Router, l *zap.Logger, s impersonationStopper, ar auditRecorder) {
+	r.Delete("/impersonation", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		admin, ok := middleware.MaybeImpersonator(r.Context())
+		if !ok {
+			l.Error("tried to stop impersonating without impersonating")
+			handleError(w, r, BadRequestError)
+			return
+		}
+
+		userID, err := s.StopImpersonating(r, w)
+		if err != nil {
+			l.Error("failed to stop impersonating", zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		// The admin stopped as themselves rather than as the user
+		ctx := audit.WithImpersonator(audit.WithUser(r.Context(), admin.ID, admin.Email), uuid.UUID{})
+		recordAuditEvent(r.WithContext(ctx), l, ar, models.AuditEvent{
+			Action:     models.AuditActionImpersonationStopped,
+			TargetType: models.AuditTargetUser,
+			TargetID:   userID,
+		})
+
+		redirectWithFlash(w, r, l, s, "/admin/users/"+userID.String(), "You are yourself again.")
+	})
+}
+
+type userGetter interface {
+	GetUser(context.Context, uuid.UUID) (models.User, error)
+}
+
+// parseAdminUserID reads the userID URL parameter, responding with an error if
+// it isn't an ID.
+func parseAdminUserID(w http.ResponseWriter, r *http.Request, l *zap.Logger) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		l.Error("failed to parse id as UUID", zap.Error(err))
+		handleError(w, r, err)
+		return uuid.UUID{}, false
+	}
+
+	return id, true
+}
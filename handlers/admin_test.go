@@ -0,0 +1,996 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"emailaddress.horse/thousand/handlers"
+	"emailaddress.horse/thousand/middleware"
+	"emailaddress.horse/thousand/models"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+var (
+	testAdmin = models.User{
+		ID:      uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		Email:   "admin@bannister.com",
+		IsAdmin: true,
+	}
+	testAdminTarget = models.User{
+		ID:    uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+		Email: "jane@bannister.com",
+	}
+)
+
+type mockShowAdminRenderer struct {
+	err error
+}
+
+func (m *mockShowAdminRenderer) ShowAdmin(w http.ResponseWriter, _ *http.Request, stats models.AdminStats) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	fmt.Fprintf(w, "%d users", stats.Users)
+	return nil
+}
+
+type mockAdminStatsGetter struct {
+	stats models.AdminStats
+	err   error
+}
+
+func (m *mockAdminStatsGetter) GetAdminStats(_ context.Context) (models.AdminStats, error) {
+	return m.stats, m.err
+}
+
+func TestShowAdmin(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		renderer       *mockShowAdminRenderer
+		getter         *mockAdminStatsGetter
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:     "successful",
+			renderer: &mockShowAdminRenderer{},
+			getter: &mockAdminStatsGetter{
+				stats: models.AdminStats{Users: 3},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "3 users",
+		},
+		{
+			name:     "error from getter",
+			renderer: &mockShowAdminRenderer{},
+			getter: &mockAdminStatsGetter{
+				err: errors.New("mock error"),
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+		{
+			name: "error from renderer",
+			renderer: &mockShowAdminRenderer{
+				err: errors.New("mock error"),
+			},
+			getter:         &mockAdminStatsGetter{},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+
+			handlers.ShowAdmin(r, testLogger(t), tt.renderer, tt.getter)
+
+			status, _, body := get(r, "/admin")
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+		})
+	}
+}
+
+type mockListAdminUsersRenderer struct {
+	err error
+}
+
+func (m *mockListAdminUsersRenderer) ListAdminUsers(w http.ResponseWriter, _ *http.Request, search string, users []models.AdminUser) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	emails := make([]string, 0, len(users))
+	for _, user := range users {
+		emails = append(emails, user.Email)
+	}
+
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(map[string]interface{}{"search": search, "users": emails}); err != nil {
+		panic(err)
+	}
+
+	return nil
+}
+
+type mockAdminUserSearcher struct {
+	search string
+	users  []models.AdminUser
+	err    error
+}
+
+func (m *mockAdminUserSearcher) SearchUsers(_ context.Context, search string) ([]models.AdminUser, error) {
+	m.search = search
+	return m.users, m.err
+}
+
+func TestListAdminUsers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		renderer       *mockListAdminUsersRenderer
+		searcher       *mockAdminUserSearcher
+		path           string
+		expectedStatus int
+		expectedBody   string
+		expectedSearch string
+	}{
+		{
+			name:     "successful",
+			renderer: &mockListAdminUsersRenderer{},
+			searcher: &mockAdminUserSearcher{
+				users: []models.AdminUser{{User: testAdminTarget}},
+			},
+			path:           "/admin/users?q=+jane+",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"search":"jane","users":["jane@bannister.com"]}`,
+			expectedSearch: "jane",
+		},
+		{
+			name:           "without search",
+			renderer:       &mockListAdminUsersRenderer{},
+			searcher:       &mockAdminUserSearcher{},
+			path:           "/admin/users",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"search":"","users":[]}`,
+		},
+		{
+			name:     "error from searcher",
+			renderer: &mockListAdminUsersRenderer{},
+			searcher: &mockAdminUserSearcher{
+				err: errors.New("mock error"),
+			},
+			path:           "/admin/users?q=jane",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+			expectedSearch: "jane",
+		},
+		{
+			name: "error from renderer",
+			renderer: &mockListAdminUsersRenderer{
+				err: errors.New("mock error"),
+			},
+			searcher:       &mockAdminUserSearcher{},
+			path:           "/admin/users",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+
+			handlers.ListAdminUsers(r, testLogger(t), tt.renderer, tt.searcher)
+
+			status, _, body := get(r, tt.path)
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+
+			if tt.expectedSearch != tt.searcher.search {
+				t.Errorf("expected search %q; got %q", tt.expectedSearch, tt.searcher.search)
+			}
+		})
+	}
+}
+
+type mockShowAdminUserRenderer struct {
+	err error
+}
+
+func (m *mockShowAdminUserRenderer) ShowAdminUser(w http.ResponseWriter, _ *http.Request, user models.User, events []models.AuditEvent) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	fmt.Fprintf(w, "%s with %d events", user.Email, len(events))
+	return nil
+}
+
+type mockAdminUserGetter struct {
+	id        uuid.UUID
+	user      models.User
+	userErr   error
+	events    []models.AuditEvent
+	eventsErr error
+}
+
+func (m *mockAdminUserGetter) GetUser(_ context.Context, id uuid.UUID) (models.User, error) {
+	m.id = id
+	return m.user, m.userErr
+}
+
+func (m *mockAdminUserGetter) GetAuditEventsForUser(_ context.Context, _ uuid.UUID) ([]models.AuditEvent, error) {
+	return m.events, m.eventsErr
+}
+
+func TestShowAdminUser(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		renderer       *mockShowAdminUserRenderer
+		getter         *mockAdminUserGetter
+		path           string
+		expectedStatus int
+		expectedBody   string
+		expectedID     uuid.UUID
+	}{
+		{
+			name:     "successful",
+			renderer: &mockShowAdminUserRenderer{},
+			getter: &mockAdminUserGetter{
+				user:   testAdminTarget,
+				events: []models.AuditEvent{{Action: models.AuditActionLogin}},
+			},
+			path:           "/admin/users/22222222-2222-2222-2222-222222222222",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "jane@bannister.com with 1 events",
+			expectedID:     testAdminTarget.ID,
+		},
+		{
+			name:     "not found from getter",
+			renderer: &mockShowAdminUserRenderer{},
+			getter: &mockAdminUserGetter{
+				userErr: models.ErrNotFound,
+			},
+			path:           "/admin/users/22222222-2222-2222-2222-222222222222",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404: Not Found",
+			expectedID:     testAdminTarget.ID,
+		},
+		{
+			name:     "error getting events",
+			renderer: &mockShowAdminUserRenderer{},
+			getter: &mockAdminUserGetter{
+				user:      testAdminTarget,
+				eventsErr: errors.New("mock error"),
+			},
+			path:           "/admin/users/22222222-2222-2222-2222-222222222222",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+			expectedID:     testAdminTarget.ID,
+		},
+		{
+			name: "error from renderer",
+			renderer: &mockShowAdminUserRenderer{
+				err: errors.New("mock error"),
+			},
+			getter: &mockAdminUserGetter{
+				user: testAdminTarget,
+			},
+			path:           "/admin/users/22222222-2222-2222-2222-222222222222",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+			expectedID:     testAdminTarget.ID,
+		},
+		{
+			name:           "error parsing id",
+			renderer:       &mockShowAdminUserRenderer{},
+			getter:         &mockAdminUserGetter{},
+			path:           "/admin/users/unknown",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+
+			handlers.ShowAdminUser(r, testLogger(t), tt.renderer, tt.getter)
+
+			status, _, body := get(r, tt.path)
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+
+			if tt.expectedID != tt.getter.id {
+				t.Errorf("expected %q; got %q", tt.expectedID, tt.getter.id)
+			}
+		})
+	}
+}
+
+type mockListAdminVampiresRenderer struct {
+	err error
+}
+
+func (m *mockListAdminVampiresRenderer) ListAdminVampires(w http.ResponseWriter, _ *http.Request, search string, vampires []models.AdminVampire) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	names := make([]string, 0, len(vampires))
+	for _, vampire := range vampires {
+		names = append(names, vampire.Name)
+	}
+
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(map[string]interface{}{"search": search, "vampires": names}); err != nil {
+		panic(err)
+	}
+
+	return nil
+}
+
+type mockAdminVampireSearcher struct {
+	search   string
+	vampires []models.AdminVampire
+	err      error
+}
+
+func (m *mockAdminVampireSearcher) SearchVampires(_ context.Context, search string) ([]models.AdminVampire, error) {
+	m.search = search
+	return m.vampires, m.err
+}
+
+func TestListAdminVampires(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		renderer       *mockListAdminVampiresRenderer
+		searcher       *mockAdminVampireSearcher
+		path           string
+		expectedStatus int
+		expectedBody   string
+		expectedSearch string
+	}{
+		{
+			name:     "successful",
+			renderer: &mockListAdminVampiresRenderer{},
+			searcher: &mockAdminVampireSearcher{
+				vampires: []models.AdminVampire{{Name: "Gruffudd"}},
+			},
+			path:           "/admin/vampires?q=gruffudd",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"search":"gruffudd","vampires":["Gruffudd"]}`,
+			expectedSearch: "gruffudd",
+		},
+		{
+			name:     "error from searcher",
+			renderer: &mockListAdminVampiresRenderer{},
+			searcher: &mockAdminVampireSearcher{
+				err: errors.New("mock error"),
+			},
+			path:           "/admin/vampires",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+		{
+			name: "error from renderer",
+			renderer: &mockListAdminVampiresRenderer{
+				err: errors.New("mock error"),
+			},
+			searcher:       &mockAdminVampireSearcher{},
+			path:           "/admin/vampires",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+
+			handlers.ListAdminVampires(r, testLogger(t), tt.renderer, tt.searcher)
+
+			status, _, body := get(r, tt.path)
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+
+			if tt.expectedSearch != tt.searcher.search {
+				t.Errorf("expected search %q; got %q", tt.expectedSearch, tt.searcher.search)
+			}
+		})
+	}
+}
+
+type mockUserDisabler struct {
+	admin    bool
+	getErr   error
+	id       uuid.UUID
+	disabled bool
+	err      error
+}
+
+func (m *mockUserDisabler) GetUser(_ context.Context, _ uuid.UUID) (models.User, error) {
+	user := testAdminTarget
+	user.IsAdmin = m.admin
+	return user, m.getErr
+}
+
+func (m *mockUserDisabler) SetUserDisabled(_ context.Context, id uuid.UUID, disabled bool) (models.User, error) {
+	m.id = id
+	m.disabled = disabled
+
+	user := testAdminTarget
+	if disabled {
+		user.DisabledAt = time.Now()
+	}
+
+	return user, m.err
+}
+
+func TestDisableUser(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		disabler         *mockUserDisabler
+		path             string
+		expectedStatus   int
+		expectedBody     string
+		expectedLocation string
+		expectedFlash    string
+		expectedID       uuid.UUID
+	}{
+		{
+			name:             "successful",
+			disabler:         &mockUserDisabler{},
+			path:             "/admin/users/22222222-2222-2222-2222-222222222222/disable",
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "/admin/users/22222222-2222-2222-2222-222222222222",
+			expectedFlash:    "Disabled jane@bannister.com.",
+			expectedID:       testAdminTarget.ID,
+		},
+		{
+			name:           "disabling themselves",
+			disabler:       &mockUserDisabler{},
+			path:           "/admin/users/11111111-1111-1111-1111-111111111111/disable",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "400: Bad Request",
+		},
+		{
+			name: "disabling an admin",
+			disabler: &mockUserDisabler{
+				admin: true,
+			},
+			path:           "/admin/users/22222222-2222-2222-2222-222222222222/disable",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "400: Bad Request",
+		},
+		{
+			name: "not found getting user",
+			disabler: &mockUserDisabler{
+				getErr: models.ErrNotFound,
+			},
+			path:           "/admin/users/22222222-2222-2222-2222-222222222222/disable",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404: Not Found",
+		},
+		{
+			name: "not found from disabler",
+			disabler: &mockUserDisabler{
+				err: models.ErrNotFound,
+			},
+			path:           "/admin/users/22222222-2222-2222-2222-222222222222/disable",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404: Not Found",
+			expectedID:     testAdminTarget.ID,
+		},
+		{
+			name:           "error parsing id",
+			disabler:       &mockUserDisabler{},
+			path:           "/admin/users/unknown/disable",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+			s := &mockFlashSetter{}
+
+			handlers.DisableUser(r, testLogger(t), tt.disabler, s)
+
+			req := postRequest(tt.path, "")
+			req.request = middleware.RequestWithCurrentUser(req.request, testAdmin)
+
+			status, headers, body := req.perform(r)
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if tt.expectedLocation == "" && tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+
+			location := headers.Get("Location")
+			if tt.expectedLocation != location {
+				t.Errorf("expected location %q; got %q", tt.expectedLocation, location)
+			}
+
+			if tt.expectedFlash != s.message {
+				t.Errorf("expected flash %q; got %q", tt.expectedFlash, s.message)
+			}
+
+			if tt.expectedID != tt.disabler.id {
+				t.Errorf("expected %q; got %q", tt.expectedID, tt.disabler.id)
+			}
+
+			if tt.expectedID != (uuid.UUID{}) && !tt.disabler.disabled {
+				t.Errorf("expected user to be disabled")
+			}
+		})
+	}
+}
+
+func TestEnableUser(t *testing.T) {
+	t.Parallel()
+
+	r := chi.NewMux()
+	s := &mockFlashSetter{}
+	disabler := &mockUserDisabler{disabled: true}
+
+	handlers.EnableUser(r, testLogger(t), disabler, s)
+
+	req := postRequest("/admin/users/22222222-2222-2222-2222-222222222222/enable", "")
+	req.request = middleware.RequestWithCurrentUser(req.request, testAdmin)
+
+	status, headers, _ := req.perform(r)
+
+	if status != http.StatusSeeOther {
+		t.Errorf("expected status %d; got %d", http.StatusSeeOther, status)
+	}
+
+	if location := headers.Get("Location"); location != "/admin/users/22222222-2222-2222-2222-222222222222" {
+		t.Errorf("expected location to be the user; got %q", location)
+	}
+
+	if s.message != "Enabled jane@bannister.com." {
+		t.Errorf("expected flash; got %q", s.message)
+	}
+
+	if disabler.id != testAdminTarget.ID || disabler.disabled {
+		t.Errorf("expected user to be enabled; got %q %t", disabler.id, disabler.disabled)
+	}
+}
+
+type mockShowPasswordResetRenderer struct {
+	err error
+}
+
+func (m *mockShowPasswordResetRenderer) ShowPasswordReset(w http.ResponseWriter, _ *http.Request, user models.User, reset models.PasswordReset) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	fmt.Fprintf(w, "%s %s", user.Email, reset.Token)
+	return nil
+}
+
+type mockPasswordResetCreator struct {
+	admin     bool
+	userID    uuid.UUID
+	userErr   error
+	createErr error
+}
+
+func (m *mockPasswordResetCreator) GetUser(_ context.Context, id uuid.UUID) (models.User, error) {
+	user := testAdminTarget
+	user.IsAdmin = m.admin
+	return user, m.userErr
+}
+
+func (m *mockPasswordResetCreator) CreatePasswordReset(_ context.Context, userID uuid.UUID) (models.PasswordReset, error) {
+	m.userID = userID
+	return models.PasswordReset{UserID: userID, Token: "token"}, m.createErr
+}
+
+func TestCreatePasswordReset(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		renderer       *mockShowPasswordResetRenderer
+		creator        *mockPasswordResetCreator
+		path           string
+		expectedStatus int
+		expectedBody   string
+		expectedUserID uuid.UUID
+	}{
+		{
+			name:           "successful",
+			renderer:       &mockShowPasswordResetRenderer{},
+			creator:        &mockPasswordResetCreator{},
+			path:           "/admin/users/22222222-2222-2222-2222-222222222222/password_resets",
+			expectedStatus: http.StatusCreated,
+			expectedBody:   "jane@bannister.com token",
+			expectedUserID: testAdminTarget.ID,
+		},
+		{
+			name:     "not found getting user",
+			renderer: &mockShowPasswordResetRenderer{},
+			creator: &mockPasswordResetCreator{
+				userErr: models.ErrNotFound,
+			},
+			path:           "/admin/users/22222222-2222-2222-2222-222222222222/password_resets",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404: Not Found",
+		},
+		{
+			name:     "resetting an admin",
+			renderer: &mockShowPasswordResetRenderer{},
+			creator: &mockPasswordResetCreator{
+				admin: true,
+			},
+			path:           "/admin/users/22222222-2222-2222-2222-222222222222/password_resets",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "400: Bad Request",
+		},
+		{
+			name:     "error from creator",
+			renderer: &mockShowPasswordResetRenderer{},
+			creator: &mockPasswordResetCreator{
+				createErr: errors.New("mock error"),
+			},
+			path:           "/admin/users/22222222-2222-2222-2222-222222222222/password_resets",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+			expectedUserID: testAdminTarget.ID,
+		},
+		{
+			name:           "error parsing id",
+			renderer:       &mockShowPasswordResetRenderer{},
+			creator:        &mockPasswordResetCreator{},
+			path:           "/admin/users/unknown/password_resets",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+
+			handlers.CreatePasswordReset(r, testLogger(t), tt.renderer, tt.creator)
+
+			req := postRequest(tt.path, "")
+			req.request = middleware.RequestWithCurrentUser(req.request, testAdmin)
+
+			status, _, body := req.perform(r)
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+
+			if tt.expectedUserID != tt.creator.userID {
+				t.Errorf("expected %q; got %q", tt.expectedUserID, tt.creator.userID)
+			}
+		})
+	}
+}
+
+type mockUserGetter struct {
+	user models.User
+	err  error
+}
+
+func (m *mockUserGetter) GetUser(_ context.Context, _ uuid.UUID) (models.User, error) {
+	return m.user, m.err
+}
+
+// mockImpersonationStore records who is impersonating whom.
+type mockImpersonationStore struct {
+	mockFlashSetter
+	adminID  uuid.UUID
+	userID   uuid.UUID
+	startErr error
+	stopErr  error
+}
+
+func (m *mockImpersonationStore) StartImpersonating(_ *http.Request, _ http.ResponseWriter, adminID, userID uuid.UUID) error {
+	m.adminID = adminID
+	m.userID = userID
+	return m.startErr
+}
+
+func (m *mockImpersonationStore) StopImpersonating(_ *http.Request, _ http.ResponseWriter) (uuid.UUID, error) {
+	userID := m.userID
+	m.adminID = uuid.UUID{}
+	m.userID = uuid.UUID{}
+	return userID, m.stopErr
+}
+
+func TestCreateImpersonation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		getter           *mockUserGetter
+		store            *mockImpersonationStore
+		path             string
+		expectedStatus   int
+		expectedBody     string
+		expectedLocation string
+		expectedFlash    string
+		expectedUserID   uuid.UUID
+		expectedAudit    []string
+	}{
+		{
+			name: "successful",
+			getter: &mockUserGetter{
+				user: testAdminTarget,
+			},
+			store:            &mockImpersonationStore{},
+			path:             "/admin/users/22222222-2222-2222-2222-222222222222/impersonation",
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "/vampires",
+			expectedFlash:    "You are now acting as jane@bannister.com.",
+			expectedUserID:   testAdminTarget.ID,
+			expectedAudit:    []string{"admin.impersonation_started"},
+		},
+		{
+			name: "impersonating themselves",
+			getter: &mockUserGetter{
+				user: testAdmin,
+			},
+			store:          &mockImpersonationStore{},
+			path:           "/admin/users/11111111-1111-1111-1111-111111111111/impersonation",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "400: Bad Request",
+		},
+		{
+			name: "impersonating an admin",
+			getter: &mockUserGetter{
+				user: models.User{ID: testAdminTarget.ID, Email: testAdminTarget.Email, IsAdmin: true},
+			},
+			store:          &mockImpersonationStore{},
+			path:           "/admin/users/22222222-2222-2222-2222-222222222222/impersonation",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "400: Bad Request",
+		},
+		{
+			name: "not found from getter",
+			getter: &mockUserGetter{
+				err: models.ErrNotFound,
+			},
+			store:          &mockImpersonationStore{},
+			path:           "/admin/users/22222222-2222-2222-2222-222222222222/impersonation",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404: Not Found",
+		},
+		{
+			name: "error from store",
+			getter: &mockUserGetter{
+				user: testAdminTarget,
+			},
+			store: &mockImpersonationStore{
+				startErr: errors.New("mock error"),
+			},
+			path:           "/admin/users/22222222-2222-2222-2222-222222222222/impersonation",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+			expectedUserID: testAdminTarget.ID,
+			expectedAudit:  []string{"admin.impersonation_started"},
+		},
+		{
+			name:           "error parsing id",
+			getter:         &mockUserGetter{},
+			store:          &mockImpersonationStore{},
+			path:           "/admin/users/unknown/impersonation",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+			ar := &mockAuditRecorder{}
+
+			handlers.CreateImpersonation(r, testLogger(t), tt.getter, tt.store, ar)
+
+			req := postRequest(tt.path, "")
+			req.request = middleware.RequestWithCurrentUser(req.request, testAdmin)
+
+			status, headers, body := req.perform(r)
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if tt.expectedLocation == "" && tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+
+			location := headers.Get("Location")
+			if tt.expectedLocation != location {
+				t.Errorf("expected location %q; got %q", tt.expectedLocation, location)
+			}
+
+			if tt.expectedFlash != tt.store.message {
+				t.Errorf("expected flash %q; got %q", tt.expectedFlash, tt.store.message)
+			}
+
+			if tt.expectedUserID != tt.store.userID {
+				t.Errorf("expected %q; got %q", tt.expectedUserID, tt.store.userID)
+			}
+
+			if tt.expectedUserID != (uuid.UUID{}) && testAdmin.ID != tt.store.adminID {
+				t.Errorf("expected impersonator %q; got %q", testAdmin.ID, tt.store.adminID)
+			}
+
+			if diff := cmp.Diff(tt.expectedAudit, ar.recorded); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestDestroyImpersonation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		impersonating    bool
+		store            *mockImpersonationStore
+		expectedStatus   int
+		expectedBody     string
+		expectedLocation string
+		expectedFlash    string
+		expectedAudit    []string
+	}{
+		{
+			name:          "successful",
+			impersonating: true,
+			store: &mockImpersonationStore{
+				adminID: testAdmin.ID,
+				userID:  testAdminTarget.ID,
+			},
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "/admin/users/22222222-2222-2222-2222-222222222222",
+			expectedFlash:    "You are yourself again.",
+			expectedAudit:    []string{"admin.impersonation_stopped"},
+		},
+		{
+			name:           "not impersonating",
+			store:          &mockImpersonationStore{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "400: Bad Request",
+		},
+		{
+			name:          "error from store",
+			impersonating: true,
+			store: &mockImpersonationStore{
+				stopErr: errors.New("mock error"),
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+			ar := &mockAuditRecorder{}
+
+			handlers.DestroyImpersonation(r, testLogger(t), tt.store, ar)
+
+			req := newRequest(http.MethodDelete, "/impersonation")
+			req.request = middleware.RequestWithCurrentUser(req.request, testAdminTarget)
+			if tt.impersonating {
+				req.request = middleware.RequestWithImpersonator(req.request, testAdmin)
+			}
+
+			status, headers, body := req.perform(r)
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if tt.expectedLocation == "" && tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+
+			location := headers.Get("Location")
+			if tt.expectedLocation != location {
+				t.Errorf("expected location %q; got %q", tt.expectedLocation, location)
+			}
+
+			if tt.expectedFlash != tt.store.message {
+				t.Errorf("expected flash %q; got %q", tt.expectedFlash, tt.store.message)
+			}
+
+			if diff := cmp.Diff(tt.expectedAudit, ar.recorded); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"emailaddress.horse/thousand/middleware"
+	"emailaddress.horse/thousand/models"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type showArchiveRenderer interface {
+	ShowArchive(http.ResponseWriter, *http.Request, []models.Vampire) error
+}
+
+type archivedVampiresGetter interface {
+	GetArchivedVampires(context.Context, uuid.UUID) ([]models.Vampire, error)
+}
+
+func ShowArchive(r chi.Router, l *zap.Logger, t showArchiveRenderer, ag archivedVampiresGetter) {
+	r.Get("/archive", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		user := middleware.CurrentUser(r.Context())
+
+		vampires, err := ag.GetArchivedVampires(r.Context(), user.ID)
+		if err != nil {
+			l.Error("failed to load archived vampires", zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		err = t.ShowArchive(w, r, vampires)
+		if err != nil {
+			l.Error("failed to render", zap.Error(err))
+			handleError(w, r, err)
+		}
+	})
+}
+
+type vampireArchiver interface {
+	ArchiveVampire(context.Context, uuid.UUID) error
+}
+
+func ArchiveVampire(r chi.Router, l *zap.Logger, va vampireArchiver) {
+	r.Post("/vampires/{vampireID}/archive", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		id, err := uuid.Parse(chi.URLParam(r, "vampireID"))
+		if err != nil {
+			l.Error("failed to parse id as UUID", zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		err = va.ArchiveVampire(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				err = NotFoundError.Cause(err)
+			}
+
+			l.Error("failed to archive vampire", zap.Stringer("id", id), zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		http.Redirect(w, r, "/vampires", http.StatusSeeOther)
+	})
+}
+
+type vampireUnarchiver interface {
+	UnarchiveVampire(context.Context, uuid.UUID) error
+}
+
+func UnarchiveVampire(r chi.Router, l *zap.Logger, vu vampireUnarchiver) {
+	r.Delete("/vampires/{vampireID}/archive", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		id, err := uuid.Parse(chi.URLParam(r, "vampireID"))
+		if err != nil {
+			l.Error("failed to parse id as UUID", zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		err = vu.UnarchiveVampire(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				err = NotFoundError.Cause(err)
+			}
+
+			l.Error("failed to unarchive vampire", zap.Stringer("id", id), zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		http.Redirect(w, r, "/vampires/"+id.String(), http.StatusSeeOther)
+	})
+}
@@ -0,0 +1,245 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"emailaddress.horse/thousand/handlers"
+	"emailaddress.horse/thousand/middleware"
+	"emailaddress.horse/thousand/models"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type mockShowArchiveRenderer struct {
+	err error
+}
+
+func (m *mockShowArchiveRenderer) ShowArchive(w http.ResponseWriter, _ *http.Request, vampires []models.Vampire) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	body := "archive"
+	for _, vampire := range vampires {
+		body += " " + vampire.Name
+	}
+
+	_, err := w.Write([]byte(body))
+	if err != nil {
+		panic(err)
+	}
+
+	return nil
+}
+
+type mockArchivedVampiresGetter struct {
+	vampires []models.Vampire
+	userID   uuid.UUID
+	err      error
+}
+
+func (m *mockArchivedVampiresGetter) GetArchivedVampires(_ context.Context, userID uuid.UUID) ([]models.Vampire, error) {
+	m.userID = userID
+	return m.vampires, m.err
+}
+
+func TestShowArchive(t *testing.T) {
+	t.Parallel()
+
+	user := models.User{
+		ID:    uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		Email: "jane@bannister.com",
+	}
+
+	tests := []struct {
+		name           string
+		renderer       *mockShowArchiveRenderer
+		getter         *mockArchivedVampiresGetter
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:     "successful",
+			renderer: &mockShowArchiveRenderer{},
+			getter: &mockArchivedVampiresGetter{
+				vampires: []models.Vampire{{Name: "Gruffudd"}},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "archive Gruffudd",
+		},
+		{
+			name:     "error from getter",
+			renderer: &mockShowArchiveRenderer{},
+			getter: &mockArchivedVampiresGetter{
+				err: errors.New("mock error"),
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+		{
+			name: "error from renderer",
+			renderer: &mockShowArchiveRenderer{
+				err: errors.New("mock error"),
+			},
+			getter:         &mockArchivedVampiresGetter{},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+
+			handlers.ShowArchive(r, testLogger(t), tt.renderer, tt.getter)
+
+			req := newRequest(http.MethodGet, "/archive")
+			req.request = middleware.RequestWithCurrentUser(req.request, user)
+
+			status, _, body := req.perform(r)
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+
+			if user.ID != tt.getter.userID {
+				t.Errorf("expected user %q; got %q", user.ID, tt.getter.userID)
+			}
+		})
+	}
+}
+
+type mockVampireArchiver struct {
+	id  uuid.UUID
+	err error
+}
+
+func (m *mockVampireArchiver) ArchiveVampire(_ context.Context, id uuid.UUID) error {
+	m.id = id
+	return m.err
+}
+
+func (m *mockVampireArchiver) UnarchiveVampire(_ context.Context, id uuid.UUID) error {
+	m.id = id
+	return m.err
+}
+
+func TestArchiveVampire(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		archiver         *mockVampireArchiver
+		method           string
+		path             string
+		expectedStatus   int
+		expectedBody     string
+		expectedLocation string
+		expectedID       uuid.UUID
+	}{
+		{
+			name:             "archiving",
+			archiver:         &mockVampireArchiver{},
+			method:           http.MethodPost,
+			path:             "/vampires/22222222-2222-2222-2222-222222222222/archive",
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "/vampires",
+			expectedID:       uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+		},
+		{
+			name:             "unarchiving",
+			archiver:         &mockVampireArchiver{},
+			method:           http.MethodDelete,
+			path:             "/vampires/22222222-2222-2222-2222-222222222222/archive",
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "/vampires/22222222-2222-2222-2222-222222222222",
+			expectedID:       uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+		},
+		{
+			name: "not found archiving",
+			archiver: &mockVampireArchiver{
+				err: models.ErrNotFound,
+			},
+			method:         http.MethodPost,
+			path:           "/vampires/22222222-2222-2222-2222-222222222222/archive",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404: Not Found",
+			expectedID:     uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+		},
+		{
+			name: "not found unarchiving",
+			archiver: &mockVampireArchiver{
+				err: models.ErrNotFound,
+			},
+			method:         http.MethodDelete,
+			path:           "/vampires/22222222-2222-2222-2222-222222222222/archive",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404: Not Found",
+			expectedID:     uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+		},
+		{
+			name: "error archiving",
+			archiver: &mockVampireArchiver{
+				err: errors.New("mock error"),
+			},
+			method:         http.MethodPost,
+			path:           "/vampires/22222222-2222-2222-2222-222222222222/archive",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+			expectedID:     uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+		},
+		{
+			name:           "error parsing id",
+			archiver:       &mockVampireArchiver{},
+			method:         http.MethodPost,
+			path:           "/vampires/unknown/archive",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+
+			handlers.ArchiveVampire(r, testLogger(t), tt.archiver)
+			handlers.UnarchiveVampire(r, testLogger(t), tt.archiver)
+
+			req := newRequest(tt.method, tt.path)
+
+			status, headers, body := req.perform(r)
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if tt.expectedLocation == "" && tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+
+			location := headers.Get("Location")
+			if tt.expectedLocation != location {
+				t.Errorf("expected location %q; got %q", tt.expectedLocation, location)
+			}
+
+			if tt.expectedID != tt.archiver.id {
+				t.Errorf("expected %q; got %q", tt.expectedID, tt.archiver.id)
+			}
+		})
+	}
+}
@@ -21,7 +21,7 @@ func Assets(r chi.Router, assetsFS fs.FS) {
 		rw := newNotFoundRescuer(w, http.StatusNotFound)
 		fs.ServeHTTP(rw, r)
 		if rw.rescued {
-			handleError(w, NotFoundError)
+			handleError(w, r, NotFoundError)
 		}
 	})
 }
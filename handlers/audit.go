@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"emailaddress.horse/thousand/middleware"
+	"emailaddress.horse/thousand/models"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type auditRecorder interface {
+	RecordAuditEvent(context.Context, models.AuditEvent) error
+}
+
+// recordAuditEvent records the event, logging rather than failing the request
+// if it can't be so that people can still log in while the audit log can't be
+// written to. Changes to vampires are audited along with the change instead.
+func recordAuditEvent(r *http.Request, l *zap.Logger, ar auditRecorder, event models.AuditEvent) {
+	if err := ar.RecordAuditEvent(r.Context(), event); err != nil {
+		l.Error("failed to record audit event", zap.String("action", string(event.Action)), zap.Error(err))
+	}
+}
+
+// userAuditEvent is an event where the user acted on their own account, such
+// as by logging in.
+func userAuditEvent(action models.AuditAction, user models.User) models.AuditEvent {
+	return models.AuditEvent{
+		ActorID:    user.ID,
+		ActorEmail: user.Email,
+		Action:     action,
+		TargetType: models.AuditTargetUser,
+		TargetID:   user.ID,
+	}
+}
+
+type showSecurityActivityRenderer interface {
+	ShowSecurityActivity(http.ResponseWriter, *http.Request, []models.AuditEvent) error
+}
+
+type userAuditEventsGetter interface {
+	GetAuditEventsForUser(context.Context, uuid.UUID) ([]models.AuditEvent, error)
+}
+
+func ShowSecurityActivity(r chi.Router, l *zap.Logger, t showSecurityActivityRenderer, ag userAuditEventsGetter) {
+	r.Get("/user/activity", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		user := middleware.CurrentUser(r.Context())
+
+		events, err := ag.GetAuditEventsForUser(r.Context(), user.ID)
+		if err != nil {
+			l.Error("failed to load audit events", zap.Stringer("userID", user.ID), zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		err = t.ShowSecurityActivity(w, r, events)
+		if err != nil {
+			l.Error("failed to render", zap.Error(err))
+			handleError(w, r, err)
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"emailaddress.horse/thousand/handlers"
+	"emailaddress.horse/thousand/middleware"
+	"emailaddress.horse/thousand/models"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type mockShowSecurityActivityRenderer struct {
+	err error
+}
+
+func (m *mockShowSecurityActivityRenderer) ShowSecurityActivity(w http.ResponseWriter, _ *http.Request, events []models.AuditEvent) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	actions := make([]models.AuditAction, 0, len(events))
+	for _, event := range events {
+		actions = append(actions, event.Action)
+	}
+
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(actions); err != nil {
+		panic(err)
+	}
+
+	return nil
+}
+
+type mockUserAuditEventsGetter struct {
+	userID uuid.UUID
+	events []models.AuditEvent
+	err    error
+}
+
+func (m *mockUserAuditEventsGetter) GetAuditEventsForUser(_ context.Context, userID uuid.UUID) ([]models.AuditEvent, error) {
+	m.userID = userID
+	return m.events, m.err
+}
+
+func TestShowSecurityActivity(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+
+	tests := []struct {
+		name           string
+		renderer       *mockShowSecurityActivityRenderer
+		getter         *mockUserAuditEventsGetter
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:     "successful",
+			renderer: &mockShowSecurityActivityRenderer{},
+			getter: &mockUserAuditEventsGetter{
+				events: []models.AuditEvent{
+					{Action: models.AuditActionLogin},
+					{Action: models.AuditActionLoginFailed},
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `["session.login","session.login_failed"]`,
+		},
+		{
+			name:     "error from getter",
+			renderer: &mockShowSecurityActivityRenderer{},
+			getter: &mockUserAuditEventsGetter{
+				err: errors.New("mock error"),
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+		{
+			name: "error from renderer",
+			renderer: &mockShowSecurityActivityRenderer{
+				err: errors.New("mock error"),
+			},
+			getter:         &mockUserAuditEventsGetter{},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+
+			handlers.ShowSecurityActivity(r, testLogger(t), tt.renderer, tt.getter)
+
+			req := newRequest(http.MethodGet, "/user/activity")
+			req.request = middleware.RequestWithCurrentUser(req.request, models.User{ID: userID})
+
+			status, _, body := req.perform(r)
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+
+			if userID != tt.getter.userID {
+				t.Errorf("expected %q; got %q", userID, tt.getter.userID)
+			}
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"net/http"
 
+	"emailaddress.horse/thousand/form"
+	"emailaddress.horse/thousand/middleware"
 	"emailaddress.horse/thousand/models"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -11,15 +13,17 @@ import (
 )
 
 type newCharacterRenderer interface {
-	NewCharacter(http.ResponseWriter, *http.Request, models.Vampire) error
+	NewCharacter(http.ResponseWriter, *http.Request, models.Vampire, *form.NewCharacterForm) error
 }
 
 func NewCharacter(r chi.Router, l *zap.Logger, t newCharacterRenderer, vg vampireGetter) {
 	r.Get("/vampires/{vampireID}/characters/new", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
 		vampireID, err := uuid.Parse(chi.URLParam(r, "vampireID"))
 		if err != nil {
 			l.Error("failed to parse id as UUID", zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 			return
 		}
 
@@ -30,40 +34,90 @@ func NewCharacter(r chi.Router, l *zap.Logger, t newCharacterRenderer, vg vampir
 			}
 
 			l.Error("failed to find vampire", zap.Stringer("vampireID", vampireID), zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 			return
 		}
 
-		err = t.NewCharacter(w, r, vampire)
+		err = t.NewCharacter(w, r, vampire, form.NewCharacter(form.Fields{}))
 		if err != nil {
 			l.Error("failed to render", zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 		}
 	})
 }
 
-func CreateCharacter(r chi.Router, l *zap.Logger, cc characterCreator) {
+type createCharacterRenderer interface {
+	NewCharacter(http.ResponseWriter, *http.Request, models.Vampire, *form.NewCharacterForm) error
+	CreateCharacter(http.ResponseWriter, *http.Request, uuid.UUID, models.Character) error
+}
+
+func CreateCharacter(r chi.Router, l *zap.Logger, t createCharacterRenderer, vg vampireGetter, cc characterCreator, b characterBroadcaster) {
 	r.Post("/vampires/{vampireID}/characters", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
 		vampireID, err := uuid.Parse(chi.URLParam(r, "vampireID"))
 		if err != nil {
 			l.Error("failed to parse id as UUID", zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
+			return
+		}
+
+		if err := r.ParseForm(); err != nil {
+			l.Error("failed to parse form", zap.Error(err))
+			handleError(w, r, BadRequestError.Cause(err))
+			return
+		}
+
+		var params models.CreateCharacterParams
+		fields, err := form.Decode(r.PostForm, &params)
+		if err != nil {
+			l.Error("failed to decode character", zap.Error(err))
+			handleError(w, r, err)
 			return
 		}
 
-		params := models.CreateCharacterParams{
-			Name: r.FormValue("name"),
-			Type: r.FormValue("type"),
+		if !fields.Valid() {
+			vampire, err := vg.GetVampire(r.Context(), vampireID)
+			if err != nil {
+				if errors.Is(err, models.ErrNotFound) {
+					err = NotFoundError.Cause(err)
+				}
+
+				l.Error("failed to find vampire", zap.Stringer("vampireID", vampireID), zap.Error(err))
+				handleError(w, r, err)
+				return
+			}
+
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			err = t.NewCharacter(w, r, vampire, form.NewCharacter(fields))
+			if err != nil {
+				l.Error("failed to render", zap.Error(err))
+				handleError(w, r, err)
+			}
+			return
 		}
 
-		_, err = cc.CreateCharacter(r.Context(), vampireID, params)
+		character, err := cc.CreateCharacter(r.Context(), vampireID, params)
 		if err != nil {
 			if errors.Is(err, models.ErrNotFound) {
 				err = NotFoundError.Cause(err)
 			}
 
 			l.Error("failed to create character", zap.Stringer("vampireID", vampireID), zap.Object("params", params), zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
+			return
+		}
+
+		if err := b.CharacterCreated(r.Context(), vampireID, character); err != nil {
+			l.Error("failed to broadcast character", zap.Stringer("vampireID", vampireID), zap.Error(err))
+		}
+
+		if acceptsTurboStream(r) {
+			err = t.CreateCharacter(w, r, vampireID, character)
+			if err != nil {
+				l.Error("failed to render", zap.Error(err))
+				handleError(w, r, err)
+			}
 			return
 		}
 
@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"testing"
 
+	"emailaddress.horse/thousand/form"
 	"emailaddress.horse/thousand/handlers"
 	"emailaddress.horse/thousand/models"
 	"github.com/go-chi/chi/v5"
@@ -17,7 +18,7 @@ type mockNewCharacterRenderer struct {
 	err error
 }
 
-func (m *mockNewCharacterRenderer) NewCharacter(w http.ResponseWriter, _ *http.Request, vampire models.Vampire) error {
+func (m *mockNewCharacterRenderer) NewCharacter(w http.ResponseWriter, _ *http.Request, vampire models.Vampire, _ *form.NewCharacterForm) error {
 	if m.err != nil {
 		return m.err
 	}
@@ -140,19 +141,50 @@ func (m *mockCharacterCreator) CreateCharacter(_ context.Context, vampireID uuid
 	return models.Character{}, m.err
 }
 
+type mockCreateCharacterRenderer struct {
+	err error
+}
+
+func (m *mockCreateCharacterRenderer) NewCharacter(w http.ResponseWriter, _ *http.Request, _ models.Vampire, f *form.NewCharacterForm) error {
+	_, err := w.Write([]byte(f.Name.Message + f.Type.Message))
+	if err != nil {
+		panic(err)
+	}
+
+	return nil
+}
+
+func (m *mockCreateCharacterRenderer) CreateCharacter(w http.ResponseWriter, _ *http.Request, vampireID uuid.UUID, _ models.Character) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	_, err := w.Write([]byte(vampireID.String()))
+	if err != nil {
+		panic(err)
+	}
+
+	return nil
+}
+
 func TestCreateCharacter(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		name              string
-		body              url.Values
-		creator           *mockCharacterCreator
-		path              string
-		expectedStatus    int
-		expectedBody      string
-		expectedLocation  string
-		expectedVampireID uuid.UUID
-		expectedParams    models.CreateCharacterParams
+		name               string
+		body               url.Values
+		accept             string
+		renderer           *mockCreateCharacterRenderer
+		getter             *mockVampireGetter
+		creator            *mockCharacterCreator
+		broadcaster        *mockBroadcaster
+		path               string
+		expectedStatus     int
+		expectedBroadcasts int
+		expectedBody       string
+		expectedLocation   string
+		expectedVampireID  uuid.UUID
+		expectedParams     models.CreateCharacterParams
 	}{
 		{
 			name: "successful",
@@ -160,11 +192,15 @@ func TestCreateCharacter(t *testing.T) {
 				"name": []string{"a name"},
 				"type": []string{"mortal"},
 			},
-			creator:           &mockCharacterCreator{},
-			path:              "/vampires/12345678-90ab-cdef-1234-567890abcdef/characters",
-			expectedStatus:    http.StatusSeeOther,
-			expectedLocation:  "/vampires/12345678-90ab-cdef-1234-567890abcdef",
-			expectedVampireID: uuid.MustParse("12345678-90ab-cdef-1234-567890abcdef"),
+			renderer:           &mockCreateCharacterRenderer{},
+			getter:             &mockVampireGetter{},
+			creator:            &mockCharacterCreator{},
+			broadcaster:        &mockBroadcaster{},
+			path:               "/vampires/12345678-90ab-cdef-1234-567890abcdef/characters",
+			expectedStatus:     http.StatusSeeOther,
+			expectedBroadcasts: 1,
+			expectedLocation:   "/vampires/12345678-90ab-cdef-1234-567890abcdef",
+			expectedVampireID:  uuid.MustParse("12345678-90ab-cdef-1234-567890abcdef"),
 			expectedParams: models.CreateCharacterParams{
 				Name: "a name",
 				Type: "mortal",
@@ -176,7 +212,10 @@ func TestCreateCharacter(t *testing.T) {
 				"name": []string{"a name"},
 				"type": []string{"mortal"},
 			},
+			renderer:       &mockCreateCharacterRenderer{},
+			getter:         &mockVampireGetter{},
 			creator:        &mockCharacterCreator{},
+			broadcaster:    &mockBroadcaster{},
 			path:           "/vampires/unknown/characters",
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody:   "500: Internal Server Error",
@@ -187,9 +226,12 @@ func TestCreateCharacter(t *testing.T) {
 				"name": []string{"a name"},
 				"type": []string{"mortal"},
 			},
+			renderer: &mockCreateCharacterRenderer{},
+			getter:   &mockVampireGetter{},
 			creator: &mockCharacterCreator{
 				err: models.ErrNotFound,
 			},
+			broadcaster:       &mockBroadcaster{},
 			path:              "/vampires/12345678-90ab-cdef-1234-567890abcdef/characters",
 			expectedStatus:    http.StatusNotFound,
 			expectedBody:      "404: Not Found",
@@ -205,9 +247,12 @@ func TestCreateCharacter(t *testing.T) {
 				"name": []string{"a name"},
 				"type": []string{"mortal"},
 			},
+			renderer: &mockCreateCharacterRenderer{},
+			getter:   &mockVampireGetter{},
 			creator: &mockCharacterCreator{
 				err: errors.New("mock error"),
 			},
+			broadcaster:       &mockBroadcaster{},
 			path:              "/vampires/12345678-90ab-cdef-1234-567890abcdef/characters",
 			expectedStatus:    http.StatusInternalServerError,
 			expectedBody:      "500: Internal Server Error",
@@ -217,6 +262,110 @@ func TestCreateCharacter(t *testing.T) {
 				Type: "mortal",
 			},
 		},
+		{
+			name: "error from broadcaster",
+			body: url.Values{
+				"name": []string{"a name"},
+				"type": []string{"mortal"},
+			},
+			renderer:           &mockCreateCharacterRenderer{},
+			getter:             &mockVampireGetter{},
+			creator:            &mockCharacterCreator{},
+			broadcaster:        &mockBroadcaster{err: errors.New("mock error")},
+			path:               "/vampires/12345678-90ab-cdef-1234-567890abcdef/characters",
+			expectedStatus:     http.StatusSeeOther,
+			expectedBroadcasts: 1,
+			expectedLocation:   "/vampires/12345678-90ab-cdef-1234-567890abcdef",
+			expectedVampireID:  uuid.MustParse("12345678-90ab-cdef-1234-567890abcdef"),
+			expectedParams: models.CreateCharacterParams{
+				Name: "a name",
+				Type: "mortal",
+			},
+		},
+		{
+			name:   "successful turbo stream",
+			accept: "text/vnd.turbo-stream.html",
+			body: url.Values{
+				"name": []string{"a name"},
+				"type": []string{"mortal"},
+			},
+			renderer:           &mockCreateCharacterRenderer{},
+			getter:             &mockVampireGetter{},
+			creator:            &mockCharacterCreator{},
+			broadcaster:        &mockBroadcaster{},
+			path:               "/vampires/12345678-90ab-cdef-1234-567890abcdef/characters",
+			expectedStatus:     http.StatusOK,
+			expectedBroadcasts: 1,
+			expectedBody:       "12345678-90ab-cdef-1234-567890abcdef",
+			expectedVampireID:  uuid.MustParse("12345678-90ab-cdef-1234-567890abcdef"),
+			expectedParams: models.CreateCharacterParams{
+				Name: "a name",
+				Type: "mortal",
+			},
+		},
+		{
+			name:   "error from renderer",
+			accept: "text/vnd.turbo-stream.html",
+			body: url.Values{
+				"name": []string{"a name"},
+				"type": []string{"mortal"},
+			},
+			renderer:           &mockCreateCharacterRenderer{err: errors.New("mock error")},
+			getter:             &mockVampireGetter{},
+			creator:            &mockCharacterCreator{},
+			broadcaster:        &mockBroadcaster{},
+			path:               "/vampires/12345678-90ab-cdef-1234-567890abcdef/characters",
+			expectedStatus:     http.StatusInternalServerError,
+			expectedBroadcasts: 1,
+			expectedBody:       "500: Internal Server Error",
+			expectedVampireID:  uuid.MustParse("12345678-90ab-cdef-1234-567890abcdef"),
+			expectedParams: models.CreateCharacterParams{
+				Name: "a name",
+				Type: "mortal",
+			},
+		},
+		{
+			name: "invalid form",
+			body: url.Values{
+				"name": []string{""},
+				"type": []string{"mortal"},
+			},
+			renderer:       &mockCreateCharacterRenderer{},
+			getter:         &mockVampireGetter{},
+			creator:        &mockCharacterCreator{},
+			broadcaster:    &mockBroadcaster{},
+			path:           "/vampires/12345678-90ab-cdef-1234-567890abcdef/characters",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   "Please provide a name.",
+		},
+		{
+			name: "unknown type",
+			body: url.Values{
+				"name": []string{"a name"},
+				"type": []string{"undead"},
+			},
+			renderer:       &mockCreateCharacterRenderer{},
+			getter:         &mockVampireGetter{},
+			creator:        &mockCharacterCreator{},
+			broadcaster:    &mockBroadcaster{},
+			path:           "/vampires/12345678-90ab-cdef-1234-567890abcdef/characters",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   "Please choose whether the character is mortal or immortal.",
+		},
+		{
+			name: "not found from getter with invalid form",
+			body: url.Values{
+				"name": []string{""},
+				"type": []string{"mortal"},
+			},
+			renderer:       &mockCreateCharacterRenderer{},
+			getter:         &mockVampireGetter{err: models.ErrNotFound},
+			creator:        &mockCharacterCreator{},
+			broadcaster:    &mockBroadcaster{},
+			path:           "/vampires/12345678-90ab-cdef-1234-567890abcdef/characters",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404: Not Found",
+		},
 	}
 
 	for _, tt := range tests {
@@ -227,9 +376,12 @@ func TestCreateCharacter(t *testing.T) {
 
 			r := chi.NewMux()
 
-			handlers.CreateCharacter(r, testLogger(t), tt.creator)
+			handlers.CreateCharacter(r, testLogger(t), tt.renderer, tt.getter, tt.creator, tt.broadcaster)
+
+			req := postRequest(tt.path, tt.body.Encode())
+			req.request.Header.Set("Accept", tt.accept)
 
-			status, headers, body := post(r, tt.path, tt.body.Encode())
+			status, headers, body := req.perform(r)
 
 			if tt.expectedStatus != status {
 				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
@@ -251,6 +403,10 @@ func TestCreateCharacter(t *testing.T) {
 			if tt.expectedLocation != location {
 				t.Errorf("expected location %q; got %q", tt.expectedLocation, location)
 			}
+
+			if tt.expectedBroadcasts != tt.broadcaster.count {
+				t.Errorf("expected %d broadcasts; got %d", tt.expectedBroadcasts, tt.broadcaster.count)
+			}
 		})
 	}
 }
@@ -4,11 +4,23 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+
+	"emailaddress.horse/thousand/middleware"
+	"emailaddress.horse/thousand/models"
+	"github.com/go-chi/chi/v5"
 )
 
 var (
-	NotFoundError       = NewError("Not Found", http.StatusNotFound)
-	InternalServerError = NewError("Internal Server Error", http.StatusInternalServerError)
+	BadRequestError          = NewError("Bad Request", http.StatusBadRequest)
+	ForbiddenError           = NewError("Forbidden", http.StatusForbidden)
+	NotFoundError            = NewError("Not Found", http.StatusNotFound)
+	ConflictError            = NewError("Conflict", http.StatusConflict)
+	UnprocessableEntityError = NewError("Unprocessable Entity", http.StatusUnprocessableEntity)
+	InternalServerError      = NewError("Internal Server Error", http.StatusInternalServerError)
+
+	MemoryFullError = NewError("This memory is full", http.StatusConflict)
+
+	methodNotAllowedError = NewError("Method Not Allowed", http.StatusMethodNotAllowed)
 )
 
 type HTTPError struct {
@@ -54,11 +66,41 @@ func (err HTTPError) Status() int {
 	return err.status
 }
 
-func handleError(w http.ResponseWriter, err error) {
+func (err HTTPError) PublicMessage() string {
+	return err.publicMsg
+}
+
+func handleError(w http.ResponseWriter, r *http.Request, err error) {
 	httpErr := HTTPError{}
 	if !errors.As(err, &httpErr) {
-		httpErr = InternalServerError
+		httpErr = publicError(err)
 	}
 
-	http.Error(w, httpErr.PublicError(), httpErr.Status())
+	middleware.Error(w, r, httpErr.PublicMessage(), httpErr.Status())
+}
+
+// publicError picks the error to show for domain errors which handlers have
+// not already mapped to one.
+func publicError(err error) HTTPError {
+	switch {
+	case errors.Is(err, models.ErrMemoryFull):
+		return MemoryFullError.Cause(err)
+	default:
+		return InternalServerError.Cause(err)
+	}
+}
+
+// NotFound responds to requests which match no route with the not found page.
+func NotFound(r chi.Router) {
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		handleError(w, r, NotFoundError)
+	})
+}
+
+// MethodNotAllowed responds to requests which match a route but not any of its
+// methods.
+func MethodNotAllowed(r chi.Router) {
+	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		handleError(w, r, methodNotAllowedError)
+	})
 }
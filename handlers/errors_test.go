@@ -0,0 +1,198 @@
+package handlers_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"emailaddress.horse/thousand/handlers"
+	"emailaddress.horse/thousand/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+type mockErrorRenderer struct {
+	err error
+}
+
+func (m *mockErrorRenderer) Error(w http.ResponseWriter, r *http.Request, status int, message, requestID string) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	w.WriteHeader(status)
+	_, err := fmt.Fprintf(w, "page %d %s %s", status, message, requestID)
+	return err
+}
+
+func TestErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		method            string
+		accept            string
+		requestID         string
+		renderer          *mockErrorRenderer
+		expectedStatus    int
+		expectedType      string
+		expectedRequestID string
+		expectedBody      string
+	}{
+		{
+			name:           "plain text without renderer",
+			method:         http.MethodGet,
+			expectedStatus: http.StatusNotFound,
+			expectedType:   "text/plain; charset=utf-8",
+			expectedBody:   "404: Not Found",
+		},
+		{
+			name:              "page with request ID",
+			method:            http.MethodGet,
+			accept:            "text/html,application/xhtml+xml,application/json;q=0.9",
+			requestID:         "abc123",
+			renderer:          &mockErrorRenderer{},
+			expectedStatus:    http.StatusNotFound,
+			expectedRequestID: "abc123",
+			expectedBody:      "page 404 Not Found abc123",
+		},
+		{
+			name:           "plain text when the page fails to render",
+			method:         http.MethodGet,
+			renderer:       &mockErrorRenderer{err: errors.New("oh no")},
+			expectedStatus: http.StatusNotFound,
+			expectedType:   "text/plain; charset=utf-8",
+			expectedBody:   "404: Not Found",
+		},
+		{
+			name:              "json",
+			method:            http.MethodGet,
+			accept:            "application/json",
+			requestID:         "abc123",
+			renderer:          &mockErrorRenderer{},
+			expectedStatus:    http.StatusNotFound,
+			expectedType:      "application/json",
+			expectedRequestID: "abc123",
+			expectedBody:      `{"error":{"status":404,"message":"Not Found","requestID":"abc123"}}`,
+		},
+		{
+			name:           "method not allowed",
+			method:         http.MethodPatch,
+			renderer:       &mockErrorRenderer{},
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+
+			middleware.RequestID(r)
+			if tt.renderer != nil {
+				middleware.RenderErrors(r, tt.renderer)
+			}
+			handlers.NotFound(r)
+			handlers.MethodNotAllowed(r)
+			handlers.Root(r)
+
+			path := "/missing"
+			if tt.method != http.MethodGet {
+				path = "/"
+			}
+
+			req := newRequest(tt.method, path)
+			req.request.Header.Set("Accept", tt.accept)
+			if tt.requestID != "" {
+				req.request.Header.Set("X-Request-Id", tt.requestID)
+			}
+
+			status, header, body := req.perform(r)
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if contentType := header.Get("Content-Type"); tt.expectedType != "" && tt.expectedType != contentType {
+				t.Errorf("expected content type %q; got %q", tt.expectedType, contentType)
+			}
+
+			if requestID := header.Get(middleware.RequestIDHeader); tt.expectedRequestID != "" && tt.expectedRequestID != requestID {
+				t.Errorf("expected request ID %q; got %q", tt.expectedRequestID, requestID)
+			}
+
+			if tt.expectedBody != "" && tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+		})
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		requestID string
+		reused    bool
+	}{
+		{
+			name:      "reused",
+			requestID: "host/abc-123_4.5",
+			reused:    true,
+		},
+		{
+			name:      "longest reused",
+			requestID: strings.Repeat("a", 64),
+			reused:    true,
+		},
+		{
+			name: "generated without one",
+		},
+		{
+			name:      "too long",
+			requestID: strings.Repeat("a", 65),
+		},
+		{
+			name:      "unexpected characters",
+			requestID: "abc */ <script>",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+
+			middleware.RequestID(r)
+
+			var seen string
+			r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+				seen = middleware.GetRequestID(r.Context())
+			})
+
+			req := newRequest(http.MethodGet, "/")
+			if tt.requestID != "" {
+				req.request.Header.Set("X-Request-Id", tt.requestID)
+			}
+
+			_, header, _ := req.perform(r)
+
+			requestID := header.Get(middleware.RequestIDHeader)
+			if requestID == "" || requestID != seen {
+				t.Fatalf("expected the request ID %q to be sent back; got %q", seen, requestID)
+			}
+
+			if reused := requestID == tt.requestID; tt.reused != reused {
+				t.Errorf("expected request ID %q to be reused: %t; got %q", tt.requestID, tt.reused, requestID)
+			}
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"net/http"
 
+	"emailaddress.horse/thousand/form"
+	"emailaddress.horse/thousand/middleware"
 	"emailaddress.horse/thousand/models"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -11,22 +13,24 @@ import (
 )
 
 type newExperienceRenderer interface {
-	NewExperience(http.ResponseWriter, *http.Request, models.Memory) error
+	NewExperience(http.ResponseWriter, *http.Request, models.Memory, *form.NewExperienceForm) error
 }
 
 func NewExperience(r chi.Router, l *zap.Logger, t newExperienceRenderer, mg memoryGetter) {
 	r.Get("/vampires/{vampireID}/memories/{id}/experiences/new", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
 		vampireID, err := uuid.Parse(chi.URLParam(r, "vampireID"))
 		if err != nil {
 			l.Error("failed to parse id as UUID", zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 			return
 		}
 
 		memoryID, err := uuid.Parse(chi.URLParam(r, "id"))
 		if err != nil {
 			l.Error("failed to parse id as UUID", zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 			return
 		}
 
@@ -37,44 +41,122 @@ func NewExperience(r chi.Router, l *zap.Logger, t newExperienceRenderer, mg memo
 			}
 
 			l.Error("failed to find memory", zap.Stringer("vampireID", vampireID), zap.Stringer("memoryID", memoryID), zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 			return
 		}
 
-		err = t.NewExperience(w, r, memory)
+		err = t.NewExperience(w, r, memory, form.NewExperience(""))
 		if err != nil {
 			l.Error("failed to render", zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 		}
 	})
 }
 
-func CreateExperience(r chi.Router, l *zap.Logger, ec experienceCreator) {
+type createExperienceRenderer interface {
+	NewExperience(http.ResponseWriter, *http.Request, models.Memory, *form.NewExperienceForm) error
+	CreateExperience(http.ResponseWriter, *http.Request, models.Memory, models.Experience) error
+}
+
+func CreateExperience(r chi.Router, l *zap.Logger, t createExperienceRenderer, ec experienceCreator, mg memoryGetter, b experienceBroadcaster, m experienceMetrics) {
 	r.Post("/vampires/{vampireID}/memories/{id}/experiences", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
 		vampireID, err := uuid.Parse(chi.URLParam(r, "vampireID"))
 		if err != nil {
 			l.Error("failed to parse id as UUID", zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 			return
 		}
 
 		memoryID, err := uuid.Parse(chi.URLParam(r, "id"))
 		if err != nil {
 			l.Error("failed to parse id as UUID", zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 			return
 		}
 
-		description := r.FormValue("description")
+		form := form.NewExperience(r.FormValue("description"))
 
-		_, err = ec.CreateExperience(r.Context(), vampireID, memoryID, description)
-		if err != nil {
+		if !form.Valid() {
+			memory, err := mg.GetMemory(r.Context(), vampireID, memoryID)
+			if err != nil {
+				if errors.Is(err, models.ErrNotFound) {
+					err = NotFoundError.Cause(err)
+				}
+
+				l.Error("failed to find memory", zap.Stringer("vampireID", vampireID), zap.Stringer("memoryID", memoryID), zap.Error(err))
+				handleError(w, r, err)
+				return
+			}
+
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			err = t.NewExperience(w, r, memory, form)
+			if err != nil {
+				l.Error("failed to render", zap.Error(err))
+				handleError(w, r, err)
+			}
+			return
+		}
+
+		// The memory may have filled up since the form was shown, in which case
+		// the form is swapped for an explanation.
+		experience, err := ec.CreateExperience(r.Context(), vampireID, memoryID, form.Description.Value)
+		if errors.Is(err, models.ErrMemoryFull) {
+			memory, err := mg.GetMemory(r.Context(), vampireID, memoryID)
+			if err != nil {
+				if errors.Is(err, models.ErrNotFound) {
+					err = NotFoundError.Cause(err)
+				}
+
+				l.Error("failed to find memory", zap.Stringer("vampireID", vampireID), zap.Stringer("memoryID", memoryID), zap.Error(err))
+				handleError(w, r, err)
+				return
+			}
+
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			err = t.NewExperience(w, r, memory, form)
+			if err != nil {
+				l.Error("failed to render", zap.Error(err))
+				handleError(w, r, err)
+			}
+			return
+		} else if err != nil {
 			if errors.Is(err, models.ErrNotFound) {
 				err = NotFoundError.Cause(err)
 			}
 
 			l.Error("failed to create experience", zap.Stringer("vampireID", vampireID), zap.Stringer("memoryID", memoryID), zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
+			return
+		}
+
+		m.ExperienceWritten()
+
+		if err := b.ExperienceCreated(r.Context(), vampireID, experience); err != nil {
+			l.Error("failed to broadcast experience", zap.Stringer("vampireID", vampireID), zap.Error(err))
+		}
+
+		// The memory is reloaded to find out whether this experience filled it.
+		// The experience has already been written, so failing to reload falls
+		// back to redirecting rather than inviting the user to write it again.
+		memory, err := mg.GetMemory(r.Context(), vampireID, memoryID)
+		if err != nil {
+			l.Error("failed to reload memory", zap.Stringer("vampireID", vampireID), zap.Stringer("memoryID", memoryID), zap.Error(err))
+			http.Redirect(w, r, "/vampires/"+vampireID.String(), http.StatusSeeOther)
+			return
+		}
+
+		if memory.Full() {
+			m.MemoryFilled()
+		}
+
+		if acceptsTurboStream(r) {
+			err = t.CreateExperience(w, r, memory, experience)
+			if err != nil {
+				l.Error("failed to render", zap.Error(err))
+				handleError(w, r, err)
+			}
 			return
 		}
 
@@ -7,9 +7,11 @@ import (
 	"net/url"
 	"testing"
 
+	"emailaddress.horse/thousand/form"
 	"emailaddress.horse/thousand/handlers"
 	"emailaddress.horse/thousand/models"
 	"github.com/go-chi/chi/v5"
+	"github.com/google/go-cmp/cmp"
 	"github.com/google/uuid"
 )
 
@@ -17,7 +19,7 @@ type mockNewExperienceRenderer struct {
 	err error
 }
 
-func (m *mockNewExperienceRenderer) NewExperience(w http.ResponseWriter, _ *http.Request, memory models.Memory) error {
+func (m *mockNewExperienceRenderer) NewExperience(w http.ResponseWriter, _ *http.Request, memory models.Memory, _ *form.NewExperienceForm) error {
 	if m.err != nil {
 		return m.err
 	}
@@ -175,29 +177,67 @@ func (m *mockExperienceCreator) CreateExperience(_ context.Context, vampireID, m
 	return models.Experience{}, m.err
 }
 
+type mockCreateExperienceRenderer struct {
+	err error
+}
+
+func (m *mockCreateExperienceRenderer) NewExperience(w http.ResponseWriter, _ *http.Request, memory models.Memory, f *form.NewExperienceForm) error {
+	return m.write(w, "new "+memory.ID.String()+" "+f.Description.Message)
+}
+
+func (m *mockCreateExperienceRenderer) CreateExperience(w http.ResponseWriter, _ *http.Request, memory models.Memory, _ models.Experience) error {
+	return m.write(w, "created "+memory.ID.String())
+}
+
+func (m *mockCreateExperienceRenderer) write(w http.ResponseWriter, body string) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	_, err := w.Write([]byte(body))
+	if err != nil {
+		panic(err)
+	}
+
+	return nil
+}
+
 func TestCreateExperience(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
 		name                string
 		body                url.Values
+		accept              string
+		renderer            *mockCreateExperienceRenderer
 		creator             *mockExperienceCreator
+		getter              *mockMemoryGetter
+		broadcaster         *mockBroadcaster
 		path                string
 		expectedStatus      int
+		expectedBroadcasts  int
 		expectedBody        string
 		expectedLocation    string
 		expectedVampireID   uuid.UUID
 		expectedMemoryID    uuid.UUID
 		expectedDescription string
+		expectedMetrics     []string
 	}{
 		{
 			name: "successful",
 			body: url.Values{
 				"description": []string{"A description"},
 			},
-			creator:             &mockExperienceCreator{},
+			renderer: &mockCreateExperienceRenderer{},
+			creator:  &mockExperienceCreator{},
+			getter: &mockMemoryGetter{
+				memory: models.Memory{ID: uuid.MustParse("22222222-2222-2222-2222-222222222222")},
+			},
+			broadcaster:         &mockBroadcaster{},
 			path:                "/vampires/11111111-1111-1111-1111-111111111111/memories/22222222-2222-2222-2222-222222222222/experiences",
 			expectedStatus:      http.StatusSeeOther,
+			expectedBroadcasts:  1,
+			expectedMetrics:     []string{"experience written"},
 			expectedLocation:    "/vampires/11111111-1111-1111-1111-111111111111",
 			expectedVampireID:   uuid.MustParse("11111111-1111-1111-1111-111111111111"),
 			expectedMemoryID:    uuid.MustParse("22222222-2222-2222-2222-222222222222"),
@@ -208,7 +248,12 @@ func TestCreateExperience(t *testing.T) {
 			body: url.Values{
 				"description": []string{"A description"},
 			},
-			creator:        &mockExperienceCreator{},
+			renderer: &mockCreateExperienceRenderer{},
+			creator:  &mockExperienceCreator{},
+			getter: &mockMemoryGetter{
+				memory: models.Memory{ID: uuid.MustParse("22222222-2222-2222-2222-222222222222")},
+			},
+			broadcaster:    &mockBroadcaster{},
 			path:           "/vampires/unknown/memories/22222222-2222-2222-2222-222222222222/experiences",
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody:   "500: Internal Server Error",
@@ -218,7 +263,12 @@ func TestCreateExperience(t *testing.T) {
 			body: url.Values{
 				"description": []string{"A description"},
 			},
-			creator:        &mockExperienceCreator{},
+			renderer: &mockCreateExperienceRenderer{},
+			creator:  &mockExperienceCreator{},
+			getter: &mockMemoryGetter{
+				memory: models.Memory{ID: uuid.MustParse("22222222-2222-2222-2222-222222222222")},
+			},
+			broadcaster:    &mockBroadcaster{},
 			path:           "/vampires/11111111-1111-1111-1111-111111111111/memories/unknown/experiences",
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody:   "500: Internal Server Error",
@@ -228,9 +278,14 @@ func TestCreateExperience(t *testing.T) {
 			body: url.Values{
 				"description": []string{"A description"},
 			},
+			renderer: &mockCreateExperienceRenderer{},
 			creator: &mockExperienceCreator{
 				err: models.ErrNotFound,
 			},
+			getter: &mockMemoryGetter{
+				memory: models.Memory{ID: uuid.MustParse("22222222-2222-2222-2222-222222222222")},
+			},
+			broadcaster:         &mockBroadcaster{},
 			path:                "/vampires/11111111-1111-1111-1111-111111111111/memories/22222222-2222-2222-2222-222222222222/experiences",
 			expectedStatus:      http.StatusNotFound,
 			expectedBody:        "404: Not Found",
@@ -243,16 +298,162 @@ func TestCreateExperience(t *testing.T) {
 			body: url.Values{
 				"description": []string{"A description"},
 			},
+			renderer: &mockCreateExperienceRenderer{},
 			creator: &mockExperienceCreator{
 				err: errors.New("mock error"),
 			},
+			getter: &mockMemoryGetter{
+				memory: models.Memory{ID: uuid.MustParse("22222222-2222-2222-2222-222222222222")},
+			},
+			broadcaster:         &mockBroadcaster{},
+			path:                "/vampires/11111111-1111-1111-1111-111111111111/memories/22222222-2222-2222-2222-222222222222/experiences",
+			expectedStatus:      http.StatusInternalServerError,
+			expectedBody:        "500: Internal Server Error",
+			expectedVampireID:   uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+			expectedMemoryID:    uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+			expectedDescription: "A description",
+		},
+		{
+			name: "error from broadcaster",
+			body: url.Values{
+				"description": []string{"A description"},
+			},
+			renderer: &mockCreateExperienceRenderer{},
+			creator:  &mockExperienceCreator{},
+			getter: &mockMemoryGetter{
+				memory: models.Memory{ID: uuid.MustParse("22222222-2222-2222-2222-222222222222")},
+			},
+			broadcaster:         &mockBroadcaster{err: errors.New("mock error")},
+			path:                "/vampires/11111111-1111-1111-1111-111111111111/memories/22222222-2222-2222-2222-222222222222/experiences",
+			expectedStatus:      http.StatusSeeOther,
+			expectedBroadcasts:  1,
+			expectedMetrics:     []string{"experience written"},
+			expectedLocation:    "/vampires/11111111-1111-1111-1111-111111111111",
+			expectedVampireID:   uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+			expectedMemoryID:    uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+			expectedDescription: "A description",
+		},
+		{
+			name: "memory full",
+			body: url.Values{
+				"description": []string{"A description"},
+			},
+			renderer: &mockCreateExperienceRenderer{},
+			creator: &mockExperienceCreator{
+				err: models.ErrMemoryFull,
+			},
+			getter: &mockMemoryGetter{
+				memory: models.Memory{ID: uuid.MustParse("22222222-2222-2222-2222-222222222222")},
+			},
+			broadcaster:         &mockBroadcaster{},
+			path:                "/vampires/11111111-1111-1111-1111-111111111111/memories/22222222-2222-2222-2222-222222222222/experiences",
+			expectedStatus:      http.StatusUnprocessableEntity,
+			expectedBody:        "new 22222222-2222-2222-2222-222222222222",
+			expectedVampireID:   uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+			expectedMemoryID:    uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+			expectedDescription: "A description",
+		},
+		{
+			name: "successful filling memory",
+			body: url.Values{
+				"description": []string{"A description"},
+			},
+			renderer: &mockCreateExperienceRenderer{},
+			creator:  &mockExperienceCreator{},
+			getter: &mockMemoryGetter{
+				memory: models.Memory{
+					ID:          uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+					Experiences: []models.Experience{{}, {}, {}},
+				},
+			},
+			broadcaster:         &mockBroadcaster{},
+			path:                "/vampires/11111111-1111-1111-1111-111111111111/memories/22222222-2222-2222-2222-222222222222/experiences",
+			expectedStatus:      http.StatusSeeOther,
+			expectedBroadcasts:  1,
+			expectedMetrics:     []string{"experience written", "memory filled"},
+			expectedLocation:    "/vampires/11111111-1111-1111-1111-111111111111",
+			expectedVampireID:   uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+			expectedMemoryID:    uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+			expectedDescription: "A description",
+		},
+		{
+			name:   "successful turbo stream",
+			accept: "text/vnd.turbo-stream.html",
+			body: url.Values{
+				"description": []string{"A description"},
+			},
+			renderer: &mockCreateExperienceRenderer{},
+			creator:  &mockExperienceCreator{},
+			getter: &mockMemoryGetter{
+				memory: models.Memory{ID: uuid.MustParse("22222222-2222-2222-2222-222222222222")},
+			},
+			broadcaster:         &mockBroadcaster{},
+			path:                "/vampires/11111111-1111-1111-1111-111111111111/memories/22222222-2222-2222-2222-222222222222/experiences",
+			expectedStatus:      http.StatusOK,
+			expectedBroadcasts:  1,
+			expectedMetrics:     []string{"experience written"},
+			expectedBody:        "created 22222222-2222-2222-2222-222222222222",
+			expectedVampireID:   uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+			expectedMemoryID:    uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+			expectedDescription: "A description",
+		},
+		{
+			name:   "error from renderer",
+			accept: "text/vnd.turbo-stream.html",
+			body: url.Values{
+				"description": []string{"A description"},
+			},
+			renderer: &mockCreateExperienceRenderer{err: errors.New("mock error")},
+			creator:  &mockExperienceCreator{},
+			getter: &mockMemoryGetter{
+				memory: models.Memory{ID: uuid.MustParse("22222222-2222-2222-2222-222222222222")},
+			},
+			broadcaster:         &mockBroadcaster{},
 			path:                "/vampires/11111111-1111-1111-1111-111111111111/memories/22222222-2222-2222-2222-222222222222/experiences",
 			expectedStatus:      http.StatusInternalServerError,
+			expectedBroadcasts:  1,
+			expectedMetrics:     []string{"experience written"},
 			expectedBody:        "500: Internal Server Error",
 			expectedVampireID:   uuid.MustParse("11111111-1111-1111-1111-111111111111"),
 			expectedMemoryID:    uuid.MustParse("22222222-2222-2222-2222-222222222222"),
 			expectedDescription: "A description",
 		},
+		{
+			name:   "error reloading memory",
+			accept: "text/vnd.turbo-stream.html",
+			body: url.Values{
+				"description": []string{"A description"},
+			},
+			renderer: &mockCreateExperienceRenderer{},
+			creator:  &mockExperienceCreator{},
+			getter: &mockMemoryGetter{
+				err: errors.New("mock error"),
+			},
+			broadcaster:         &mockBroadcaster{},
+			path:                "/vampires/11111111-1111-1111-1111-111111111111/memories/22222222-2222-2222-2222-222222222222/experiences",
+			expectedStatus:      http.StatusSeeOther,
+			expectedBroadcasts:  1,
+			expectedMetrics:     []string{"experience written"},
+			expectedLocation:    "/vampires/11111111-1111-1111-1111-111111111111",
+			expectedVampireID:   uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+			expectedMemoryID:    uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+			expectedDescription: "A description",
+		},
+		{
+			name: "invalid form",
+			body: url.Values{
+				"description": []string{""},
+			},
+			renderer: &mockCreateExperienceRenderer{},
+			creator:  &mockExperienceCreator{},
+			getter: &mockMemoryGetter{
+				memory: models.Memory{ID: uuid.MustParse("22222222-2222-2222-2222-222222222222")},
+			},
+			broadcaster:    &mockBroadcaster{},
+			path:           "/vampires/11111111-1111-1111-1111-111111111111/memories/22222222-2222-2222-2222-222222222222/experiences",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   "new 22222222-2222-2222-2222-222222222222 Please provide a description.",
+		},
 	}
 
 	for _, tt := range tests {
@@ -262,10 +463,14 @@ func TestCreateExperience(t *testing.T) {
 			t.Parallel()
 
 			r := chi.NewMux()
+			metrics := &mockMetrics{}
 
-			handlers.CreateExperience(r, testLogger(t), tt.creator)
+			handlers.CreateExperience(r, testLogger(t), tt.renderer, tt.creator, tt.getter, tt.broadcaster, metrics)
 
-			status, headers, body := post(r, tt.path, tt.body.Encode())
+			req := postRequest(tt.path, tt.body.Encode())
+			req.request.Header.Set("Accept", tt.accept)
+
+			status, headers, body := req.perform(r)
 
 			if tt.expectedStatus != status {
 				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
@@ -291,6 +496,14 @@ func TestCreateExperience(t *testing.T) {
 			if tt.expectedDescription != tt.creator.description {
 				t.Errorf("expected %q; got %q", tt.expectedDescription, tt.creator.description)
 			}
+
+			if tt.expectedBroadcasts != tt.broadcaster.count {
+				t.Errorf("expected %d broadcasts; got %d", tt.expectedBroadcasts, tt.broadcaster.count)
+			}
+
+			if diff := cmp.Diff(tt.expectedMetrics, metrics.counted); diff != "" {
+				t.Errorf("unexpected metrics: %s", diff)
+			}
 		})
 	}
 }
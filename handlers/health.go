@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"emailaddress.horse/thousand/health"
+	"emailaddress.horse/thousand/middleware"
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
 )
@@ -14,20 +15,41 @@ type checker interface {
 	Check(context.Context) (health.Result, bool)
 }
 
+// Health serves liveness on /livez, which only shows the process can respond,
+// and readiness on /readyz, which checks every component. /health is kept as
+// an alias of /readyz.
 func Health(r chi.Router, l *zap.Logger, h checker) {
-	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
+	r.Get("/livez", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		writeHealth(w, r, l, health.Result{
+			Details: []health.ComponentResult{},
+			Status:  "ok",
+		})
+	})
+
+	ready := func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
 		result, ok := h.Check(r.Context())
 		if !ok {
-			w.WriteHeader(http.StatusBadGateway)
+			w.WriteHeader(http.StatusServiceUnavailable)
 		}
 
-		enc := json.NewEncoder(w)
-		enc.SetIndent("", "  ")
+		writeHealth(w, r, l, result)
+	}
 
-		err := enc.Encode(result)
-		if err != nil {
-			l.Error("error encoding health response", zap.Error(err))
-			handleError(w, err)
-		}
-	})
+	r.Get("/readyz", ready)
+	r.Get("/health", ready)
+}
+
+func writeHealth(w http.ResponseWriter, r *http.Request, l *zap.Logger, result health.Result) {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	err := enc.Encode(result)
+	if err != nil {
+		l.Error("error encoding health response", zap.Error(err))
+		handleError(w, r, err)
+	}
 }
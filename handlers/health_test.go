@@ -39,6 +39,7 @@ func TestHealth(t *testing.T) {
 						{
 							Name:      "mock component",
 							Status:    "ok",
+							Critical:  true,
 							Timestamp: now,
 						},
 					},
@@ -52,6 +53,7 @@ func TestHealth(t *testing.T) {
     {
       "name": "mock component",
       "status": "ok",
+      "critical": true,
       "timestamp": "2021-12-12T11:17:00Z"
     }
   ],
@@ -66,6 +68,7 @@ func TestHealth(t *testing.T) {
 						{
 							Name:      "mock component",
 							Status:    "failed",
+							Critical:  true,
 							Error:     "mock error",
 							Timestamp: now,
 						},
@@ -74,17 +77,48 @@ func TestHealth(t *testing.T) {
 				},
 				ok: false,
 			},
-			expectedStatus: http.StatusBadGateway,
+			expectedStatus: http.StatusServiceUnavailable,
 			expectedBody: `{
   "details": [
     {
       "name": "mock component",
       "status": "failed",
+      "critical": true,
       "error": "mock error",
       "timestamp": "2021-12-12T11:17:00Z"
     }
   ],
   "status": "failed"
+}`,
+		},
+		{
+			name: "degraded",
+			checker: &mockChecker{
+				result: health.Result{
+					Details: []health.ComponentResult{
+						{
+							Name:      "mock component",
+							Status:    "failed",
+							Error:     "mock error",
+							Timestamp: now,
+						},
+					},
+					Status: "degraded",
+				},
+				ok: true,
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+  "details": [
+    {
+      "name": "mock component",
+      "status": "failed",
+      "critical": false,
+      "error": "mock error",
+      "timestamp": "2021-12-12T11:17:00Z"
+    }
+  ],
+  "status": "degraded"
 }`,
 		},
 	}
@@ -99,15 +133,42 @@ func TestHealth(t *testing.T) {
 
 			handlers.Health(r, testLogger(t), tt.checker)
 
-			status, _, body := get(r, "/health")
+			for _, path := range []string{"/readyz", "/health"} {
+				status, _, body := get(r, path)
 
-			if tt.expectedStatus != status {
-				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
-			}
+				if tt.expectedStatus != status {
+					t.Errorf("expected status %d from %s; got %d", tt.expectedStatus, path, status)
+				}
 
-			if tt.expectedBody != body {
-				t.Errorf("expected body:\n%s\n\ngot:\n%s", tt.expectedBody, body)
+				if tt.expectedBody != body {
+					t.Errorf("expected body from %s:\n%s\n\ngot:\n%s", path, tt.expectedBody, body)
+				}
 			}
 		})
 	}
 }
+
+func TestHealth_Liveness(t *testing.T) {
+	t.Parallel()
+
+	r := chi.NewMux()
+
+	handlers.Health(r, testLogger(t), &mockChecker{
+		result: health.Result{Status: "failed"},
+		ok:     false,
+	})
+
+	status, _, body := get(r, "/livez")
+
+	if http.StatusOK != status {
+		t.Errorf("expected status %d; got %d", http.StatusOK, status)
+	}
+
+	expectedBody := `{
+  "details": [],
+  "status": "ok"
+}`
+	if expectedBody != body {
+		t.Errorf("expected body:\n%s\n\ngot:\n%s", expectedBody, body)
+	}
+}
@@ -0,0 +1,337 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"emailaddress.horse/thousand/audit"
+	"emailaddress.horse/thousand/middleware"
+	"emailaddress.horse/thousand/models"
+	"emailaddress.horse/thousand/openid"
+	"emailaddress.horse/thousand/registry"
+	"emailaddress.horse/thousand/session"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type authCodeURLer interface {
+	AuthCodeURL(context.Context, string, string, string) (string, error)
+}
+
+type authFlowSetter interface {
+	SetAuthFlow(*http.Request, http.ResponseWriter, session.AuthFlow) error
+}
+
+func NewOIDCSession(r chi.Router, l *zap.Logger, p authCodeURLer, s authFlowSetter) {
+	r.Get("/session/oidc", func(w http.ResponseWriter, r *http.Request) {
+		beginAuthFlow(w, r, l, p, s, false)
+	})
+}
+
+// CreateUserIdentity starts linking an identity to the current user. Admins
+// impersonating a user can't change how they log in.
+func CreateUserIdentity(r chi.Router, l *zap.Logger, p authCodeURLer, s authFlowSetter) {
+	r.Post("/user/identities", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		if _, ok := middleware.MaybeImpersonator(r.Context()); ok {
+			l.Error("tried to link identity while impersonating")
+			handleError(w, r, BadRequestError)
+			return
+		}
+
+		beginAuthFlow(w, r, l, p, s, true)
+	})
+}
+
+func beginAuthFlow(w http.ResponseWriter, r *http.Request, l *zap.Logger, p authCodeURLer, s authFlowSetter, linking bool) {
+	flow, err := session.NewAuthFlow(linking)
+	if err != nil {
+		l.Error("failed to generate auth flow", zap.Error(err))
+		handleError(w, r, err)
+		return
+	}
+
+	url, err := p.AuthCodeURL(r.Context(), flow.State, flow.Nonce, flow.CodeVerifier)
+	if err != nil {
+		l.Error("failed to build auth code URL", zap.Error(err))
+		handleError(w, r, err)
+		return
+	}
+
+	if err := s.SetAuthFlow(r, w, flow); err != nil {
+		l.Error("failed to set auth flow in session", zap.Error(err))
+		handleError(w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, url, http.StatusSeeOther)
+}
+
+type identityExchanger interface {
+	Name() string
+	Exchange(context.Context, string, string, string) (openid.Identity, error)
+}
+
+type identityUserStore interface {
+	GetUser(context.Context, uuid.UUID) (models.User, error)
+	GetUserByIdentity(context.Context, string, string) (models.User, error)
+	CreateUserFromIdentity(context.Context, models.UserIdentity) (models.User, error)
+	LinkUserIdentity(context.Context, uuid.UUID, models.UserIdentity) (models.UserIdentity, error)
+}
+
+type authFlowSession interface {
+	PopAuthFlow(*http.Request, http.ResponseWriter) (session.AuthFlow, error)
+	GetCurrentUserID(*http.Request) (uuid.UUID, bool)
+	GetImpersonatorID(*http.Request) (uuid.UUID, bool)
+	ClearCurrentUserID(http.ResponseWriter, *http.Request) error
+	sessionSetter
+}
+
+type identityMetrics interface {
+	loginMetrics
+	signUpMetrics
+}
+
+func CreateOIDCSession(r chi.Router, l *zap.Logger, e identityExchanger, us identityUserStore, s authFlowSession, m identityMetrics, ar auditRecorder) {
+	r.Get("/session/oidc/callback", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		flow, err := s.PopAuthFlow(r, w)
+		if err != nil {
+			l.Error("failed to load auth flow from session", zap.Error(err))
+			handleError(w, r, BadRequestError.Cause(err))
+			return
+		}
+
+		query := r.URL.Query()
+
+		if query.Get("state") != flow.State {
+			l.Error("auth flow state does not match")
+			handleError(w, r, BadRequestError)
+			return
+		}
+
+		failurePath := "/session/new"
+		if flow.Linking {
+			failurePath = "/user"
+		}
+
+		if providerErr := query.Get("error"); providerErr != "" {
+			if !flow.Linking {
+				m.Login(registry.AuthOIDC, false)
+			}
+
+			l.Info("identity provider returned error", zap.String("error", providerErr), zap.String("description", query.Get("error_description")))
+			redirectWithFlash(w, r, l, s, failurePath, "Could not log in with "+e.Name()+".")
+			return
+		}
+
+		identity, err := e.Exchange(r.Context(), query.Get("code"), flow.CodeVerifier, flow.Nonce)
+		if err != nil {
+			if !flow.Linking {
+				m.Login(registry.AuthOIDC, false)
+			}
+
+			l.Error("failed to exchange code for identity", zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		userIdentity := models.UserIdentity{
+			Issuer:  identity.Issuer,
+			Subject: identity.Subject,
+			Email:   identity.Email,
+		}
+
+		if flow.Linking {
+			userID, ok := s.GetCurrentUserID(r)
+			if !ok {
+				http.Redirect(w, r, "/session/new", http.StatusSeeOther)
+				return
+			}
+
+			// Impersonation may have started since the link did
+			if _, ok := s.GetImpersonatorID(r); ok {
+				l.Error("tried to link identity while impersonating", zap.Stringer("userID", userID))
+				handleError(w, r, BadRequestError)
+				return
+			}
+
+			// The callback isn't behind EnsureLoggedIn, so the user linking the
+			// identity is only known from the session, and may have been disabled
+			// since the link started
+			user, err := us.GetUser(r.Context(), userID)
+			if err != nil {
+				l.Error("failed to get user", zap.Stringer("userID", userID), zap.Error(err))
+				handleError(w, r, err)
+				return
+			}
+
+			if user.Disabled() {
+				l.Info("disabled user tried to link identity", zap.Stringer("userID", userID))
+				if err := s.ClearCurrentUserID(w, r); err != nil {
+					l.Error("failed to clear user id in session", zap.Error(err))
+					handleError(w, r, err)
+					return
+				}
+
+				redirectWithFlash(w, r, l, s, "/session/new", "This account has been disabled.")
+				return
+			}
+
+			ctx := audit.WithUser(r.Context(), user.ID, user.Email)
+
+			_, err = us.LinkUserIdentity(ctx, userID, userIdentity)
+			if errors.Is(err, models.ErrIdentityAlreadyLinked) {
+				redirectWithFlash(w, r, l, s, "/user", "This "+e.Name()+" account is already linked to a user.")
+				return
+			} else if err != nil {
+				l.Error("failed to link identity", zap.Stringer("userID", userID), zap.Error(err))
+				handleError(w, r, err)
+				return
+			}
+
+			redirectWithFlash(w, r, l, s, "/user", "Linked your "+e.Name()+" account.")
+			return
+		}
+
+		// Logging in with an identity which isn't linked to a user signs up
+		user, err := us.GetUserByIdentity(r.Context(), identity.Issuer, identity.Subject)
+		flash := "Welcome back!"
+		record := m.Login
+		action := models.AuditActionLogin
+		if errors.Is(err, models.ErrNotFound) {
+			record = m.SignUp
+			action = models.AuditActionSignUp
+
+			if identity.Email == "" || !identity.EmailVerified {
+				record(registry.AuthOIDC, false)
+				redirectWithFlash(w, r, l, s, failurePath, "Your "+e.Name()+" account does not have a verified email address.")
+				return
+			}
+
+			user, err = us.CreateUserFromIdentity(r.Context(), userIdentity)
+			if errors.Is(err, models.ErrEmailAlreadyInUse) {
+				record(registry.AuthOIDC, false)
+				redirectWithFlash(w, r, l, s, failurePath, "An account already uses this email address. Log in with your password, then link "+e.Name()+" from your account settings.")
+				return
+			}
+			flash = "Thank you for signing up!"
+		}
+		if err != nil {
+			record(registry.AuthOIDC, false)
+
+			l.Error("failed to find user for identity", zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		if user.Disabled() {
+			record(registry.AuthOIDC, false)
+			recordAuditEvent(r, l, ar, models.AuditEvent{
+				ActorEmail: user.Email,
+				Action:     models.AuditActionLoginFailed,
+			})
+			redirectWithFlash(w, r, l, s, failurePath, "This account has been disabled.")
+			return
+		}
+
+		record(registry.AuthOIDC, true)
+		recordAuditEvent(r, l, ar, userAuditEvent(action, user))
+
+		if err := s.SetCurrentUserID(r, w, user.ID); err != nil {
+			l.Error("failed to set user id in session", zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		redirectWithFlash(w, r, l, s, "/", flash)
+	})
+}
+
+type flashSetter interface {
+	SetFlash(*http.Request, http.ResponseWriter, string) error
+}
+
+func redirectWithFlash(w http.ResponseWriter, r *http.Request, l *zap.Logger, s flashSetter, path, msg string) {
+	if err := s.SetFlash(r, w, msg); err != nil {
+		l.Error("failed to set flash", zap.Error(err))
+		handleError(w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, path, http.StatusSeeOther)
+}
+
+type showUserRenderer interface {
+	ShowUser(http.ResponseWriter, *http.Request, []models.UserIdentity) error
+}
+
+type userIdentitiesGetter interface {
+	GetUserIdentities(context.Context, uuid.UUID) ([]models.UserIdentity, error)
+}
+
+func ShowUser(r chi.Router, l *zap.Logger, t showUserRenderer, ig userIdentitiesGetter) {
+	r.Get("/user", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		user := middleware.CurrentUser(r.Context())
+
+		identities, err := ig.GetUserIdentities(r.Context(), user.ID)
+		if err != nil {
+			l.Error("failed to load identities", zap.Stringer("userID", user.ID), zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		err = t.ShowUser(w, r, identities)
+		if err != nil {
+			l.Error("failed to render", zap.Error(err))
+			handleError(w, r, err)
+		}
+	})
+}
+
+type userIdentityUnlinker interface {
+	UnlinkUserIdentity(context.Context, uuid.UUID, uuid.UUID) error
+}
+
+func DestroyUserIdentity(r chi.Router, l *zap.Logger, iu userIdentityUnlinker, s flashSetter) {
+	r.Delete("/user/identities/{id}", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		user := middleware.CurrentUser(r.Context())
+
+		if _, ok := middleware.MaybeImpersonator(r.Context()); ok {
+			l.Error("tried to unlink identity while impersonating", zap.Stringer("userID", user.ID))
+			handleError(w, r, BadRequestError)
+			return
+		}
+
+		id, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			l.Error("failed to parse id as UUID", zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		err = iu.UnlinkUserIdentity(r.Context(), user.ID, id)
+		if errors.Is(err, models.ErrLastLoginMethod) {
+			redirectWithFlash(w, r, l, s, "/user", "You cannot unlink your only way of logging in.")
+			return
+		} else if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				err = NotFoundError.Cause(err)
+			}
+
+			l.Error("failed to unlink identity", zap.Stringer("userID", user.ID), zap.Stringer("id", id), zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		redirectWithFlash(w, r, l, s, "/user", "Unlinked account.")
+	})
+}
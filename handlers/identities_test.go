@@ -0,0 +1,804 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"emailaddress.horse/thousand/handlers"
+	"emailaddress.horse/thousand/middleware"
+	"emailaddress.horse/thousand/models"
+	"emailaddress.horse/thousand/openid"
+	"emailaddress.horse/thousand/openid/openidtest"
+	"emailaddress.horse/thousand/session"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+type mockAuthCodeURLer struct {
+	state string
+	err   error
+}
+
+func (m *mockAuthCodeURLer) AuthCodeURL(_ context.Context, state, _, _ string) (string, error) {
+	m.state = state
+	return "https://provider.test/authorize", m.err
+}
+
+type mockAuthFlowSession struct {
+	flow           *session.AuthFlow
+	userID         uuid.UUID
+	impersonatorID uuid.UUID
+	message        string
+	err            error
+}
+
+func (m *mockAuthFlowSession) SetAuthFlow(_ *http.Request, _ http.ResponseWriter, flow session.AuthFlow) error {
+	m.flow = &flow
+	return m.err
+}
+
+func (m *mockAuthFlowSession) PopAuthFlow(_ *http.Request, _ http.ResponseWriter) (session.AuthFlow, error) {
+	if m.flow == nil {
+		return session.AuthFlow{}, session.ErrAuthFlowMissing
+	}
+
+	flow := *m.flow
+	m.flow = nil
+	return flow, nil
+}
+
+func (m *mockAuthFlowSession) GetCurrentUserID(_ *http.Request) (uuid.UUID, bool) {
+	return m.userID, m.userID != uuid.UUID{}
+}
+
+func (m *mockAuthFlowSession) GetImpersonatorID(_ *http.Request) (uuid.UUID, bool) {
+	return m.impersonatorID, m.impersonatorID != uuid.UUID{}
+}
+
+func (m *mockAuthFlowSession) SetCurrentUserID(_ *http.Request, _ http.ResponseWriter, id uuid.UUID) error {
+	m.userID = id
+	return m.err
+}
+
+func (m *mockAuthFlowSession) ClearCurrentUserID(_ http.ResponseWriter, _ *http.Request) error {
+	m.userID = uuid.UUID{}
+	return m.err
+}
+
+func (m *mockAuthFlowSession) SetFlash(_ *http.Request, _ http.ResponseWriter, message string) error {
+	m.message = message
+	return m.err
+}
+
+func TestNewOIDCSession(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		urler            *mockAuthCodeURLer
+		session          *mockAuthFlowSession
+		expectedStatus   int
+		expectedBody     string
+		expectedLocation string
+	}{
+		{
+			name:             "successful",
+			urler:            &mockAuthCodeURLer{},
+			session:          &mockAuthFlowSession{},
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "https://provider.test/authorize",
+		},
+		{
+			name: "error from provider",
+			urler: &mockAuthCodeURLer{
+				err: errors.New("mock error"),
+			},
+			session:        &mockAuthFlowSession{},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+		{
+			name:  "error from session",
+			urler: &mockAuthCodeURLer{},
+			session: &mockAuthFlowSession{
+				err: errors.New("mock error"),
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+
+			handlers.NewOIDCSession(r, testLogger(t), tt.urler, tt.session)
+
+			status, headers, body := get(r, "/session/oidc")
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if tt.expectedLocation == "" && tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+
+			location := headers.Get("Location")
+			if tt.expectedLocation != location {
+				t.Errorf("expected location %q; got %q", tt.expectedLocation, location)
+			}
+
+			if tt.expectedStatus == http.StatusSeeOther {
+				if tt.session.flow == nil || tt.session.flow.State != tt.urler.state {
+					t.Errorf("expected auth flow with state %q in session; got %+v", tt.urler.state, tt.session.flow)
+				}
+
+				if tt.session.flow.Linking {
+					t.Error("expected auth flow not to be linking")
+				}
+			}
+		})
+	}
+}
+
+func TestCreateUserIdentity(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		impersonating    bool
+		expectedStatus   int
+		expectedBody     string
+		expectedLocation string
+	}{
+		{
+			name:             "successful",
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "https://provider.test/authorize",
+		},
+		{
+			name:           "impersonating",
+			impersonating:  true,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "400: Bad Request",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+			s := &mockAuthFlowSession{}
+
+			handlers.CreateUserIdentity(r, testLogger(t), &mockAuthCodeURLer{}, s)
+
+			req := postRequest("/user/identities", "")
+			req.request = middleware.RequestWithCurrentUser(req.request, models.User{ID: uuid.MustParse("11111111-1111-1111-1111-111111111111")})
+			if tt.impersonating {
+				req.request = middleware.RequestWithImpersonator(req.request, models.User{ID: uuid.MustParse("22222222-2222-2222-2222-222222222222"), IsAdmin: true})
+			}
+
+			status, headers, body := req.perform(r)
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if tt.expectedLocation == "" && tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+
+			location := headers.Get("Location")
+			if tt.expectedLocation != location {
+				t.Errorf("expected location %q; got %q", tt.expectedLocation, location)
+			}
+
+			if tt.expectedStatus == http.StatusSeeOther {
+				if s.flow == nil || !s.flow.Linking {
+					t.Errorf("expected linking auth flow in session; got %+v", s.flow)
+				}
+			} else if s.flow != nil {
+				t.Errorf("expected no auth flow in session; got %+v", s.flow)
+			}
+		})
+	}
+}
+
+type mockIdentityUserStore struct {
+	user        models.User
+	current     models.User
+	getErr      error
+	findErr     error
+	createErr   error
+	linkErr     error
+	created     *models.UserIdentity
+	linked      *models.UserIdentity
+	linkedTo    uuid.UUID
+	findSubject string
+}
+
+func (m *mockIdentityUserStore) GetUser(_ context.Context, id uuid.UUID) (models.User, error) {
+	user := m.current
+	user.ID = id
+	return user, m.getErr
+}
+
+func (m *mockIdentityUserStore) GetUserByIdentity(_ context.Context, _, subject string) (models.User, error) {
+	m.findSubject = subject
+	return m.user, m.findErr
+}
+
+func (m *mockIdentityUserStore) CreateUserFromIdentity(_ context.Context, identity models.UserIdentity) (models.User, error) {
+	m.created = &identity
+	return m.user, m.createErr
+}
+
+func (m *mockIdentityUserStore) LinkUserIdentity(_ context.Context, userID uuid.UUID, identity models.UserIdentity) (models.UserIdentity, error) {
+	m.linkedTo = userID
+	m.linked = &identity
+	return identity, m.linkErr
+}
+
+func TestCreateOIDCSession(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	verifiedIdentity := openidtest.Identity{
+		Subject:       "12345",
+		Email:         "john@bannister.com",
+		EmailVerified: true,
+	}
+
+	tests := []struct {
+		name             string
+		identity         openidtest.Identity
+		linking          bool
+		currentUserID    uuid.UUID
+		impersonatorID   uuid.UUID
+		query            func(url.Values)
+		store            *mockIdentityUserStore
+		noFlow           bool
+		expectedStatus   int
+		expectedBody     string
+		expectedLocation string
+		expectedFlash    string
+		expectedUserID   uuid.UUID
+		expectCreated    bool
+		expectLinked     bool
+		expectedMetrics  []string
+		expectedAudit    []string
+	}{
+		{
+			name:     "successful log in",
+			identity: verifiedIdentity,
+			store: &mockIdentityUserStore{
+				user: models.User{ID: userID},
+			},
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "/",
+			expectedFlash:    "Welcome back!",
+			expectedUserID:   userID,
+			expectedMetrics:  []string{"login oidc success"},
+			expectedAudit:    []string{"session.login"},
+		},
+		{
+			name:     "log in disabled",
+			identity: verifiedIdentity,
+			store: &mockIdentityUserStore{
+				user: models.User{ID: userID, Email: "john@bannister.com", DisabledAt: time.Now()},
+			},
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "/session/new",
+			expectedFlash:    "This account has been disabled.",
+			expectedMetrics:  []string{"login oidc failure"},
+			expectedAudit:    []string{"session.login_failed john@bannister.com"},
+		},
+		{
+			name:     "successful sign up",
+			identity: verifiedIdentity,
+			store: &mockIdentityUserStore{
+				user:    models.User{ID: userID},
+				findErr: models.ErrNotFound,
+			},
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "/",
+			expectedFlash:    "Thank you for signing up!",
+			expectedUserID:   userID,
+			expectCreated:    true,
+			expectedMetrics:  []string{"sign up oidc success"},
+			expectedAudit:    []string{"user.sign_up"},
+		},
+		{
+			name: "sign up without verified email",
+			identity: openidtest.Identity{
+				Subject: "12345",
+				Email:   "john@bannister.com",
+			},
+			store: &mockIdentityUserStore{
+				findErr: models.ErrNotFound,
+			},
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "/session/new",
+			expectedFlash:    "Your OpenID Connect account does not have a verified email address.",
+			expectedMetrics:  []string{"sign up oidc failure"},
+		},
+		{
+			name:     "sign up with email already in use",
+			identity: verifiedIdentity,
+			store: &mockIdentityUserStore{
+				findErr:   models.ErrNotFound,
+				createErr: models.ErrEmailAlreadyInUse,
+			},
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "/session/new",
+			expectedFlash:    "An account already uses this email address. Log in with your password, then link OpenID Connect from your account settings.",
+			expectCreated:    true,
+			expectedMetrics:  []string{"sign up oidc failure"},
+		},
+		{
+			name:     "error finding user",
+			identity: verifiedIdentity,
+			store: &mockIdentityUserStore{
+				findErr: errors.New("mock error"),
+			},
+			expectedStatus:  http.StatusInternalServerError,
+			expectedBody:    "500: Internal Server Error",
+			expectedMetrics: []string{"login oidc failure"},
+		},
+		{
+			name:             "successful link",
+			identity:         verifiedIdentity,
+			linking:          true,
+			currentUserID:    userID,
+			store:            &mockIdentityUserStore{},
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "/user",
+			expectedFlash:    "Linked your OpenID Connect account.",
+			expectedUserID:   userID,
+			expectLinked:     true,
+		},
+		{
+			name:          "link already linked identity",
+			identity:      verifiedIdentity,
+			linking:       true,
+			currentUserID: userID,
+			store: &mockIdentityUserStore{
+				linkErr: models.ErrIdentityAlreadyLinked,
+			},
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "/user",
+			expectedFlash:    "This OpenID Connect account is already linked to a user.",
+			expectedUserID:   userID,
+			expectLinked:     true,
+		},
+		{
+			name:           "link while impersonating",
+			identity:       verifiedIdentity,
+			linking:        true,
+			currentUserID:  userID,
+			impersonatorID: uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+			store:          &mockIdentityUserStore{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "400: Bad Request",
+			expectedUserID: userID,
+		},
+		{
+			name:          "link by disabled user",
+			identity:      verifiedIdentity,
+			linking:       true,
+			currentUserID: userID,
+			store: &mockIdentityUserStore{
+				current: models.User{DisabledAt: time.Now()},
+			},
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "/session/new",
+			expectedFlash:    "This account has been disabled.",
+		},
+		{
+			name:          "error getting linking user",
+			identity:      verifiedIdentity,
+			linking:       true,
+			currentUserID: userID,
+			store: &mockIdentityUserStore{
+				getErr: errors.New("mock error"),
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+			expectedUserID: userID,
+		},
+		{
+			name:             "link without current user",
+			identity:         verifiedIdentity,
+			linking:          true,
+			store:            &mockIdentityUserStore{},
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "/session/new",
+		},
+		{
+			name:     "error from identity provider",
+			identity: verifiedIdentity,
+			query: func(q url.Values) {
+				q.Del("code")
+				q.Set("error", "access_denied")
+			},
+			store:            &mockIdentityUserStore{},
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "/session/new",
+			expectedFlash:    "Could not log in with OpenID Connect.",
+			expectedMetrics:  []string{"login oidc failure"},
+		},
+		{
+			name:     "state mismatch",
+			identity: verifiedIdentity,
+			query: func(q url.Values) {
+				q.Set("state", "another state")
+			},
+			store:          &mockIdentityUserStore{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "400: Bad Request",
+		},
+		{
+			name:           "no auth flow in session",
+			identity:       verifiedIdentity,
+			noFlow:         true,
+			store:          &mockIdentityUserStore{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "400: Bad Request",
+		},
+		{
+			name:     "invalid code",
+			identity: verifiedIdentity,
+			query: func(q url.Values) {
+				q.Set("code", "invalid")
+			},
+			store:           &mockIdentityUserStore{},
+			expectedStatus:  http.StatusInternalServerError,
+			expectedBody:    "500: Internal Server Error",
+			expectedMetrics: []string{"login oidc failure"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := openidtest.NewServer()
+			t.Cleanup(server.Close)
+
+			provider := openid.New(openid.Options{
+				Issuer:       server.URL,
+				ClientID:     openidtest.ClientID,
+				ClientSecret: openidtest.ClientSecret,
+				RedirectURL:  "http://thousand.test/session/oidc/callback",
+			})
+
+			flow, err := session.NewAuthFlow(tt.linking)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			authURL, err := provider.AuthCodeURL(context.Background(), flow.State, flow.Nonce, flow.CodeVerifier)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			redirect, err := server.Authorize(authURL, tt.identity)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			redirectURL, err := url.Parse(redirect)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			query := redirectURL.Query()
+			if tt.query != nil {
+				tt.query(query)
+			}
+
+			s := &mockAuthFlowSession{userID: tt.currentUserID, impersonatorID: tt.impersonatorID}
+			if !tt.noFlow {
+				s.flow = &flow
+			}
+
+			r := chi.NewMux()
+			metrics := &mockMetrics{}
+			recorder := &mockAuditRecorder{}
+
+			handlers.CreateOIDCSession(r, testLogger(t), provider, tt.store, s, metrics, recorder)
+
+			status, headers, body := get(r, "/session/oidc/callback?"+query.Encode())
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if tt.expectedLocation == "" && tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+
+			location := headers.Get("Location")
+			if tt.expectedLocation != location {
+				t.Errorf("expected location %q; got %q", tt.expectedLocation, location)
+			}
+
+			if tt.expectedFlash != s.message {
+				t.Errorf("expected flash %q; got %q", tt.expectedFlash, s.message)
+			}
+
+			if tt.expectedUserID != s.userID {
+				t.Errorf("expected current user %q; got %q", tt.expectedUserID, s.userID)
+			}
+
+			if diff := cmp.Diff(tt.expectedMetrics, metrics.counted); diff != "" {
+				t.Errorf("unexpected metrics: %s", diff)
+			}
+
+			if diff := cmp.Diff(tt.expectedAudit, recorder.recorded); diff != "" {
+				t.Errorf("unexpected audit events: %s", diff)
+			}
+
+			expectedIdentity := models.UserIdentity{
+				Issuer:  server.URL,
+				Subject: tt.identity.Subject,
+				Email:   tt.identity.Email,
+			}
+
+			if tt.expectCreated {
+				if diff := cmp.Diff(&expectedIdentity, tt.store.created); diff != "" {
+					t.Error(diff)
+				}
+			} else if tt.store.created != nil {
+				t.Errorf("expected no user to be created; got %+v", tt.store.created)
+			}
+
+			if tt.expectLinked {
+				if diff := cmp.Diff(&expectedIdentity, tt.store.linked); diff != "" {
+					t.Error(diff)
+				}
+
+				if tt.currentUserID != tt.store.linkedTo {
+					t.Errorf("expected identity to be linked to %q; got %q", tt.currentUserID, tt.store.linkedTo)
+				}
+			} else if tt.store.linked != nil {
+				t.Errorf("expected no identity to be linked; got %+v", tt.store.linked)
+			}
+		})
+	}
+}
+
+type mockShowUserRenderer struct {
+	err error
+}
+
+func (m *mockShowUserRenderer) ShowUser(w http.ResponseWriter, _ *http.Request, identities []models.UserIdentity) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(identities); err != nil {
+		panic(err)
+	}
+
+	return nil
+}
+
+type mockUserIdentitiesGetter struct {
+	userID     uuid.UUID
+	identities []models.UserIdentity
+	err        error
+}
+
+func (m *mockUserIdentitiesGetter) GetUserIdentities(_ context.Context, userID uuid.UUID) ([]models.UserIdentity, error) {
+	m.userID = userID
+	return m.identities, m.err
+}
+
+func TestShowUser(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+
+	tests := []struct {
+		name           string
+		renderer       *mockShowUserRenderer
+		getter         *mockUserIdentitiesGetter
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:     "successful",
+			renderer: &mockShowUserRenderer{},
+			getter: &mockUserIdentitiesGetter{
+				identities: []models.UserIdentity{
+					{Email: "john@bannister.com"},
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"ID":"00000000-0000-0000-0000-000000000000","UserID":"00000000-0000-0000-0000-000000000000","Issuer":"","Subject":"","Email":"john@bannister.com"}]`,
+		},
+		{
+			name:     "error from getter",
+			renderer: &mockShowUserRenderer{},
+			getter: &mockUserIdentitiesGetter{
+				err: errors.New("mock error"),
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+		{
+			name: "error from renderer",
+			renderer: &mockShowUserRenderer{
+				err: errors.New("mock error"),
+			},
+			getter:         &mockUserIdentitiesGetter{},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+
+			handlers.ShowUser(r, testLogger(t), tt.renderer, tt.getter)
+
+			req := newRequest(http.MethodGet, "/user")
+			req.request = middleware.RequestWithCurrentUser(req.request, models.User{ID: userID})
+
+			status, _, body := req.perform(r)
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+
+			if userID != tt.getter.userID {
+				t.Errorf("expected %q; got %q", userID, tt.getter.userID)
+			}
+		})
+	}
+}
+
+type mockUserIdentityUnlinker struct {
+	userID uuid.UUID
+	id     uuid.UUID
+	err    error
+}
+
+func (m *mockUserIdentityUnlinker) UnlinkUserIdentity(_ context.Context, userID, id uuid.UUID) error {
+	m.userID = userID
+	m.id = id
+	return m.err
+}
+
+func TestDestroyUserIdentity(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+
+	tests := []struct {
+		name             string
+		unlinker         *mockUserIdentityUnlinker
+		path             string
+		impersonating    bool
+		expectedStatus   int
+		expectedBody     string
+		expectedLocation string
+		expectedFlash    string
+		expectedID       uuid.UUID
+	}{
+		{
+			name:             "successful",
+			unlinker:         &mockUserIdentityUnlinker{},
+			path:             "/user/identities/22222222-2222-2222-2222-222222222222",
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "/user",
+			expectedFlash:    "Unlinked account.",
+			expectedID:       uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+		},
+		{
+			name: "last login method",
+			unlinker: &mockUserIdentityUnlinker{
+				err: models.ErrLastLoginMethod,
+			},
+			path:             "/user/identities/22222222-2222-2222-2222-222222222222",
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "/user",
+			expectedFlash:    "You cannot unlink your only way of logging in.",
+			expectedID:       uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+		},
+		{
+			name: "not found from unlinker",
+			unlinker: &mockUserIdentityUnlinker{
+				err: models.ErrNotFound,
+			},
+			path:           "/user/identities/22222222-2222-2222-2222-222222222222",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404: Not Found",
+			expectedID:     uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+		},
+		{
+			name:           "impersonating",
+			unlinker:       &mockUserIdentityUnlinker{},
+			path:           "/user/identities/22222222-2222-2222-2222-222222222222",
+			impersonating:  true,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "400: Bad Request",
+		},
+		{
+			name:           "error parsing id",
+			unlinker:       &mockUserIdentityUnlinker{},
+			path:           "/user/identities/unknown",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+			s := &mockFlashSetter{}
+
+			handlers.DestroyUserIdentity(r, testLogger(t), tt.unlinker, s)
+
+			req := newRequest(http.MethodDelete, tt.path)
+			req.request = middleware.RequestWithCurrentUser(req.request, models.User{ID: userID})
+			if tt.impersonating {
+				req.request = middleware.RequestWithImpersonator(req.request, models.User{ID: uuid.MustParse("33333333-3333-3333-3333-333333333333"), IsAdmin: true})
+			}
+
+			status, headers, body := req.perform(r)
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if tt.expectedLocation == "" && tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+
+			location := headers.Get("Location")
+			if tt.expectedLocation != location {
+				t.Errorf("expected location %q; got %q", tt.expectedLocation, location)
+			}
+
+			if tt.expectedFlash != s.message {
+				t.Errorf("expected flash %q; got %q", tt.expectedFlash, s.message)
+			}
+
+			if tt.expectedID != tt.unlinker.id {
+				t.Errorf("expected %q; got %q", tt.expectedID, tt.unlinker.id)
+			}
+		})
+	}
+}
@@ -7,38 +7,76 @@ import (
 	"github.com/google/uuid"
 )
 
+type characterBroadcaster interface {
+	CharacterCreated(context.Context, uuid.UUID, models.Character) error
+}
+
 type characterCreator interface {
 	CreateCharacter(context.Context, uuid.UUID, models.CreateCharacterParams) (models.Character, error)
 }
 
+type experienceBroadcaster interface {
+	ExperienceCreated(context.Context, uuid.UUID, models.Experience) error
+}
+
 type experienceCreator interface {
 	CreateExperience(context.Context, uuid.UUID, uuid.UUID, string) (models.Experience, error)
 }
 
+type markBroadcaster interface {
+	MarkCreated(context.Context, uuid.UUID, models.Mark) error
+}
+
 type markCreator interface {
 	CreateMark(context.Context, uuid.UUID, string) (models.Mark, error)
 }
 
+type experienceMetrics interface {
+	ExperienceWritten()
+	MemoryFilled()
+}
+
+type loginMetrics interface {
+	Login(string, bool)
+}
+
 type memoryGetter interface {
 	GetMemory(context.Context, uuid.UUID, uuid.UUID) (models.Memory, error)
 }
 
+type resourceBroadcaster interface {
+	ResourceCreated(context.Context, uuid.UUID, models.Resource) error
+}
+
 type resourceCreator interface {
 	CreateResource(context.Context, uuid.UUID, models.CreateResourceParams) (models.Resource, error)
 }
 
+type skillBroadcaster interface {
+	SkillCreated(context.Context, uuid.UUID, models.Skill) error
+}
+
 type skillCreator interface {
 	CreateSkill(context.Context, uuid.UUID, string) (models.Skill, error)
 }
 
+type signUpMetrics interface {
+	SignUp(string, bool)
+}
+
 type vampireCreator interface {
 	CreateVampire(context.Context, uuid.UUID, string) (models.Vampire, error)
 }
 
+type vampireMetrics interface {
+	VampireCreated()
+}
+
 type vampireGetter interface {
 	GetVampire(context.Context, uuid.UUID) (models.Vampire, error)
 }
 
 type vampiresGetter interface {
-	GetVampires(context.Context) ([]models.Vampire, error)
+	GetVampires(context.Context, uuid.UUID, models.VampireQuery) (models.VampirePage, error)
+	GetSharedVampires(context.Context, uuid.UUID, models.VampireQuery) (models.VampirePage, error)
 }
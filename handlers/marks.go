@@ -4,6 +4,8 @@ import (
 	"errors"
 	"net/http"
 
+	"emailaddress.horse/thousand/form"
+	"emailaddress.horse/thousand/middleware"
 	"emailaddress.horse/thousand/models"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -11,15 +13,17 @@ import (
 )
 
 type newMarkRenderer interface {
-	NewMark(http.ResponseWriter, *http.Request, models.Vampire) error
+	NewMark(http.ResponseWriter, *http.Request, models.Vampire, *form.NewMarkForm) error
 }
 
 func NewMark(r chi.Router, l *zap.Logger, t newMarkRenderer, vg vampireGetter) {
 	r.Get("/vampires/{vampireID}/marks/new", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
 		vampireID, err := uuid.Parse(chi.URLParam(r, "vampireID"))
 		if err != nil {
 			l.Error("failed to parse id as UUID", zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 			return
 		}
 
@@ -30,37 +34,79 @@ func NewMark(r chi.Router, l *zap.Logger, t newMarkRenderer, vg vampireGetter) {
 			}
 
 			l.Error("failed to find vampire", zap.Stringer("vampireID", vampireID), zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 			return
 		}
 
-		err = t.NewMark(w, r, vampire)
+		err = t.NewMark(w, r, vampire, form.NewMark(""))
 		if err != nil {
 			l.Error("failed to render", zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 		}
 	})
 }
 
-func CreateMark(r chi.Router, l *zap.Logger, cm markCreator) {
+type createMarkRenderer interface {
+	NewMark(http.ResponseWriter, *http.Request, models.Vampire, *form.NewMarkForm) error
+	CreateMark(http.ResponseWriter, *http.Request, uuid.UUID, models.Mark) error
+}
+
+func CreateMark(r chi.Router, l *zap.Logger, t createMarkRenderer, vg vampireGetter, cm markCreator, b markBroadcaster) {
 	r.Post("/vampires/{vampireID}/marks", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
 		vampireID, err := uuid.Parse(chi.URLParam(r, "vampireID"))
 		if err != nil {
 			l.Error("failed to parse id as UUID", zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 			return
 		}
 
-		description := r.FormValue("description")
+		form := form.NewMark(r.FormValue("description"))
+		if !form.Valid() {
+			vampire, err := vg.GetVampire(r.Context(), vampireID)
+			if err != nil {
+				if errors.Is(err, models.ErrNotFound) {
+					err = NotFoundError.Cause(err)
+				}
 
-		_, err = cm.CreateMark(r.Context(), vampireID, description)
+				l.Error("failed to find vampire", zap.Stringer("vampireID", vampireID), zap.Error(err))
+				handleError(w, r, err)
+				return
+			}
+
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			err = t.NewMark(w, r, vampire, form)
+			if err != nil {
+				l.Error("failed to render", zap.Error(err))
+				handleError(w, r, err)
+			}
+			return
+		}
+
+		description := form.Description.Value
+
+		mark, err := cm.CreateMark(r.Context(), vampireID, description)
 		if err != nil {
 			if errors.Is(err, models.ErrNotFound) {
 				err = NotFoundError.Cause(err)
 			}
 
 			l.Error("failed to create mark", zap.Stringer("vampireID", vampireID), zap.String("description", description), zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
+			return
+		}
+
+		if err := b.MarkCreated(r.Context(), vampireID, mark); err != nil {
+			l.Error("failed to broadcast mark", zap.Stringer("vampireID", vampireID), zap.Error(err))
+		}
+
+		if acceptsTurboStream(r) {
+			err = t.CreateMark(w, r, vampireID, mark)
+			if err != nil {
+				l.Error("failed to render", zap.Error(err))
+				handleError(w, r, err)
+			}
 			return
 		}
 
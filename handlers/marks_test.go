@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"testing"
 
+	"emailaddress.horse/thousand/form"
 	"emailaddress.horse/thousand/handlers"
 	"emailaddress.horse/thousand/models"
 	"github.com/go-chi/chi/v5"
@@ -17,7 +18,7 @@ type mockNewMarkRenderer struct {
 	err error
 }
 
-func (m *mockNewMarkRenderer) NewMark(w http.ResponseWriter, _ *http.Request, vampire models.Vampire) error {
+func (m *mockNewMarkRenderer) NewMark(w http.ResponseWriter, _ *http.Request, vampire models.Vampire, _ *form.NewMarkForm) error {
 	if m.err != nil {
 		return m.err
 	}
@@ -140,15 +141,46 @@ func (m *mockMarkCreator) CreateMark(_ context.Context, vampireID uuid.UUID, des
 	return models.Mark{}, m.err
 }
 
+type mockCreateMarkRenderer struct {
+	err error
+}
+
+func (m *mockCreateMarkRenderer) NewMark(w http.ResponseWriter, _ *http.Request, _ models.Vampire, f *form.NewMarkForm) error {
+	_, err := w.Write([]byte(f.Description.Message))
+	if err != nil {
+		panic(err)
+	}
+
+	return nil
+}
+
+func (m *mockCreateMarkRenderer) CreateMark(w http.ResponseWriter, _ *http.Request, vampireID uuid.UUID, _ models.Mark) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	_, err := w.Write([]byte(vampireID.String()))
+	if err != nil {
+		panic(err)
+	}
+
+	return nil
+}
+
 func TestCreateMark(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
 		name                string
 		body                url.Values
+		accept              string
+		renderer            *mockCreateMarkRenderer
+		getter              *mockVampireGetter
 		creator             *mockMarkCreator
+		broadcaster         *mockBroadcaster
 		path                string
 		expectedStatus      int
+		expectedBroadcasts  int
 		expectedBody        string
 		expectedLocation    string
 		expectedVampireID   uuid.UUID
@@ -159,9 +191,13 @@ func TestCreateMark(t *testing.T) {
 			body: url.Values{
 				"description": []string{"a description"},
 			},
+			renderer:            &mockCreateMarkRenderer{},
+			getter:              &mockVampireGetter{},
 			creator:             &mockMarkCreator{},
+			broadcaster:         &mockBroadcaster{},
 			path:                "/vampires/12345678-90ab-cdef-1234-567890abcdef/marks",
 			expectedStatus:      http.StatusSeeOther,
+			expectedBroadcasts:  1,
 			expectedLocation:    "/vampires/12345678-90ab-cdef-1234-567890abcdef",
 			expectedVampireID:   uuid.MustParse("12345678-90ab-cdef-1234-567890abcdef"),
 			expectedDescription: "a description",
@@ -171,7 +207,10 @@ func TestCreateMark(t *testing.T) {
 			body: url.Values{
 				"description": []string{"a description"},
 			},
+			renderer:       &mockCreateMarkRenderer{},
+			getter:         &mockVampireGetter{},
 			creator:        &mockMarkCreator{},
+			broadcaster:    &mockBroadcaster{},
 			path:           "/vampires/unknown/marks",
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody:   "500: Internal Server Error",
@@ -181,9 +220,12 @@ func TestCreateMark(t *testing.T) {
 			body: url.Values{
 				"description": []string{"a description"},
 			},
+			renderer: &mockCreateMarkRenderer{},
+			getter:   &mockVampireGetter{},
 			creator: &mockMarkCreator{
 				err: models.ErrNotFound,
 			},
+			broadcaster:         &mockBroadcaster{},
 			path:                "/vampires/12345678-90ab-cdef-1234-567890abcdef/marks",
 			expectedStatus:      http.StatusNotFound,
 			expectedBody:        "404: Not Found",
@@ -195,15 +237,94 @@ func TestCreateMark(t *testing.T) {
 			body: url.Values{
 				"description": []string{"a description"},
 			},
+			renderer: &mockCreateMarkRenderer{},
+			getter:   &mockVampireGetter{},
 			creator: &mockMarkCreator{
 				err: errors.New("mock error"),
 			},
+			broadcaster:         &mockBroadcaster{},
 			path:                "/vampires/12345678-90ab-cdef-1234-567890abcdef/marks",
 			expectedStatus:      http.StatusInternalServerError,
 			expectedBody:        "500: Internal Server Error",
 			expectedVampireID:   uuid.MustParse("12345678-90ab-cdef-1234-567890abcdef"),
 			expectedDescription: "a description",
 		},
+		{
+			name: "error from broadcaster",
+			body: url.Values{
+				"description": []string{"a description"},
+			},
+			renderer:            &mockCreateMarkRenderer{},
+			getter:              &mockVampireGetter{},
+			creator:             &mockMarkCreator{},
+			broadcaster:         &mockBroadcaster{err: errors.New("mock error")},
+			path:                "/vampires/12345678-90ab-cdef-1234-567890abcdef/marks",
+			expectedStatus:      http.StatusSeeOther,
+			expectedBroadcasts:  1,
+			expectedLocation:    "/vampires/12345678-90ab-cdef-1234-567890abcdef",
+			expectedVampireID:   uuid.MustParse("12345678-90ab-cdef-1234-567890abcdef"),
+			expectedDescription: "a description",
+		},
+		{
+			name:   "successful turbo stream",
+			accept: "text/vnd.turbo-stream.html",
+			body: url.Values{
+				"description": []string{"a description"},
+			},
+			renderer:            &mockCreateMarkRenderer{},
+			getter:              &mockVampireGetter{},
+			creator:             &mockMarkCreator{},
+			broadcaster:         &mockBroadcaster{},
+			path:                "/vampires/12345678-90ab-cdef-1234-567890abcdef/marks",
+			expectedStatus:      http.StatusOK,
+			expectedBroadcasts:  1,
+			expectedBody:        "12345678-90ab-cdef-1234-567890abcdef",
+			expectedVampireID:   uuid.MustParse("12345678-90ab-cdef-1234-567890abcdef"),
+			expectedDescription: "a description",
+		},
+		{
+			name:   "error from renderer",
+			accept: "text/vnd.turbo-stream.html",
+			body: url.Values{
+				"description": []string{"a description"},
+			},
+			renderer:            &mockCreateMarkRenderer{err: errors.New("mock error")},
+			getter:              &mockVampireGetter{},
+			creator:             &mockMarkCreator{},
+			broadcaster:         &mockBroadcaster{},
+			path:                "/vampires/12345678-90ab-cdef-1234-567890abcdef/marks",
+			expectedStatus:      http.StatusInternalServerError,
+			expectedBroadcasts:  1,
+			expectedBody:        "500: Internal Server Error",
+			expectedVampireID:   uuid.MustParse("12345678-90ab-cdef-1234-567890abcdef"),
+			expectedDescription: "a description",
+		},
+		{
+			name: "invalid form",
+			body: url.Values{
+				"description": []string{""},
+			},
+			renderer:       &mockCreateMarkRenderer{},
+			getter:         &mockVampireGetter{},
+			creator:        &mockMarkCreator{},
+			broadcaster:    &mockBroadcaster{},
+			path:           "/vampires/12345678-90ab-cdef-1234-567890abcdef/marks",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   "Please provide a description.",
+		},
+		{
+			name: "not found from getter with invalid form",
+			body: url.Values{
+				"description": []string{""},
+			},
+			renderer:       &mockCreateMarkRenderer{},
+			getter:         &mockVampireGetter{err: models.ErrNotFound},
+			creator:        &mockMarkCreator{},
+			broadcaster:    &mockBroadcaster{},
+			path:           "/vampires/12345678-90ab-cdef-1234-567890abcdef/marks",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404: Not Found",
+		},
 	}
 
 	for _, tt := range tests {
@@ -214,9 +335,12 @@ func TestCreateMark(t *testing.T) {
 
 			r := chi.NewMux()
 
-			handlers.CreateMark(r, testLogger(t), tt.creator)
+			handlers.CreateMark(r, testLogger(t), tt.renderer, tt.getter, tt.creator, tt.broadcaster)
+
+			req := postRequest(tt.path, tt.body.Encode())
+			req.request.Header.Set("Accept", tt.accept)
 
-			status, headers, body := post(r, tt.path, tt.body.Encode())
+			status, headers, body := req.perform(r)
 
 			if tt.expectedStatus != status {
 				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
@@ -238,6 +362,10 @@ func TestCreateMark(t *testing.T) {
 			if tt.expectedLocation != location {
 				t.Errorf("expected location %q; got %q", tt.expectedLocation, location)
 			}
+
+			if tt.expectedBroadcasts != tt.broadcaster.count {
+				t.Errorf("expected %d broadcasts; got %d", tt.expectedBroadcasts, tt.broadcaster.count)
+			}
 		})
 	}
 }
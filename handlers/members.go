@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"emailaddress.horse/thousand/middleware"
+	"emailaddress.horse/thousand/models"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type showMembersRenderer interface {
+	ShowMembers(http.ResponseWriter, *http.Request, models.Vampire, []models.Member, []models.Invitation) error
+}
+
+type membersGetter interface {
+	GetMembers(context.Context, uuid.UUID) ([]models.Member, error)
+	GetInvitations(context.Context, uuid.UUID) ([]models.Invitation, error)
+}
+
+func ListMembers(r chi.Router, l *zap.Logger, t showMembersRenderer, vg vampireGetter, mg membersGetter) {
+	r.Get("/vampires/{vampireID}/members", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		vampireID, err := uuid.Parse(chi.URLParam(r, "vampireID"))
+		if err != nil {
+			l.Error("failed to parse vampire id as UUID", zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		vampire, err := vg.GetVampire(r.Context(), vampireID)
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				err = NotFoundError.Cause(err)
+			}
+
+			l.Error("failed to find vampire", zap.Stringer("vampireID", vampireID), zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		members, err := mg.GetMembers(r.Context(), vampireID)
+		if err != nil {
+			l.Error("failed to load members", zap.Stringer("vampireID", vampireID), zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		invitations, err := mg.GetInvitations(r.Context(), vampireID)
+		if err != nil {
+			l.Error("failed to load invitations", zap.Stringer("vampireID", vampireID), zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		err = t.ShowMembers(w, r, vampire, members, invitations)
+		if err != nil {
+			l.Error("failed to render", zap.Error(err))
+			handleError(w, r, err)
+		}
+	})
+}
+
+type memberInviter interface {
+	InviteMember(context.Context, uuid.UUID, uuid.UUID, string, models.MemberRole) (models.Invitation, error)
+}
+
+func CreateInvitation(r chi.Router, l *zap.Logger, mi memberInviter, s flashSetter) {
+	r.Post("/vampires/{vampireID}/invitations", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		user := middleware.CurrentUser(r.Context())
+
+		vampireID, err := uuid.Parse(chi.URLParam(r, "vampireID"))
+		if err != nil {
+			l.Error("failed to parse vampire id as UUID", zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		membersPath := "/vampires/" + vampireID.String() + "/members"
+
+		email := r.FormValue("email")
+		if email == "" {
+			redirectWithFlash(w, r, l, s, membersPath, "Enter an email address to invite.")
+			return
+		}
+
+		role, err := models.ParseMemberRole(r.FormValue("role"))
+		if err != nil {
+			l.Error("failed to parse role", zap.String("role", r.FormValue("role")), zap.Error(err))
+			handleError(w, r, BadRequestError.Cause(err))
+			return
+		}
+
+		_, err = mi.InviteMember(r.Context(), vampireID, user.ID, email, role)
+		if errors.Is(err, models.ErrAlreadyInvited) {
+			redirectWithFlash(w, r, l, s, membersPath, email+" has already been invited.")
+			return
+		} else if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				err = NotFoundError.Cause(err)
+			}
+
+			l.Error("failed to invite member", zap.Stringer("vampireID", vampireID), zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		redirectWithFlash(w, r, l, s, membersPath, "Invited "+email+".")
+	})
+}
+
+type invitationRevoker interface {
+	RevokeInvitation(context.Context, uuid.UUID, uuid.UUID) error
+}
+
+func DestroyInvitation(r chi.Router, l *zap.Logger, ir invitationRevoker) {
+	r.Delete("/vampires/{vampireID}/invitations/{id}", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		vampireID, err := uuid.Parse(chi.URLParam(r, "vampireID"))
+		if err != nil {
+			l.Error("failed to parse vampire id as UUID", zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		id, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			l.Error("failed to parse id as UUID", zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		err = ir.RevokeInvitation(r.Context(), vampireID, id)
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				err = NotFoundError.Cause(err)
+			}
+
+			l.Error("failed to revoke invitation", zap.Stringer("vampireID", vampireID), zap.Stringer("id", id), zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		http.Redirect(w, r, "/vampires/"+vampireID.String()+"/members", http.StatusSeeOther)
+	})
+}
+
+type memberRemover interface {
+	RemoveMember(context.Context, uuid.UUID, uuid.UUID) (models.Member, error)
+}
+
+type memberBroadcaster interface {
+	MemberRemoved(context.Context, uuid.UUID, uuid.UUID) error
+}
+
+func DestroyMember(r chi.Router, l *zap.Logger, mr memberRemover, b memberBroadcaster) {
+	r.Delete("/vampires/{vampireID}/members/{id}", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		vampireID, err := uuid.Parse(chi.URLParam(r, "vampireID"))
+		if err != nil {
+			l.Error("failed to parse vampire id as UUID", zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		id, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			l.Error("failed to parse id as UUID", zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		member, err := mr.RemoveMember(r.Context(), vampireID, id)
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				err = NotFoundError.Cause(err)
+			}
+
+			l.Error("failed to remove member", zap.Stringer("vampireID", vampireID), zap.Stringer("id", id), zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		if err := b.MemberRemoved(r.Context(), vampireID, member.UserID); err != nil {
+			l.Error("failed to broadcast removed member", zap.Stringer("vampireID", vampireID), zap.Stringer("id", id), zap.Error(err))
+		}
+
+		http.Redirect(w, r, "/vampires/"+vampireID.String()+"/members", http.StatusSeeOther)
+	})
+}
+
+type invitationAccepter interface {
+	AcceptInvitation(context.Context, models.User, uuid.UUID) (models.Invitation, error)
+}
+
+func AcceptInvitation(r chi.Router, l *zap.Logger, ia invitationAccepter, s flashSetter) {
+	r.Post("/invitations/{id}/accept", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		user := middleware.CurrentUser(r.Context())
+
+		id, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			l.Error("failed to parse id as UUID", zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		invitation, err := ia.AcceptInvitation(r.Context(), user, id)
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				err = NotFoundError.Cause(err)
+			}
+
+			l.Error("failed to accept invitation", zap.Stringer("id", id), zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		redirectWithFlash(w, r, l, s, "/vampires/"+invitation.VampireID.String(), "Invitation accepted.")
+	})
+}
+
+type invitationDecliner interface {
+	DeclineInvitation(context.Context, string, uuid.UUID) error
+}
+
+func DeclineInvitation(r chi.Router, l *zap.Logger, d invitationDecliner) {
+	r.Delete("/invitations/{id}", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		user := middleware.CurrentUser(r.Context())
+
+		id, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			l.Error("failed to parse id as UUID", zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		err = d.DeclineInvitation(r.Context(), user.Email, id)
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				err = NotFoundError.Cause(err)
+			}
+
+			l.Error("failed to decline invitation", zap.Stringer("id", id), zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		http.Redirect(w, r, "/vampires", http.StatusSeeOther)
+	})
+}
@@ -0,0 +1,631 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"emailaddress.horse/thousand/handlers"
+	"emailaddress.horse/thousand/middleware"
+	"emailaddress.horse/thousand/models"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type mockShowMembersRenderer struct {
+	err error
+}
+
+func (m *mockShowMembersRenderer) ShowMembers(w http.ResponseWriter, _ *http.Request, vampire models.Vampire, members []models.Member, invitations []models.Invitation) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	emails := []string{vampire.Name}
+	for _, member := range members {
+		emails = append(emails, member.Email)
+	}
+	for _, invitation := range invitations {
+		emails = append(emails, invitation.Email)
+	}
+
+	_, err := w.Write([]byte(strings.Join(emails, ", ")))
+	if err != nil {
+		panic(err)
+	}
+
+	return nil
+}
+
+type mockMembersGetter struct {
+	members        []models.Member
+	invitations    []models.Invitation
+	err            error
+	invitationsErr error
+	vampireID      uuid.UUID
+}
+
+func (m *mockMembersGetter) GetMembers(_ context.Context, vampireID uuid.UUID) ([]models.Member, error) {
+	m.vampireID = vampireID
+	return m.members, m.err
+}
+
+func (m *mockMembersGetter) GetInvitations(_ context.Context, vampireID uuid.UUID) ([]models.Invitation, error) {
+	return m.invitations, m.invitationsErr
+}
+
+func TestListMembers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		renderer          *mockShowMembersRenderer
+		vampireGetter     *mockVampireGetter
+		membersGetter     *mockMembersGetter
+		path              string
+		expectedStatus    int
+		expectedBody      string
+		expectedVampireID uuid.UUID
+	}{
+		{
+			name:     "successful",
+			renderer: &mockShowMembersRenderer{},
+			vampireGetter: &mockVampireGetter{
+				vampire: models.Vampire{Name: "a vampire"},
+			},
+			membersGetter: &mockMembersGetter{
+				members: []models.Member{
+					{Email: "owner@example.com"},
+				},
+				invitations: []models.Invitation{
+					{Email: "invited@example.com"},
+				},
+			},
+			path:              "/vampires/11111111-1111-1111-1111-111111111111/members",
+			expectedStatus:    http.StatusOK,
+			expectedBody:      "a vampire, owner@example.com, invited@example.com",
+			expectedVampireID: uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		},
+		{
+			name:           "error parsing vampire id",
+			renderer:       &mockShowMembersRenderer{},
+			vampireGetter:  &mockVampireGetter{},
+			membersGetter:  &mockMembersGetter{},
+			path:           "/vampires/unknown/members",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+		{
+			name:     "not found from vampire getter",
+			renderer: &mockShowMembersRenderer{},
+			vampireGetter: &mockVampireGetter{
+				err: models.ErrNotFound,
+			},
+			membersGetter:  &mockMembersGetter{},
+			path:           "/vampires/11111111-1111-1111-1111-111111111111/members",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404: Not Found",
+		},
+		{
+			name:          "error from members getter",
+			renderer:      &mockShowMembersRenderer{},
+			vampireGetter: &mockVampireGetter{},
+			membersGetter: &mockMembersGetter{
+				err: errors.New("mock error"),
+			},
+			path:              "/vampires/11111111-1111-1111-1111-111111111111/members",
+			expectedStatus:    http.StatusInternalServerError,
+			expectedBody:      "500: Internal Server Error",
+			expectedVampireID: uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		},
+		{
+			name:          "error from invitations getter",
+			renderer:      &mockShowMembersRenderer{},
+			vampireGetter: &mockVampireGetter{},
+			membersGetter: &mockMembersGetter{
+				invitationsErr: errors.New("mock error"),
+			},
+			path:              "/vampires/11111111-1111-1111-1111-111111111111/members",
+			expectedStatus:    http.StatusInternalServerError,
+			expectedBody:      "500: Internal Server Error",
+			expectedVampireID: uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		},
+		{
+			name: "error from renderer",
+			renderer: &mockShowMembersRenderer{
+				err: errors.New("mock error"),
+			},
+			vampireGetter:     &mockVampireGetter{},
+			membersGetter:     &mockMembersGetter{},
+			path:              "/vampires/11111111-1111-1111-1111-111111111111/members",
+			expectedStatus:    http.StatusInternalServerError,
+			expectedBody:      "500: Internal Server Error",
+			expectedVampireID: uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+
+			handlers.ListMembers(r, testLogger(t), tt.renderer, tt.vampireGetter, tt.membersGetter)
+
+			status, _, body := get(r, tt.path)
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+
+			if tt.expectedVampireID != tt.membersGetter.vampireID {
+				t.Errorf("expected %q; got %q", tt.expectedVampireID, tt.membersGetter.vampireID)
+			}
+		})
+	}
+}
+
+type mockMemberInviter struct {
+	vampireID uuid.UUID
+	invitedBy uuid.UUID
+	email     string
+	role      models.MemberRole
+	err       error
+}
+
+func (m *mockMemberInviter) InviteMember(_ context.Context, vampireID, invitedBy uuid.UUID, email string, role models.MemberRole) (models.Invitation, error) {
+	m.vampireID = vampireID
+	m.invitedBy = invitedBy
+	m.email = email
+	m.role = role
+	return models.Invitation{}, m.err
+}
+
+func TestCreateInvitation(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+
+	tests := []struct {
+		name             string
+		body             url.Values
+		inviter          *mockMemberInviter
+		path             string
+		expectedStatus   int
+		expectedBody     string
+		expectedLocation string
+		expectedFlash    string
+		expectedEmail    string
+		expectedRole     models.MemberRole
+	}{
+		{
+			name: "successful",
+			body: url.Values{
+				"email": []string{"jane@bannister.com"},
+				"role":  []string{"editor"},
+			},
+			inviter:          &mockMemberInviter{},
+			path:             "/vampires/22222222-2222-2222-2222-222222222222/invitations",
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "/vampires/22222222-2222-2222-2222-222222222222/members",
+			expectedFlash:    "Invited jane@bannister.com.",
+			expectedEmail:    "jane@bannister.com",
+			expectedRole:     models.RoleEditor,
+		},
+		{
+			name: "already invited",
+			body: url.Values{
+				"email": []string{"jane@bannister.com"},
+				"role":  []string{"viewer"},
+			},
+			inviter: &mockMemberInviter{
+				err: models.ErrAlreadyInvited,
+			},
+			path:             "/vampires/22222222-2222-2222-2222-222222222222/invitations",
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "/vampires/22222222-2222-2222-2222-222222222222/members",
+			expectedFlash:    "jane@bannister.com has already been invited.",
+			expectedEmail:    "jane@bannister.com",
+			expectedRole:     models.RoleViewer,
+		},
+		{
+			name: "missing email",
+			body: url.Values{
+				"role": []string{"viewer"},
+			},
+			inviter:          &mockMemberInviter{},
+			path:             "/vampires/22222222-2222-2222-2222-222222222222/invitations",
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "/vampires/22222222-2222-2222-2222-222222222222/members",
+			expectedFlash:    "Enter an email address to invite.",
+		},
+		{
+			name: "owner role",
+			body: url.Values{
+				"email": []string{"jane@bannister.com"},
+				"role":  []string{"owner"},
+			},
+			inviter:        &mockMemberInviter{},
+			path:           "/vampires/22222222-2222-2222-2222-222222222222/invitations",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "400: Bad Request",
+		},
+		{
+			name: "error from inviter",
+			body: url.Values{
+				"email": []string{"jane@bannister.com"},
+				"role":  []string{"editor"},
+			},
+			inviter: &mockMemberInviter{
+				err: errors.New("mock error"),
+			},
+			path:           "/vampires/22222222-2222-2222-2222-222222222222/invitations",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+			expectedEmail:  "jane@bannister.com",
+			expectedRole:   models.RoleEditor,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+			s := &mockFlashSetter{}
+
+			handlers.CreateInvitation(r, testLogger(t), tt.inviter, s)
+
+			req := postRequest(tt.path, tt.body.Encode())
+			req.request = middleware.RequestWithCurrentUser(req.request, models.User{ID: userID})
+
+			status, headers, body := req.perform(r)
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if tt.expectedLocation == "" && tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+
+			location := headers.Get("Location")
+			if tt.expectedLocation != location {
+				t.Errorf("expected location %q; got %q", tt.expectedLocation, location)
+			}
+
+			if tt.expectedFlash != s.message {
+				t.Errorf("expected flash %q; got %q", tt.expectedFlash, s.message)
+			}
+
+			if tt.expectedEmail != tt.inviter.email {
+				t.Errorf("expected inviter to receive email %q; got %q", tt.expectedEmail, tt.inviter.email)
+			}
+
+			if tt.expectedRole != tt.inviter.role {
+				t.Errorf("expected inviter to receive role %q; got %q", tt.expectedRole, tt.inviter.role)
+			}
+
+			if tt.expectedEmail != "" && userID != tt.inviter.invitedBy {
+				t.Errorf("expected inviter to receive invited by %q; got %q", userID, tt.inviter.invitedBy)
+			}
+		})
+	}
+}
+
+type mockMemberRemover struct {
+	vampireID uuid.UUID
+	id        uuid.UUID
+	err       error
+}
+
+func (m *mockMemberRemover) RemoveMember(_ context.Context, vampireID, id uuid.UUID) (models.Member, error) {
+	m.vampireID = vampireID
+	m.id = id
+	return models.Member{ID: id, VampireID: vampireID, UserID: uuid.MustParse("33333333-3333-3333-3333-333333333333")}, m.err
+}
+
+func TestDestroyMember(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name               string
+		remover            *mockMemberRemover
+		broadcaster        *mockBroadcaster
+		path               string
+		expectedStatus     int
+		expectedBody       string
+		expectedLocation   string
+		expectedID         uuid.UUID
+		expectedBroadcasts int
+	}{
+		{
+			name:               "successful",
+			remover:            &mockMemberRemover{},
+			broadcaster:        &mockBroadcaster{},
+			path:               "/vampires/11111111-1111-1111-1111-111111111111/members/22222222-2222-2222-2222-222222222222",
+			expectedStatus:     http.StatusSeeOther,
+			expectedLocation:   "/vampires/11111111-1111-1111-1111-111111111111/members",
+			expectedID:         uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+			expectedBroadcasts: 1,
+		},
+		{
+			name:               "error from broadcaster",
+			remover:            &mockMemberRemover{},
+			broadcaster:        &mockBroadcaster{err: errors.New("mock error")},
+			path:               "/vampires/11111111-1111-1111-1111-111111111111/members/22222222-2222-2222-2222-222222222222",
+			expectedStatus:     http.StatusSeeOther,
+			expectedLocation:   "/vampires/11111111-1111-1111-1111-111111111111/members",
+			expectedID:         uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+			expectedBroadcasts: 1,
+		},
+		{
+			name: "not found from remover",
+			remover: &mockMemberRemover{
+				err: models.ErrNotFound,
+			},
+			broadcaster:    &mockBroadcaster{},
+			path:           "/vampires/11111111-1111-1111-1111-111111111111/members/22222222-2222-2222-2222-222222222222",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404: Not Found",
+			expectedID:     uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+		},
+		{
+			name:           "error parsing id",
+			remover:        &mockMemberRemover{},
+			broadcaster:    &mockBroadcaster{},
+			path:           "/vampires/11111111-1111-1111-1111-111111111111/members/unknown",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+
+			handlers.DestroyMember(r, testLogger(t), tt.remover, tt.broadcaster)
+
+			status, headers, body := deleteRequest(r, tt.path)
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if tt.expectedLocation == "" && tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+
+			location := headers.Get("Location")
+			if tt.expectedLocation != location {
+				t.Errorf("expected location %q; got %q", tt.expectedLocation, location)
+			}
+
+			if tt.expectedID != tt.remover.id {
+				t.Errorf("expected %q; got %q", tt.expectedID, tt.remover.id)
+			}
+
+			if tt.expectedBroadcasts != tt.broadcaster.count {
+				t.Errorf("expected %d broadcasts; got %d", tt.expectedBroadcasts, tt.broadcaster.count)
+			}
+
+			// The removed member's streams are closed, rather than the owner's
+			expectedUserID := uuid.UUID{}
+			if tt.expectedBroadcasts > 0 {
+				expectedUserID = uuid.MustParse("33333333-3333-3333-3333-333333333333")
+			}
+			if expectedUserID != tt.broadcaster.userID {
+				t.Errorf("expected broadcast for user %q; got %q", expectedUserID, tt.broadcaster.userID)
+			}
+		})
+	}
+}
+
+type mockInvitationAccepter struct {
+	invitation models.Invitation
+	err        error
+	user       models.User
+	id         uuid.UUID
+}
+
+func (m *mockInvitationAccepter) AcceptInvitation(_ context.Context, user models.User, id uuid.UUID) (models.Invitation, error) {
+	m.user = user
+	m.id = id
+	return m.invitation, m.err
+}
+
+func TestAcceptInvitation(t *testing.T) {
+	t.Parallel()
+
+	user := models.User{
+		ID:    uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		Email: "jane@bannister.com",
+	}
+
+	tests := []struct {
+		name             string
+		accepter         *mockInvitationAccepter
+		path             string
+		expectedStatus   int
+		expectedBody     string
+		expectedLocation string
+		expectedFlash    string
+		expectedID       uuid.UUID
+	}{
+		{
+			name: "successful",
+			accepter: &mockInvitationAccepter{
+				invitation: models.Invitation{
+					VampireID: uuid.MustParse("33333333-3333-3333-3333-333333333333"),
+				},
+			},
+			path:             "/invitations/22222222-2222-2222-2222-222222222222/accept",
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "/vampires/33333333-3333-3333-3333-333333333333",
+			expectedFlash:    "Invitation accepted.",
+			expectedID:       uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+		},
+		{
+			name: "not found from accepter",
+			accepter: &mockInvitationAccepter{
+				err: models.ErrNotFound,
+			},
+			path:           "/invitations/22222222-2222-2222-2222-222222222222/accept",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404: Not Found",
+			expectedID:     uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+		},
+		{
+			name:           "error parsing id",
+			accepter:       &mockInvitationAccepter{},
+			path:           "/invitations/unknown/accept",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+			s := &mockFlashSetter{}
+
+			handlers.AcceptInvitation(r, testLogger(t), tt.accepter, s)
+
+			req := postRequest(tt.path, "")
+			req.request = middleware.RequestWithCurrentUser(req.request, user)
+
+			status, headers, body := req.perform(r)
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if tt.expectedLocation == "" && tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+
+			location := headers.Get("Location")
+			if tt.expectedLocation != location {
+				t.Errorf("expected location %q; got %q", tt.expectedLocation, location)
+			}
+
+			if tt.expectedFlash != s.message {
+				t.Errorf("expected flash %q; got %q", tt.expectedFlash, s.message)
+			}
+
+			if tt.expectedID != tt.accepter.id {
+				t.Errorf("expected %q; got %q", tt.expectedID, tt.accepter.id)
+			}
+		})
+	}
+}
+
+type mockInvitationDecliner struct {
+	email string
+	id    uuid.UUID
+	err   error
+}
+
+func (m *mockInvitationDecliner) DeclineInvitation(_ context.Context, email string, id uuid.UUID) error {
+	m.email = email
+	m.id = id
+	return m.err
+}
+
+func TestDeclineInvitation(t *testing.T) {
+	t.Parallel()
+
+	user := models.User{
+		ID:    uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		Email: "jane@bannister.com",
+	}
+
+	tests := []struct {
+		name             string
+		decliner         *mockInvitationDecliner
+		path             string
+		expectedStatus   int
+		expectedBody     string
+		expectedLocation string
+		expectedEmail    string
+	}{
+		{
+			name:             "successful",
+			decliner:         &mockInvitationDecliner{},
+			path:             "/invitations/22222222-2222-2222-2222-222222222222",
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "/vampires",
+			expectedEmail:    "jane@bannister.com",
+		},
+		{
+			name: "not found from decliner",
+			decliner: &mockInvitationDecliner{
+				err: models.ErrNotFound,
+			},
+			path:           "/invitations/22222222-2222-2222-2222-222222222222",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404: Not Found",
+			expectedEmail:  "jane@bannister.com",
+		},
+		{
+			name:           "error parsing id",
+			decliner:       &mockInvitationDecliner{},
+			path:           "/invitations/unknown",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+
+			handlers.DeclineInvitation(r, testLogger(t), tt.decliner)
+
+			req := newRequest(http.MethodDelete, tt.path)
+			req.request = middleware.RequestWithCurrentUser(req.request, user)
+
+			status, headers, body := req.perform(r)
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if tt.expectedLocation == "" && tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+
+			location := headers.Get("Location")
+			if tt.expectedLocation != location {
+				t.Errorf("expected location %q; got %q", tt.expectedLocation, location)
+			}
+
+			if tt.expectedEmail != tt.decliner.email {
+				t.Errorf("expected email %q; got %q", tt.expectedEmail, tt.decliner.email)
+			}
+		})
+	}
+}
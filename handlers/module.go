@@ -3,9 +3,13 @@ package handlers
 import (
 	"emailaddress.horse/thousand/health"
 	"emailaddress.horse/thousand/middleware"
+	"emailaddress.horse/thousand/models"
+	"emailaddress.horse/thousand/openid"
+	"emailaddress.horse/thousand/registry"
 	"emailaddress.horse/thousand/repository"
 	"emailaddress.horse/thousand/session"
 	"emailaddress.horse/thousand/static"
+	"emailaddress.horse/thousand/streams"
 	"emailaddress.horse/thousand/templates"
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/fx"
@@ -19,48 +23,128 @@ var Module = fx.Options(
 type RegisterParams struct {
 	fx.In
 
-	Health     *health.Health
-	Logger     *zap.Logger
-	Renderer   *templates.Renderer
-	Repository *repository.Repository
-	Router     chi.Router
-	Store      *session.Store
+	Broadcaster *streams.Broadcaster
+	Game        *registry.Game
+	Health      *health.Health
+	Hub         *streams.Hub
+	Logger      *zap.Logger
+	Provider    *openid.Provider `optional:"true"`
+	Renderer    *templates.Renderer
+	Repository  *repository.Repository
+	Router      chi.Router
+	Store       *session.Store
 }
 
 func fxRegister(p RegisterParams) {
+	middleware.RenderErrors(p.Router, p.Renderer)
+	NotFound(p.Router)
+	MethodNotAllowed(p.Router)
+
 	Assets(p.Router, static.Assets)
 	Health(p.Router, p.Logger, p.Health)
 
 	Root(p.Router)
 
 	NewSession(p.Router, p.Logger, p.Renderer)
-	CreateSession(p.Router, p.Logger, p.Repository, p.Renderer, p.Store)
-	DestroySession(p.Router, p.Logger, p.Store)
+	CreateSession(p.Router, p.Logger, p.Repository, p.Renderer, p.Store, p.Game, p.Repository)
+	DestroySession(p.Router, p.Logger, p.Store, p.Repository)
 
 	NewUser(p.Router, p.Logger, p.Renderer)
-	CreateUser(p.Router, p.Logger, p.Repository, p.Renderer, p.Store)
+	CreateUser(p.Router, p.Logger, p.Repository, p.Renderer, p.Store, p.Game, p.Repository)
+
+	if p.Provider != nil {
+		NewOIDCSession(p.Router, p.Logger, p.Provider, p.Store)
+		CreateOIDCSession(p.Router, p.Logger, p.Provider, p.Repository, p.Store, p.Game, p.Repository)
+	}
 
 	p.Router.Group(func(r chi.Router) {
-		middleware.EnsureLoggedIn(r, p.Store, p.Repository)
+		middleware.NoIndex(r)
 
-		NewCharacter(r, p.Logger, p.Renderer, p.Repository)
-		CreateCharacter(r, p.Logger, p.Repository)
+		ShowSharedVampire(r, p.Logger, p.Renderer, p.Repository)
 
-		NewExperience(r, p.Logger, p.Renderer, p.Repository)
-		CreateExperience(r, p.Logger, p.Repository)
+		NewPasswordReset(r, p.Logger, p.Renderer, p.Repository)
+		UsePasswordReset(r, p.Logger, p.Renderer, p.Repository, p.Store)
+	})
 
-		NewMark(r, p.Logger, p.Renderer, p.Repository)
-		CreateMark(r, p.Logger, p.Repository)
+	p.Router.Group(func(r chi.Router) {
+		middleware.EnsureLoggedIn(r, p.Store, p.Repository)
 
-		NewResource(r, p.Logger, p.Renderer, p.Repository)
-		CreateResource(r, p.Logger, p.Repository)
+		ShowUser(r, p.Logger, p.Renderer, p.Repository)
+		ShowSecurityActivity(r, p.Logger, p.Renderer, p.Repository)
+		DestroyUserIdentity(r, p.Logger, p.Repository, p.Store)
+		if p.Provider != nil {
+			CreateUserIdentity(r, p.Logger, p.Provider, p.Store)
+		}
 
-		NewSkill(r, p.Logger, p.Renderer, p.Repository)
-		CreateSkill(r, p.Logger, p.Repository)
+		AcceptInvitation(r, p.Logger, p.Repository, p.Store)
+		DeclineInvitation(r, p.Logger, p.Repository)
 
-		ListVampires(r, p.Logger, p.Renderer, p.Repository)
+		ListVampires(r, p.Logger, p.Renderer, p.Repository, p.Repository)
 		NewVampire(r, p.Logger, p.Renderer)
-		CreateVampire(r, p.Logger, p.Repository)
-		ShowVampire(r, p.Logger, p.Renderer, p.Repository)
+		CreateVampire(r, p.Logger, p.Repository, p.Game)
+
+		ShowTrash(r, p.Logger, p.Renderer, p.Repository)
+		RestoreVampire(r, p.Logger, p.Repository)
+
+		ShowArchive(r, p.Logger, p.Renderer, p.Repository)
+
+		DestroyImpersonation(r, p.Logger, p.Store, p.Repository)
+
+		r.Group(func(r chi.Router) {
+			middleware.RequireAdmin(r)
+
+			ShowAdmin(r, p.Logger, p.Renderer, p.Repository)
+			ListAdminUsers(r, p.Logger, p.Renderer, p.Repository)
+			ShowAdminUser(r, p.Logger, p.Renderer, p.Repository)
+			ListAdminVampires(r, p.Logger, p.Renderer, p.Repository)
+			DisableUser(r, p.Logger, p.Repository, p.Store)
+			EnableUser(r, p.Logger, p.Repository, p.Store)
+			CreatePasswordReset(r, p.Logger, p.Renderer, p.Repository)
+			CreateImpersonation(r, p.Logger, p.Repository, p.Store, p.Repository)
+		})
+
+		r.Group(func(r chi.Router) {
+			middleware.AuthorizeVampire(r, p.Repository, models.RoleViewer)
+
+			ShowVampire(r, p.Logger, p.Renderer, p.Repository)
+			SearchVampire(r, p.Logger, p.Renderer, p.Repository, p.Repository)
+			StreamVampire(r, p.Logger, p.Hub)
+		})
+
+		r.Group(func(r chi.Router) {
+			middleware.AuthorizeVampire(r, p.Repository, models.RoleEditor)
+
+			NewCharacter(r, p.Logger, p.Renderer, p.Repository)
+			CreateCharacter(r, p.Logger, p.Renderer, p.Repository, p.Repository, p.Broadcaster)
+
+			NewExperience(r, p.Logger, p.Renderer, p.Repository)
+			CreateExperience(r, p.Logger, p.Renderer, p.Repository, p.Repository, p.Broadcaster, p.Game)
+
+			NewMark(r, p.Logger, p.Renderer, p.Repository)
+			CreateMark(r, p.Logger, p.Renderer, p.Repository, p.Repository, p.Broadcaster)
+
+			NewResource(r, p.Logger, p.Renderer, p.Repository)
+			CreateResource(r, p.Logger, p.Renderer, p.Repository, p.Repository, p.Broadcaster)
+
+			NewSkill(r, p.Logger, p.Renderer, p.Repository)
+			CreateSkill(r, p.Logger, p.Renderer, p.Repository, p.Repository, p.Broadcaster)
+		})
+
+		r.Group(func(r chi.Router) {
+			middleware.AuthorizeVampire(r, p.Repository, models.RoleOwner)
+
+			ListMembers(r, p.Logger, p.Renderer, p.Repository, p.Repository)
+			DestroyMember(r, p.Logger, p.Repository, p.Broadcaster)
+			CreateInvitation(r, p.Logger, p.Repository, p.Store)
+			DestroyInvitation(r, p.Logger, p.Repository)
+
+			ListShareLinks(r, p.Logger, p.Renderer, p.Repository, p.Repository)
+			CreateShareLink(r, p.Logger, p.Repository)
+			DestroyShareLink(r, p.Logger, p.Repository)
+
+			ArchiveVampire(r, p.Logger, p.Repository)
+			UnarchiveVampire(r, p.Logger, p.Repository)
+			DestroyVampire(r, p.Logger, p.Repository, p.Broadcaster)
+		})
 	})
 }
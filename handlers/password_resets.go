@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"emailaddress.horse/thousand/form"
+	"emailaddress.horse/thousand/middleware"
+	"emailaddress.horse/thousand/models"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type newPasswordResetRenderer interface {
+	NewPasswordReset(http.ResponseWriter, *http.Request, string, *form.PasswordResetForm) error
+}
+
+type passwordResetGetter interface {
+	GetPasswordReset(context.Context, string) (models.PasswordReset, error)
+}
+
+func NewPasswordReset(r chi.Router, l *zap.Logger, t newPasswordResetRenderer, rg passwordResetGetter) {
+	r.Get("/password_resets/{token}", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		token := chi.URLParam(r, "token")
+
+		_, err := rg.GetPasswordReset(r.Context(), token)
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				err = NotFoundError.Cause(err)
+			}
+
+			l.Error("failed to get password reset", zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		err = t.NewPasswordReset(w, r, token, form.NewPasswordReset(""))
+		if err != nil {
+			l.Error("failed to render", zap.Error(err))
+			handleError(w, r, err)
+		}
+	})
+}
+
+type passwordResetter interface {
+	ResetPassword(context.Context, string, string) (models.User, error)
+}
+
+func UsePasswordReset(r chi.Router, l *zap.Logger, t newPasswordResetRenderer, pr passwordResetter, s flashSetter) {
+	r.Post("/password_resets/{token}", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		token := chi.URLParam(r, "token")
+
+		form := form.NewPasswordReset(r.FormValue("password"))
+
+		if !form.Valid() {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			err := t.NewPasswordReset(w, r, token, form)
+			if err != nil {
+				l.Error("failed to render", zap.Error(err))
+				handleError(w, r, err)
+			}
+			return
+		}
+
+		_, err := pr.ResetPassword(r.Context(), token, form.Password.Value)
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				err = NotFoundError.Cause(err)
+			}
+
+			l.Error("failed to reset password", zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		redirectWithFlash(w, r, l, s, "/session/new", "Your password has been changed. Log in with your new password.")
+	})
+}
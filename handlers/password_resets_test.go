@@ -0,0 +1,208 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"emailaddress.horse/thousand/form"
+	"emailaddress.horse/thousand/handlers"
+	"emailaddress.horse/thousand/models"
+	"github.com/go-chi/chi/v5"
+)
+
+type mockNewPasswordResetRenderer struct {
+	err error
+}
+
+func (m *mockNewPasswordResetRenderer) NewPasswordReset(w http.ResponseWriter, _ *http.Request, token string, f *form.PasswordResetForm) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	fmt.Fprintf(w, "%s %s", token, f.Password.Message)
+	return nil
+}
+
+type mockPasswordResetGetter struct {
+	token string
+	err   error
+}
+
+func (m *mockPasswordResetGetter) GetPasswordReset(_ context.Context, token string) (models.PasswordReset, error) {
+	m.token = token
+	return models.PasswordReset{Token: token}, m.err
+}
+
+func TestNewPasswordReset(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		renderer       *mockNewPasswordResetRenderer
+		getter         *mockPasswordResetGetter
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "successful",
+			renderer:       &mockNewPasswordResetRenderer{},
+			getter:         &mockPasswordResetGetter{},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "token",
+		},
+		{
+			name:     "not found from getter",
+			renderer: &mockNewPasswordResetRenderer{},
+			getter: &mockPasswordResetGetter{
+				err: models.ErrNotFound,
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404: Not Found",
+		},
+		{
+			name: "error from renderer",
+			renderer: &mockNewPasswordResetRenderer{
+				err: errors.New("mock error"),
+			},
+			getter:         &mockPasswordResetGetter{},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+
+			handlers.NewPasswordReset(r, testLogger(t), tt.renderer, tt.getter)
+
+			status, _, body := get(r, "/password_resets/token")
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+
+			if tt.getter.token != "token" {
+				t.Errorf("expected token %q; got %q", "token", tt.getter.token)
+			}
+		})
+	}
+}
+
+type mockPasswordResetter struct {
+	token    string
+	password string
+	err      error
+}
+
+func (m *mockPasswordResetter) ResetPassword(_ context.Context, token, password string) (models.User, error) {
+	m.token = token
+	m.password = password
+	return models.User{Email: "jane@bannister.com"}, m.err
+}
+
+func TestUsePasswordReset(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		resetter         *mockPasswordResetter
+		body             url.Values
+		expectedStatus   int
+		expectedBody     string
+		expectedLocation string
+		expectedFlash    string
+		expectedPassword string
+	}{
+		{
+			name:     "successful",
+			resetter: &mockPasswordResetter{},
+			body: url.Values{
+				"password": []string{"hunter2"},
+			},
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "/session/new",
+			expectedFlash:    "Your password has been changed. Log in with your new password.",
+			expectedPassword: "hunter2",
+		},
+		{
+			name:           "missing password",
+			resetter:       &mockPasswordResetter{},
+			body:           url.Values{},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   "token Please provide a new password.",
+		},
+		{
+			name: "not found from resetter",
+			resetter: &mockPasswordResetter{
+				err: models.ErrNotFound,
+			},
+			body: url.Values{
+				"password": []string{"hunter2"},
+			},
+			expectedStatus:   http.StatusNotFound,
+			expectedBody:     "404: Not Found",
+			expectedPassword: "hunter2",
+		},
+		{
+			name: "error from resetter",
+			resetter: &mockPasswordResetter{
+				err: errors.New("mock error"),
+			},
+			body: url.Values{
+				"password": []string{"hunter2"},
+			},
+			expectedStatus:   http.StatusInternalServerError,
+			expectedBody:     "500: Internal Server Error",
+			expectedPassword: "hunter2",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+			s := &mockFlashSetter{}
+
+			handlers.UsePasswordReset(r, testLogger(t), &mockNewPasswordResetRenderer{}, tt.resetter, s)
+
+			status, headers, body := post(r, "/password_resets/token", tt.body.Encode())
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if tt.expectedLocation == "" && tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+
+			location := headers.Get("Location")
+			if tt.expectedLocation != location {
+				t.Errorf("expected location %q; got %q", tt.expectedLocation, location)
+			}
+
+			if tt.expectedFlash != s.message {
+				t.Errorf("expected flash %q; got %q", tt.expectedFlash, s.message)
+			}
+
+			if tt.expectedPassword != tt.resetter.password {
+				t.Errorf("expected password %q; got %q", tt.expectedPassword, tt.resetter.password)
+			}
+		})
+	}
+}
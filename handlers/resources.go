@@ -3,8 +3,9 @@ package handlers
 import (
 	"errors"
 	"net/http"
-	"strconv"
 
+	"emailaddress.horse/thousand/form"
+	"emailaddress.horse/thousand/middleware"
 	"emailaddress.horse/thousand/models"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -12,15 +13,17 @@ import (
 )
 
 type newResourceRenderer interface {
-	NewResource(http.ResponseWriter, *http.Request, models.Vampire) error
+	NewResource(http.ResponseWriter, *http.Request, models.Vampire, *form.NewResourceForm) error
 }
 
 func NewResource(r chi.Router, l *zap.Logger, t newResourceRenderer, vg vampireGetter) {
 	r.Get("/vampires/{vampireID}/resources/new", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
 		vampireID, err := uuid.Parse(chi.URLParam(r, "vampireID"))
 		if err != nil {
 			l.Error("failed to parse id as UUID", zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 			return
 		}
 
@@ -31,45 +34,90 @@ func NewResource(r chi.Router, l *zap.Logger, t newResourceRenderer, vg vampireG
 			}
 
 			l.Error("failed to find vampire", zap.Stringer("vampireID", vampireID), zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 			return
 		}
 
-		err = t.NewResource(w, r, vampire)
+		err = t.NewResource(w, r, vampire, form.NewResource(form.Fields{}))
 		if err != nil {
 			l.Error("failed to render", zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 		}
 	})
 }
 
-func CreateResource(r chi.Router, l *zap.Logger, rc resourceCreator) {
+type createResourceRenderer interface {
+	NewResource(http.ResponseWriter, *http.Request, models.Vampire, *form.NewResourceForm) error
+	CreateResource(http.ResponseWriter, *http.Request, uuid.UUID, models.Resource) error
+}
+
+func CreateResource(r chi.Router, l *zap.Logger, t createResourceRenderer, vg vampireGetter, rc resourceCreator, b resourceBroadcaster) {
 	r.Post("/vampires/{vampireID}/resources", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
 		vampireID, err := uuid.Parse(chi.URLParam(r, "vampireID"))
 		if err != nil {
 			l.Error("failed to parse id as UUID", zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 			return
 		}
 
-		params := models.CreateResourceParams{
-			Description: r.FormValue("description"),
+		if err := r.ParseForm(); err != nil {
+			l.Error("failed to parse form", zap.Error(err))
+			handleError(w, r, BadRequestError.Cause(err))
+			return
 		}
 
-		if params.Stationary, err = strconv.ParseBool(r.FormValue("stationary")); err != nil {
-			l.Error("failed to parse stationary param as bool", zap.Error(err))
-			handleError(w, err)
+		var params models.CreateResourceParams
+		fields, err := form.Decode(r.PostForm, &params)
+		if err != nil {
+			l.Error("failed to decode resource", zap.Error(err))
+			handleError(w, r, err)
 			return
 		}
 
-		_, err = rc.CreateResource(r.Context(), vampireID, params)
+		if !fields.Valid() {
+			vampire, err := vg.GetVampire(r.Context(), vampireID)
+			if err != nil {
+				if errors.Is(err, models.ErrNotFound) {
+					err = NotFoundError.Cause(err)
+				}
+
+				l.Error("failed to find vampire", zap.Stringer("vampireID", vampireID), zap.Error(err))
+				handleError(w, r, err)
+				return
+			}
+
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			err = t.NewResource(w, r, vampire, form.NewResource(fields))
+			if err != nil {
+				l.Error("failed to render", zap.Error(err))
+				handleError(w, r, err)
+			}
+			return
+		}
+
+		resource, err := rc.CreateResource(r.Context(), vampireID, params)
 		if err != nil {
 			if errors.Is(err, models.ErrNotFound) {
 				err = NotFoundError.Cause(err)
 			}
 
 			l.Error("failed to create resource", zap.Stringer("vampireID", vampireID), zap.Object("params", params), zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
+			return
+		}
+
+		if err := b.ResourceCreated(r.Context(), vampireID, resource); err != nil {
+			l.Error("failed to broadcast resource", zap.Stringer("vampireID", vampireID), zap.Error(err))
+		}
+
+		if acceptsTurboStream(r) {
+			err = t.CreateResource(w, r, vampireID, resource)
+			if err != nil {
+				l.Error("failed to render", zap.Error(err))
+				handleError(w, r, err)
+			}
 			return
 		}
 
@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"testing"
 
+	"emailaddress.horse/thousand/form"
 	"emailaddress.horse/thousand/handlers"
 	"emailaddress.horse/thousand/models"
 	"github.com/go-chi/chi/v5"
@@ -17,7 +18,7 @@ type mockNewResourceRenderer struct {
 	err error
 }
 
-func (m *mockNewResourceRenderer) NewResource(w http.ResponseWriter, _ *http.Request, vampire models.Vampire) error {
+func (m *mockNewResourceRenderer) NewResource(w http.ResponseWriter, _ *http.Request, vampire models.Vampire, _ *form.NewResourceForm) error {
 	if m.err != nil {
 		return m.err
 	}
@@ -140,19 +141,50 @@ func (m *mockResourceCreator) CreateResource(_ context.Context, vampireID uuid.U
 	return models.Resource{}, m.err
 }
 
+type mockCreateResourceRenderer struct {
+	err error
+}
+
+func (m *mockCreateResourceRenderer) NewResource(w http.ResponseWriter, _ *http.Request, _ models.Vampire, f *form.NewResourceForm) error {
+	_, err := w.Write([]byte(f.Description.Message + f.Stationary.Message))
+	if err != nil {
+		panic(err)
+	}
+
+	return nil
+}
+
+func (m *mockCreateResourceRenderer) CreateResource(w http.ResponseWriter, _ *http.Request, vampireID uuid.UUID, _ models.Resource) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	_, err := w.Write([]byte(vampireID.String()))
+	if err != nil {
+		panic(err)
+	}
+
+	return nil
+}
+
 func TestCreateResource(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		name              string
-		body              url.Values
-		creator           *mockResourceCreator
-		path              string
-		expectedStatus    int
-		expectedBody      string
-		expectedLocation  string
-		expectedVampireID uuid.UUID
-		expectedParams    models.CreateResourceParams
+		name               string
+		body               url.Values
+		accept             string
+		renderer           *mockCreateResourceRenderer
+		getter             *mockVampireGetter
+		creator            *mockResourceCreator
+		broadcaster        *mockBroadcaster
+		path               string
+		expectedStatus     int
+		expectedBroadcasts int
+		expectedBody       string
+		expectedLocation   string
+		expectedVampireID  uuid.UUID
+		expectedParams     models.CreateResourceParams
 	}{
 		{
 			name: "successful",
@@ -160,11 +192,15 @@ func TestCreateResource(t *testing.T) {
 				"description": []string{"A description"},
 				"stationary":  []string{"1"},
 			},
-			creator:           &mockResourceCreator{},
-			path:              "/vampires/12345678-90ab-cdef-1234-567890abcdef/resources",
-			expectedStatus:    http.StatusSeeOther,
-			expectedLocation:  "/vampires/12345678-90ab-cdef-1234-567890abcdef",
-			expectedVampireID: uuid.MustParse("12345678-90ab-cdef-1234-567890abcdef"),
+			renderer:           &mockCreateResourceRenderer{},
+			getter:             &mockVampireGetter{},
+			creator:            &mockResourceCreator{},
+			broadcaster:        &mockBroadcaster{},
+			path:               "/vampires/12345678-90ab-cdef-1234-567890abcdef/resources",
+			expectedStatus:     http.StatusSeeOther,
+			expectedBroadcasts: 1,
+			expectedLocation:   "/vampires/12345678-90ab-cdef-1234-567890abcdef",
+			expectedVampireID:  uuid.MustParse("12345678-90ab-cdef-1234-567890abcdef"),
 			expectedParams: models.CreateResourceParams{
 				Description: "A description",
 				Stationary:  true,
@@ -176,21 +212,27 @@ func TestCreateResource(t *testing.T) {
 				"description": []string{"A description"},
 				"stationary":  []string{"1"},
 			},
+			renderer:       &mockCreateResourceRenderer{},
+			getter:         &mockVampireGetter{},
 			creator:        &mockResourceCreator{},
+			broadcaster:    &mockBroadcaster{},
 			path:           "/vampires/unknown/resources",
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody:   "500: Internal Server Error",
 		},
 		{
-			name: "error parsing stationary",
+			name: "invalid stationary",
 			body: url.Values{
 				"description": []string{"A description"},
 				"stationary":  []string{"horses"},
 			},
+			renderer:       &mockCreateResourceRenderer{},
+			getter:         &mockVampireGetter{},
 			creator:        &mockResourceCreator{},
+			broadcaster:    &mockBroadcaster{},
 			path:           "/vampires/12345678-90ab-cdef-1234-567890abcdef/resources",
-			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   "500: Internal Server Error",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   "Please choose whether the resource is stationary.",
 		},
 		{
 			name: "not found from creator",
@@ -198,9 +240,12 @@ func TestCreateResource(t *testing.T) {
 				"description": []string{"A description"},
 				"stationary":  []string{"1"},
 			},
+			renderer: &mockCreateResourceRenderer{},
+			getter:   &mockVampireGetter{},
 			creator: &mockResourceCreator{
 				err: models.ErrNotFound,
 			},
+			broadcaster:       &mockBroadcaster{},
 			path:              "/vampires/12345678-90ab-cdef-1234-567890abcdef/resources",
 			expectedStatus:    http.StatusNotFound,
 			expectedBody:      "404: Not Found",
@@ -216,9 +261,12 @@ func TestCreateResource(t *testing.T) {
 				"description": []string{"A description"},
 				"stationary":  []string{"1"},
 			},
+			renderer: &mockCreateResourceRenderer{},
+			getter:   &mockVampireGetter{},
 			creator: &mockResourceCreator{
 				err: errors.New("mock error"),
 			},
+			broadcaster:       &mockBroadcaster{},
 			path:              "/vampires/12345678-90ab-cdef-1234-567890abcdef/resources",
 			expectedStatus:    http.StatusInternalServerError,
 			expectedBody:      "500: Internal Server Error",
@@ -228,6 +276,94 @@ func TestCreateResource(t *testing.T) {
 				Stationary:  true,
 			},
 		},
+		{
+			name: "error from broadcaster",
+			body: url.Values{
+				"description": []string{"A description"},
+				"stationary":  []string{"1"},
+			},
+			renderer:           &mockCreateResourceRenderer{},
+			getter:             &mockVampireGetter{},
+			creator:            &mockResourceCreator{},
+			broadcaster:        &mockBroadcaster{err: errors.New("mock error")},
+			path:               "/vampires/12345678-90ab-cdef-1234-567890abcdef/resources",
+			expectedStatus:     http.StatusSeeOther,
+			expectedBroadcasts: 1,
+			expectedLocation:   "/vampires/12345678-90ab-cdef-1234-567890abcdef",
+			expectedVampireID:  uuid.MustParse("12345678-90ab-cdef-1234-567890abcdef"),
+			expectedParams: models.CreateResourceParams{
+				Description: "A description",
+				Stationary:  true,
+			},
+		},
+		{
+			name:   "successful turbo stream",
+			accept: "text/vnd.turbo-stream.html",
+			body: url.Values{
+				"description": []string{"A description"},
+				"stationary":  []string{"1"},
+			},
+			renderer:           &mockCreateResourceRenderer{},
+			getter:             &mockVampireGetter{},
+			creator:            &mockResourceCreator{},
+			broadcaster:        &mockBroadcaster{},
+			path:               "/vampires/12345678-90ab-cdef-1234-567890abcdef/resources",
+			expectedStatus:     http.StatusOK,
+			expectedBroadcasts: 1,
+			expectedBody:       "12345678-90ab-cdef-1234-567890abcdef",
+			expectedVampireID:  uuid.MustParse("12345678-90ab-cdef-1234-567890abcdef"),
+			expectedParams: models.CreateResourceParams{
+				Description: "A description",
+				Stationary:  true,
+			},
+		},
+		{
+			name:   "error from renderer",
+			accept: "text/vnd.turbo-stream.html",
+			body: url.Values{
+				"description": []string{"A description"},
+				"stationary":  []string{"1"},
+			},
+			renderer:           &mockCreateResourceRenderer{err: errors.New("mock error")},
+			getter:             &mockVampireGetter{},
+			creator:            &mockResourceCreator{},
+			broadcaster:        &mockBroadcaster{},
+			path:               "/vampires/12345678-90ab-cdef-1234-567890abcdef/resources",
+			expectedStatus:     http.StatusInternalServerError,
+			expectedBroadcasts: 1,
+			expectedBody:       "500: Internal Server Error",
+			expectedVampireID:  uuid.MustParse("12345678-90ab-cdef-1234-567890abcdef"),
+			expectedParams: models.CreateResourceParams{
+				Description: "A description",
+				Stationary:  true,
+			},
+		},
+		{
+			name: "invalid form",
+			body: url.Values{
+				"description": []string{""},
+			},
+			renderer:       &mockCreateResourceRenderer{},
+			getter:         &mockVampireGetter{},
+			creator:        &mockResourceCreator{},
+			broadcaster:    &mockBroadcaster{},
+			path:           "/vampires/12345678-90ab-cdef-1234-567890abcdef/resources",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   "Please provide a description.",
+		},
+		{
+			name: "not found from getter with invalid form",
+			body: url.Values{
+				"description": []string{""},
+			},
+			renderer:       &mockCreateResourceRenderer{},
+			getter:         &mockVampireGetter{err: models.ErrNotFound},
+			creator:        &mockResourceCreator{},
+			broadcaster:    &mockBroadcaster{},
+			path:           "/vampires/12345678-90ab-cdef-1234-567890abcdef/resources",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404: Not Found",
+		},
 	}
 
 	for _, tt := range tests {
@@ -238,9 +374,12 @@ func TestCreateResource(t *testing.T) {
 
 			r := chi.NewMux()
 
-			handlers.CreateResource(r, testLogger(t), tt.creator)
+			handlers.CreateResource(r, testLogger(t), tt.renderer, tt.getter, tt.creator, tt.broadcaster)
+
+			req := postRequest(tt.path, tt.body.Encode())
+			req.request.Header.Set("Accept", tt.accept)
 
-			status, headers, body := post(r, tt.path, tt.body.Encode())
+			status, headers, body := req.perform(r)
 
 			if tt.expectedStatus != status {
 				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
@@ -262,6 +401,10 @@ func TestCreateResource(t *testing.T) {
 			if tt.expectedLocation != location {
 				t.Errorf("expected location %q; got %q", tt.expectedLocation, location)
 			}
+
+			if tt.expectedBroadcasts != tt.broadcaster.count {
+				t.Errorf("expected %d broadcasts; got %d", tt.expectedBroadcasts, tt.broadcaster.count)
+			}
 		})
 	}
 }
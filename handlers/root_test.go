@@ -6,7 +6,14 @@ import (
 	"testing"
 
 	"emailaddress.horse/thousand/handlers"
+	"emailaddress.horse/thousand/middleware"
+	"emailaddress.horse/thousand/tracing"
 	"github.com/go-chi/chi/v5"
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
 )
 
 func TestRoot(t *testing.T) {
@@ -50,3 +57,139 @@ func TestRoot(t *testing.T) {
 		})
 	}
 }
+
+func TestRoot_Trace(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedName   string
+		expectedRoute  string
+		expectedStatus int64
+	}{
+		{
+			name:           "matched route",
+			path:           "/",
+			expectedName:   "GET /",
+			expectedRoute:  "/",
+			expectedStatus: http.StatusSeeOther,
+		},
+		{
+			name:           "no matching route",
+			path:           "/missing",
+			expectedName:   "GET",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := tracetest.NewSpanRecorder()
+
+			r := chi.NewMux()
+			middleware.Trace(r, tracing.NewWithProcessor(recorder))
+			handlers.NotFound(r)
+			handlers.Root(r)
+
+			req := newRequest(http.MethodGet, tt.path)
+			req.request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+			req.perform(r)
+
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("expected 1 span; got %d", len(spans))
+			}
+			span := spans[0]
+
+			if tt.expectedName != span.Name() {
+				t.Errorf("expected name %q; got %q", tt.expectedName, span.Name())
+			}
+
+			if traceID := span.SpanContext().TraceID().String(); traceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+				t.Errorf("expected trace to be continued; got trace ID %q", traceID)
+			}
+
+			attributes := attribute.NewSet(span.Attributes()...)
+
+			route, _ := attributes.Value(semconv.HTTPRouteKey)
+			if tt.expectedRoute != route.AsString() {
+				t.Errorf("expected route %q; got %q", tt.expectedRoute, route.AsString())
+			}
+
+			status, _ := attributes.Value(semconv.HTTPStatusCodeKey)
+			if tt.expectedStatus != status.AsInt64() {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status.AsInt64())
+			}
+		})
+	}
+}
+
+func TestRoot_Metrics(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	r := chi.NewMux()
+	middleware.CollectMetrics(r, registry)
+	handlers.NotFound(r)
+	handlers.Root(r)
+	handlers.ShowVampire(r, testLogger(t), &mockShowVampireRenderer{}, &mockVampireGetter{})
+
+	for _, path := range []string{
+		"/",
+		"/vampires/11111111-1111-1111-1111-111111111111",
+		"/vampires/22222222-2222-2222-2222-222222222222",
+		"/missing",
+	} {
+		newRequest(http.MethodGet, path).perform(r)
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Requests are labelled by route, so both vampires share a series and
+	// requests which match no route share another
+	expected := map[string]map[string]uint64{
+		"app_http_requests_total": {
+			"GET / 303":                     1,
+			"GET /vampires/{vampireID} 200": 2,
+			"GET unmatched 404":             1,
+		},
+		"app_http_requests_duration_seconds": {
+			"GET / 303":                     1,
+			"GET /vampires/{vampireID} 200": 2,
+			"GET unmatched 404":             1,
+		},
+	}
+
+	actual := map[string]map[string]uint64{}
+	for _, family := range families {
+		series := map[string]uint64{}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			key := labels["method"] + " " + labels["route"] + " " + labels["code"]
+
+			if counter := metric.GetCounter(); counter != nil {
+				series[key] = uint64(counter.GetValue())
+			}
+			if histogram := metric.GetHistogram(); histogram != nil {
+				series[key] = histogram.GetSampleCount()
+			}
+		}
+		actual[family.GetName()] = series
+	}
+
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"emailaddress.horse/thousand/middleware"
+	"emailaddress.horse/thousand/models"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type searchVampireRenderer interface {
+	SearchVampire(http.ResponseWriter, *http.Request, models.Vampire, string, []models.SearchResult) error
+}
+
+type vampireSearcher interface {
+	SearchVampire(context.Context, uuid.UUID, string) ([]models.SearchResult, error)
+}
+
+func SearchVampire(r chi.Router, l *zap.Logger, t searchVampireRenderer, vg vampireGetter, vs vampireSearcher) {
+	r.Get("/vampires/{vampireID}/search", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		vampireID, err := uuid.Parse(chi.URLParam(r, "vampireID"))
+		if err != nil {
+			l.Error("failed to parse vampire id as UUID", zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		search := strings.TrimSpace(r.URL.Query().Get("q"))
+		if utf8.RuneCountInString(search) > models.MaxSearchLength {
+			l.Error("search too long", zap.Int("length", len(search)))
+			handleError(w, r, BadRequestError)
+			return
+		}
+
+		vampire, err := vg.GetVampire(r.Context(), vampireID)
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				err = NotFoundError.Cause(err)
+			}
+
+			l.Error("failed to find vampire", zap.Stringer("vampireID", vampireID), zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		// A blank search shows the search box on its own
+		results := []models.SearchResult{}
+		if search != "" {
+			results, err = vs.SearchVampire(r.Context(), vampireID, search)
+			if err != nil {
+				l.Error("failed to search vampire", zap.Stringer("vampireID", vampireID), zap.String("search", search), zap.Error(err))
+				handleError(w, r, err)
+				return
+			}
+		}
+
+		err = t.SearchVampire(w, r, vampire, search, results)
+		if err != nil {
+			l.Error("failed to render", zap.Error(err))
+			handleError(w, r, err)
+		}
+	})
+}
@@ -0,0 +1,167 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"emailaddress.horse/thousand/handlers"
+	"emailaddress.horse/thousand/models"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type mockSearchVampireRenderer struct {
+	err error
+}
+
+func (m *mockSearchVampireRenderer) SearchVampire(w http.ResponseWriter, _ *http.Request, vampire models.Vampire, search string, results []models.SearchResult) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	kinds := make([]string, len(results))
+	for i, result := range results {
+		kinds[i] = string(result.Kind)
+	}
+
+	_, err := fmt.Fprintf(w, "%s | %s | %s", vampire.Name, search, strings.Join(kinds, ", "))
+	if err != nil {
+		panic(err)
+	}
+
+	return nil
+}
+
+type mockVampireSearcher struct {
+	results []models.SearchResult
+	err     error
+	id      uuid.UUID
+	search  string
+}
+
+func (m *mockVampireSearcher) SearchVampire(_ context.Context, id uuid.UUID, search string) ([]models.SearchResult, error) {
+	m.id = id
+	m.search = search
+	return m.results, m.err
+}
+
+func TestSearchVampire(t *testing.T) {
+	t.Parallel()
+
+	vampireID := uuid.MustParse("12345678-90ab-cdef-1234-567890abcdef")
+
+	tests := []struct {
+		name           string
+		path           string
+		renderer       *mockSearchVampireRenderer
+		getter         *mockVampireGetter
+		searcher       *mockVampireSearcher
+		expectedStatus int
+		expectedBody   string
+		expectedSearch string
+	}{
+		{
+			name:     "successful",
+			path:     "/vampires/12345678-90ab-cdef-1234-567890abcdef/search?q=+elisabeth+",
+			renderer: &mockSearchVampireRenderer{},
+			getter: &mockVampireGetter{
+				vampire: models.Vampire{Name: "A vampire"},
+			},
+			searcher: &mockVampireSearcher{
+				results: []models.SearchResult{
+					{Kind: models.SearchResultExperience},
+					{Kind: models.SearchResultCharacter},
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "A vampire | elisabeth | experience, character",
+			expectedSearch: "elisabeth",
+		},
+		{
+			name:     "blank search",
+			path:     "/vampires/12345678-90ab-cdef-1234-567890abcdef/search",
+			renderer: &mockSearchVampireRenderer{},
+			getter: &mockVampireGetter{
+				vampire: models.Vampire{Name: "A vampire"},
+			},
+			searcher:       &mockVampireSearcher{},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "A vampire |  |",
+		},
+		{
+			name:           "search too long",
+			path:           "/vampires/12345678-90ab-cdef-1234-567890abcdef/search?q=" + strings.Repeat("a", models.MaxSearchLength+1),
+			getter:         &mockVampireGetter{},
+			searcher:       &mockVampireSearcher{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "400: Bad Request",
+		},
+		{
+			name: "vampire not found",
+			path: "/vampires/12345678-90ab-cdef-1234-567890abcdef/search?q=elisabeth",
+			getter: &mockVampireGetter{
+				err: models.ErrNotFound,
+			},
+			searcher:       &mockVampireSearcher{},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404: Not Found",
+		},
+		{
+			name:   "error from searcher",
+			path:   "/vampires/12345678-90ab-cdef-1234-567890abcdef/search?q=elisabeth",
+			getter: &mockVampireGetter{},
+			searcher: &mockVampireSearcher{
+				err: errors.New("mock error"),
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+			expectedSearch: "elisabeth",
+		},
+		{
+			name: "error from renderer",
+			path: "/vampires/12345678-90ab-cdef-1234-567890abcdef/search?q=elisabeth",
+			renderer: &mockSearchVampireRenderer{
+				err: errors.New("mock error"),
+			},
+			getter:         &mockVampireGetter{},
+			searcher:       &mockVampireSearcher{},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+			expectedSearch: "elisabeth",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+
+			handlers.SearchVampire(r, testLogger(t), tt.renderer, tt.getter, tt.searcher)
+
+			status, _, body := get(r, tt.path)
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+
+			if tt.expectedSearch != tt.searcher.search {
+				t.Errorf("expected searcher to receive %q; got %q", tt.expectedSearch, tt.searcher.search)
+			}
+
+			if tt.expectedSearch != "" && vampireID != tt.searcher.id {
+				t.Errorf("expected searcher to receive vampire ID %s; got %s", vampireID, tt.searcher.id)
+			}
+		})
+	}
+}
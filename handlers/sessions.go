@@ -5,7 +5,9 @@ import (
 	"net/http"
 
 	"emailaddress.horse/thousand/form"
+	"emailaddress.horse/thousand/middleware"
 	"emailaddress.horse/thousand/models"
+	"emailaddress.horse/thousand/registry"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -17,10 +19,12 @@ type newSessionRenderer interface {
 
 func NewSession(r chi.Router, l *zap.Logger, t newSessionRenderer) {
 	r.Get("/session/new", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
 		err := t.NewSession(w, r, form.NewSession("", ""))
 		if err != nil {
 			l.Error("failed to render", zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 		}
 	})
 }
@@ -29,51 +33,84 @@ type userAuthenticator interface {
 	AuthenticateUser(context.Context, *form.NewSessionForm) (models.User, error)
 }
 
-func CreateSession(r chi.Router, l *zap.Logger, ua userAuthenticator, t newSessionRenderer, s sessionSetter) {
+func CreateSession(r chi.Router, l *zap.Logger, ua userAuthenticator, t newSessionRenderer, s sessionSetter, m loginMetrics, ar auditRecorder) {
 	r.Post("/session", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
 		form := form.NewSession(
 			r.FormValue("email"),
 			r.FormValue("password"),
 		)
 
 		if !form.Valid() {
+			m.Login(registry.AuthPassword, false)
+
 			w.WriteHeader(http.StatusUnprocessableEntity)
 			err := t.NewSession(w, r, form)
 			if err != nil {
 				l.Error("failed to render", zap.Error(err))
-				handleError(w, err)
+				handleError(w, r, err)
 			}
 			return
 		}
 
 		user, err := ua.AuthenticateUser(r.Context(), form)
 		if err != nil {
+			m.Login(registry.AuthPassword, false)
+
 			l.Error("failed to authenticate user", zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 			return
 		}
 
 		if user.ID == (uuid.UUID{}) {
+			m.Login(registry.AuthPassword, false)
+			recordAuditEvent(r, l, ar, models.AuditEvent{
+				ActorEmail: form.Email.Value,
+				Action:     models.AuditActionLoginFailed,
+			})
+
 			form.Email.Message = "No user found with this email address and password."
 
 			w.WriteHeader(http.StatusUnprocessableEntity)
 			err := t.NewSession(w, r, form)
 			if err != nil {
 				l.Error("failed to render", zap.Error(err))
-				handleError(w, err)
+				handleError(w, r, err)
 			}
 			return
 		}
 
+		if user.Disabled() {
+			m.Login(registry.AuthPassword, false)
+			recordAuditEvent(r, l, ar, models.AuditEvent{
+				ActorEmail: user.Email,
+				Action:     models.AuditActionLoginFailed,
+			})
+
+			form.Email.Message = "This account has been disabled."
+
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			err := t.NewSession(w, r, form)
+			if err != nil {
+				l.Error("failed to render", zap.Error(err))
+				handleError(w, r, err)
+			}
+			return
+		}
+
+		m.Login(registry.AuthPassword, true)
+		recordAuditEvent(r, l, ar, userAuditEvent(models.AuditActionLogin, user))
+
 		if err := s.SetCurrentUserID(r, w, user.ID); err != nil {
 			l.Error("failed to set user id in session", zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 			return
 		}
 
 		if err := s.SetFlash(r, w, "Welcome back!"); err != nil {
 			l.Error("failed to set flash", zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 			return
 		}
 
@@ -82,14 +119,24 @@ func CreateSession(r chi.Router, l *zap.Logger, ua userAuthenticator, t newSessi
 }
 
 type currentUserIDClearer interface {
+	GetCurrentUserID(*http.Request) (uuid.UUID, bool)
+	GetImpersonatorID(*http.Request) (uuid.UUID, bool)
 	ClearCurrentUserID(http.ResponseWriter, *http.Request) error
 }
 
-func DestroySession(r chi.Router, l *zap.Logger, s currentUserIDClearer) {
+func DestroySession(r chi.Router, l *zap.Logger, s currentUserIDClearer, ar auditRecorder) {
 	r.Delete("/session", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		if userID, ok := s.GetCurrentUserID(r); ok {
+			event := userAuditEvent(models.AuditActionLogout, models.User{ID: userID})
+			event.ImpersonatorID, _ = s.GetImpersonatorID(r)
+			recordAuditEvent(r, l, ar, event)
+		}
+
 		if err := s.ClearCurrentUserID(w, r); err != nil {
 			l.Error("failed to clear user id in session", zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 			return
 		}
 
@@ -7,11 +7,13 @@ import (
 	"net/http"
 	"net/url"
 	"testing"
+	"time"
 
 	"emailaddress.horse/thousand/form"
 	"emailaddress.horse/thousand/handlers"
 	"emailaddress.horse/thousand/models"
 	"github.com/go-chi/chi/v5"
+	"github.com/google/go-cmp/cmp"
 	"github.com/google/uuid"
 )
 
@@ -103,6 +105,8 @@ func TestCreateSession(t *testing.T) {
 		expectedStatus   int
 		expectedBody     string
 		expectedLocation string
+		expectedMetrics  []string
+		expectedAudit    []string
 	}{
 		{
 			name: "successful",
@@ -111,23 +115,26 @@ func TestCreateSession(t *testing.T) {
 				"password": []string{"password"},
 			},
 			authenticator: &mockUserAuthenticator{
-				user: models.User{ID: uuid.New()},
+				user: models.User{ID: uuid.New(), Email: "john@bannister.com"},
 			},
 			renderer:         &mockNewSessionRenderer{},
 			setter:           &mockSetter{&mockCurrentUserIDSetter{}, &mockFlashSetter{}},
 			expectedStatus:   http.StatusSeeOther,
 			expectedLocation: "/",
+			expectedMetrics:  []string{"login password success"},
+			expectedAudit:    []string{"session.login john@bannister.com"},
 		},
 		{
 			name: "form invalid",
 			body: url.Values{
 				"email": []string{"john@bannister.com"},
 			},
-			authenticator:  &mockUserAuthenticator{},
-			renderer:       &mockNewSessionRenderer{},
-			setter:         &mockSetter{&mockCurrentUserIDSetter{}, &mockFlashSetter{}},
-			expectedStatus: http.StatusUnprocessableEntity,
-			expectedBody:   `{"Email":{"Message":"","Value":"john@bannister.com"},"Password":{"Message":"Please provide a password.","Value":""}}`,
+			authenticator:   &mockUserAuthenticator{},
+			renderer:        &mockNewSessionRenderer{},
+			setter:          &mockSetter{&mockCurrentUserIDSetter{}, &mockFlashSetter{}},
+			expectedStatus:  http.StatusUnprocessableEntity,
+			expectedBody:    `{"Email":{"Message":"","Value":"john@bannister.com"},"Password":{"Message":"Please provide a password.","Value":""}}`,
+			expectedMetrics: []string{"login password failure"},
 		},
 		{
 			name: "no user from authenticator",
@@ -138,10 +145,28 @@ func TestCreateSession(t *testing.T) {
 			authenticator: &mockUserAuthenticator{
 				user: models.User{},
 			},
-			renderer:       &mockNewSessionRenderer{},
-			setter:         &mockSetter{&mockCurrentUserIDSetter{}, &mockFlashSetter{}},
-			expectedStatus: http.StatusUnprocessableEntity,
-			expectedBody:   `{"Email":{"Message":"No user found with this email address and password.","Value":"john@bannister.com"},"Password":{"Message":"","Value":"password"}}`,
+			renderer:        &mockNewSessionRenderer{},
+			setter:          &mockSetter{&mockCurrentUserIDSetter{}, &mockFlashSetter{}},
+			expectedStatus:  http.StatusUnprocessableEntity,
+			expectedBody:    `{"Email":{"Message":"No user found with this email address and password.","Value":"john@bannister.com"},"Password":{"Message":"","Value":"password"}}`,
+			expectedMetrics: []string{"login password failure"},
+			expectedAudit:   []string{"session.login_failed john@bannister.com"},
+		},
+		{
+			name: "user disabled",
+			body: url.Values{
+				"email":    []string{"john@bannister.com"},
+				"password": []string{"password"},
+			},
+			authenticator: &mockUserAuthenticator{
+				user: models.User{ID: uuid.New(), Email: "john@bannister.com", DisabledAt: time.Now()},
+			},
+			renderer:        &mockNewSessionRenderer{},
+			setter:          &mockSetter{&mockCurrentUserIDSetter{}, &mockFlashSetter{}},
+			expectedStatus:  http.StatusUnprocessableEntity,
+			expectedBody:    `{"Email":{"Message":"This account has been disabled.","Value":"john@bannister.com"},"Password":{"Message":"","Value":"password"}}`,
+			expectedMetrics: []string{"login password failure"},
+			expectedAudit:   []string{"session.login_failed john@bannister.com"},
 		},
 		{
 			name: "error from authenticator",
@@ -152,10 +177,11 @@ func TestCreateSession(t *testing.T) {
 			authenticator: &mockUserAuthenticator{
 				err: errors.New("mock error"),
 			},
-			renderer:       &mockNewSessionRenderer{},
-			setter:         &mockSetter{&mockCurrentUserIDSetter{}, &mockFlashSetter{}},
-			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   "500: Internal Server Error",
+			renderer:        &mockNewSessionRenderer{},
+			setter:          &mockSetter{&mockCurrentUserIDSetter{}, &mockFlashSetter{}},
+			expectedStatus:  http.StatusInternalServerError,
+			expectedBody:    "500: Internal Server Error",
+			expectedMetrics: []string{"login password failure"},
 		},
 		{
 			name: "error from setter",
@@ -173,8 +199,10 @@ func TestCreateSession(t *testing.T) {
 				},
 				&mockFlashSetter{},
 			},
-			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   "500: Internal Server Error",
+			expectedStatus:  http.StatusInternalServerError,
+			expectedBody:    "500: Internal Server Error",
+			expectedMetrics: []string{"login password success"},
+			expectedAudit:   []string{"session.login"},
 		},
 	}
 
@@ -185,8 +213,10 @@ func TestCreateSession(t *testing.T) {
 			t.Parallel()
 
 			r := chi.NewMux()
+			metrics := &mockMetrics{}
+			recorder := &mockAuditRecorder{}
 
-			handlers.CreateSession(r, testLogger(t), tt.authenticator, tt.renderer, tt.setter)
+			handlers.CreateSession(r, testLogger(t), tt.authenticator, tt.renderer, tt.setter, metrics, recorder)
 
 			status, headers, body := post(r, "/session", tt.body.Encode())
 
@@ -202,12 +232,30 @@ func TestCreateSession(t *testing.T) {
 			if tt.expectedBody != body {
 				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
 			}
+
+			if diff := cmp.Diff(tt.expectedMetrics, metrics.counted); diff != "" {
+				t.Errorf("unexpected metrics: %s", diff)
+			}
+
+			if diff := cmp.Diff(tt.expectedAudit, recorder.recorded); diff != "" {
+				t.Errorf("unexpected audit events: %s", diff)
+			}
 		})
 	}
 }
 
 type mockCurrentUserIDClearer struct {
-	err error
+	userID         uuid.UUID
+	impersonatorID uuid.UUID
+	err            error
+}
+
+func (m *mockCurrentUserIDClearer) GetCurrentUserID(_ *http.Request) (uuid.UUID, bool) {
+	return m.userID, m.userID != uuid.UUID{}
+}
+
+func (m *mockCurrentUserIDClearer) GetImpersonatorID(_ *http.Request) (uuid.UUID, bool) {
+	return m.impersonatorID, m.impersonatorID != uuid.UUID{}
 }
 
 func (m *mockCurrentUserIDClearer) ClearCurrentUserID(_ http.ResponseWriter, _ *http.Request) error {
@@ -223,9 +271,19 @@ func TestDestroySession(t *testing.T) {
 		expectedStatus   int
 		expectedBody     string
 		expectedLocation string
+		expectedAudit    []string
 	}{
 		{
-			name:             "successful",
+			name: "successful",
+			clearer: &mockCurrentUserIDClearer{
+				userID: uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+			},
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "/",
+			expectedAudit:    []string{"session.logout"},
+		},
+		{
+			name:             "not logged in",
 			clearer:          &mockCurrentUserIDClearer{},
 			expectedStatus:   http.StatusSeeOther,
 			expectedLocation: "/",
@@ -248,7 +306,9 @@ func TestDestroySession(t *testing.T) {
 
 			r := chi.NewMux()
 
-			handlers.DestroySession(r, testLogger(t), tt.clearer)
+			recorder := &mockAuditRecorder{}
+
+			handlers.DestroySession(r, testLogger(t), tt.clearer, recorder)
 
 			status, headers, body := deleteRequest(r, "/session")
 
@@ -264,6 +324,10 @@ func TestDestroySession(t *testing.T) {
 			if tt.expectedBody != body {
 				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
 			}
+
+			if diff := cmp.Diff(tt.expectedAudit, recorder.recorded); diff != "" {
+				t.Errorf("unexpected audit events: %s", diff)
+			}
 		})
 	}
 }
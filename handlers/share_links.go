@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"emailaddress.horse/thousand/middleware"
+	"emailaddress.horse/thousand/models"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type showShareLinksRenderer interface {
+	ShowShareLinks(http.ResponseWriter, *http.Request, models.Vampire, []models.ShareLink) error
+}
+
+type shareLinksGetter interface {
+	GetShareLinks(context.Context, uuid.UUID) ([]models.ShareLink, error)
+}
+
+func ListShareLinks(r chi.Router, l *zap.Logger, t showShareLinksRenderer, vg vampireGetter, sg shareLinksGetter) {
+	r.Get("/vampires/{vampireID}/share_links", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		vampireID, err := uuid.Parse(chi.URLParam(r, "vampireID"))
+		if err != nil {
+			l.Error("failed to parse vampire id as UUID", zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		vampire, err := vg.GetVampire(r.Context(), vampireID)
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				err = NotFoundError.Cause(err)
+			}
+
+			l.Error("failed to find vampire", zap.Stringer("vampireID", vampireID), zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		links, err := sg.GetShareLinks(r.Context(), vampireID)
+		if err != nil {
+			l.Error("failed to load share links", zap.Stringer("vampireID", vampireID), zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		err = t.ShowShareLinks(w, r, vampire, links)
+		if err != nil {
+			l.Error("failed to render", zap.Error(err))
+			handleError(w, r, err)
+		}
+	})
+}
+
+type shareLinkCreator interface {
+	CreateShareLink(context.Context, uuid.UUID, time.Duration) (models.ShareLink, error)
+}
+
+func CreateShareLink(r chi.Router, l *zap.Logger, sc shareLinkCreator) {
+	r.Post("/vampires/{vampireID}/share_links", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		vampireID, err := uuid.Parse(chi.URLParam(r, "vampireID"))
+		if err != nil {
+			l.Error("failed to parse vampire id as UUID", zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		// A blank expiry creates a link which never expires
+		var expiresIn time.Duration
+		if value := r.FormValue("expires_in"); value != "" {
+			expiresIn, err = time.ParseDuration(value)
+			if err == nil && expiresIn <= 0 {
+				err = errors.New("expiry must be positive")
+			} else if err == nil && expiresIn > models.MaxShareLinkExpiry {
+				err = fmt.Errorf("expiry must be at most %s", models.MaxShareLinkExpiry)
+			}
+			if err != nil {
+				l.Error("failed to parse expiry", zap.String("expiresIn", value), zap.Error(err))
+				handleError(w, r, BadRequestError.Cause(err))
+				return
+			}
+		}
+
+		_, err = sc.CreateShareLink(r.Context(), vampireID, expiresIn)
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				err = NotFoundError.Cause(err)
+			}
+
+			l.Error("failed to create share link", zap.Stringer("vampireID", vampireID), zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		http.Redirect(w, r, "/vampires/"+vampireID.String()+"/share_links", http.StatusSeeOther)
+	})
+}
+
+type shareLinkRevoker interface {
+	RevokeShareLink(context.Context, uuid.UUID, uuid.UUID) (models.ShareLink, error)
+}
+
+func DestroyShareLink(r chi.Router, l *zap.Logger, sr shareLinkRevoker) {
+	r.Delete("/vampires/{vampireID}/share_links/{id}", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		vampireID, err := uuid.Parse(chi.URLParam(r, "vampireID"))
+		if err != nil {
+			l.Error("failed to parse vampire id as UUID", zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		id, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			l.Error("failed to parse id as UUID", zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		_, err = sr.RevokeShareLink(r.Context(), vampireID, id)
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				err = NotFoundError.Cause(err)
+			}
+
+			l.Error("failed to revoke share link", zap.Stringer("vampireID", vampireID), zap.Stringer("id", id), zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		http.Redirect(w, r, "/vampires/"+vampireID.String()+"/share_links", http.StatusSeeOther)
+	})
+}
+
+type showSharedVampireRenderer interface {
+	ShowSharedVampire(http.ResponseWriter, *http.Request, models.Vampire) error
+}
+
+type sharedVampireGetter interface {
+	GetSharedVampire(context.Context, string) (models.Vampire, error)
+}
+
+func ShowSharedVampire(r chi.Router, l *zap.Logger, t showSharedVampireRenderer, sg sharedVampireGetter) {
+	r.Get("/shared/{token}", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		vampire, err := sg.GetSharedVampire(r.Context(), chi.URLParam(r, "token"))
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				err = NotFoundError.Cause(err)
+			}
+
+			// The token is a credential so it is deliberately not logged
+			l.Error("failed to find shared vampire", zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		err = t.ShowSharedVampire(w, r, vampire)
+		if err != nil {
+			l.Error("failed to render", zap.Error(err))
+			handleError(w, r, err)
+		}
+	})
+}
@@ -0,0 +1,498 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"emailaddress.horse/thousand/handlers"
+	"emailaddress.horse/thousand/models"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type mockShowShareLinksRenderer struct {
+	err error
+}
+
+func (m *mockShowShareLinksRenderer) ShowShareLinks(w http.ResponseWriter, _ *http.Request, vampire models.Vampire, links []models.ShareLink) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	body := vampire.Name
+	for _, link := range links {
+		body += " " + link.Token
+	}
+
+	_, err := w.Write([]byte(body))
+	if err != nil {
+		panic(err)
+	}
+
+	return nil
+}
+
+type mockShareLinksGetter struct {
+	links []models.ShareLink
+	err   error
+	id    uuid.UUID
+}
+
+func (m *mockShareLinksGetter) GetShareLinks(_ context.Context, id uuid.UUID) ([]models.ShareLink, error) {
+	m.id = id
+	return m.links, m.err
+}
+
+func TestListShareLinks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		renderer       *mockShowShareLinksRenderer
+		vampireGetter  *mockVampireGetter
+		linksGetter    *mockShareLinksGetter
+		path           string
+		expectedStatus int
+		expectedBody   string
+		expectedID     uuid.UUID
+	}{
+		{
+			name:     "successful",
+			renderer: &mockShowShareLinksRenderer{},
+			vampireGetter: &mockVampireGetter{
+				vampire: models.Vampire{
+					Name: "a vampire",
+				},
+			},
+			linksGetter: &mockShareLinksGetter{
+				links: []models.ShareLink{
+					{Token: "a-token"},
+				},
+			},
+			path:           "/vampires/11111111-1111-1111-1111-111111111111/share_links",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "a vampire a-token",
+			expectedID:     uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		},
+		{
+			name:           "error parsing vampire id",
+			renderer:       &mockShowShareLinksRenderer{},
+			vampireGetter:  &mockVampireGetter{},
+			linksGetter:    &mockShareLinksGetter{},
+			path:           "/vampires/unknown/share_links",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+		{
+			name:     "not found from vampire getter",
+			renderer: &mockShowShareLinksRenderer{},
+			vampireGetter: &mockVampireGetter{
+				err: models.ErrNotFound,
+			},
+			linksGetter:    &mockShareLinksGetter{},
+			path:           "/vampires/11111111-1111-1111-1111-111111111111/share_links",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404: Not Found",
+		},
+		{
+			name:          "error from links getter",
+			renderer:      &mockShowShareLinksRenderer{},
+			vampireGetter: &mockVampireGetter{},
+			linksGetter: &mockShareLinksGetter{
+				err: errors.New("mock error"),
+			},
+			path:           "/vampires/11111111-1111-1111-1111-111111111111/share_links",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+			expectedID:     uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		},
+		{
+			name: "error from renderer",
+			renderer: &mockShowShareLinksRenderer{
+				err: errors.New("mock error"),
+			},
+			vampireGetter:  &mockVampireGetter{},
+			linksGetter:    &mockShareLinksGetter{},
+			path:           "/vampires/11111111-1111-1111-1111-111111111111/share_links",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+			expectedID:     uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+
+			handlers.ListShareLinks(r, testLogger(t), tt.renderer, tt.vampireGetter, tt.linksGetter)
+
+			status, _, body := get(r, tt.path)
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+
+			if tt.expectedID != tt.linksGetter.id {
+				t.Errorf("expected %q; got %q", tt.expectedID, tt.linksGetter.id)
+			}
+		})
+	}
+}
+
+type mockShareLinkCreator struct {
+	vampireID uuid.UUID
+	expiresIn time.Duration
+	err       error
+}
+
+func (m *mockShareLinkCreator) CreateShareLink(_ context.Context, vampireID uuid.UUID, expiresIn time.Duration) (models.ShareLink, error) {
+	m.vampireID = vampireID
+	m.expiresIn = expiresIn
+	return models.ShareLink{}, m.err
+}
+
+func TestCreateShareLink(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		body              url.Values
+		creator           *mockShareLinkCreator
+		path              string
+		expectedStatus    int
+		expectedBody      string
+		expectedLocation  string
+		expectedVampireID uuid.UUID
+		expectedExpiresIn time.Duration
+	}{
+		{
+			name:              "successful without expiry",
+			body:              url.Values{},
+			creator:           &mockShareLinkCreator{},
+			path:              "/vampires/12345678-90ab-cdef-1234-567890abcdef/share_links",
+			expectedStatus:    http.StatusSeeOther,
+			expectedLocation:  "/vampires/12345678-90ab-cdef-1234-567890abcdef/share_links",
+			expectedVampireID: uuid.MustParse("12345678-90ab-cdef-1234-567890abcdef"),
+		},
+		{
+			name: "successful with expiry",
+			body: url.Values{
+				"expires_in": []string{"24h"},
+			},
+			creator:           &mockShareLinkCreator{},
+			path:              "/vampires/12345678-90ab-cdef-1234-567890abcdef/share_links",
+			expectedStatus:    http.StatusSeeOther,
+			expectedLocation:  "/vampires/12345678-90ab-cdef-1234-567890abcdef/share_links",
+			expectedVampireID: uuid.MustParse("12345678-90ab-cdef-1234-567890abcdef"),
+			expectedExpiresIn: 24 * time.Hour,
+		},
+		{
+			name: "invalid expiry",
+			body: url.Values{
+				"expires_in": []string{"soon"},
+			},
+			creator:        &mockShareLinkCreator{},
+			path:           "/vampires/12345678-90ab-cdef-1234-567890abcdef/share_links",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "400: Bad Request",
+		},
+		{
+			name: "negative expiry",
+			body: url.Values{
+				"expires_in": []string{"-24h"},
+			},
+			creator:        &mockShareLinkCreator{},
+			path:           "/vampires/12345678-90ab-cdef-1234-567890abcdef/share_links",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "400: Bad Request",
+		},
+		{
+			name: "expiry too long",
+			body: url.Values{
+				"expires_in": []string{"1000000h"},
+			},
+			creator:        &mockShareLinkCreator{},
+			path:           "/vampires/12345678-90ab-cdef-1234-567890abcdef/share_links",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "400: Bad Request",
+		},
+		{
+			name:           "error parsing vampire ID",
+			body:           url.Values{},
+			creator:        &mockShareLinkCreator{},
+			path:           "/vampires/unknown/share_links",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+		{
+			name: "not found from creator",
+			body: url.Values{},
+			creator: &mockShareLinkCreator{
+				err: models.ErrNotFound,
+			},
+			path:              "/vampires/12345678-90ab-cdef-1234-567890abcdef/share_links",
+			expectedStatus:    http.StatusNotFound,
+			expectedBody:      "404: Not Found",
+			expectedVampireID: uuid.MustParse("12345678-90ab-cdef-1234-567890abcdef"),
+		},
+		{
+			name: "error from creator",
+			body: url.Values{},
+			creator: &mockShareLinkCreator{
+				err: errors.New("mock error"),
+			},
+			path:              "/vampires/12345678-90ab-cdef-1234-567890abcdef/share_links",
+			expectedStatus:    http.StatusInternalServerError,
+			expectedBody:      "500: Internal Server Error",
+			expectedVampireID: uuid.MustParse("12345678-90ab-cdef-1234-567890abcdef"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+
+			handlers.CreateShareLink(r, testLogger(t), tt.creator)
+
+			status, headers, body := post(r, tt.path, tt.body.Encode())
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+
+			if tt.expectedVampireID != tt.creator.vampireID {
+				t.Errorf("expected creator to receive vampire ID %q; got %q", tt.expectedVampireID, tt.creator.vampireID)
+			}
+
+			if tt.expectedExpiresIn != tt.creator.expiresIn {
+				t.Errorf("expected creator to receive expiry %s; got %s", tt.expectedExpiresIn, tt.creator.expiresIn)
+			}
+
+			location := headers.Get("Location")
+			if tt.expectedLocation != location {
+				t.Errorf("expected location %q; got %q", tt.expectedLocation, location)
+			}
+		})
+	}
+}
+
+type mockShareLinkRevoker struct {
+	vampireID uuid.UUID
+	id        uuid.UUID
+	err       error
+}
+
+func (m *mockShareLinkRevoker) RevokeShareLink(_ context.Context, vampireID, id uuid.UUID) (models.ShareLink, error) {
+	m.vampireID = vampireID
+	m.id = id
+	return models.ShareLink{}, m.err
+}
+
+func TestDestroyShareLink(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		revoker           *mockShareLinkRevoker
+		path              string
+		expectedStatus    int
+		expectedBody      string
+		expectedLocation  string
+		expectedVampireID uuid.UUID
+		expectedID        uuid.UUID
+	}{
+		{
+			name:              "successful",
+			revoker:           &mockShareLinkRevoker{},
+			path:              "/vampires/11111111-1111-1111-1111-111111111111/share_links/22222222-2222-2222-2222-222222222222",
+			expectedStatus:    http.StatusSeeOther,
+			expectedLocation:  "/vampires/11111111-1111-1111-1111-111111111111/share_links",
+			expectedVampireID: uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+			expectedID:        uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+		},
+		{
+			name: "not found from revoker",
+			revoker: &mockShareLinkRevoker{
+				err: models.ErrNotFound,
+			},
+			path:              "/vampires/11111111-1111-1111-1111-111111111111/share_links/22222222-2222-2222-2222-222222222222",
+			expectedStatus:    http.StatusNotFound,
+			expectedBody:      "404: Not Found",
+			expectedVampireID: uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+			expectedID:        uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+		},
+		{
+			name:           "error parsing vampire id",
+			revoker:        &mockShareLinkRevoker{},
+			path:           "/vampires/unknown/share_links/22222222-2222-2222-2222-222222222222",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+		{
+			name:           "error parsing id",
+			revoker:        &mockShareLinkRevoker{},
+			path:           "/vampires/11111111-1111-1111-1111-111111111111/share_links/unknown",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+
+			handlers.DestroyShareLink(r, testLogger(t), tt.revoker)
+
+			status, headers, body := deleteRequest(r, tt.path)
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if tt.expectedLocation == "" && tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+
+			location := headers.Get("Location")
+			if tt.expectedLocation != location {
+				t.Errorf("expected location %q; got %q", tt.expectedLocation, location)
+			}
+
+			if tt.expectedVampireID != tt.revoker.vampireID {
+				t.Errorf("expected vampire ID %q; got %q", tt.expectedVampireID, tt.revoker.vampireID)
+			}
+
+			if tt.expectedID != tt.revoker.id {
+				t.Errorf("expected %q; got %q", tt.expectedID, tt.revoker.id)
+			}
+		})
+	}
+}
+
+type mockShowSharedVampireRenderer struct {
+	err error
+}
+
+func (m *mockShowSharedVampireRenderer) ShowSharedVampire(w http.ResponseWriter, _ *http.Request, vampire models.Vampire) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	_, err := w.Write([]byte(vampire.Name))
+	if err != nil {
+		panic(err)
+	}
+
+	return nil
+}
+
+type mockSharedVampireGetter struct {
+	vampire models.Vampire
+	err     error
+	token   string
+}
+
+func (m *mockSharedVampireGetter) GetSharedVampire(_ context.Context, token string) (models.Vampire, error) {
+	m.token = token
+	return m.vampire, m.err
+}
+
+func TestShowSharedVampire(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		renderer       *mockShowSharedVampireRenderer
+		getter         *mockSharedVampireGetter
+		path           string
+		expectedStatus int
+		expectedBody   string
+		expectedToken  string
+	}{
+		{
+			name:     "successful",
+			renderer: &mockShowSharedVampireRenderer{},
+			getter: &mockSharedVampireGetter{
+				vampire: models.Vampire{
+					Name: "a vampire",
+				},
+			},
+			path:           "/shared/a-token",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "a vampire",
+			expectedToken:  "a-token",
+		},
+		{
+			name:     "not found from getter",
+			renderer: &mockShowSharedVampireRenderer{},
+			getter: &mockSharedVampireGetter{
+				err: models.ErrNotFound,
+			},
+			path:           "/shared/a-token",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404: Not Found",
+			expectedToken:  "a-token",
+		},
+		{
+			name: "error from renderer",
+			renderer: &mockShowSharedVampireRenderer{
+				err: errors.New("mock error"),
+			},
+			getter:         &mockSharedVampireGetter{},
+			path:           "/shared/a-token",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+			expectedToken:  "a-token",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+
+			handlers.ShowSharedVampire(r, testLogger(t), tt.renderer, tt.getter)
+
+			status, _, body := get(r, tt.path)
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+
+			if tt.expectedToken != tt.getter.token {
+				t.Errorf("expected token %q; got %q", tt.expectedToken, tt.getter.token)
+			}
+		})
+	}
+}
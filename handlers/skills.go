@@ -4,6 +4,8 @@ import (
 	"errors"
 	"net/http"
 
+	"emailaddress.horse/thousand/form"
+	"emailaddress.horse/thousand/middleware"
 	"emailaddress.horse/thousand/models"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -11,15 +13,17 @@ import (
 )
 
 type newSkillRenderer interface {
-	NewSkill(http.ResponseWriter, *http.Request, models.Vampire) error
+	NewSkill(http.ResponseWriter, *http.Request, models.Vampire, *form.NewSkillForm) error
 }
 
 func NewSkill(r chi.Router, l *zap.Logger, t newSkillRenderer, vg vampireGetter) {
 	r.Get("/vampires/{vampireID}/skills/new", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
 		vampireID, err := uuid.Parse(chi.URLParam(r, "vampireID"))
 		if err != nil {
 			l.Error("failed to parse id as UUID", zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 			return
 		}
 
@@ -30,37 +34,79 @@ func NewSkill(r chi.Router, l *zap.Logger, t newSkillRenderer, vg vampireGetter)
 			}
 
 			l.Error("failed to find vampire", zap.Stringer("vampireID", vampireID), zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 			return
 		}
 
-		err = t.NewSkill(w, r, vampire)
+		err = t.NewSkill(w, r, vampire, form.NewSkill(""))
 		if err != nil {
 			l.Error("failed to render", zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 		}
 	})
 }
 
-func CreateSkill(r chi.Router, l *zap.Logger, sc skillCreator) {
+type createSkillRenderer interface {
+	NewSkill(http.ResponseWriter, *http.Request, models.Vampire, *form.NewSkillForm) error
+	CreateSkill(http.ResponseWriter, *http.Request, uuid.UUID, models.Skill) error
+}
+
+func CreateSkill(r chi.Router, l *zap.Logger, t createSkillRenderer, vg vampireGetter, sc skillCreator, b skillBroadcaster) {
 	r.Post("/vampires/{vampireID}/skills", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
 		vampireID, err := uuid.Parse(chi.URLParam(r, "vampireID"))
 		if err != nil {
 			l.Error("failed to parse id as UUID", zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 			return
 		}
 
-		description := r.FormValue("description")
+		form := form.NewSkill(r.FormValue("description"))
+		if !form.Valid() {
+			vampire, err := vg.GetVampire(r.Context(), vampireID)
+			if err != nil {
+				if errors.Is(err, models.ErrNotFound) {
+					err = NotFoundError.Cause(err)
+				}
 
-		_, err = sc.CreateSkill(r.Context(), vampireID, description)
+				l.Error("failed to find vampire", zap.Stringer("vampireID", vampireID), zap.Error(err))
+				handleError(w, r, err)
+				return
+			}
+
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			err = t.NewSkill(w, r, vampire, form)
+			if err != nil {
+				l.Error("failed to render", zap.Error(err))
+				handleError(w, r, err)
+			}
+			return
+		}
+
+		description := form.Description.Value
+
+		skill, err := sc.CreateSkill(r.Context(), vampireID, description)
 		if err != nil {
 			if errors.Is(err, models.ErrNotFound) {
 				err = NotFoundError.Cause(err)
 			}
 
 			l.Error("failed to create experience", zap.Stringer("vampireID", vampireID), zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
+			return
+		}
+
+		if err := b.SkillCreated(r.Context(), vampireID, skill); err != nil {
+			l.Error("failed to broadcast skill", zap.Stringer("vampireID", vampireID), zap.Error(err))
+		}
+
+		if acceptsTurboStream(r) {
+			err = t.CreateSkill(w, r, vampireID, skill)
+			if err != nil {
+				l.Error("failed to render", zap.Error(err))
+				handleError(w, r, err)
+			}
 			return
 		}
 
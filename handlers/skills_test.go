@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"testing"
 
+	"emailaddress.horse/thousand/form"
 	"emailaddress.horse/thousand/handlers"
 	"emailaddress.horse/thousand/models"
 	"github.com/go-chi/chi/v5"
@@ -17,7 +18,7 @@ type mockNewSkillRenderer struct {
 	err error
 }
 
-func (m *mockNewSkillRenderer) NewSkill(w http.ResponseWriter, _ *http.Request, vampire models.Vampire) error {
+func (m *mockNewSkillRenderer) NewSkill(w http.ResponseWriter, _ *http.Request, vampire models.Vampire, _ *form.NewSkillForm) error {
 	if m.err != nil {
 		return m.err
 	}
@@ -136,15 +137,46 @@ func (m *mockSkillCreator) CreateSkill(_ context.Context, vampireID uuid.UUID, d
 	return models.Skill{}, m.err
 }
 
+type mockCreateSkillRenderer struct {
+	err error
+}
+
+func (m *mockCreateSkillRenderer) NewSkill(w http.ResponseWriter, _ *http.Request, _ models.Vampire, f *form.NewSkillForm) error {
+	_, err := w.Write([]byte(f.Description.Message))
+	if err != nil {
+		panic(err)
+	}
+
+	return nil
+}
+
+func (m *mockCreateSkillRenderer) CreateSkill(w http.ResponseWriter, _ *http.Request, vampireID uuid.UUID, _ models.Skill) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	_, err := w.Write([]byte(vampireID.String()))
+	if err != nil {
+		panic(err)
+	}
+
+	return nil
+}
+
 func TestCreateSkill(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
 		name                string
 		body                url.Values
+		accept              string
+		renderer            *mockCreateSkillRenderer
+		getter              *mockVampireGetter
 		creator             *mockSkillCreator
+		broadcaster         *mockBroadcaster
 		path                string
 		expectedStatus      int
+		expectedBroadcasts  int
 		expectedBody        string
 		expectedLocation    string
 		expectedVampireID   uuid.UUID
@@ -153,9 +185,13 @@ func TestCreateSkill(t *testing.T) {
 		{
 			name:                "successful",
 			body:                url.Values{"description": []string{"A description"}},
+			renderer:            &mockCreateSkillRenderer{},
+			getter:              &mockVampireGetter{},
 			creator:             &mockSkillCreator{},
+			broadcaster:         &mockBroadcaster{},
 			path:                "/vampires/12345678-90ab-cdef-1234-567890abcdef/skills",
 			expectedStatus:      http.StatusSeeOther,
+			expectedBroadcasts:  1,
 			expectedVampireID:   uuid.MustParse("12345678-90ab-cdef-1234-567890abcdef"),
 			expectedDescription: "A description",
 			expectedLocation:    "/vampires/12345678-90ab-cdef-1234-567890abcdef",
@@ -163,17 +199,23 @@ func TestCreateSkill(t *testing.T) {
 		{
 			name:           "error parsing vampire ID",
 			body:           url.Values{"description": []string{"A description"}},
+			renderer:       &mockCreateSkillRenderer{},
+			getter:         &mockVampireGetter{},
 			creator:        &mockSkillCreator{},
+			broadcaster:    &mockBroadcaster{},
 			path:           "/vampires/unknown/skills",
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody:   "500: Internal Server Error",
 		},
 		{
-			name: "not found from creator",
-			body: url.Values{"description": []string{"A description"}},
+			name:     "not found from creator",
+			body:     url.Values{"description": []string{"A description"}},
+			renderer: &mockCreateSkillRenderer{},
+			getter:   &mockVampireGetter{},
 			creator: &mockSkillCreator{
 				err: models.ErrNotFound,
 			},
+			broadcaster:         &mockBroadcaster{},
 			path:                "/vampires/12345678-90ab-cdef-1234-567890abcdef/skills",
 			expectedStatus:      http.StatusNotFound,
 			expectedBody:        "404: Not Found",
@@ -181,17 +223,86 @@ func TestCreateSkill(t *testing.T) {
 			expectedDescription: "A description",
 		},
 		{
-			name: "error from creator",
-			body: url.Values{"description": []string{"A description"}},
+			name:     "error from creator",
+			body:     url.Values{"description": []string{"A description"}},
+			renderer: &mockCreateSkillRenderer{},
+			getter:   &mockVampireGetter{},
 			creator: &mockSkillCreator{
 				err: errors.New("mock error"),
 			},
+			broadcaster:         &mockBroadcaster{},
 			path:                "/vampires/12345678-90ab-cdef-1234-567890abcdef/skills",
 			expectedStatus:      http.StatusInternalServerError,
 			expectedBody:        "500: Internal Server Error",
 			expectedVampireID:   uuid.MustParse("12345678-90ab-cdef-1234-567890abcdef"),
 			expectedDescription: "A description",
 		},
+		{
+			name:                "error from broadcaster",
+			body:                url.Values{"description": []string{"A description"}},
+			renderer:            &mockCreateSkillRenderer{},
+			getter:              &mockVampireGetter{},
+			creator:             &mockSkillCreator{},
+			broadcaster:         &mockBroadcaster{err: errors.New("mock error")},
+			path:                "/vampires/12345678-90ab-cdef-1234-567890abcdef/skills",
+			expectedStatus:      http.StatusSeeOther,
+			expectedBroadcasts:  1,
+			expectedVampireID:   uuid.MustParse("12345678-90ab-cdef-1234-567890abcdef"),
+			expectedDescription: "A description",
+			expectedLocation:    "/vampires/12345678-90ab-cdef-1234-567890abcdef",
+		},
+		{
+			name:                "successful turbo stream",
+			accept:              "text/vnd.turbo-stream.html",
+			body:                url.Values{"description": []string{"A description"}},
+			renderer:            &mockCreateSkillRenderer{},
+			getter:              &mockVampireGetter{},
+			creator:             &mockSkillCreator{},
+			broadcaster:         &mockBroadcaster{},
+			path:                "/vampires/12345678-90ab-cdef-1234-567890abcdef/skills",
+			expectedStatus:      http.StatusOK,
+			expectedBroadcasts:  1,
+			expectedVampireID:   uuid.MustParse("12345678-90ab-cdef-1234-567890abcdef"),
+			expectedDescription: "A description",
+			expectedBody:        "12345678-90ab-cdef-1234-567890abcdef",
+		},
+		{
+			name:                "error from renderer",
+			accept:              "text/vnd.turbo-stream.html",
+			body:                url.Values{"description": []string{"A description"}},
+			renderer:            &mockCreateSkillRenderer{err: errors.New("mock error")},
+			getter:              &mockVampireGetter{},
+			creator:             &mockSkillCreator{},
+			broadcaster:         &mockBroadcaster{},
+			path:                "/vampires/12345678-90ab-cdef-1234-567890abcdef/skills",
+			expectedStatus:      http.StatusInternalServerError,
+			expectedBroadcasts:  1,
+			expectedVampireID:   uuid.MustParse("12345678-90ab-cdef-1234-567890abcdef"),
+			expectedDescription: "A description",
+			expectedBody:        "500: Internal Server Error",
+		},
+		{
+			name:           "invalid form",
+			body:           url.Values{"description": []string{""}},
+			renderer:       &mockCreateSkillRenderer{},
+			getter:         &mockVampireGetter{},
+			creator:        &mockSkillCreator{},
+			broadcaster:    &mockBroadcaster{},
+			path:           "/vampires/12345678-90ab-cdef-1234-567890abcdef/skills",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   "Please provide a description.",
+		},
+		{
+			name:           "not found from getter with invalid form",
+			body:           url.Values{"description": []string{""}},
+			renderer:       &mockCreateSkillRenderer{},
+			getter:         &mockVampireGetter{err: models.ErrNotFound},
+			creator:        &mockSkillCreator{},
+			broadcaster:    &mockBroadcaster{},
+			path:           "/vampires/12345678-90ab-cdef-1234-567890abcdef/skills",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404: Not Found",
+		},
 	}
 
 	for _, tt := range tests {
@@ -202,9 +313,12 @@ func TestCreateSkill(t *testing.T) {
 
 			r := chi.NewMux()
 
-			handlers.CreateSkill(r, testLogger(t), tt.creator)
+			handlers.CreateSkill(r, testLogger(t), tt.renderer, tt.getter, tt.creator, tt.broadcaster)
 
-			status, headers, body := post(r, tt.path, tt.body.Encode())
+			req := postRequest(tt.path, tt.body.Encode())
+			req.request.Header.Set("Accept", tt.accept)
+
+			status, headers, body := req.perform(r)
 
 			if tt.expectedStatus != status {
 				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
@@ -226,6 +340,10 @@ func TestCreateSkill(t *testing.T) {
 			if tt.expectedDescription != tt.creator.description {
 				t.Errorf("expected %q; got %q", tt.expectedDescription, tt.creator.description)
 			}
+
+			if tt.expectedBroadcasts != tt.broadcaster.count {
+				t.Errorf("expected %d broadcasts; got %d", tt.expectedBroadcasts, tt.broadcaster.count)
+			}
 		})
 	}
 }
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"emailaddress.horse/thousand/middleware"
+	"emailaddress.horse/thousand/streams"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// keepAliveInterval is how often a comment is sent down an otherwise idle
+// stream so that proxies don't close the connection.
+const keepAliveInterval = 30 * time.Second
+
+type streamSubscriber interface {
+	Subscribe(string, string) (<-chan string, func())
+}
+
+func StreamVampire(r chi.Router, l *zap.Logger, s streamSubscriber) {
+	r.Get("/vampires/{vampireID}/stream", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		vampireID, err := uuid.Parse(chi.URLParam(r, "vampireID"))
+		if err != nil {
+			l.Error("failed to parse vampire id as UUID", zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			l.Error("response writer does not support streaming")
+			handleError(w, r, InternalServerError)
+			return
+		}
+
+		user := middleware.CurrentUser(r.Context())
+
+		// The stream ends when the subscription is closed, such as when the user
+		// loses access to the vampire
+		messages, unsubscribe := s.Subscribe(streams.VampireTopic(vampireID), streams.SubscriberKey(user.ID))
+		defer unsubscribe()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		keepAlive := time.NewTicker(keepAliveInterval)
+		defer keepAlive.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-keepAlive.C:
+				fmt.Fprint(w, ": keep-alive\n\n")
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				writeEvent(w, message)
+			}
+
+			flusher.Flush()
+		}
+	})
+}
+
+// writeEvent writes the data as a single server-sent event, splitting it over
+// as many data fields as it has lines.
+func writeEvent(w http.ResponseWriter, data string) {
+	for _, line := range strings.Split(data, "\n") {
+		fmt.Fprintf(w, "data: %s\n", line)
+	}
+	fmt.Fprint(w, "\n")
+}
@@ -0,0 +1,204 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"emailaddress.horse/thousand/handlers"
+	"emailaddress.horse/thousand/middleware"
+	"emailaddress.horse/thousand/models"
+	"emailaddress.horse/thousand/streams"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type mockBroadcaster struct {
+	vampireID uuid.UUID
+	userID    uuid.UUID
+	count     int
+	err       error
+}
+
+func (m *mockBroadcaster) broadcast(vampireID uuid.UUID) error {
+	m.vampireID = vampireID
+	m.count++
+	return m.err
+}
+
+func (m *mockBroadcaster) CharacterCreated(_ context.Context, vampireID uuid.UUID, _ models.Character) error {
+	return m.broadcast(vampireID)
+}
+
+func (m *mockBroadcaster) ExperienceCreated(_ context.Context, vampireID uuid.UUID, _ models.Experience) error {
+	return m.broadcast(vampireID)
+}
+
+func (m *mockBroadcaster) MarkCreated(_ context.Context, vampireID uuid.UUID, _ models.Mark) error {
+	return m.broadcast(vampireID)
+}
+
+func (m *mockBroadcaster) ResourceCreated(_ context.Context, vampireID uuid.UUID, _ models.Resource) error {
+	return m.broadcast(vampireID)
+}
+
+func (m *mockBroadcaster) SkillCreated(_ context.Context, vampireID uuid.UUID, _ models.Skill) error {
+	return m.broadcast(vampireID)
+}
+
+func (m *mockBroadcaster) VampireTrashed(_ context.Context, vampireID uuid.UUID) error {
+	return m.broadcast(vampireID)
+}
+
+func (m *mockBroadcaster) MemberRemoved(_ context.Context, vampireID, userID uuid.UUID) error {
+	m.userID = userID
+	return m.broadcast(vampireID)
+}
+
+type mockStreamSubscriber struct {
+	topic        string
+	key          string
+	messages     []string
+	unsubscribed bool
+}
+
+func (m *mockStreamSubscriber) Subscribe(topic, key string) (<-chan string, func()) {
+	m.topic = topic
+	m.key = key
+
+	ch := make(chan string, len(m.messages))
+	for _, message := range m.messages {
+		ch <- message
+	}
+	close(ch)
+
+	return ch, func() { m.unsubscribed = true }
+}
+
+func TestStreamVampire(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+
+	tests := []struct {
+		name                string
+		subscriber          *mockStreamSubscriber
+		path                string
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+		expectedTopic       string
+	}{
+		{
+			name: "successful",
+			subscriber: &mockStreamSubscriber{
+				messages: []string{
+					"<turbo-stream>one</turbo-stream>",
+					"<turbo-stream>\ntwo\n</turbo-stream>",
+				},
+			},
+			path:                "/vampires/12345678-90ab-cdef-1234-567890abcdef/stream",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/event-stream",
+			expectedBody:        "data: <turbo-stream>one</turbo-stream>\n\ndata: <turbo-stream>\ndata: two\ndata: </turbo-stream>",
+			expectedTopic:       "vampire:12345678-90ab-cdef-1234-567890abcdef",
+		},
+		{
+			name:                "no messages",
+			subscriber:          &mockStreamSubscriber{},
+			path:                "/vampires/12345678-90ab-cdef-1234-567890abcdef/stream",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/event-stream",
+			expectedBody:        "",
+			expectedTopic:       "vampire:12345678-90ab-cdef-1234-567890abcdef",
+		},
+		{
+			name:                "error parsing vampire id",
+			subscriber:          &mockStreamSubscriber{},
+			path:                "/vampires/unknown/stream",
+			expectedStatus:      http.StatusInternalServerError,
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        "500: Internal Server Error",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+
+			handlers.StreamVampire(r, testLogger(t), tt.subscriber)
+
+			req := newRequest(http.MethodGet, tt.path)
+			req.request = middleware.RequestWithCurrentUser(req.request, models.User{ID: userID})
+
+			status, headers, body := req.perform(r)
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if contentType := headers.Get("Content-Type"); tt.expectedContentType != contentType {
+				t.Errorf("expected content type %q; got %q", tt.expectedContentType, contentType)
+			}
+
+			if tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+
+			if tt.expectedTopic != tt.subscriber.topic {
+				t.Errorf("expected topic %q; got %q", tt.expectedTopic, tt.subscriber.topic)
+			}
+
+			if tt.expectedTopic != "" && !tt.subscriber.unsubscribed {
+				t.Error("expected to unsubscribe")
+			}
+
+			// Subscribing with the user lets their stream be closed if they lose
+			// access to the vampire
+			if tt.expectedTopic != "" && tt.subscriber.key != streams.SubscriberKey(userID) {
+				t.Errorf("expected key %q; got %q", streams.SubscriberKey(userID), tt.subscriber.key)
+			}
+		})
+	}
+}
+
+func TestStreamVampire_RequestLogger(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.InfoLevel)
+
+	r := chi.NewMux()
+	middleware.RequestID(r)
+	middleware.ContextLogger(r, zap.New(core))
+
+	handlers.StreamVampire(r, testLogger(t), &mockStreamSubscriber{})
+
+	req := newRequest(http.MethodGet, "/vampires/not-a-uuid/stream")
+	req.request = middleware.RequestWithCurrentUser(req.request, models.User{})
+	req.request.Header.Set("X-Request-ID", "abc123")
+
+	status, header, _ := req.perform(r)
+
+	if status != http.StatusInternalServerError {
+		t.Errorf("expected status %d; got %d", http.StatusInternalServerError, status)
+	}
+
+	if requestID := header.Get(middleware.RequestIDHeader); requestID != "abc123" {
+		t.Errorf("expected request ID %q; got %q", "abc123", requestID)
+	}
+
+	entries := logs.FilterField(zap.String("requestID", "abc123")).All()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 log entry with the request ID; got %d", len(entries))
+	}
+
+	if entries[0].Message != "failed to parse vampire id as UUID" {
+		t.Errorf("expected error to be logged; got %q", entries[0].Message)
+	}
+}
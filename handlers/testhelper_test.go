@@ -1,12 +1,15 @@
 package handlers_test
 
 import (
+	"context"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
+	"emailaddress.horse/thousand/models"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -45,6 +48,13 @@ func (r testRequest) perform(handler http.Handler) (int, http.Header, string) {
 	return result.StatusCode, result.Header, strings.TrimSpace(string(body))
 }
 
+func newRequest(method, path string) *testRequest {
+	return &testRequest{
+		request:  httptest.NewRequest(method, path, nil),
+		response: httptest.NewRecorder(),
+	}
+}
+
 func postRequest(path, data string) *testRequest {
 	request := httptest.NewRequest(http.MethodPost, path, strings.NewReader(data))
 	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
@@ -101,3 +111,54 @@ func deleteRequest(handler http.Handler, path string) (int, http.Header, string)
 
 	return result.StatusCode, result.Header, strings.TrimSpace(string(body))
 }
+
+// mockMetrics records what was counted, in order, as strings such as
+// "login password success".
+type mockMetrics struct {
+	counted []string
+}
+
+func (m *mockMetrics) VampireCreated() {
+	m.counted = append(m.counted, "vampire created")
+}
+
+func (m *mockMetrics) ExperienceWritten() {
+	m.counted = append(m.counted, "experience written")
+}
+
+func (m *mockMetrics) MemoryFilled() {
+	m.counted = append(m.counted, "memory filled")
+}
+
+func (m *mockMetrics) SignUp(method string, ok bool) {
+	m.counted = append(m.counted, fmt.Sprintf("sign up %s %s", method, outcome(ok)))
+}
+
+func (m *mockMetrics) Login(method string, ok bool) {
+	m.counted = append(m.counted, fmt.Sprintf("login %s %s", method, outcome(ok)))
+}
+
+func outcome(ok bool) string {
+	if ok {
+		return "success"
+	}
+
+	return "failure"
+}
+
+// mockAuditRecorder records the actions of audit events, in order, along with
+// the email address of the actor if there is one.
+type mockAuditRecorder struct {
+	recorded []string
+	err      error
+}
+
+func (m *mockAuditRecorder) RecordAuditEvent(_ context.Context, event models.AuditEvent) error {
+	recorded := string(event.Action)
+	if event.ActorEmail != "" {
+		recorded += " " + event.ActorEmail
+	}
+
+	m.recorded = append(m.recorded, recorded)
+	return m.err
+}
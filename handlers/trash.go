@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"emailaddress.horse/thousand/middleware"
+	"emailaddress.horse/thousand/models"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type showTrashRenderer interface {
+	ShowTrash(http.ResponseWriter, *http.Request, []models.TrashedVampire) error
+}
+
+type trashedVampiresGetter interface {
+	GetTrashedVampires(context.Context, uuid.UUID) ([]models.TrashedVampire, error)
+}
+
+func ShowTrash(r chi.Router, l *zap.Logger, t showTrashRenderer, tg trashedVampiresGetter) {
+	r.Get("/trash", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		user := middleware.CurrentUser(r.Context())
+
+		vampires, err := tg.GetTrashedVampires(r.Context(), user.ID)
+		if err != nil {
+			l.Error("failed to load trashed vampires", zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		err = t.ShowTrash(w, r, vampires)
+		if err != nil {
+			l.Error("failed to render", zap.Error(err))
+			handleError(w, r, err)
+		}
+	})
+}
+
+type trashedVampireRestorer interface {
+	RestoreTrashedVampire(context.Context, uuid.UUID, uuid.UUID) error
+}
+
+func RestoreVampire(r chi.Router, l *zap.Logger, vr trashedVampireRestorer) {
+	r.Post("/trash/{vampireID}/restore", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		user := middleware.CurrentUser(r.Context())
+
+		id, err := uuid.Parse(chi.URLParam(r, "vampireID"))
+		if err != nil {
+			l.Error("failed to parse id as UUID", zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		err = vr.RestoreTrashedVampire(r.Context(), user.ID, id)
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				err = NotFoundError.Cause(err)
+			}
+
+			l.Error("failed to restore vampire", zap.Stringer("id", id), zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		http.Redirect(w, r, "/vampires/"+id.String(), http.StatusSeeOther)
+	})
+}
@@ -0,0 +1,214 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"emailaddress.horse/thousand/handlers"
+	"emailaddress.horse/thousand/middleware"
+	"emailaddress.horse/thousand/models"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type mockShowTrashRenderer struct {
+	err error
+}
+
+func (m *mockShowTrashRenderer) ShowTrash(w http.ResponseWriter, _ *http.Request, vampires []models.TrashedVampire) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	body := "trash"
+	for _, vampire := range vampires {
+		body += " " + vampire.Name
+	}
+
+	_, err := w.Write([]byte(body))
+	if err != nil {
+		panic(err)
+	}
+
+	return nil
+}
+
+type mockTrashedVampiresGetter struct {
+	vampires []models.TrashedVampire
+	userID   uuid.UUID
+	err      error
+}
+
+func (m *mockTrashedVampiresGetter) GetTrashedVampires(_ context.Context, userID uuid.UUID) ([]models.TrashedVampire, error) {
+	m.userID = userID
+	return m.vampires, m.err
+}
+
+func TestShowTrash(t *testing.T) {
+	t.Parallel()
+
+	user := models.User{
+		ID:    uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		Email: "jane@bannister.com",
+	}
+
+	tests := []struct {
+		name           string
+		renderer       *mockShowTrashRenderer
+		getter         *mockTrashedVampiresGetter
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:     "successful",
+			renderer: &mockShowTrashRenderer{},
+			getter: &mockTrashedVampiresGetter{
+				vampires: []models.TrashedVampire{
+					{Vampire: models.Vampire{Name: "Gruffudd"}},
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "trash Gruffudd",
+		},
+		{
+			name:     "error from getter",
+			renderer: &mockShowTrashRenderer{},
+			getter: &mockTrashedVampiresGetter{
+				err: errors.New("mock error"),
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+		{
+			name: "error from renderer",
+			renderer: &mockShowTrashRenderer{
+				err: errors.New("mock error"),
+			},
+			getter:         &mockTrashedVampiresGetter{},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+
+			handlers.ShowTrash(r, testLogger(t), tt.renderer, tt.getter)
+
+			req := newRequest(http.MethodGet, "/trash")
+			req.request = middleware.RequestWithCurrentUser(req.request, user)
+
+			status, _, body := req.perform(r)
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+
+			if user.ID != tt.getter.userID {
+				t.Errorf("expected user %q; got %q", user.ID, tt.getter.userID)
+			}
+		})
+	}
+}
+
+type mockTrashedVampireRestorer struct {
+	userID uuid.UUID
+	id     uuid.UUID
+	err    error
+}
+
+func (m *mockTrashedVampireRestorer) RestoreTrashedVampire(_ context.Context, userID, id uuid.UUID) error {
+	m.userID = userID
+	m.id = id
+	return m.err
+}
+
+func TestRestoreVampire(t *testing.T) {
+	t.Parallel()
+
+	user := models.User{
+		ID:    uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		Email: "jane@bannister.com",
+	}
+
+	tests := []struct {
+		name             string
+		restorer         *mockTrashedVampireRestorer
+		path             string
+		expectedStatus   int
+		expectedBody     string
+		expectedLocation string
+		expectedID       uuid.UUID
+	}{
+		{
+			name:             "successful",
+			restorer:         &mockTrashedVampireRestorer{},
+			path:             "/trash/22222222-2222-2222-2222-222222222222/restore",
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "/vampires/22222222-2222-2222-2222-222222222222",
+			expectedID:       uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+		},
+		{
+			name: "not found from restorer",
+			restorer: &mockTrashedVampireRestorer{
+				err: models.ErrNotFound,
+			},
+			path:           "/trash/22222222-2222-2222-2222-222222222222/restore",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404: Not Found",
+			expectedID:     uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+		},
+		{
+			name:           "error parsing id",
+			restorer:       &mockTrashedVampireRestorer{},
+			path:           "/trash/unknown/restore",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+
+			handlers.RestoreVampire(r, testLogger(t), tt.restorer)
+
+			req := postRequest(tt.path, "")
+			req.request = middleware.RequestWithCurrentUser(req.request, user)
+
+			status, headers, body := req.perform(r)
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if tt.expectedLocation == "" && tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+
+			location := headers.Get("Location")
+			if tt.expectedLocation != location {
+				t.Errorf("expected location %q; got %q", tt.expectedLocation, location)
+			}
+
+			if tt.expectedID != tt.restorer.id {
+				t.Errorf("expected %q; got %q", tt.expectedID, tt.restorer.id)
+			}
+		})
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"net/http"
 
 	"emailaddress.horse/thousand/form"
+	"emailaddress.horse/thousand/middleware"
 	"emailaddress.horse/thousand/models"
+	"emailaddress.horse/thousand/registry"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -18,10 +20,12 @@ type newUserRenderer interface {
 
 func NewUser(r chi.Router, l *zap.Logger, t newUserRenderer) {
 	r.Get("/user/new", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
 		err := t.NewUser(w, r, form.NewUser("", ""))
 		if err != nil {
 			l.Error("failed to render", zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 		}
 	})
 }
@@ -35,49 +39,60 @@ type sessionSetter interface {
 	SetFlash(*http.Request, http.ResponseWriter, string) error
 }
 
-func CreateUser(r chi.Router, l *zap.Logger, uc userCreator, t newUserRenderer, s sessionSetter) {
+func CreateUser(r chi.Router, l *zap.Logger, uc userCreator, t newUserRenderer, s sessionSetter, m signUpMetrics, ar auditRecorder) {
 	r.Post("/user", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
 		form := form.NewUser(
 			r.FormValue("email"),
 			r.FormValue("password"),
 		)
 
 		if !form.Valid() {
+			m.SignUp(registry.AuthPassword, false)
+
 			w.WriteHeader(http.StatusUnprocessableEntity)
 			err := t.NewUser(w, r, form)
 			if err != nil {
 				l.Error("failed to render", zap.Error(err))
-				handleError(w, err)
+				handleError(w, r, err)
 			}
 			return
 		}
 
 		user, err := uc.CreateUser(r.Context(), form)
 		if errors.Is(err, models.ErrEmailAlreadyInUse) {
+			m.SignUp(registry.AuthPassword, false)
+
 			form.Email.Message = "Email already in use."
 
 			w.WriteHeader(http.StatusUnprocessableEntity)
 			err := t.NewUser(w, r, form)
 			if err != nil {
 				l.Error("failed to render", zap.Error(err))
-				handleError(w, err)
+				handleError(w, r, err)
 			}
 			return
 		} else if err != nil {
+			m.SignUp(registry.AuthPassword, false)
+
 			l.Error("failed to create user", zap.Object("params", form), zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 			return
 		}
 
+		m.SignUp(registry.AuthPassword, true)
+		recordAuditEvent(r, l, ar, userAuditEvent(models.AuditActionSignUp, user))
+
 		if err := s.SetCurrentUserID(r, w, user.ID); err != nil {
 			l.Error("failed to set new user id in session", zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 			return
 		}
 
 		if err := s.SetFlash(r, w, "Thank you for signing up!"); err != nil {
 			l.Error("failed to set flash", zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 			return
 		}
 
@@ -12,6 +12,7 @@ import (
 	"emailaddress.horse/thousand/handlers"
 	"emailaddress.horse/thousand/models"
 	"github.com/go-chi/chi/v5"
+	"github.com/google/go-cmp/cmp"
 	"github.com/google/uuid"
 )
 
@@ -128,6 +129,8 @@ func TestCreateUser(t *testing.T) {
 		expectedStatus   int
 		expectedBody     string
 		expectedLocation string
+		expectedMetrics  []string
+		expectedAudit    []string
 	}{
 		{
 			name: "successful",
@@ -135,22 +138,27 @@ func TestCreateUser(t *testing.T) {
 				"email":    []string{"john@bannister.com"},
 				"password": []string{"password"},
 			},
-			creator:          &mockUserCreator{},
+			creator: &mockUserCreator{
+				user: models.User{ID: uuid.New(), Email: "john@bannister.com"},
+			},
 			renderer:         &mockNewUserRenderer{},
 			setter:           &mockSetter{&mockCurrentUserIDSetter{}, &mockFlashSetter{}},
 			expectedStatus:   http.StatusSeeOther,
 			expectedLocation: "/",
+			expectedMetrics:  []string{"sign up password success"},
+			expectedAudit:    []string{"user.sign_up john@bannister.com"},
 		},
 		{
 			name: "form invalid",
 			body: url.Values{
 				"email": []string{"john@bannister.com"},
 			},
-			creator:        &mockUserCreator{},
-			renderer:       &mockNewUserRenderer{},
-			setter:         &mockSetter{&mockCurrentUserIDSetter{}, &mockFlashSetter{}},
-			expectedStatus: http.StatusUnprocessableEntity,
-			expectedBody:   `{"Email":{"Message":"","Value":"john@bannister.com"},"Password":{"Message":"Please provide a password.","Value":""}}`,
+			creator:         &mockUserCreator{},
+			renderer:        &mockNewUserRenderer{},
+			setter:          &mockSetter{&mockCurrentUserIDSetter{}, &mockFlashSetter{}},
+			expectedStatus:  http.StatusUnprocessableEntity,
+			expectedBody:    `{"Email":{"Message":"","Value":"john@bannister.com"},"Password":{"Message":"Please provide a password.","Value":""}}`,
+			expectedMetrics: []string{"sign up password failure"},
 		},
 		{
 			name: "email already in use from creator",
@@ -161,10 +169,11 @@ func TestCreateUser(t *testing.T) {
 			creator: &mockUserCreator{
 				err: models.ErrEmailAlreadyInUse,
 			},
-			renderer:       &mockNewUserRenderer{},
-			setter:         &mockSetter{&mockCurrentUserIDSetter{}, &mockFlashSetter{}},
-			expectedStatus: http.StatusUnprocessableEntity,
-			expectedBody:   `{"Email":{"Message":"Email already in use.","Value":"john@bannister.com"},"Password":{"Message":"","Value":"password"}}`,
+			renderer:        &mockNewUserRenderer{},
+			setter:          &mockSetter{&mockCurrentUserIDSetter{}, &mockFlashSetter{}},
+			expectedStatus:  http.StatusUnprocessableEntity,
+			expectedBody:    `{"Email":{"Message":"Email already in use.","Value":"john@bannister.com"},"Password":{"Message":"","Value":"password"}}`,
+			expectedMetrics: []string{"sign up password failure"},
 		},
 		{
 			name: "error from creator",
@@ -175,10 +184,11 @@ func TestCreateUser(t *testing.T) {
 			creator: &mockUserCreator{
 				err: errors.New("mock error"),
 			},
-			renderer:       &mockNewUserRenderer{},
-			setter:         &mockSetter{&mockCurrentUserIDSetter{}, &mockFlashSetter{}},
-			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   "500: Internal Server Error",
+			renderer:        &mockNewUserRenderer{},
+			setter:          &mockSetter{&mockCurrentUserIDSetter{}, &mockFlashSetter{}},
+			expectedStatus:  http.StatusInternalServerError,
+			expectedBody:    "500: Internal Server Error",
+			expectedMetrics: []string{"sign up password failure"},
 		},
 		{
 			name: "error from setter",
@@ -194,8 +204,10 @@ func TestCreateUser(t *testing.T) {
 				},
 				&mockFlashSetter{},
 			},
-			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   "500: Internal Server Error",
+			expectedStatus:  http.StatusInternalServerError,
+			expectedBody:    "500: Internal Server Error",
+			expectedMetrics: []string{"sign up password success"},
+			expectedAudit:   []string{"user.sign_up"},
 		},
 	}
 
@@ -206,8 +218,10 @@ func TestCreateUser(t *testing.T) {
 			t.Parallel()
 
 			r := chi.NewMux()
+			metrics := &mockMetrics{}
+			recorder := &mockAuditRecorder{}
 
-			handlers.CreateUser(r, testLogger(t), tt.creator, tt.renderer, tt.setter)
+			handlers.CreateUser(r, testLogger(t), tt.creator, tt.renderer, tt.setter, metrics, recorder)
 
 			status, headers, body := post(r, "/user", tt.body.Encode())
 
@@ -223,6 +237,14 @@ func TestCreateUser(t *testing.T) {
 			if tt.expectedBody != body {
 				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
 			}
+
+			if diff := cmp.Diff(tt.expectedMetrics, metrics.counted); diff != "" {
+				t.Errorf("unexpected metrics: %s", diff)
+			}
+
+			if diff := cmp.Diff(tt.expectedAudit, recorder.recorded); diff != "" {
+				t.Errorf("unexpected audit events: %s", diff)
+			}
 		})
 	}
 }
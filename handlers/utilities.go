@@ -1,6 +1,11 @@
 package handlers
 
-import "net/http"
+import (
+	"net/http"
+	"strings"
+
+	"emailaddress.horse/thousand/templates"
+)
 
 type notFoundRescuingResponseWriter struct {
 	http.ResponseWriter
@@ -27,3 +32,9 @@ func (r *notFoundRescuingResponseWriter) WriteHeader(statusCode int) {
 
 	r.ResponseWriter.WriteHeader(statusCode)
 }
+
+// acceptsTurboStream returns true if the request was made by Turbo and can be
+// answered with Turbo Stream actions instead of a redirect.
+func acceptsTurboStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), templates.TurboStreamMediaType)
+}
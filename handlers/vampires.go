@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
+	"emailaddress.horse/thousand/form"
 	"emailaddress.horse/thousand/middleware"
 	"emailaddress.horse/thousand/models"
 	"github.com/go-chi/chi/v5"
@@ -12,22 +14,67 @@ import (
 )
 
 type showVampiresRenderer interface {
-	ShowVampires(http.ResponseWriter, *http.Request, []models.Vampire) error
+	ShowVampires(http.ResponseWriter, *http.Request, models.VampireQuery, models.VampirePage, models.VampirePage, []models.Invitation) error
 }
 
-func ListVampires(r chi.Router, l *zap.Logger, t showVampiresRenderer, vg vampiresGetter) {
+type invitationsForEmailGetter interface {
+	GetInvitationsForEmail(context.Context, string) ([]models.Invitation, error)
+}
+
+func ListVampires(r chi.Router, l *zap.Logger, t showVampiresRenderer, vg vampiresGetter, ig invitationsForEmailGetter) {
 	r.Get("/vampires", func(w http.ResponseWriter, r *http.Request) {
-		vampires, err := vg.GetVampires(r.Context())
+		l := middleware.Logger(r.Context(), l)
+
+		user := middleware.CurrentUser(r.Context())
+
+		var query models.VampireQuery
+		fields, err := form.Decode(r.URL.Query(), &query)
+		if err == nil && !fields.Valid() {
+			err = BadRequestError
+		}
+		if err != nil {
+			l.Error("failed to decode vampire query", zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+		query = query.Normalize()
+
+		page, err := vg.GetVampires(r.Context(), user.ID, query)
+		if err != nil {
+			if errors.Is(err, models.ErrInvalidCursor) {
+				err = BadRequestError.Cause(err)
+			}
+
+			l.Error("failed to load vampires", zap.Object("query", query), zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		// Shared vampires are listed in the same order and filtered by the same
+		// search, but aren't paginated. Only the first page is shown, and its next
+		// cursor tells the page that there are more to search for.
+		shared, err := vg.GetSharedVampires(r.Context(), user.ID, models.VampireQuery{
+			Sort:   query.Sort,
+			Search: query.Search,
+			Limit:  models.MaxVampirePageSize,
+		})
+		if err != nil {
+			l.Error("failed to load shared vampires", zap.Object("query", query), zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		invitations, err := ig.GetInvitationsForEmail(r.Context(), user.Email)
 		if err != nil {
-			l.Error("failed to load vampires", zap.Error(err))
-			handleError(w, err)
+			l.Error("failed to load invitations", zap.Error(err))
+			handleError(w, r, err)
 			return
 		}
 
-		err = t.ShowVampires(w, r, vampires)
+		err = t.ShowVampires(w, r, query, page, shared, invitations)
 		if err != nil {
 			l.Error("failed to render", zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 		}
 	})
 }
@@ -38,26 +85,32 @@ type newVampireRenderer interface {
 
 func NewVampire(r chi.Router, l *zap.Logger, t newVampireRenderer) {
 	r.Get("/vampires/new", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
 		err := t.NewVampire(w, r)
 		if err != nil {
 			l.Error("failed to render", zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 		}
 	})
 }
 
-func CreateVampire(r chi.Router, l *zap.Logger, vc vampireCreator) {
+func CreateVampire(r chi.Router, l *zap.Logger, vc vampireCreator, m vampireMetrics) {
 	r.Post("/vampires", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
 		user := middleware.CurrentUser(r.Context())
 		name := r.FormValue("name")
 
 		vampire, err := vc.CreateVampire(r.Context(), user.ID, name)
 		if err != nil {
 			l.Error("failed to create vampire", zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 			return
 		}
 
+		m.VampireCreated()
+
 		http.Redirect(w, r, "/vampires/"+vampire.ID.String(), http.StatusSeeOther)
 	})
 }
@@ -67,11 +120,13 @@ type showVampireRenderer interface {
 }
 
 func ShowVampire(r chi.Router, l *zap.Logger, t showVampireRenderer, vg vampireGetter) {
-	r.Get("/vampires/{id}", func(w http.ResponseWriter, r *http.Request) {
-		id, err := uuid.Parse(chi.URLParam(r, "id"))
+	r.Get("/vampires/{vampireID}", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		id, err := uuid.Parse(chi.URLParam(r, "vampireID"))
 		if err != nil {
 			l.Error("failed to parse id as UUID", zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 			return
 		}
 
@@ -82,14 +137,52 @@ func ShowVampire(r chi.Router, l *zap.Logger, t showVampireRenderer, vg vampireG
 			}
 
 			l.Error("failed to find vampire", zap.Stringer("id", id), zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 			return
 		}
 
 		err = t.ShowVampire(w, r, vampire)
 		if err != nil {
 			l.Error("failed to render", zap.Error(err))
-			handleError(w, err)
+			handleError(w, r, err)
 		}
 	})
 }
+
+type vampireTrasher interface {
+	TrashVampire(context.Context, uuid.UUID) error
+}
+
+type vampireTrashBroadcaster interface {
+	VampireTrashed(context.Context, uuid.UUID) error
+}
+
+func DestroyVampire(r chi.Router, l *zap.Logger, vt vampireTrasher, b vampireTrashBroadcaster) {
+	r.Delete("/vampires/{vampireID}", func(w http.ResponseWriter, r *http.Request) {
+		l := middleware.Logger(r.Context(), l)
+
+		id, err := uuid.Parse(chi.URLParam(r, "vampireID"))
+		if err != nil {
+			l.Error("failed to parse id as UUID", zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		err = vt.TrashVampire(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				err = NotFoundError.Cause(err)
+			}
+
+			l.Error("failed to trash vampire", zap.Stringer("id", id), zap.Error(err))
+			handleError(w, r, err)
+			return
+		}
+
+		if err := b.VampireTrashed(r.Context(), id); err != nil {
+			l.Error("failed to broadcast trashed vampire", zap.Stringer("id", id), zap.Error(err))
+		}
+
+		http.Redirect(w, r, "/vampires", http.StatusSeeOther)
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"emailaddress.horse/thousand/middleware"
 	"emailaddress.horse/thousand/models"
 	"github.com/go-chi/chi/v5"
+	"github.com/google/go-cmp/cmp"
 	"github.com/google/uuid"
 )
 
@@ -19,17 +20,35 @@ type mockShowVampiresRenderer struct {
 	err error
 }
 
-func (m *mockShowVampiresRenderer) ShowVampires(w http.ResponseWriter, _ *http.Request, vampires []models.Vampire) error {
+func (m *mockShowVampiresRenderer) ShowVampires(w http.ResponseWriter, _ *http.Request, _ models.VampireQuery, page, shared models.VampirePage, invitations []models.Invitation) error {
 	if m.err != nil {
 		return m.err
 	}
 
-	names := make([]string, len(vampires))
-	for i, v := range vampires {
+	names := make([]string, len(page.Vampires))
+	for i, v := range page.Vampires {
 		names[i] = v.Name
 	}
 
-	_, err := w.Write([]byte(strings.Join(names, ", ")))
+	sharedNames := make([]string, len(shared.Vampires))
+	for i, v := range shared.Vampires {
+		sharedNames[i] = v.Name
+	}
+
+	invitationNames := make([]string, len(invitations))
+	for i, invitation := range invitations {
+		invitationNames[i] = invitation.VampireName
+	}
+
+	body := strings.Join([]string{
+		strings.Join(names, ", "),
+		strings.Join(sharedNames, ", "),
+		strings.Join(invitationNames, ", "),
+		page.Next,
+		shared.Next,
+	}, " | ")
+
+	_, err := w.Write([]byte(body))
 	if err != nil {
 		panic(err)
 	}
@@ -38,23 +57,63 @@ func (m *mockShowVampiresRenderer) ShowVampires(w http.ResponseWriter, _ *http.R
 }
 
 type mockVampiresGetter struct {
-	vampires []models.Vampire
-	err      error
+	vampires       []models.Vampire
+	next           string
+	sharedVampires []models.Vampire
+	sharedNext     string
+	err            error
+	sharedErr      error
+	userID         uuid.UUID
+	query          models.VampireQuery
+	sharedQuery    models.VampireQuery
+}
+
+func (m *mockVampiresGetter) GetVampires(_ context.Context, userID uuid.UUID, query models.VampireQuery) (models.VampirePage, error) {
+	m.userID = userID
+	m.query = query
+	return models.VampirePage{Vampires: m.vampires, Next: m.next}, m.err
+}
+
+func (m *mockVampiresGetter) GetSharedVampires(_ context.Context, userID uuid.UUID, query models.VampireQuery) (models.VampirePage, error) {
+	m.sharedQuery = query
+	return models.VampirePage{Vampires: m.sharedVampires, Next: m.sharedNext}, m.sharedErr
 }
 
-func (m *mockVampiresGetter) GetVampires(_ context.Context) ([]models.Vampire, error) {
-	return m.vampires, m.err
+type mockInvitationsForEmailGetter struct {
+	invitations []models.Invitation
+	err         error
+	email       string
+}
+
+func (m *mockInvitationsForEmailGetter) GetInvitationsForEmail(_ context.Context, email string) ([]models.Invitation, error) {
+	m.email = email
+	return m.invitations, m.err
 }
 
 func TestListVampires(t *testing.T) {
 	t.Parallel()
 
+	user := models.User{
+		ID:    uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		Email: "john@bannister.com",
+	}
+
+	defaultQuery := models.VampireQuery{
+		Sort:  models.VampireSortCreated,
+		Limit: models.DefaultVampirePageSize,
+	}
+
 	tests := []struct {
-		name           string
-		renderer       *mockShowVampiresRenderer
-		getter         *mockVampiresGetter
-		expectedStatus int
-		expectedBody   string
+		name                string
+		path                string
+		renderer            *mockShowVampiresRenderer
+		getter              *mockVampiresGetter
+		invitationsGetter   *mockInvitationsForEmailGetter
+		expectedStatus      int
+		expectedBody        string
+		expectedEmail       string
+		expectedQuery       models.VampireQuery
+		expectedSharedQuery models.VampireQuery
 	}{
 		{
 			name:     "successful",
@@ -65,17 +124,126 @@ func TestListVampires(t *testing.T) {
 					{Name: "two"},
 					{Name: "three"},
 				},
+				sharedVampires: []models.Vampire{
+					{Name: "four"},
+				},
+			},
+			invitationsGetter: &mockInvitationsForEmailGetter{
+				invitations: []models.Invitation{
+					{VampireName: "five"},
+				},
+			},
+			expectedStatus:      http.StatusOK,
+			expectedBody:        "one, two, three | four | five |  |",
+			expectedEmail:       "john@bannister.com",
+			expectedQuery:       defaultQuery,
+			expectedSharedQuery: models.VampireQuery{Sort: models.VampireSortCreated, Limit: models.MaxVampirePageSize},
+		},
+		{
+			name:     "with query",
+			path:     "/vampires?sort=name&q=shipwreck&after=abc&limit=5",
+			renderer: &mockShowVampiresRenderer{},
+			getter: &mockVampiresGetter{
+				vampires: []models.Vampire{
+					{Name: "one"},
+				},
+				next: "def",
+			},
+			invitationsGetter: &mockInvitationsForEmailGetter{},
+			expectedStatus:    http.StatusOK,
+			expectedBody:      "one |  |  | def |",
+			expectedEmail:     "john@bannister.com",
+			expectedQuery: models.VampireQuery{
+				Sort:   models.VampireSortName,
+				Search: "shipwreck",
+				After:  "abc",
+				Limit:  5,
+			},
+			expectedSharedQuery: models.VampireQuery{
+				Sort:   models.VampireSortName,
+				Search: "shipwreck",
+				Limit:  models.MaxVampirePageSize,
+			},
+		},
+		{
+			name:     "with more shared vampires",
+			renderer: &mockShowVampiresRenderer{},
+			getter: &mockVampiresGetter{
+				sharedVampires: []models.Vampire{
+					{Name: "four"},
+				},
+				sharedNext: "ghi",
+			},
+			invitationsGetter:   &mockInvitationsForEmailGetter{},
+			expectedStatus:      http.StatusOK,
+			expectedBody:        "| four |  |  | ghi",
+			expectedEmail:       "john@bannister.com",
+			expectedQuery:       defaultQuery,
+			expectedSharedQuery: models.VampireQuery{Sort: models.VampireSortCreated, Limit: models.MaxVampirePageSize},
+		},
+		{
+			name:              "invalid sort",
+			path:              "/vampires?sort=age",
+			getter:            &mockVampiresGetter{},
+			invitationsGetter: &mockInvitationsForEmailGetter{},
+			expectedStatus:    http.StatusBadRequest,
+			expectedBody:      "400: Bad Request",
+		},
+		{
+			name:              "invalid limit",
+			path:              "/vampires?limit=1000",
+			getter:            &mockVampiresGetter{},
+			invitationsGetter: &mockInvitationsForEmailGetter{},
+			expectedStatus:    http.StatusBadRequest,
+			expectedBody:      "400: Bad Request",
+		},
+		{
+			name: "invalid cursor",
+			path: "/vampires?after=nonsense",
+			getter: &mockVampiresGetter{
+				err: models.ErrInvalidCursor,
+			},
+			invitationsGetter: &mockInvitationsForEmailGetter{},
+			expectedStatus:    http.StatusBadRequest,
+			expectedBody:      "400: Bad Request",
+			expectedQuery: models.VampireQuery{
+				Sort:  models.VampireSortCreated,
+				After: "nonsense",
+				Limit: models.DefaultVampirePageSize,
 			},
-			expectedStatus: http.StatusOK,
-			expectedBody:   "one, two, three",
 		},
 		{
 			name: "error from getter",
 			getter: &mockVampiresGetter{
 				err: errors.New("mock error"),
 			},
-			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   "500: Internal Server Error",
+			invitationsGetter: &mockInvitationsForEmailGetter{},
+			expectedStatus:    http.StatusInternalServerError,
+			expectedBody:      "500: Internal Server Error",
+			expectedQuery:     defaultQuery,
+		},
+		{
+			name: "error from getter for shared vampires",
+			getter: &mockVampiresGetter{
+				sharedErr: errors.New("mock error"),
+			},
+			invitationsGetter:   &mockInvitationsForEmailGetter{},
+			expectedStatus:      http.StatusInternalServerError,
+			expectedBody:        "500: Internal Server Error",
+			expectedQuery:       defaultQuery,
+			expectedSharedQuery: models.VampireQuery{Sort: models.VampireSortCreated, Limit: models.MaxVampirePageSize},
+		},
+		{
+			name:   "error from invitations getter",
+			getter: &mockVampiresGetter{},
+			invitationsGetter: &mockInvitationsForEmailGetter{
+				err: errors.New("mock error"),
+			},
+			expectedStatus:      http.StatusInternalServerError,
+			expectedBody:        "500: Internal Server Error",
+			expectedEmail:       "john@bannister.com",
+			expectedQuery:       defaultQuery,
+			expectedSharedQuery: models.VampireQuery{Sort: models.VampireSortCreated, Limit: models.MaxVampirePageSize},
 		},
 		{
 			name: "error from renderer",
@@ -89,8 +257,12 @@ func TestListVampires(t *testing.T) {
 					{Name: "three"},
 				},
 			},
-			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   "500: Internal Server Error",
+			invitationsGetter:   &mockInvitationsForEmailGetter{},
+			expectedStatus:      http.StatusInternalServerError,
+			expectedBody:        "500: Internal Server Error",
+			expectedEmail:       "john@bannister.com",
+			expectedQuery:       defaultQuery,
+			expectedSharedQuery: models.VampireQuery{Sort: models.VampireSortCreated, Limit: models.MaxVampirePageSize},
 		},
 	}
 
@@ -102,9 +274,17 @@ func TestListVampires(t *testing.T) {
 
 			r := chi.NewMux()
 
-			handlers.ListVampires(r, testLogger(t), tt.renderer, tt.getter)
+			handlers.ListVampires(r, testLogger(t), tt.renderer, tt.getter, tt.invitationsGetter)
 
-			status, _, body := get(r, "/vampires")
+			path := tt.path
+			if path == "" {
+				path = "/vampires"
+			}
+
+			req := newRequest(http.MethodGet, path)
+			req.request = middleware.RequestWithCurrentUser(req.request, user)
+
+			status, _, body := req.perform(r)
 
 			if tt.expectedStatus != status {
 				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
@@ -113,6 +293,23 @@ func TestListVampires(t *testing.T) {
 			if tt.expectedBody != body {
 				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
 			}
+
+			// The getter is never called if the query can't be decoded
+			if tt.expectedQuery != (models.VampireQuery{}) && user.ID != tt.getter.userID {
+				t.Errorf("expected getter to receive user ID %s; got %s", user.ID, tt.getter.userID)
+			}
+
+			if tt.expectedQuery != tt.getter.query {
+				t.Errorf("expected getter to receive query %+v; got %+v", tt.expectedQuery, tt.getter.query)
+			}
+
+			if tt.expectedSharedQuery != tt.getter.sharedQuery {
+				t.Errorf("expected getter to receive shared query %+v; got %+v", tt.expectedSharedQuery, tt.getter.sharedQuery)
+			}
+
+			if tt.expectedEmail != tt.invitationsGetter.email {
+				t.Errorf("expected invitations getter to receive email %q; got %q", tt.expectedEmail, tt.invitationsGetter.email)
+			}
 		})
 	}
 }
@@ -207,6 +404,7 @@ func TestCreateVampire(t *testing.T) {
 		expectedName     string
 		expectedUserID   uuid.UUID
 		expectedLocation string
+		expectedMetrics  []string
 	}{
 		{
 			name: "successful",
@@ -223,6 +421,7 @@ func TestCreateVampire(t *testing.T) {
 			expectedName:     "Gruffudd",
 			expectedUserID:   uuid.MustParse("12345678-90ab-cdef-1234-567890abcdef"),
 			expectedLocation: "/vampires/12345678-90ab-cdef-1234-567890abcdef",
+			expectedMetrics:  []string{"vampire created"},
 		},
 		{
 			name: "error from creator",
@@ -247,8 +446,9 @@ func TestCreateVampire(t *testing.T) {
 			t.Parallel()
 
 			r := chi.NewMux()
+			metrics := &mockMetrics{}
 
-			handlers.CreateVampire(r, testLogger(t), tt.creator)
+			handlers.CreateVampire(r, testLogger(t), tt.creator, metrics)
 
 			req := postRequest("/vampires", tt.body.Encode())
 			req.request = middleware.RequestWithCurrentUser(req.request, tt.user)
@@ -271,6 +471,10 @@ func TestCreateVampire(t *testing.T) {
 			if tt.expectedLocation != actualLocation {
 				t.Errorf("expected location %q; got %q", tt.expectedLocation, actualLocation)
 			}
+
+			if diff := cmp.Diff(tt.expectedMetrics, metrics.counted); diff != "" {
+				t.Errorf("unexpected metrics: %s", diff)
+			}
 		})
 	}
 }
@@ -395,3 +599,119 @@ func TestShowVampire(t *testing.T) {
 		})
 	}
 }
+
+type mockVampireTrasher struct {
+	id  uuid.UUID
+	err error
+}
+
+func (m *mockVampireTrasher) TrashVampire(_ context.Context, id uuid.UUID) error {
+	m.id = id
+	return m.err
+}
+
+func TestDestroyVampire(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name               string
+		trasher            *mockVampireTrasher
+		broadcaster        *mockBroadcaster
+		path               string
+		expectedStatus     int
+		expectedBody       string
+		expectedLocation   string
+		expectedID         uuid.UUID
+		expectedBroadcasts int
+	}{
+		{
+			name:               "successful",
+			trasher:            &mockVampireTrasher{},
+			broadcaster:        &mockBroadcaster{},
+			path:               "/vampires/11111111-1111-1111-1111-111111111111",
+			expectedStatus:     http.StatusSeeOther,
+			expectedLocation:   "/vampires",
+			expectedID:         uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+			expectedBroadcasts: 1,
+		},
+		{
+			name:               "error from broadcaster",
+			trasher:            &mockVampireTrasher{},
+			broadcaster:        &mockBroadcaster{err: errors.New("mock error")},
+			path:               "/vampires/11111111-1111-1111-1111-111111111111",
+			expectedStatus:     http.StatusSeeOther,
+			expectedLocation:   "/vampires",
+			expectedID:         uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+			expectedBroadcasts: 1,
+		},
+		{
+			name: "not found from trasher",
+			trasher: &mockVampireTrasher{
+				err: models.ErrNotFound,
+			},
+			broadcaster:    &mockBroadcaster{},
+			path:           "/vampires/11111111-1111-1111-1111-111111111111",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404: Not Found",
+			expectedID:     uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		},
+		{
+			name: "error from trasher",
+			trasher: &mockVampireTrasher{
+				err: errors.New("mock error"),
+			},
+			broadcaster:    &mockBroadcaster{},
+			path:           "/vampires/11111111-1111-1111-1111-111111111111",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+			expectedID:     uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		},
+		{
+			name:           "error parsing id",
+			trasher:        &mockVampireTrasher{},
+			broadcaster:    &mockBroadcaster{},
+			path:           "/vampires/unknown",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "500: Internal Server Error",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewMux()
+
+			handlers.DestroyVampire(r, testLogger(t), tt.trasher, tt.broadcaster)
+
+			status, headers, body := deleteRequest(r, tt.path)
+
+			if tt.expectedStatus != status {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, status)
+			}
+
+			if tt.expectedLocation == "" && tt.expectedBody != body {
+				t.Errorf("expected body %q; got %q", tt.expectedBody, body)
+			}
+
+			location := headers.Get("Location")
+			if tt.expectedLocation != location {
+				t.Errorf("expected location %q; got %q", tt.expectedLocation, location)
+			}
+
+			if tt.expectedID != tt.trasher.id {
+				t.Errorf("expected %q; got %q", tt.expectedID, tt.trasher.id)
+			}
+
+			if tt.expectedBroadcasts != tt.broadcaster.count {
+				t.Errorf("expected %d broadcasts; got %d", tt.expectedBroadcasts, tt.broadcaster.count)
+			}
+
+			if tt.expectedBroadcasts > 0 && tt.expectedID != tt.broadcaster.vampireID {
+				t.Errorf("expected broadcast to %q; got %q", tt.expectedID, tt.broadcaster.vampireID)
+			}
+		})
+	}
+}
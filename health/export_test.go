@@ -0,0 +1,8 @@
+package health
+
+import "time"
+
+// SetNow replaces the clock used to expire cached results.
+func (h *Health) SetNow(now func() time.Time) {
+	h.now = now
+}
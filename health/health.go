@@ -2,29 +2,97 @@ package health
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"time"
 )
 
 const (
-	_okLabel     = "ok"
-	_failedLabel = "failed"
+	_okLabel       = "ok"
+	_degradedLabel = "degraded"
+	_failedLabel   = "failed"
+	_notReadyLabel = "not ready"
+)
+
+const (
+	// DefaultTimeout is how long a component's check may run if neither the
+	// component nor the options give a timeout.
+	DefaultTimeout = time.Second
+
+	// DefaultTTL is how long the result of checking every component is reused
+	// for if the options don't give a TTL.
+	DefaultTTL = 5 * time.Second
 )
 
 type Health struct {
 	components []*Component
+	timeout    time.Duration
+	ttl        time.Duration
+	now        func() time.Time
+
+	// checking is held while the components are checked, so that callers
+	// arriving meanwhile wait to share the result rather than checking again.
+	// It is separate from mu so that checks don't hold up SetReady.
+	checking sync.Mutex
+
+	mu       sync.Mutex
+	notReady bool
+	cached   Result
+	healthy  bool
+	cachedAt time.Time
+}
+
+type Options struct {
+	// Timeout is how long each component's check may run before it is failed,
+	// unless the component sets its own.
+	Timeout time.Duration
+	// TTL is how long a result is reused for before the components are checked
+	// again.
+	TTL time.Duration
 }
 
-func New() *Health {
-	return &Health{}
+func New(opts Options) *Health {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultTTL
+	}
+
+	return &Health{
+		timeout: opts.Timeout,
+		ttl:     opts.TTL,
+		now:     time.Now,
+	}
 }
 
 type Component struct {
 	Name  string
 	Check func(context.Context) error
+	// Critical components make the app unready when they fail. Failures of
+	// other components only degrade it.
+	Critical bool
+	// Timeout overrides the default timeout for this component's check.
+	Timeout time.Duration
 }
 
 func (h *Health) Register(c *Component) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
 	h.components = append(h.components, c)
+	h.cachedAt = time.Time{}
+}
+
+// SetReady gates readiness on something other than the components, such as the
+// server having started or having begun to shut down. While not ready, checks
+// fail without running any component.
+func (h *Health) SetReady(ready bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.notReady = !ready
 }
 
 type Result struct {
@@ -35,41 +103,134 @@ type Result struct {
 type ComponentResult struct {
 	Name      string    `json:"name"`
 	Status    string    `json:"status"`
+	Critical  bool      `json:"critical"`
 	Error     string    `json:"error,omitempty"`
 	Timestamp time.Time `json:"timestamp"`
 }
 
-func (h *Health) Check(ctx context.Context) (Result, bool) {
+// Check runs every component's check concurrently, reporting the app as
+// unhealthy only if a critical component fails. Results are shared between
+// callers until the TTL expires, so checks don't run with the caller's context
+// and aren't cancelled if the caller goes away.
+func (h *Health) Check(_ context.Context) (Result, bool) {
+	h.checking.Lock()
+	defer h.checking.Unlock()
+
+	h.mu.Lock()
+	if h.notReady {
+		h.mu.Unlock()
+		return notReadyResult(), false
+	}
+
+	if !h.cachedAt.IsZero() && h.now().Sub(h.cachedAt) < h.ttl {
+		result, healthy := h.cached, h.healthy
+		h.mu.Unlock()
+		return result, healthy
+	}
+
+	components := make([]*Component, len(h.components))
+	copy(components, h.components)
+	h.mu.Unlock()
+
+	result, healthy := h.checkComponents(components)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// The app may have stopped being ready while the components were checked
+	if h.notReady {
+		return notReadyResult(), false
+	}
+
+	// Components registered meanwhile are checked by the next caller instead
+	if len(components) == len(h.components) {
+		h.cached = result
+		h.healthy = healthy
+		h.cachedAt = h.now()
+	}
+
+	return result, healthy
+}
+
+func (h *Health) checkComponents(components []*Component) (Result, bool) {
 	healthy := true
 
 	result := Result{
-		Details: make([]ComponentResult, len(h.components)),
+		Details: make([]ComponentResult, len(components)),
 		Status:  _okLabel,
 	}
 
-	for i := range h.components {
-		cr, ok := h.components[i].PerformCheck(ctx)
-		result.Details[i] = cr
+	var wg sync.WaitGroup
+	oks := make([]bool, len(components))
+
+	for i := range components {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			timeout := components[i].Timeout
+			if timeout <= 0 {
+				timeout = h.timeout
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			result.Details[i], oks[i] = components[i].PerformCheck(ctx)
+		}(i)
+	}
+
+	wg.Wait()
 
-		if !ok {
+	for i, ok := range oks {
+		if ok {
+			continue
+		}
+
+		if components[i].Critical {
 			healthy = false
 			result.Status = _failedLabel
+		} else if healthy {
+			result.Status = _degradedLabel
 		}
 	}
 
 	return result, healthy
 }
 
+func notReadyResult() Result {
+	return Result{
+		Details: []ComponentResult{},
+		Status:  _notReadyLabel,
+	}
+}
+
+// PerformCheck runs the component's check, failing it if the check doesn't
+// return before the context is done.
 func (c *Component) PerformCheck(ctx context.Context) (ComponentResult, bool) {
 	healthy := true
 
 	result := ComponentResult{
 		Name:      c.Name,
 		Status:    _okLabel,
+		Critical:  c.Critical,
 		Timestamp: time.Now(),
 	}
 
-	if err := c.Check(ctx); err != nil {
+	errs := make(chan error, 1)
+	go func() {
+		errs <- c.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errs:
+	case <-ctx.Done():
+		err = fmt.Errorf("check timed out: %w", ctx.Err())
+	}
+
+	if err != nil {
 		healthy = false
 
 		result.Status = _failedLabel
@@ -0,0 +1,245 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"emailaddress.horse/thousand/health"
+)
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	pass := func(context.Context) error { return nil }
+	fail := func(context.Context) error { return errors.New("mock error") }
+
+	tests := []struct {
+		name            string
+		components      []*health.Component
+		expectedStatus  string
+		expectedHealthy bool
+		expectedErrors  []string
+	}{
+		{
+			name: "all passing",
+			components: []*health.Component{
+				{Name: "critical", Check: pass, Critical: true},
+				{Name: "optional", Check: pass},
+			},
+			expectedStatus:  "ok",
+			expectedHealthy: true,
+			expectedErrors:  []string{"", ""},
+		},
+		{
+			name: "non-critical failing",
+			components: []*health.Component{
+				{Name: "critical", Check: pass, Critical: true},
+				{Name: "optional", Check: fail},
+			},
+			expectedStatus:  "degraded",
+			expectedHealthy: true,
+			expectedErrors:  []string{"", "mock error"},
+		},
+		{
+			name: "critical failing",
+			components: []*health.Component{
+				{Name: "critical", Check: fail, Critical: true},
+				{Name: "optional", Check: fail},
+			},
+			expectedStatus:  "failed",
+			expectedHealthy: false,
+			expectedErrors:  []string{"mock error", "mock error"},
+		},
+		{
+			name: "critical timing out",
+			components: []*health.Component{
+				{
+					Name: "critical",
+					Check: func(ctx context.Context) error {
+						time.Sleep(time.Second)
+						return nil
+					},
+					Critical: true,
+					Timeout:  10 * time.Millisecond,
+				},
+			},
+			expectedStatus:  "failed",
+			expectedHealthy: false,
+			expectedErrors:  []string{"check timed out: context deadline exceeded"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := health.New(health.Options{})
+			for _, c := range tt.components {
+				h.Register(c)
+			}
+
+			result, healthy := h.Check(context.Background())
+
+			if tt.expectedHealthy != healthy {
+				t.Errorf("expected healthy to be %t; got %t", tt.expectedHealthy, healthy)
+			}
+
+			if tt.expectedStatus != result.Status {
+				t.Errorf("expected status %q; got %q", tt.expectedStatus, result.Status)
+			}
+
+			if len(tt.expectedErrors) != len(result.Details) {
+				t.Fatalf("expected %d details; got %d", len(tt.expectedErrors), len(result.Details))
+			}
+
+			for i, expected := range tt.expectedErrors {
+				if expected != result.Details[i].Error {
+					t.Errorf("expected %s to have error %q; got %q", result.Details[i].Name, expected, result.Details[i].Error)
+				}
+
+				if tt.components[i].Critical != result.Details[i].Critical {
+					t.Errorf("expected %s to have critical %t", result.Details[i].Name, tt.components[i].Critical)
+				}
+			}
+		})
+	}
+}
+
+func TestCheck_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	h := health.New(health.Options{})
+
+	for _, name := range []string{"first", "second", "third"} {
+		h.Register(&health.Component{
+			Name: name,
+			Check: func(context.Context) error {
+				time.Sleep(100 * time.Millisecond)
+				return nil
+			},
+		})
+	}
+
+	start := time.Now()
+	h.Check(context.Background())
+
+	if elapsed := time.Since(start); elapsed >= 250*time.Millisecond {
+		t.Errorf("expected checks to run concurrently; took %s", elapsed)
+	}
+}
+
+func TestCheck_Cached(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+
+	h := health.New(health.Options{TTL: time.Minute})
+	h.Register(&health.Component{
+		Name: "counted",
+		Check: func(context.Context) error {
+			atomic.AddInt32(&calls, 1)
+			return nil
+		},
+	})
+
+	now := time.Date(2022, time.February, 5, 10, 0, 0, 0, time.UTC)
+	h.SetNow(func() time.Time { return now })
+
+	h.Check(context.Background())
+	h.Check(context.Background())
+
+	if calls := atomic.LoadInt32(&calls); calls != 1 {
+		t.Errorf("expected 1 check within TTL; got %d", calls)
+	}
+
+	now = now.Add(time.Minute)
+	h.Check(context.Background())
+
+	if calls := atomic.LoadInt32(&calls); calls != 2 {
+		t.Errorf("expected 2 checks after TTL; got %d", calls)
+	}
+}
+
+func TestCheck_NotReady(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+
+	h := health.New(health.Options{})
+	h.Register(&health.Component{
+		Name: "counted",
+		Check: func(context.Context) error {
+			atomic.AddInt32(&calls, 1)
+			return nil
+		},
+	})
+
+	h.SetReady(false)
+
+	result, healthy := h.Check(context.Background())
+	if healthy {
+		t.Error("expected not to be healthy while not ready")
+	}
+
+	if result.Status != "not ready" {
+		t.Errorf("expected status %q; got %q", "not ready", result.Status)
+	}
+
+	if calls := atomic.LoadInt32(&calls); calls != 0 {
+		t.Errorf("expected no checks while not ready; got %d", calls)
+	}
+
+	h.SetReady(true)
+
+	if _, healthy := h.Check(context.Background()); !healthy {
+		t.Error("expected to be healthy once ready")
+	}
+}
+
+func TestCheck_SetReadyWhileChecking(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	h := health.New(health.Options{Timeout: time.Minute})
+	h.Register(&health.Component{
+		Name: "slow",
+		Check: func(context.Context) error {
+			close(started)
+			<-release
+			return nil
+		},
+	})
+
+	done := make(chan bool)
+	go func() {
+		_, healthy := h.Check(context.Background())
+		done <- healthy
+	}()
+
+	<-started
+
+	ready := make(chan struct{})
+	go func() {
+		h.SetReady(false)
+		close(ready)
+	}()
+
+	select {
+	case <-ready:
+	case <-time.After(time.Second):
+		t.Fatal("expected SetReady not to wait for the check")
+	}
+
+	close(release)
+
+	if healthy := <-done; healthy {
+		t.Error("expected not to be healthy once no longer ready")
+	}
+}
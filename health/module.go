@@ -1,7 +1,25 @@
 package health
 
-import "go.uber.org/fx"
+import (
+	"time"
+
+	"go.uber.org/fx"
+)
 
 var Module = fx.Options(
-	fx.Provide(New),
+	fx.Provide(fxNew),
 )
+
+type Params struct {
+	fx.In
+
+	Timeout time.Duration `name:"healthTimeout" optional:"true"`
+	TTL     time.Duration `name:"healthCacheTTL" optional:"true"`
+}
+
+func fxNew(params Params) *Health {
+	return New(Options{
+		Timeout: params.Timeout,
+		TTL:     params.TTL,
+	})
+}
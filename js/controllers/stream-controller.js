@@ -0,0 +1,21 @@
+import { Controller } from "@hotwired/stimulus";
+import { connectStreamSource, disconnectStreamSource } from "@hotwired/turbo";
+
+export default class extends Controller {
+  static values = { src: String };
+
+  connect() {
+    this.source = new EventSource(this.srcValue);
+    connectStreamSource(this.source);
+  }
+
+  disconnect() {
+    if (!this.source) {
+      return;
+    }
+
+    disconnectStreamSource(this.source);
+    this.source.close();
+    this.source = null;
+  }
+}
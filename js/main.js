@@ -3,6 +3,8 @@ import "@hotwired/turbo";
 import { Application } from "@hotwired/stimulus";
 
 import FrameController from "./controllers/frame-controller.js";
+import StreamController from "./controllers/stream-controller.js";
 
 window.Stimulus = Application.start();
 Stimulus.register("frame", FrameController);
+Stimulus.register("stream", StreamController);
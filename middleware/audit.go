@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+
+	"emailaddress.horse/thousand/audit"
+	"github.com/go-chi/chi/v5"
+)
+
+// AuditActor records where each request came from as its audit actor, who is
+// filled in once the request is authenticated.
+func AuditActor(r chi.Router) {
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				ip = r.RemoteAddr
+			}
+
+			ctx := audit.WithActor(r.Context(), audit.Actor{
+				IP:        ip,
+				UserAgent: r.UserAgent(),
+			})
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+}
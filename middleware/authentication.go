@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 
+	"emailaddress.horse/thousand/audit"
 	"emailaddress.horse/thousand/models"
 	"emailaddress.horse/thousand/session"
 	"github.com/go-chi/chi/v5"
@@ -15,9 +16,17 @@ type userGetter interface {
 	GetUser(context.Context, uuid.UUID) (models.User, error)
 }
 
+// EnsureLoggedIn redirects to the log in page unless someone is logged in
+// and, if they're impersonating the current user, is still an admin. Users who
+// have been disabled are logged out, though admins can still impersonate them.
 func EnsureLoggedIn(r chi.Router, s *session.Store, ug userGetter) {
 	r.Use(func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			logOut := func() {
+				_ = s.ClearCurrentUserID(w, r)
+				http.Redirect(w, r, "/session/new", http.StatusSeeOther)
+			}
+
 			id, ok := s.GetCurrentUserID(r)
 			if !ok {
 				http.Redirect(w, r, "/session/new", http.StatusSeeOther)
@@ -26,12 +35,26 @@ func EnsureLoggedIn(r chi.Router, s *session.Store, ug userGetter) {
 
 			user, err := ug.GetUser(r.Context(), id)
 			if err != nil {
-				_ = s.ClearCurrentUserID(w, r)
-				http.Redirect(w, r, "/session/new", http.StatusSeeOther)
+				logOut()
 				return
 			}
 
-			next.ServeHTTP(w, RequestWithCurrentUser(r, user))
+			r = RequestWithCurrentUser(r, user)
+
+			if adminID, ok := s.GetImpersonatorID(r); ok {
+				admin, err := ug.GetUser(r.Context(), adminID)
+				if err != nil || !admin.IsAdmin || admin.Disabled() {
+					logOut()
+					return
+				}
+
+				r = RequestWithImpersonator(r, admin)
+			} else if user.Disabled() {
+				logOut()
+				return
+			}
+
+			next.ServeHTTP(w, r)
 		})
 	})
 }
@@ -39,11 +62,16 @@ func EnsureLoggedIn(r chi.Router, s *session.Store, ug userGetter) {
 type contextKey string
 
 const (
-	currentUserContextKey contextKey = "currentUser"
+	currentUserContextKey  contextKey = "currentUser"
+	impersonatorContextKey contextKey = "impersonator"
 )
 
+// RequestWithCurrentUser returns a copy of the request with the user set as
+// both the current user and the actor of anything audited.
 func RequestWithCurrentUser(r *http.Request, user models.User) *http.Request {
-	return r.WithContext(context.WithValue(r.Context(), currentUserContextKey, user))
+	ctx := context.WithValue(r.Context(), currentUserContextKey, user)
+	ctx = audit.WithUser(ctx, user.ID, user.Email)
+	return r.WithContext(ctx)
 }
 
 func MaybeCurrentUser(ctx context.Context) (models.User, bool) {
@@ -59,3 +87,19 @@ func CurrentUser(ctx context.Context) models.User {
 
 	return user
 }
+
+// RequestWithImpersonator returns a copy of the request with the admin set as
+// impersonating the current user, both for showing that they are and for
+// auditing what they do.
+func RequestWithImpersonator(r *http.Request, admin models.User) *http.Request {
+	ctx := context.WithValue(r.Context(), impersonatorContextKey, admin)
+	ctx = audit.WithImpersonator(ctx, admin.ID)
+	return r.WithContext(ctx)
+}
+
+// MaybeImpersonator returns the admin impersonating the current user, if there
+// is one.
+func MaybeImpersonator(ctx context.Context) (models.User, bool) {
+	admin, ok := ctx.Value(impersonatorContextKey).(models.User)
+	return admin, ok
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"emailaddress.horse/thousand/models"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type vampireRoleGetter interface {
+	GetVampireRole(context.Context, uuid.UUID, uuid.UUID) (models.MemberRole, error)
+}
+
+// AuthorizeVampire ensures the current user has at least the required role for
+// the vampire identified by the vampireID URL parameter. Users who are not
+// members cannot tell the vampire exists. It must follow EnsureLoggedIn and be
+// used inside a group so that the route has been matched before it runs.
+func AuthorizeVampire(r chi.Router, rg vampireRoleGetter, required models.MemberRole) {
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := CurrentUser(r.Context())
+
+			vampireID, err := uuid.Parse(chi.URLParam(r, "vampireID"))
+			if err != nil {
+				Error(w, r, "Not Found", http.StatusNotFound)
+				return
+			}
+
+			role, err := rg.GetVampireRole(r.Context(), vampireID, user.ID)
+			if errors.Is(err, models.ErrNotFound) {
+				Error(w, r, "Not Found", http.StatusNotFound)
+				return
+			} else if err != nil {
+				Error(w, r, "Internal Server Error", http.StatusInternalServerError)
+				return
+			}
+
+			if !role.Allows(required) {
+				Error(w, r, "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, RequestWithVampireRole(r, role))
+		})
+	})
+}
+
+const (
+	vampireRoleContextKey contextKey = "vampireRole"
+)
+
+func RequestWithVampireRole(r *http.Request, role models.MemberRole) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), vampireRoleContextKey, role))
+}
+
+func MaybeVampireRole(ctx context.Context) (models.MemberRole, bool) {
+	role, ok := ctx.Value(vampireRoleContextKey).(models.MemberRole)
+	return role, ok
+}
+
+// RequireAdmin ensures the current user is an admin. Everyone else cannot tell
+// the admin interface exists. It must follow EnsureLoggedIn.
+func RequireAdmin(r chi.Router) {
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !CurrentUser(r.Context()).IsAdmin {
+				Error(w, r, "Not Found", http.StatusNotFound)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	})
+}
@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// RequestIDHeader is the header the request ID is read from and echoed back in
+// so that users can quote it when asking for support.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength is the longest request ID which will be reused from the
+// request's headers.
+const maxRequestIDLength = 64
+
+// RequestID gives every request an ID, reusing one set by a proxy in front of
+// the server if present. Since the ID is echoed back and logged, one which is
+// too long or has characters other than letters, digits, "-", "_", "." and
+// "/" is replaced by a new one.
+func RequestID(r chi.Router) {
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !validRequestID(r.Header.Get(RequestIDHeader)) {
+				r.Header.Del(RequestIDHeader)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	})
+	r.Use(middleware.RequestID)
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if id := GetRequestID(r.Context()); id != "" {
+				w.Header().Set(RequestIDHeader, id)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	})
+}
+
+func validRequestID(id string) bool {
+	if len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == '/':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetRequestID returns the ID of the request, or blank if it has none.
+func GetRequestID(ctx context.Context) string {
+	return middleware.GetReqID(ctx)
+}
+
+type errorRenderer interface {
+	Error(w http.ResponseWriter, r *http.Request, status int, message, requestID string) error
+}
+
+const (
+	errorRendererContextKey contextKey = "errorRenderer"
+)
+
+// RenderErrors renders errors within the group as pages rather than plain
+// text.
+func RenderErrors(r chi.Router, t errorRenderer) {
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), errorRendererContextKey, t)))
+		})
+	})
+}
+
+type jsonError struct {
+	Error jsonErrorBody `json:"error"`
+}
+
+type jsonErrorBody struct {
+	Status    int    `json:"status"`
+	Message   string `json:"message"`
+	RequestID string `json:"requestID,omitempty"`
+}
+
+// Error responds with the status and a message which is safe to show to the
+// user. Clients asking for JSON get JSON; everyone else gets an error page,
+// falling back to plain text if pages are not being rendered or the page
+// itself fails to render.
+func Error(w http.ResponseWriter, r *http.Request, message string, status int) {
+	requestID := GetRequestID(r.Context())
+
+	if acceptsJSON(r) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(jsonError{
+			Error: jsonErrorBody{
+				Status:    status,
+				Message:   message,
+				RequestID: requestID,
+			},
+		})
+		return
+	}
+
+	if t, ok := r.Context().Value(errorRendererContextKey).(errorRenderer); ok {
+		if err := t.Error(w, r, status, message, requestID); err == nil {
+			return
+		}
+	}
+
+	http.Error(w, fmt.Sprintf("%d: %s", status, message), status)
+}
+
+// acceptsJSON returns true if the client prefers JSON to HTML, as API clients
+// do. Browsers list HTML first.
+func acceptsJSON(r *http.Request) bool {
+	accept := r.Header.Get("Accept")
+	json := strings.Index(accept, "application/json")
+	if json == -1 {
+		return false
+	}
+
+	html := strings.Index(accept, "text/html")
+	return html == -1 || json < html
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
@@ -10,6 +11,35 @@ import (
 	"go.uber.org/zap"
 )
 
+const (
+	loggerContextKey contextKey = "logger"
+)
+
+// ContextLogger makes a logger tagged with the request ID available to
+// everything handling the request. It must follow RequestID.
+func ContextLogger(r chi.Router, logger *zap.Logger) {
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			l := logger
+			if id := GetRequestID(r.Context()); id != "" {
+				l = l.With(zap.String("requestID", id))
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), loggerContextKey, l)))
+		})
+	})
+}
+
+// Logger returns the logger for the request the context belongs to, or the
+// fallback outside of a request.
+func Logger(ctx context.Context, fallback *zap.Logger) *zap.Logger {
+	if l, ok := ctx.Value(loggerContextKey).(*zap.Logger); ok {
+		return l
+	}
+
+	return fallback
+}
+
 func RequestLogger(r chi.Router, logger *zap.Logger) {
 	r.Use(middleware.RequestLogger(&zapRequestLogger{
 		logger: logger,
@@ -29,6 +59,7 @@ func (l *zapRequestLogger) NewLogEntry(r *http.Request) middleware.LogEntry {
 	}
 
 	logger := l.logger.With(
+		zap.String("requestID", GetRequestID(r.Context())),
 		zap.String("method", r.Method),
 		zap.String("uri", fmt.Sprintf("%s://%s%s", scheme, r.Host, r.RequestURI)),
 	)
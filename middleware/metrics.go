@@ -11,17 +11,25 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
-func CollectMetrics(r chi.Router, registry *prometheus.Registry) {
+// unmatchedRoute labels requests which didn't match any route, so that
+// requests for made up paths can't add labels without limit.
+const unmatchedRoute = "unmatched"
+
+// CollectMetrics counts requests and measures how long they take. Requests are
+// labelled by the pattern of the route they matched, such as
+// /vampires/{vampireID}, rather than their path so that there is one series
+// per route instead of one per vampire.
+func CollectMetrics(r chi.Router, registry prometheus.Registerer) {
 	requests := promauto.With(registry).NewCounterVec(prometheus.CounterOpts{
 		Name: "app_http_requests_total",
 		Help: "The total number of HTTP requests.",
-	}, []string{"method", "path", "code"})
+	}, []string{"method", "route", "code"})
 
 	requestDuration := promauto.With(registry).NewHistogramVec(prometheus.HistogramOpts{
 		Name:    "app_http_requests_duration_seconds",
 		Help:    "HTTP request durations.",
-		Buckets: []float64{.005, .01, .05, .1, .5, 1},
-	}, []string{"code"})
+		Buckets: []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"method", "route", "code"})
 
 	r.Use(func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -29,13 +37,22 @@ func CollectMetrics(r chi.Router, registry *prometheus.Registry) {
 			before := time.Now()
 			next.ServeHTTP(ww, r)
 			duration := time.Since(before)
+
 			status := ww.Status()
 			if status == 0 {
 				status = http.StatusOK
 			}
 			code := strconv.Itoa(status)
-			requests.WithLabelValues(r.Method, r.URL.Path, code).Inc()
-			requestDuration.WithLabelValues(code).Observe(duration.Seconds())
+
+			route := unmatchedRoute
+			if rctx := chi.RouteContext(r.Context()); rctx != nil {
+				if pattern := rctx.RoutePattern(); pattern != "" {
+					route = pattern
+				}
+			}
+
+			requests.WithLabelValues(r.Method, route, code).Inc()
+			requestDuration.WithLabelValues(r.Method, route, code).Observe(duration.Seconds())
 		})
 	})
 }
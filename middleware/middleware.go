@@ -27,3 +27,14 @@ func MethodOverride(r chi.Router) {
 		})
 	})
 }
+
+// NoIndex asks search engines not to index or follow links from any of the
+// routes in the group, such as pages only reachable through a secret link.
+func NoIndex(r chi.Router) {
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Robots-Tag", "noindex, nofollow")
+			next.ServeHTTP(w, r)
+		})
+	})
+}
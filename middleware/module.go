@@ -3,6 +3,7 @@ package middleware
 import (
 	"github.com/go-chi/chi/v5"
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 )
@@ -14,13 +15,20 @@ var Module = fx.Options(
 type RegisterParams struct {
 	fx.In
 
-	Logger   *zap.Logger
-	Registry *prometheus.Registry `optional:"true"`
-	Router   chi.Router
+	Logger         *zap.Logger
+	Registry       *prometheus.Registry `optional:"true"`
+	Router         chi.Router
+	TracerProvider trace.TracerProvider `optional:"true"`
 }
 
 func register(p RegisterParams) {
+	RequestID(p.Router)
+	if p.TracerProvider != nil {
+		Trace(p.Router, p.TracerProvider)
+	}
+	ContextLogger(p.Router, p.Logger)
 	RequestLogger(p.Router, p.Logger.Named("server"))
+	AuditActor(p.Router)
 	MethodOverride(p.Router)
 	RedirectSlashes(p.Router)
 
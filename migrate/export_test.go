@@ -0,0 +1,17 @@
+package migrate
+
+import (
+	"io"
+
+	"github.com/pressly/goose/v3"
+)
+
+// Plan works out the steps to the target without a database.
+func Plan(migrations goose.Migrations, applied map[int64]bool, target Target) ([]Step, error) {
+	return plan(migrations, applied, target)
+}
+
+// SQLSection returns the up or down section of a SQL migration.
+func SQLSection(r io.Reader, up bool) (string, error) {
+	return sqlSection(r, up)
+}
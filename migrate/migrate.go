@@ -0,0 +1,342 @@
+// Package migrate applies the embedded migrations to the database. Migrating
+// holds a Postgres advisory lock so that processes starting together, such as
+// several instances of a deploy, take turns rather than racing.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"sort"
+
+	"emailaddress.horse/thousand/db"
+	_ "github.com/jackc/pgx/v4/stdlib"
+	"github.com/pressly/goose/v3"
+	"go.uber.org/zap"
+)
+
+func init() {
+	goose.SetBaseFS(db.Migrations)
+}
+
+// lockKey identifies the advisory lock held while migrating. Any number will
+// do as long as every process uses the same one.
+const lockKey int64 = 1000
+
+// ErrNoMigrationsApplied is returned when rolling back with nothing to roll
+// back.
+var ErrNoMigrationsApplied = errors.New("no migrations have been applied")
+
+type Options struct {
+	DatabaseURL string
+	Logger      *zap.Logger
+}
+
+// Migrator migrates a database. It must be closed once finished with.
+type Migrator struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+func Open(opts Options) (*Migrator, error) {
+	if opts.Logger == nil {
+		opts.Logger = zap.NewNop()
+	}
+
+	conn, err := sql.Open("pgx", opts.DatabaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: conn, logger: opts.Logger}, nil
+}
+
+func (m *Migrator) Close() error {
+	return m.db.Close()
+}
+
+// Target is the state to migrate the database to.
+type Target struct {
+	kind    targetKind
+	version int64
+}
+
+type targetKind int
+
+const (
+	targetLatest targetKind = iota
+	targetRollback
+	targetRedo
+	targetVersion
+)
+
+// Latest applies every pending migration.
+func Latest() Target {
+	return Target{kind: targetLatest}
+}
+
+// Rollback rolls back the latest applied migration.
+func Rollback() Target {
+	return Target{kind: targetRollback}
+}
+
+// Redo rolls back the latest applied migration and applies it again.
+func Redo() Target {
+	return Target{kind: targetRedo}
+}
+
+// To applies or rolls back migrations until those up to and including the
+// version are applied and none after it are. Version 0 rolls back everything.
+func To(version int64) Target {
+	return Target{kind: targetVersion, version: version}
+}
+
+// Step is a migration to apply, or to roll back if Up is false.
+type Step struct {
+	Migration *goose.Migration
+	Up        bool
+}
+
+func (s Step) String() string {
+	direction := "down"
+	if s.Up {
+		direction = "up"
+	}
+
+	return fmt.Sprintf("%s (%s)", path.Base(s.Migration.Source), direction)
+}
+
+// SQL returns the statements the step runs, as written in the migration.
+func (s Step) SQL() (string, error) {
+	f, err := db.Migrations.Open(s.Migration.Source)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	return sqlSection(f, s.Up)
+}
+
+// Plan returns the steps migrating to the target would take, without taking
+// the lock or changing anything.
+func (m *Migrator) Plan(ctx context.Context, target Target) ([]Step, error) {
+	migrations, err := goose.CollectMigrations(db.FSMigrationsPath, 0, math.MaxInt64)
+	if err != nil {
+		return nil, fmt.Errorf("error reading migrations: %w", err)
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan(migrations, applied, target)
+}
+
+// Apply migrates to the target while holding the lock, waiting for any other
+// process migrating to finish first. It returns the steps which were taken.
+func (m *Migrator) Apply(ctx context.Context, target Target) ([]Step, error) {
+	var steps []Step
+
+	err := m.withLock(ctx, func() error {
+		if _, err := goose.EnsureDBVersion(m.db); err != nil {
+			return fmt.Errorf("error creating version table: %w", err)
+		}
+
+		var err error
+		steps, err = m.Plan(ctx, target)
+		if err != nil {
+			return err
+		}
+
+		for i, step := range steps {
+			if step.Up {
+				err = step.Migration.Up(m.db)
+			} else {
+				err = step.Migration.Down(m.db)
+			}
+			if err != nil {
+				steps = steps[:i]
+				return fmt.Errorf("error migrating %s: %w", step, err)
+			}
+
+			m.logger.Info("migrated", zap.Stringer("step", step))
+		}
+
+		return nil
+	})
+
+	return steps, err
+}
+
+// Pending returns the versions of embedded migrations which haven't been
+// applied.
+func (m *Migrator) Pending(ctx context.Context) ([]int64, error) {
+	versions, err := db.Versions()
+	if err != nil {
+		return nil, fmt.Errorf("error reading migrations: %w", err)
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := []int64{}
+	for _, version := range versions {
+		if !applied[version] {
+			pending = append(pending, version)
+		}
+	}
+
+	return pending, nil
+}
+
+// Status prints whether each migration has been applied.
+func (m *Migrator) Status() error {
+	return goose.Status(m.db, db.FSMigrationsPath)
+}
+
+// applied returns the applied versions. A database which has never been
+// migrated has no version table, which counts as nothing applied so that
+// planning doesn't need to create it.
+func (m *Migrator) applied(ctx context.Context) (map[int64]bool, error) {
+	var exists bool
+	if err := m.db.QueryRowContext(ctx, "SELECT to_regclass($1) IS NOT NULL", goose.TableName()).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("error finding version table: %w", err)
+	}
+
+	applied := map[int64]bool{}
+	if !exists {
+		return applied, nil
+	}
+
+	rows, err := m.db.QueryContext(ctx, db.AppliedVersionsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("error querying applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version int64
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("error scanning applied migration: %w", err)
+		}
+		applied[version] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error querying applied migrations: %w", err)
+	}
+
+	return applied, nil
+}
+
+// withLock runs f holding the advisory lock. The lock belongs to a session,
+// so it's taken on a connection of its own which is held until f returns.
+func (m *Migrator) withLock(ctx context.Context, f func() error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", lockKey).Scan(&locked); err != nil {
+		return fmt.Errorf("error taking migration lock: %w", err)
+	}
+
+	if !locked {
+		m.logger.Info("waiting for another process to finish migrating")
+
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+			return fmt.Errorf("error taking migration lock: %w", err)
+		}
+	}
+
+	defer func() {
+		// If unlocking fails the lock is released once the migrator is closed
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockKey); err != nil {
+			m.logger.Warn("error releasing migration lock", zap.Error(err))
+		}
+	}()
+
+	return f()
+}
+
+// plan works out the steps from the applied versions to the target.
+func plan(migrations goose.Migrations, applied map[int64]bool, target Target) ([]Step, error) {
+	known := map[int64]*goose.Migration{}
+	for _, migration := range migrations {
+		known[migration.Version] = migration
+	}
+
+	// Goose's own version 0 is never a migration to roll back
+	appliedVersions := make([]int64, 0, len(applied))
+	for version, ok := range applied {
+		if ok && version > 0 {
+			appliedVersions = append(appliedVersions, version)
+		}
+	}
+	sort.Slice(appliedVersions, func(i, j int) bool { return appliedVersions[i] > appliedVersions[j] })
+
+	// down rolls back the applied versions after the given one, latest first
+	down := func(after int64, limit int) ([]Step, error) {
+		var steps []Step
+		for _, version := range appliedVersions {
+			if version <= after || len(steps) == limit {
+				break
+			}
+
+			migration, ok := known[version]
+			if !ok {
+				return nil, fmt.Errorf("migration %d has been applied but isn't in this build, so can't be rolled back", version)
+			}
+
+			steps = append(steps, Step{Migration: migration, Up: false})
+		}
+		return steps, nil
+	}
+
+	// up applies the pending migrations up to and including the version
+	up := func(to int64) []Step {
+		var steps []Step
+		for _, migration := range migrations {
+			if migration.Version <= to && !applied[migration.Version] {
+				steps = append(steps, Step{Migration: migration, Up: true})
+			}
+		}
+		return steps
+	}
+
+	switch target.kind {
+	case targetLatest:
+		return up(math.MaxInt64), nil
+	case targetRollback, targetRedo:
+		if len(appliedVersions) == 0 {
+			return nil, ErrNoMigrationsApplied
+		}
+
+		steps, err := down(math.MinInt64, 1)
+		if err != nil || target.kind == targetRollback {
+			return steps, err
+		}
+
+		return append(steps, Step{Migration: steps[0].Migration, Up: true}), nil
+	case targetVersion:
+		if _, ok := known[target.version]; !ok && target.version != 0 {
+			return nil, fmt.Errorf("no migration %d", target.version)
+		}
+
+		steps, err := down(target.version, -1)
+		if err != nil {
+			return nil, err
+		}
+
+		return append(steps, up(target.version)...), nil
+	default:
+		return nil, fmt.Errorf("unknown target %d", target.kind)
+	}
+}
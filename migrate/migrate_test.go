@@ -0,0 +1,232 @@
+package migrate_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"emailaddress.horse/thousand/db"
+	"emailaddress.horse/thousand/migrate"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pressly/goose/v3"
+)
+
+func TestPlan(t *testing.T) {
+	t.Parallel()
+
+	migrations := goose.Migrations{
+		{Version: 1, Source: "migrations/1_a.sql"},
+		{Version: 2, Source: "migrations/2_b.sql"},
+		{Version: 3, Source: "migrations/3_c.sql"},
+		{Version: 4, Source: "migrations/4_d.sql"},
+	}
+
+	tests := []struct {
+		name     string
+		applied  []int64
+		target   migrate.Target
+		expected []string
+		err      string
+	}{
+		{
+			name:     "latest from nothing",
+			target:   migrate.Latest(),
+			expected: []string{"1_a.sql (up)", "2_b.sql (up)", "3_c.sql (up)", "4_d.sql (up)"},
+		},
+		{
+			name:     "latest with some applied",
+			applied:  []int64{1, 2},
+			target:   migrate.Latest(),
+			expected: []string{"3_c.sql (up)", "4_d.sql (up)"},
+		},
+		{
+			name:     "latest applies migrations missed out of order",
+			applied:  []int64{1, 3},
+			target:   migrate.Latest(),
+			expected: []string{"2_b.sql (up)", "4_d.sql (up)"},
+		},
+		{
+			name:     "latest when up to date",
+			applied:  []int64{1, 2, 3, 4},
+			target:   migrate.Latest(),
+			expected: nil,
+		},
+		{
+			name:     "rollback",
+			applied:  []int64{1, 2, 3},
+			target:   migrate.Rollback(),
+			expected: []string{"3_c.sql (down)"},
+		},
+		{
+			name:   "rollback with nothing applied",
+			target: migrate.Rollback(),
+			err:    migrate.ErrNoMigrationsApplied.Error(),
+		},
+		{
+			name:    "rollback with only goose's version applied",
+			applied: []int64{0},
+			target:  migrate.Rollback(),
+			err:     migrate.ErrNoMigrationsApplied.Error(),
+		},
+		{
+			name:     "rollback ignores goose's version",
+			applied:  []int64{0, 1},
+			target:   migrate.Rollback(),
+			expected: []string{"1_a.sql (down)"},
+		},
+		{
+			name:     "to zero ignores goose's version",
+			applied:  []int64{0, 1, 2},
+			target:   migrate.To(0),
+			expected: []string{"2_b.sql (down)", "1_a.sql (down)"},
+		},
+		{
+			name:     "redo",
+			applied:  []int64{1, 2},
+			target:   migrate.Redo(),
+			expected: []string{"2_b.sql (down)", "2_b.sql (up)"},
+		},
+		{
+			name:     "to a later version",
+			applied:  []int64{1},
+			target:   migrate.To(3),
+			expected: []string{"2_b.sql (up)", "3_c.sql (up)"},
+		},
+		{
+			name:     "to an earlier version",
+			applied:  []int64{1, 2, 3, 4},
+			target:   migrate.To(2),
+			expected: []string{"4_d.sql (down)", "3_c.sql (down)"},
+		},
+		{
+			name:     "to zero",
+			applied:  []int64{1, 2},
+			target:   migrate.To(0),
+			expected: []string{"2_b.sql (down)", "1_a.sql (down)"},
+		},
+		{
+			name:     "to the current version",
+			applied:  []int64{1, 2},
+			target:   migrate.To(2),
+			expected: nil,
+		},
+		{
+			name:   "to an unknown version",
+			target: migrate.To(5),
+			err:    "no migration 5",
+		},
+		{
+			name:    "rollback of a migration not in this build",
+			applied: []int64{1, 2, 3, 4, 5},
+			target:  migrate.Rollback(),
+			err:     "migration 5 has been applied but isn't in this build, so can't be rolled back",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			applied := map[int64]bool{}
+			for _, version := range tt.applied {
+				applied[version] = true
+			}
+
+			steps, err := migrate.Plan(migrations, applied, tt.target)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q; got %v", tt.err, err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var actual []string
+			for _, step := range steps {
+				actual = append(actual, step.String())
+			}
+
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Errorf("steps mismatch (-expected +actual):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPlan_NoMigrationsApplied(t *testing.T) {
+	t.Parallel()
+
+	_, err := migrate.Plan(goose.Migrations{{Version: 1}}, map[int64]bool{}, migrate.Redo())
+	if !errors.Is(err, migrate.ErrNoMigrationsApplied) {
+		t.Errorf("expected ErrNoMigrationsApplied; got %v", err)
+	}
+}
+
+func TestSQLSection(t *testing.T) {
+	t.Parallel()
+
+	migration := `-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE vampires (
+    id uuid PRIMARY KEY
+);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE vampires;
+
+-- +goose StatementEnd
+`
+
+	for _, tt := range []struct {
+		up       bool
+		expected string
+	}{
+		{true, "CREATE TABLE vampires (\n    id uuid PRIMARY KEY\n);"},
+		{false, "DROP TABLE vampires;"},
+	} {
+		tt := tt
+
+		t.Run(fmt.Sprintf("up=%t", tt.up), func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := migrate.SQLSection(strings.NewReader(migration), tt.up)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if actual != tt.expected {
+				t.Errorf("expected %q; got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+// TestStep_SQL makes sure every embedded migration has SQL to run up.
+func TestStep_SQL(t *testing.T) {
+	t.Parallel()
+
+	migrations, err := goose.CollectMigrations(db.FSMigrationsPath, 0, math.MaxInt64)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(migrations) == 0 {
+		t.Fatal("expected embedded migrations")
+	}
+
+	for _, migration := range migrations {
+		sql, err := migrate.Step{Migration: migration, Up: true}.SQL()
+		if err != nil {
+			t.Errorf("unexpected error reading %s: %v", migration.Source, err)
+		} else if sql == "" {
+			t.Errorf("expected %s to have SQL to run up", migration.Source)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// Module migrates the database on start if asked to, then refuses to start
+// while migrations are pending unless they're allowed. It must come before
+// the server's module so the check runs before the server starts listening.
+var Module = fx.Options(
+	fx.Invoke(fxRegister),
+)
+
+type Params struct {
+	fx.In
+
+	AllowPending   bool   `name:"allowPendingMigrations" optional:"true"`
+	DatabaseURL    string `name:"databaseURL"`
+	MigrateOnStart bool   `name:"migrateOnStart" optional:"true"`
+
+	Lifecycle fx.Lifecycle
+	Logger    *zap.Logger
+}
+
+func fxRegister(params Params) {
+	logger := params.Logger.Named("migrate")
+
+	params.Lifecycle.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			m, err := Open(Options{DatabaseURL: params.DatabaseURL, Logger: logger})
+			if err != nil {
+				return err
+			}
+			defer m.Close()
+
+			if params.MigrateOnStart {
+				steps, err := m.Apply(ctx, Latest())
+				if err != nil {
+					return err
+				}
+
+				logger.Info("migrated on start", zap.Int("applied", len(steps)))
+			}
+
+			pending, err := m.Pending(ctx)
+			if err != nil && params.AllowPending {
+				logger.Warn("error checking for pending migrations", zap.Error(err))
+				return nil
+			} else if err != nil {
+				return fmt.Errorf("error checking for pending migrations: %w", err)
+			}
+
+			if len(pending) == 0 {
+				return nil
+			}
+
+			if params.AllowPending {
+				logger.Warn("serving with pending migrations", zap.Int64s("pending", pending))
+				return nil
+			}
+
+			return fmt.Errorf(
+				"%d pending migrations, starting with %d: run migrate run, or set --migrate-on-start to apply them or --allow-pending-migrations to serve anyway",
+				len(pending), pending[0],
+			)
+		},
+	})
+}
@@ -0,0 +1,46 @@
+package migrate
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+const annotationPrefix = "-- +goose"
+
+// sqlSection returns the up or down section of a SQL migration, without the
+// goose annotations which only tell goose how to split and run it.
+func sqlSection(r io.Reader, up bool) (string, error) {
+	want := "Down"
+	if up {
+		want = "Up"
+	}
+
+	var (
+		b       strings.Builder
+		section string
+	)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.HasPrefix(strings.TrimSpace(line), annotationPrefix) {
+			annotation := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), annotationPrefix))
+			if annotation == "Up" || annotation == "Down" {
+				section = annotation
+			}
+			continue
+		}
+
+		if section == want {
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(b.String()), nil
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// AdminSearchLimit is the most users or vampires listed at once in the
+	// admin interface.
+	AdminSearchLimit = 50
+
+	// AdminStatsPeriod is how far back the stats count recent activity.
+	AdminStatsPeriod = 7 * 24 * time.Hour
+)
+
+// AdminStats are totals across every user, for operators.
+type AdminStats struct {
+	Users           int64
+	Admins          int64
+	DisabledUsers   int64
+	Vampires        int64
+	TrashedVampires int64
+	Experiences     int64
+
+	// Recent counts are of things within AdminStatsPeriod before now.
+	RecentUsers        int64
+	RecentLogins       int64
+	RecentFailedLogins int64
+}
+
+// AdminUser is a user as listed to admins.
+type AdminUser struct {
+	User
+	CreatedAt    time.Time
+	VampireCount int64
+}
+
+// AdminVampire is a vampire as listed to admins, along with who owns it.
+type AdminVampire struct {
+	ID         uuid.UUID
+	Name       string
+	OwnerID    uuid.UUID
+	OwnerEmail string
+	CreatedAt  time.Time
+
+	// DeletedAt is the zero time unless the vampire is in the trash.
+	DeletedAt time.Time
+}
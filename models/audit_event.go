@@ -0,0 +1,165 @@
+package models
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditAction is something recorded in the audit log.
+type AuditAction string
+
+const (
+	AuditActionLogin          AuditAction = "session.login"
+	AuditActionLoginFailed    AuditAction = "session.login_failed"
+	AuditActionLogout         AuditAction = "session.logout"
+	AuditActionSignUp         AuditAction = "user.sign_up"
+	AuditActionUserRestored   AuditAction = "user.restored"
+	AuditActionIdentityLink   AuditAction = "identity.linked"
+	AuditActionIdentityUnlink AuditAction = "identity.unlinked"
+
+	AuditActionVampireTrashed    AuditAction = "vampire.trashed"
+	AuditActionVampireRestored   AuditAction = "vampire.restored"
+	AuditActionVampirePurged     AuditAction = "vampire.purged"
+	AuditActionVampireArchived   AuditAction = "vampire.archived"
+	AuditActionVampireUnarchived AuditAction = "vampire.unarchived"
+	AuditActionMemberRemoved     AuditAction = "member.removed"
+	AuditActionInvitationRevoked AuditAction = "invitation.revoked"
+	AuditActionShareLinkRevoked  AuditAction = "share_link.revoked"
+
+	AuditActionPasswordReset        AuditAction = "user.password_reset"
+	AuditActionAdminGranted         AuditAction = "admin.granted"
+	AuditActionImpersonationStarted AuditAction = "admin.impersonation_started"
+	AuditActionImpersonationStopped AuditAction = "admin.impersonation_stopped"
+	AuditActionUserDisabled         AuditAction = "admin.user_disabled"
+	AuditActionUserEnabled          AuditAction = "admin.user_enabled"
+	AuditActionPasswordResetCreated AuditAction = "admin.password_reset_created"
+)
+
+var auditActionDescriptions = map[AuditAction]string{
+	AuditActionLogin:             "Logged in",
+	AuditActionLoginFailed:       "Failed to log in",
+	AuditActionLogout:            "Logged out",
+	AuditActionSignUp:            "Signed up",
+	AuditActionUserRestored:      "Account restored from a backup",
+	AuditActionIdentityLink:      "Linked an account",
+	AuditActionIdentityUnlink:    "Unlinked an account",
+	AuditActionVampireTrashed:    "Moved a vampire to the trash",
+	AuditActionVampireRestored:   "Restored a vampire from the trash",
+	AuditActionVampirePurged:     "Deleted a vampire for good",
+	AuditActionVampireArchived:   "Archived a vampire",
+	AuditActionVampireUnarchived: "Took a vampire out of the archive",
+	AuditActionMemberRemoved:     "Removed a member from a vampire",
+	AuditActionInvitationRevoked: "Revoked an invitation",
+	AuditActionShareLinkRevoked:  "Revoked a share link",
+
+	AuditActionPasswordReset:        "Reset password",
+	AuditActionAdminGranted:         "Made an admin",
+	AuditActionImpersonationStarted: "Admin impersonation started",
+	AuditActionImpersonationStopped: "Admin impersonation stopped",
+	AuditActionUserDisabled:         "Account disabled by an admin",
+	AuditActionUserEnabled:          "Account enabled by an admin",
+	AuditActionPasswordResetCreated: "Password reset link created by an admin",
+}
+
+// Description describes the action for people reading their own activity.
+func (a AuditAction) Description() string {
+	if description, ok := auditActionDescriptions[a]; ok {
+		return description
+	}
+
+	return string(a)
+}
+
+// Kinds of thing an audit event can be about.
+const (
+	AuditTargetUser       = "user"
+	AuditTargetIdentity   = "identity"
+	AuditTargetVampire    = "vampire"
+	AuditTargetMember     = "member"
+	AuditTargetInvitation = "invitation"
+	AuditTargetShareLink  = "share_link"
+)
+
+// AuditEvent records who did something, from where, and what it changed.
+type AuditEvent struct {
+	ID uuid.UUID
+	// ActorID is blank when nobody was logged in, such as for a failed login
+	// or a command run by an operator.
+	ActorID    uuid.UUID
+	ActorEmail string
+	// ImpersonatorID is the admin acting as the actor, if there is one.
+	ImpersonatorID uuid.UUID
+	IP             string
+	UserAgent      string
+	Action         AuditAction
+	TargetType     string
+	TargetID       uuid.UUID
+	Changes        AuditChanges
+	CreatedAt      time.Time
+}
+
+// Impersonated is whether an admin took the action while acting as the actor.
+func (e AuditEvent) Impersonated() bool {
+	return e.ImpersonatorID != uuid.UUID{}
+}
+
+// AuditChange is the value of a field before and after an action. Before is
+// nil for something created and After is nil for something removed.
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditChanges are the changes to each field, keyed by its name.
+type AuditChanges map[string]AuditChange
+
+// DiffAuditValues returns the changes between the values of fields before and
+// after an action, leaving out those which are the same. Either may be nil.
+func DiffAuditValues(before, after map[string]interface{}) AuditChanges {
+	changes := AuditChanges{}
+
+	for field, value := range before {
+		if other, ok := after[field]; !ok || !reflect.DeepEqual(other, value) {
+			changes[field] = AuditChange{Before: value, After: after[field]}
+		}
+	}
+
+	for field, value := range after {
+		if _, ok := before[field]; !ok {
+			changes[field] = AuditChange{After: value}
+		}
+	}
+
+	return changes
+}
+
+const (
+	// DefaultAuditEventLimit is how many audit events are listed if no limit
+	// is given.
+	DefaultAuditEventLimit = 50
+	// MaxAuditEventLimit is the most audit events which can be listed at once.
+	MaxAuditEventLimit = 1000
+)
+
+// AuditEventQuery filters the audit log. Blank fields match everything.
+type AuditEventQuery struct {
+	ActorEmail string
+	Action     AuditAction
+	TargetID   uuid.UUID
+	// Since only matches events from within the duration before now.
+	Since time.Duration
+	Limit int
+}
+
+// Normalize fills in the defaults for anything left blank.
+func (q AuditEventQuery) Normalize() AuditEventQuery {
+	if q.Limit <= 0 {
+		q.Limit = DefaultAuditEventLimit
+	} else if q.Limit > MaxAuditEventLimit {
+		q.Limit = MaxAuditEventLimit
+	}
+
+	return q
+}
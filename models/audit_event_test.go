@@ -0,0 +1,86 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func TestDiffAuditValues(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		before          map[string]interface{}
+		after           map[string]interface{}
+		expectedChanges AuditChanges
+	}{
+		{
+			name:            "unchanged",
+			before:          map[string]interface{}{"name": "Gruffudd"},
+			after:           map[string]interface{}{"name": "Gruffudd"},
+			expectedChanges: AuditChanges{},
+		},
+		{
+			name:   "changed",
+			before: map[string]interface{}{"name": "Gruffudd", "role": "owner"},
+			after:  map[string]interface{}{"name": "Gwydion", "role": "owner"},
+			expectedChanges: AuditChanges{
+				"name": {Before: "Gruffudd", After: "Gwydion"},
+			},
+		},
+		{
+			name:  "created",
+			after: map[string]interface{}{"email": "john@bannister.com"},
+			expectedChanges: AuditChanges{
+				"email": {After: "john@bannister.com"},
+			},
+		},
+		{
+			name:   "removed",
+			before: map[string]interface{}{"email": "john@bannister.com"},
+			expectedChanges: AuditChanges{
+				"email": {Before: "john@bannister.com"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			actualChanges := DiffAuditValues(tt.before, tt.after)
+
+			if diff := cmp.Diff(tt.expectedChanges, actualChanges); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestAuditAction_Description(t *testing.T) {
+	t.Parallel()
+
+	if actual := AuditActionLoginFailed.Description(); actual != "Failed to log in" {
+		t.Errorf("expected description; got %q", actual)
+	}
+
+	if actual := AuditAction("unknown.action").Description(); actual != "unknown.action" {
+		t.Errorf("expected unknown actions to describe themselves; got %q", actual)
+	}
+}
+
+func TestAuditEvent_Impersonated(t *testing.T) {
+	t.Parallel()
+
+	if (AuditEvent{}).Impersonated() {
+		t.Errorf("expected event without impersonator not to be impersonated")
+	}
+
+	if !(AuditEvent{ImpersonatorID: uuid.New()}).Impersonated() {
+		t.Errorf("expected event with impersonator to be impersonated")
+	}
+}
@@ -12,8 +12,8 @@ type Character struct {
 }
 
 type CreateCharacterParams struct {
-	Name string `form:"name"`
-	Type string `form:"type"`
+	Name string `form:"name" validate:"required,max=100"`
+	Type string `form:"type" validate:"required,oneof=mortal immortal" message:"Please choose whether the character is mortal or immortal."`
 }
 
 func (p CreateCharacterParams) MarshalLogObject(enc zapcore.ObjectEncoder) error {
@@ -16,4 +16,24 @@ var (
 
 	// ErrMemoryFull is returned when trying to add experiences to a full memory.
 	ErrMemoryFull = errors.New("Memory is full")
+
+	// ErrIdentityAlreadyLinked is returned when attempting to link an external
+	// identity which is already linked to a user.
+	ErrIdentityAlreadyLinked = errors.New("Identity already linked")
+
+	// ErrLastLoginMethod is returned when attempting to unlink the only
+	// remaining way a user has of logging in.
+	ErrLastLoginMethod = errors.New("Cannot remove last login method")
+
+	// ErrInvalidRole is returned when a role is not one that can be given to
+	// an invited member.
+	ErrInvalidRole = errors.New("Invalid role")
+
+	// ErrInvalidCursor is returned when a cursor given to fetch a page of
+	// results was not one given out with a previous page.
+	ErrInvalidCursor = errors.New("Invalid cursor")
+
+	// ErrAlreadyInvited is returned when inviting an email address which
+	// already has a pending invitation to the vampire.
+	ErrAlreadyInvited = errors.New("Already invited")
 )
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// MemberRole describes what a member of a vampire's chronicle is allowed to
+// do with it.
+type MemberRole string
+
+const (
+	// RoleOwner can do anything with the vampire, including managing who else
+	// has access to it.
+	RoleOwner MemberRole = "owner"
+
+	// RoleEditor can add to the vampire's chronicle.
+	RoleEditor MemberRole = "editor"
+
+	// RoleViewer can read the vampire's chronicle but cannot change it.
+	RoleViewer MemberRole = "viewer"
+)
+
+var memberRoleRanks = map[MemberRole]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleOwner:  3,
+}
+
+// ParseMemberRole returns the role with the provided name. Only editors and
+// viewers can be invited so any other value is an error.
+func ParseMemberRole(name string) (MemberRole, error) {
+	switch role := MemberRole(name); role {
+	case RoleEditor, RoleViewer:
+		return role, nil
+	default:
+		return "", ErrInvalidRole
+	}
+}
+
+// Allows returns true if the role grants at least the permissions of the
+// required role.
+func (r MemberRole) Allows(required MemberRole) bool {
+	rank, ok := memberRoleRanks[r]
+	return ok && rank >= memberRoleRanks[required]
+}
+
+// CanEdit returns true if the role allows adding to the vampire.
+func (r MemberRole) CanEdit() bool {
+	return r.Allows(RoleEditor)
+}
+
+// CanManage returns true if the role allows managing the vampire's members
+// and share links.
+func (r MemberRole) CanManage() bool {
+	return r.Allows(RoleOwner)
+}
+
+// Member is a user with access to a vampire.
+type Member struct {
+	ID        uuid.UUID
+	VampireID uuid.UUID
+	UserID    uuid.UUID
+	Email     string
+	Role      MemberRole
+}
+
+// Invitation is a pending offer for the user with the email address to become
+// a member of a vampire.
+type Invitation struct {
+	ID          uuid.UUID
+	VampireID   uuid.UUID
+	VampireName string
+	Email       string
+	Role        MemberRole
+}
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMemberRole_Allows(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		role           MemberRole
+		required       MemberRole
+		expectedResult bool
+	}{
+		{
+			name:           "owner allows owner",
+			role:           RoleOwner,
+			required:       RoleOwner,
+			expectedResult: true,
+		},
+		{
+			name:           "owner allows viewer",
+			role:           RoleOwner,
+			required:       RoleViewer,
+			expectedResult: true,
+		},
+		{
+			name:           "editor allows editor",
+			role:           RoleEditor,
+			required:       RoleEditor,
+			expectedResult: true,
+		},
+		{
+			name:           "editor does not allow owner",
+			role:           RoleEditor,
+			required:       RoleOwner,
+			expectedResult: false,
+		},
+		{
+			name:           "viewer allows viewer",
+			role:           RoleViewer,
+			required:       RoleViewer,
+			expectedResult: true,
+		},
+		{
+			name:           "viewer does not allow editor",
+			role:           RoleViewer,
+			required:       RoleEditor,
+			expectedResult: false,
+		},
+		{
+			name:           "unknown role allows nothing",
+			role:           MemberRole("admin"),
+			required:       RoleViewer,
+			expectedResult: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			actualResult := tt.role.Allows(tt.required)
+
+			if tt.expectedResult != actualResult {
+				t.Errorf("expected %t; actual %t", tt.expectedResult, actualResult)
+			}
+		})
+	}
+}
+
+func TestParseMemberRole(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		input         string
+		expectedRole  MemberRole
+		expectedError error
+	}{
+		{
+			name:         "editor",
+			input:        "editor",
+			expectedRole: RoleEditor,
+		},
+		{
+			name:         "viewer",
+			input:        "viewer",
+			expectedRole: RoleViewer,
+		},
+		{
+			name:          "owner",
+			input:         "owner",
+			expectedError: ErrInvalidRole,
+		},
+		{
+			name:          "unknown",
+			input:         "admin",
+			expectedError: ErrInvalidRole,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			role, err := ParseMemberRole(tt.input)
+
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected %q; received %q", tt.expectedError, err)
+			}
+
+			if tt.expectedRole != role {
+				t.Errorf("expected %q; actual %q", tt.expectedRole, role)
+			}
+		})
+	}
+}
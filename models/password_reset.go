@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PasswordResetExpiry is how long a password reset link can be used for.
+const PasswordResetExpiry = 24 * time.Hour
+
+// PasswordReset lets whoever has its token choose a new password for the user,
+// once, until it expires. Admins create them for users who can't log in.
+type PasswordReset struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Token     string
+	ExpiresAt time.Time
+}
@@ -13,8 +13,8 @@ type Resource struct {
 }
 
 type CreateResourceParams struct {
-	Description string `form:"description"`
-	Stationary  bool   `form:"stationary"`
+	Description string `form:"description" validate:"required,max=500"`
+	Stationary  bool   `form:"stationary" message:"Please choose whether the resource is stationary."`
 }
 
 func (p CreateResourceParams) MarshalLogObject(enc zapcore.ObjectEncoder) error {
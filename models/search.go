@@ -0,0 +1,41 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxSearchLength is the longest search that can be made, in characters.
+	MaxSearchLength = 200
+	// MaxSearchResults is the most results returned by a single search.
+	MaxSearchResults = 50
+)
+
+// SearchResultKind is the kind of item a search result was found in.
+type SearchResultKind string
+
+const (
+	SearchResultExperience SearchResultKind = "experience"
+	SearchResultSkill      SearchResultKind = "skill"
+	SearchResultResource   SearchResultKind = "resource"
+	SearchResultCharacter  SearchResultKind = "character"
+	SearchResultMark       SearchResultKind = "mark"
+)
+
+// SearchResult is an item in a vampire's chronicle which matched a search.
+type SearchResult struct {
+	Kind SearchResultKind
+	ID   uuid.UUID
+	// MemoryID is the memory an experience was found in. It is blank for
+	// every other kind of result.
+	MemoryID uuid.UUID
+	Snippet  []SnippetPart
+	Rank     float32
+}
+
+// SnippetPart is a piece of the text around a search match. Parts which
+// matched the search are marked so that they can be highlighted.
+type SnippetPart struct {
+	Text  string
+	Match bool
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxShareLinkExpiry is the longest a share link can be created to last for.
+// Links which should last longer can be created to never expire instead.
+const MaxShareLinkExpiry = 365 * 24 * time.Hour
+
+// ShareLink grants anyone with its token read-only access to a vampire until
+// it expires or is revoked.
+type ShareLink struct {
+	ID        uuid.UUID
+	VampireID uuid.UUID
+	Token     string
+
+	// ExpiresAt is the zero time if the link never expires.
+	ExpiresAt time.Time
+
+	// RevokedAt is the zero time if the link has not been revoked.
+	RevokedAt time.Time
+}
+
+// Active returns true if the link can still be used to view the vampire.
+func (l ShareLink) Active(now time.Time) bool {
+	if !l.RevokedAt.IsZero() {
+		return false
+	}
+
+	return l.ExpiresAt.IsZero() || l.ExpiresAt.After(now)
+}
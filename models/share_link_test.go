@@ -0,0 +1,53 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestShareLink_Active(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2022, time.January, 12, 19, 15, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		link           ShareLink
+		expectedResult bool
+	}{
+		{
+			name:           "true without expiry",
+			link:           ShareLink{},
+			expectedResult: true,
+		},
+		{
+			name:           "true before expiry",
+			link:           ShareLink{ExpiresAt: now.Add(time.Hour)},
+			expectedResult: true,
+		},
+		{
+			name:           "false after expiry",
+			link:           ShareLink{ExpiresAt: now.Add(-time.Hour)},
+			expectedResult: false,
+		},
+		{
+			name:           "false when revoked",
+			link:           ShareLink{RevokedAt: now.Add(-time.Hour)},
+			expectedResult: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			actualResult := tt.link.Active(now)
+
+			if tt.expectedResult != actualResult {
+				t.Errorf("expected %t; actual %t", tt.expectedResult, actualResult)
+			}
+		})
+	}
+}
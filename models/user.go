@@ -1,10 +1,34 @@
 package models
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
 type User struct {
 	ID    uuid.UUID
 	Email string
+
+	// IsAdmin is true for operators who can use the admin interface.
+	IsAdmin bool
+
+	// DisabledAt is the zero time unless an admin has disabled the user so
+	// that they can no longer log in.
+	DisabledAt time.Time
+}
+
+// Disabled returns true if the user has been stopped from logging in.
+func (u User) Disabled() bool {
+	return !u.DisabledAt.IsZero()
+}
+
+// UserIdentity is an account with an external identity provider which has been
+// linked to a user so that they can log in with it.
+type UserIdentity struct {
+	ID      uuid.UUID
+	UserID  uuid.UUID
+	Issuer  string
+	Subject string
+	Email   string
 }
@@ -1,13 +1,25 @@
 package models
 
 import (
+	"time"
+
 	"github.com/google/uuid"
+	"go.uber.org/zap/zapcore"
 )
 
 // VampireMemorySize specifies how many active memories a vampire should have,
 // whether they are empty or not.
 const VampireMemorySize = 5
 
+// DefaultTrashRetention is how long a vampire stays in the trash, where it
+// can still be restored, before it is purged.
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
+// MaxTrashRetention is the longest a vampire can be kept in the trash. The
+// retention is passed to the database in seconds, which longer retentions
+// would overflow.
+const MaxTrashRetention = 10 * 365 * 24 * time.Hour
+
 type Vampire struct {
 	ID         uuid.UUID
 	Name       string
@@ -16,4 +28,80 @@ type Vampire struct {
 	Resources  []Resource
 	Characters []Character
 	Marks      []Mark
+	// ArchivedAt is the zero time unless the owner has archived the vampire,
+	// which hides it from the lists of vampires without putting it in the
+	// trash.
+	ArchivedAt time.Time
+}
+
+// Archived returns true if the vampire has been put away in the archive.
+func (v Vampire) Archived() bool {
+	return !v.ArchivedAt.IsZero()
+}
+
+// VampireSort is an order vampires can be listed in.
+type VampireSort string
+
+const (
+	// VampireSortCreated lists the most recently created vampires first.
+	VampireSortCreated VampireSort = "created"
+	// VampireSortName lists vampires alphabetically.
+	VampireSortName VampireSort = "name"
+	// VampireSortPlayed lists the vampires most recently added to first.
+	VampireSortPlayed VampireSort = "played"
+)
+
+const (
+	// DefaultVampirePageSize is how many vampires are listed per page if no
+	// limit is given.
+	DefaultVampirePageSize = 20
+	// MaxVampirePageSize is the most vampires which can be listed per page.
+	MaxVampirePageSize = 100
+)
+
+// VampireQuery describes which page of vampires to list and how.
+type VampireQuery struct {
+	Sort   VampireSort `form:"sort" validate:"oneof=created name played" label:"sort order"`
+	Search string      `form:"q" validate:"max=200" label:"search"`
+	After  string      `form:"after" label:"page"`
+	Limit  int         `form:"limit" validate:"min=1,max=100" label:"limit"`
+}
+
+// Normalize fills in the defaults for anything left blank.
+func (q VampireQuery) Normalize() VampireQuery {
+	if q.Sort == "" {
+		q.Sort = VampireSortCreated
+	}
+
+	if q.Limit <= 0 {
+		q.Limit = DefaultVampirePageSize
+	} else if q.Limit > MaxVampirePageSize {
+		q.Limit = MaxVampirePageSize
+	}
+
+	return q
+}
+
+func (q VampireQuery) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("sort", string(q.Sort))
+	enc.AddString("search", q.Search)
+	enc.AddString("after", q.After)
+	enc.AddInt("limit", q.Limit)
+	return nil
+}
+
+// VampirePage is a page of vampires, along with the cursor for the next page.
+type VampirePage struct {
+	Vampires []Vampire
+	// Next is passed as VampireQuery.After to list the following page. It is
+	// blank on the last page.
+	Next string
+}
+
+// TrashedVampire is a vampire which has been moved to the trash.
+type TrashedVampire struct {
+	Vampire
+	DeletedAt time.Time
+	// PurgeAt is when the vampire can no longer be restored.
+	PurgeAt time.Time
 }
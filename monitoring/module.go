@@ -0,0 +1,89 @@
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"emailaddress.horse/thousand/health"
+	"emailaddress.horse/thousand/server"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var Module = fx.Options(
+	fx.Invoke(register),
+)
+
+type Params struct {
+	fx.In
+
+	Address   string `name:"adminAddress" optional:"true"`
+	BasicAuth string `name:"adminBasicAuth" optional:"true"`
+	Pprof     bool   `name:"adminPprof" optional:"true"`
+	Token     string `name:"adminToken" optional:"true"`
+
+	Health   *health.Health
+	Logger   *zap.Logger
+	Registry *prometheus.Registry
+}
+
+// register starts the admin listener alongside the server, unless it has no
+// address. Startup fails if the address can't be listened on.
+func register(lc fx.Lifecycle, p Params) error {
+	logger := p.Logger.Named("admin")
+
+	if p.Address == "" {
+		logger.Info("admin listener disabled")
+		return nil
+	}
+
+	if p.BasicAuth != "" && !strings.Contains(p.BasicAuth, ":") {
+		return errors.New("admin basic auth must be a username and password separated by a colon")
+	}
+
+	host, portName, err := net.SplitHostPort(p.Address)
+	if err != nil {
+		return fmt.Errorf("error parsing admin address %q: %w", p.Address, err)
+	}
+
+	port, err := strconv.Atoi(portName)
+	if err != nil {
+		return fmt.Errorf("error parsing admin port %q: %w", portName, err)
+	}
+
+	s := server.New(server.Options{
+		Host:   host,
+		Logger: logger,
+		Port:   port,
+		Router: NewRouter(Options{
+			BasicAuth: p.BasicAuth,
+			Health:    p.Health,
+			Logger:    logger,
+			Pprof:     p.Pprof,
+			Registry:  p.Registry,
+			Token:     p.Token,
+		}),
+	})
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			if err := s.Listen(ctx); err != nil {
+				return fmt.Errorf("error starting admin listener on %s: %w", p.Address, err)
+			}
+
+			return nil
+		},
+	})
+
+	lc.Append(fx.Hook{
+		OnStart: s.Start,
+		OnStop:  s.Stop,
+	})
+
+	return nil
+}
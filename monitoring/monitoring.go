@@ -0,0 +1,102 @@
+// Package monitoring serves metrics, health checks and profiles on a listener
+// of their own, so that they can be kept off the public internet.
+package monitoring
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"emailaddress.horse/thousand/handlers"
+	"emailaddress.horse/thousand/health"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+)
+
+const realm = "thousand admin"
+
+type Options struct {
+	// BasicAuth is a username and password, separated by a colon, which must
+	// be given to use the listener. It can be combined with Token.
+	BasicAuth string
+	Health    *health.Health
+	Logger    *zap.Logger
+	// Pprof serves runtime profiles under /debug/pprof.
+	Pprof    bool
+	Registry prometheus.Gatherer
+	// Token is a bearer token which must be given to use the listener. It can
+	// be combined with BasicAuth.
+	Token string
+}
+
+// NewRouter routes requests to the admin listener. Everything is behind
+// authentication if either BasicAuth or Token are set.
+func NewRouter(opts Options) chi.Router {
+	if opts.Logger == nil {
+		opts.Logger = zap.NewNop()
+	}
+
+	r := chi.NewMux()
+
+	Authenticate(r, opts.BasicAuth, opts.Token)
+
+	r.Handle("/metrics", promhttp.HandlerFor(opts.Registry, promhttp.HandlerOpts{}))
+	handlers.Health(r, opts.Logger, opts.Health)
+
+	if opts.Pprof {
+		r.Mount("/debug", middleware.Profiler())
+	}
+
+	return r
+}
+
+// Authenticate requires requests to have either the basic auth credentials or
+// the bearer token. Requests are let through if neither is set.
+func Authenticate(r chi.Router, basicAuth, token string) {
+	if basicAuth == "" && token == "" {
+		return
+	}
+
+	challenge := "Bearer realm=\"" + realm + "\""
+	if basicAuth != "" {
+		challenge = "Basic realm=\"" + realm + "\""
+	}
+
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if authorized(r, basicAuth, token) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("WWW-Authenticate", challenge)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		})
+	})
+}
+
+func authorized(r *http.Request, basicAuth, token string) bool {
+	if basicAuth != "" {
+		if username, password, ok := r.BasicAuth(); ok && matches(username+":"+password, basicAuth) {
+			return true
+		}
+	}
+
+	if token != "" {
+		header := r.Header.Get("Authorization")
+		if strings.HasPrefix(header, "Bearer ") && matches(strings.TrimPrefix(header, "Bearer "), token) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matches compares secrets in constant time so that they can't be guessed a
+// character at a time.
+func matches(given, expected string) bool {
+	return subtle.ConstantTimeCompare([]byte(given), []byte(expected)) == 1
+}
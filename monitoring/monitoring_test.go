@@ -0,0 +1,160 @@
+package monitoring_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"emailaddress.horse/thousand/health"
+	"emailaddress.horse/thousand/monitoring"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestNewRouter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		opts           monitoring.Options
+		path           string
+		authorize      func(*http.Request)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "metrics",
+			path:           "/metrics",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "test_total 1",
+		},
+		{
+			name:           "health",
+			path:           "/health",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"name": "test"`,
+		},
+		{
+			name:           "liveness",
+			path:           "/livez",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"status": "ok"`,
+		},
+		{
+			name:           "readiness",
+			path:           "/readyz",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"name": "test"`,
+		},
+		{
+			name:           "pprof disabled",
+			path:           "/debug/pprof/",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "pprof enabled",
+			opts:           monitoring.Options{Pprof: true},
+			path:           "/debug/pprof/",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "goroutine",
+		},
+		{
+			name:           "missing token",
+			opts:           monitoring.Options{Token: "token"},
+			path:           "/metrics",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "wrong token",
+			opts: monitoring.Options{Token: "token"},
+			path: "/metrics",
+			authorize: func(r *http.Request) {
+				r.Header.Set("Authorization", "Bearer wrong")
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "token",
+			opts: monitoring.Options{Token: "token"},
+			path: "/metrics",
+			authorize: func(r *http.Request) {
+				r.Header.Set("Authorization", "Bearer token")
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "test_total 1",
+		},
+		{
+			name: "wrong basic auth",
+			opts: monitoring.Options{BasicAuth: "admin:password"},
+			path: "/metrics",
+			authorize: func(r *http.Request) {
+				r.SetBasicAuth("admin", "wrong")
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "basic auth",
+			opts: monitoring.Options{BasicAuth: "admin:password"},
+			path: "/metrics",
+			authorize: func(r *http.Request) {
+				r.SetBasicAuth("admin", "password")
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "test_total 1",
+		},
+		{
+			name: "token when both are accepted",
+			opts: monitoring.Options{BasicAuth: "admin:password", Token: "token"},
+			path: "/metrics",
+			authorize: func(r *http.Request) {
+				r.Header.Set("Authorization", "Bearer token")
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "test_total 1",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := prometheus.NewRegistry()
+			counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "test_total", Help: "A test counter."})
+			registry.MustRegister(counter)
+			counter.Inc()
+
+			h := health.New(health.Options{})
+			h.Register(&health.Component{
+				Name:  "test",
+				Check: func(context.Context) error { return nil },
+			})
+
+			opts := tt.opts
+			opts.Health = h
+			opts.Registry = registry
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.authorize != nil {
+				tt.authorize(req)
+			}
+			rec := httptest.NewRecorder()
+
+			monitoring.NewRouter(opts).ServeHTTP(rec, req)
+
+			if tt.expectedStatus != rec.Code {
+				t.Errorf("expected status %d; got %d", tt.expectedStatus, rec.Code)
+			}
+
+			if !strings.Contains(rec.Body.String(), tt.expectedBody) {
+				t.Errorf("expected body to contain %q; got %q", tt.expectedBody, rec.Body.String())
+			}
+
+			if rec.Code == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("expected a challenge")
+			}
+		})
+	}
+}
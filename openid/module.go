@@ -0,0 +1,33 @@
+package openid
+
+import "go.uber.org/fx"
+
+var Module = fx.Options(
+	fx.Provide(fxNew),
+)
+
+type Params struct {
+	fx.In
+
+	Name         string `name:"oidcName" optional:"true"`
+	Issuer       string `name:"oidcIssuer" optional:"true"`
+	ClientID     string `name:"oidcClientID" optional:"true"`
+	ClientSecret string `name:"oidcClientSecret" optional:"true"`
+	RedirectURL  string `name:"oidcRedirectURL" optional:"true"`
+}
+
+// fxNew provides a nil provider when no issuer has been configured, which
+// disables logging in with OpenID Connect.
+func fxNew(params Params) *Provider {
+	if params.Issuer == "" {
+		return nil
+	}
+
+	return New(Options{
+		Name:         params.Name,
+		Issuer:       params.Issuer,
+		ClientID:     params.ClientID,
+		ClientSecret: params.ClientSecret,
+		RedirectURL:  params.RedirectURL,
+	})
+}
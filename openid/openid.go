@@ -0,0 +1,170 @@
+package openid
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"emailaddress.horse/thousand/errors"
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+var (
+	// ErrMissingIDToken is returned when the token response from the identity
+	// provider does not include an ID token.
+	ErrMissingIDToken = errors.New("missing ID token")
+
+	// ErrNonceMismatch is returned when the nonce in the ID token does not match
+	// the nonce sent with the authorization request.
+	ErrNonceMismatch = errors.New("nonce mismatch")
+)
+
+// Provider performs the OpenID Connect authorization code flow, with PKCE,
+// against a single identity provider. The provider's configuration is
+// discovered the first time it is needed so that an unavailable provider does
+// not prevent the application from starting.
+type Provider struct {
+	name   string
+	issuer string
+	client *http.Client
+	config oauth2.Config
+
+	mu       sync.Mutex
+	verifier *oidc.IDTokenVerifier
+}
+
+type Options struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	Client       *http.Client
+}
+
+// Identity is the verified identity of a user returned by the identity
+// provider.
+type Identity struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+}
+
+func New(opts Options) *Provider {
+	if opts.Name == "" {
+		opts.Name = "OpenID Connect"
+	}
+
+	if opts.Scopes == nil {
+		opts.Scopes = []string{"email"}
+	}
+
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+
+	return &Provider{
+		name:   opts.Name,
+		issuer: opts.Issuer,
+		client: opts.Client,
+		config: oauth2.Config{
+			ClientID:     opts.ClientID,
+			ClientSecret: opts.ClientSecret,
+			RedirectURL:  opts.RedirectURL,
+			Scopes:       append([]string{oidc.ScopeOpenID}, opts.Scopes...),
+		},
+	}
+}
+
+// Name returns the human readable name of the identity provider.
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// AuthCodeURL returns the URL of the identity provider's authorization
+// endpoint which the user should be sent to in order to log in.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	config, _, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return config.AuthCodeURL(state,
+		oidc.Nonce(nonce),
+		oauth2.SetAuthURLParam("code_challenge", codeChallenge(codeVerifier)),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	), nil
+}
+
+// Exchange swaps the authorization code returned to the redirect URL for an ID
+// token and returns the identity it contains once it has been verified.
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (Identity, error) {
+	config, verifier, err := p.discover(ctx)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	ctx = oidc.ClientContext(ctx, p.client)
+
+	token, err := config.Exchange(ctx, code, oauth2.SetAuthURLParam("code_verifier", codeVerifier))
+	if err != nil {
+		return Identity{}, fmt.Errorf("error exchanging code: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return Identity{}, ErrMissingIDToken
+	}
+
+	idToken, err := verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return Identity{}, fmt.Errorf("error verifying ID token: %w", err)
+	}
+
+	if idToken.Nonce != nonce {
+		return Identity{}, ErrNonceMismatch
+	}
+
+	var claims struct {
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return Identity{}, fmt.Errorf("error parsing ID token claims: %w", err)
+	}
+
+	return Identity{
+		Issuer:        idToken.Issuer,
+		Subject:       idToken.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+	}, nil
+}
+
+func (p *Provider) discover(ctx context.Context) (oauth2.Config, *oidc.IDTokenVerifier, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.verifier == nil {
+		provider, err := oidc.NewProvider(oidc.ClientContext(ctx, p.client), p.issuer)
+		if err != nil {
+			return oauth2.Config{}, nil, fmt.Errorf("error discovering identity provider: %w", err)
+		}
+
+		p.config.Endpoint = provider.Endpoint()
+		p.verifier = provider.Verifier(&oidc.Config{ClientID: p.config.ClientID})
+	}
+
+	return p.config, p.verifier, nil
+}
+
+func codeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,202 @@
+package openid_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"emailaddress.horse/thousand/openid"
+	"emailaddress.horse/thousand/openid/openidtest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProvider_Exchange(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		identity         openidtest.Identity
+		exchangeVerifier string
+		exchangeNonce    string
+		exchangeCode     string
+		expectedIdentity openid.Identity
+		expectedError    error
+		expectError      bool
+	}{
+		{
+			name: "successful",
+			identity: openidtest.Identity{
+				Subject:       "12345",
+				Email:         "john@bannister.com",
+				EmailVerified: true,
+			},
+			expectedIdentity: openid.Identity{
+				Subject:       "12345",
+				Email:         "john@bannister.com",
+				EmailVerified: true,
+			},
+		},
+		{
+			name: "unverified email",
+			identity: openidtest.Identity{
+				Subject: "12345",
+				Email:   "john@bannister.com",
+			},
+			expectedIdentity: openid.Identity{
+				Subject: "12345",
+				Email:   "john@bannister.com",
+			},
+		},
+		{
+			name:          "nonce mismatch",
+			identity:      openidtest.Identity{Subject: "12345"},
+			exchangeNonce: "another nonce",
+			expectedError: openid.ErrNonceMismatch,
+			expectError:   true,
+		},
+		{
+			name:             "code verifier mismatch",
+			identity:         openidtest.Identity{Subject: "12345"},
+			exchangeVerifier: "another verifier",
+			expectError:      true,
+		},
+		{
+			name:         "unknown code",
+			identity:     openidtest.Identity{Subject: "12345"},
+			exchangeCode: "unknown",
+			expectError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := openidtest.NewServer()
+			t.Cleanup(server.Close)
+
+			provider := openid.New(openid.Options{
+				Issuer:       server.URL,
+				ClientID:     openidtest.ClientID,
+				ClientSecret: openidtest.ClientSecret,
+				RedirectURL:  "http://thousand.test/session/oidc/callback",
+			})
+
+			authURL, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "verifier")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			redirect, err := server.Authorize(authURL, tt.identity)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			redirectURL, err := url.Parse(redirect)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if state := redirectURL.Query().Get("state"); state != "state" {
+				t.Errorf("expected state %q; got %q", "state", state)
+			}
+
+			code := redirectURL.Query().Get("code")
+			if tt.exchangeCode != "" {
+				code = tt.exchangeCode
+			}
+
+			verifier := "verifier"
+			if tt.exchangeVerifier != "" {
+				verifier = tt.exchangeVerifier
+			}
+
+			nonce := "nonce"
+			if tt.exchangeNonce != "" {
+				nonce = tt.exchangeNonce
+			}
+
+			identity, err := provider.Exchange(context.Background(), code, verifier, nonce)
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("expected error; got nil")
+				}
+
+				if tt.expectedError != nil && !errors.Is(err, tt.expectedError) {
+					t.Errorf("expected %q; received %q", tt.expectedError, err)
+				}
+
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tt.expectedIdentity.Issuer = server.URL
+			if diff := cmp.Diff(tt.expectedIdentity, identity); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestProvider_AuthCodeURL(t *testing.T) {
+	t.Parallel()
+
+	server := openidtest.NewServer()
+	t.Cleanup(server.Close)
+
+	provider := openid.New(openid.Options{
+		Issuer:      server.URL,
+		ClientID:    openidtest.ClientID,
+		RedirectURL: "http://thousand.test/session/oidc/callback",
+	})
+
+	authURL, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "verifier")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedQuery := url.Values{
+		"client_id":             []string{openidtest.ClientID},
+		"code_challenge":        []string{"iMnq5o6zALKXGivsnlom_0F5_WYda32GHkxlV7mq7hQ"},
+		"code_challenge_method": []string{"S256"},
+		"nonce":                 []string{"nonce"},
+		"redirect_uri":          []string{"http://thousand.test/session/oidc/callback"},
+		"response_type":         []string{"code"},
+		"scope":                 []string{"openid email"},
+		"state":                 []string{"state"},
+	}
+
+	if diff := cmp.Diff(expectedQuery, u.Query()); diff != "" {
+		t.Error(diff)
+	}
+
+	if expected := server.URL + "/authorize"; u.Scheme+"://"+u.Host+u.Path != expected {
+		t.Errorf("expected endpoint %q; got %q", expected, u.Scheme+"://"+u.Host+u.Path)
+	}
+}
+
+func TestProvider_UnavailableIssuer(t *testing.T) {
+	t.Parallel()
+
+	server := openidtest.NewServer()
+	server.Close()
+
+	provider := openid.New(openid.Options{
+		Issuer:   server.URL,
+		ClientID: openidtest.ClientID,
+	})
+
+	if _, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "verifier"); err == nil {
+		t.Error("expected error; got nil")
+	}
+}
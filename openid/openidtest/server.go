@@ -0,0 +1,242 @@
+// Package openidtest provides a stand-in OpenID Connect provider for use in
+// tests.
+package openidtest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	jose "gopkg.in/square/go-jose.v2"
+)
+
+const (
+	// ClientID is the only client ID the server will issue tokens for.
+	ClientID = "thousand"
+
+	// ClientSecret is the secret the client must authenticate with.
+	ClientSecret = "secret"
+)
+
+// Server is an OpenID Connect provider served from httptest which supports
+// discovery, the authorization code flow with PKCE and signed ID tokens.
+type Server struct {
+	*httptest.Server
+
+	signer jose.Signer
+	keys   jose.JSONWebKeySet
+
+	mu     sync.Mutex
+	grants map[string]grant
+}
+
+// Identity is the user the server should authenticate when a client sends
+// them to the authorization endpoint.
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+}
+
+type grant struct {
+	identity      Identity
+	nonce         string
+	codeChallenge string
+	redirectURI   string
+}
+
+// NewServer starts a new provider. Callers should call Close when finished.
+func NewServer() *Server {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+
+	signer, err := jose.NewSigner(jose.SigningKey{
+		Algorithm: jose.RS256,
+		Key:       jose.JSONWebKey{Key: key, KeyID: "test", Algorithm: string(jose.RS256), Use: "sig"},
+	}, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	s := &Server{
+		signer: signer,
+		keys: jose.JSONWebKeySet{
+			Keys: []jose.JSONWebKey{
+				{Key: key.Public(), KeyID: "test", Algorithm: string(jose.RS256), Use: "sig"},
+			},
+		},
+		grants: map[string]grant{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.discovery)
+	mux.HandleFunc("/keys", s.jwks)
+	mux.HandleFunc("/token", s.token)
+
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// Authorize simulates the user logging in at the authorization URL built by
+// the client. It returns the URL the provider would redirect the user back to,
+// including the authorization code and state.
+func (s *Server) Authorize(authURL string, identity Identity) (string, error) {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		return "", err
+	}
+
+	query := u.Query()
+
+	if query.Get("client_id") != ClientID {
+		return "", fmt.Errorf("unknown client_id: %q", query.Get("client_id"))
+	}
+
+	if query.Get("response_type") != "code" {
+		return "", fmt.Errorf("unsupported response_type: %q", query.Get("response_type"))
+	}
+
+	if query.Get("code_challenge_method") != "S256" {
+		return "", fmt.Errorf("unsupported code_challenge_method: %q", query.Get("code_challenge_method"))
+	}
+
+	code := randomString()
+
+	s.mu.Lock()
+	s.grants[code] = grant{
+		identity:      identity,
+		nonce:         query.Get("nonce"),
+		codeChallenge: query.Get("code_challenge"),
+		redirectURI:   query.Get("redirect_uri"),
+	}
+	s.mu.Unlock()
+
+	redirect, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil {
+		return "", err
+	}
+
+	redirectQuery := redirect.Query()
+	redirectQuery.Set("code", code)
+	redirectQuery.Set("state", query.Get("state"))
+	redirect.RawQuery = redirectQuery.Encode()
+
+	return redirect.String(), nil
+}
+
+func (s *Server) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                s.URL,
+		"authorization_endpoint":                s.URL + "/authorize",
+		"token_endpoint":                        s.URL + "/token",
+		"jwks_uri":                              s.URL + "/keys",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (s *Server) jwks(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.keys)
+}
+
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, "invalid_request")
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+
+	if clientID != ClientID || clientSecret != ClientSecret {
+		tokenError(w, "invalid_client")
+		return
+	}
+
+	code := r.PostForm.Get("code")
+
+	s.mu.Lock()
+	g, ok := s.grants[code]
+	delete(s.grants, code)
+	s.mu.Unlock()
+
+	if !ok || r.PostForm.Get("grant_type") != "authorization_code" || r.PostForm.Get("redirect_uri") != g.redirectURI {
+		tokenError(w, "invalid_grant")
+		return
+	}
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != g.codeChallenge {
+		tokenError(w, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss":            s.URL,
+		"sub":            g.identity.Subject,
+		"aud":            ClientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+		"nonce":          g.nonce,
+		"email":          g.identity.Email,
+		"email_verified": g.identity.EmailVerified,
+	})
+	if err != nil {
+		tokenError(w, "server_error")
+		return
+	}
+
+	signed, err := s.signer.Sign(claims)
+	if err != nil {
+		tokenError(w, "server_error")
+		return
+	}
+
+	idToken, err := signed.CompactSerialize()
+	if err != nil {
+		tokenError(w, "server_error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+func tokenError(w http.ResponseWriter, code string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func randomString() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,110 @@
+package registry
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The ways a user can sign up or log in, used to label the sign up and login
+// counters.
+const (
+	AuthPassword = "password"
+	AuthOIDC     = "oidc"
+)
+
+// Game counts what players do, as opposed to how the server is performing. It
+// is a collector so that it can be created before the registry it's exported
+// through.
+//
+// There's no counter for forgotten memories because nothing can forget or
+// replace a memory yet. Add one alongside memoriesFilled when something can.
+type Game struct {
+	vampiresCreated    prometheus.Counter
+	experiencesWritten prometheus.Counter
+	memoriesFilled     prometheus.Counter
+	signUps            *prometheus.CounterVec
+	logins             *prometheus.CounterVec
+}
+
+func NewGame() *Game {
+	g := &Game{
+		vampiresCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "app_vampires_created_total",
+			Help: "The total number of vampires created.",
+		}),
+		experiencesWritten: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "app_experiences_written_total",
+			Help: "The total number of experiences written.",
+		}),
+		memoriesFilled: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "app_memories_filled_total",
+			Help: "The total number of memories which have had their last experience written.",
+		}),
+		signUps: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "app_sign_ups_total",
+			Help: "The total number of attempts to sign up.",
+		}, []string{"method", "result"}),
+		logins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "app_logins_total",
+			Help: "The total number of attempts to log in.",
+		}, []string{"method", "result"}),
+	}
+
+	// Every combination of labels is exported from the start so that rates
+	// can be calculated before the first failure
+	for _, method := range []string{AuthPassword, AuthOIDC} {
+		for _, ok := range []bool{true, false} {
+			g.signUps.WithLabelValues(method, result(ok))
+			g.logins.WithLabelValues(method, result(ok))
+		}
+	}
+
+	return g
+}
+
+func (g *Game) VampireCreated() {
+	g.vampiresCreated.Inc()
+}
+
+func (g *Game) ExperienceWritten() {
+	g.experiencesWritten.Inc()
+}
+
+func (g *Game) MemoryFilled() {
+	g.memoriesFilled.Inc()
+}
+
+// SignUp counts an attempt to sign up with the method, which is one of the
+// Auth constants.
+func (g *Game) SignUp(method string, ok bool) {
+	g.signUps.WithLabelValues(method, result(ok)).Inc()
+}
+
+// Login counts an attempt to log in with the method, which is one of the Auth
+// constants.
+func (g *Game) Login(method string, ok bool) {
+	g.logins.WithLabelValues(method, result(ok)).Inc()
+}
+
+func (g *Game) Describe(ch chan<- *prometheus.Desc) {
+	g.vampiresCreated.Describe(ch)
+	g.experiencesWritten.Describe(ch)
+	g.memoriesFilled.Describe(ch)
+	g.signUps.Describe(ch)
+	g.logins.Describe(ch)
+}
+
+func (g *Game) Collect(ch chan<- prometheus.Metric) {
+	g.vampiresCreated.Collect(ch)
+	g.experiencesWritten.Collect(ch)
+	g.memoriesFilled.Collect(ch)
+	g.signUps.Collect(ch)
+	g.logins.Collect(ch)
+}
+
+func result(ok bool) string {
+	if ok {
+		return "success"
+	}
+
+	return "failure"
+}
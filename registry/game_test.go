@@ -0,0 +1,71 @@
+package registry_test
+
+import (
+	"strings"
+	"testing"
+
+	"emailaddress.horse/thousand/registry"
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestGame(t *testing.T) {
+	r := prometheus.NewRegistry()
+	game := registry.NewGame()
+	if err := r.Register(game); err != nil {
+		t.Fatal(err)
+	}
+
+	game.VampireCreated()
+	game.ExperienceWritten()
+	game.ExperienceWritten()
+	game.MemoryFilled()
+	game.SignUp(registry.AuthPassword, true)
+	game.SignUp(registry.AuthOIDC, false)
+	game.Login(registry.AuthPassword, false)
+	game.Login(registry.AuthPassword, false)
+	game.Login(registry.AuthOIDC, true)
+
+	families, err := r.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every combination of labels is present, even those which haven't been
+	// counted yet
+	expected := map[string]map[string]float64{
+		"app_vampires_created_total":    {"": 1},
+		"app_experiences_written_total": {"": 2},
+		"app_memories_filled_total":     {"": 1},
+		"app_sign_ups_total": {
+			"oidc failure":     1,
+			"oidc success":     0,
+			"password failure": 0,
+			"password success": 1,
+		},
+		"app_logins_total": {
+			"oidc failure":     0,
+			"oidc success":     1,
+			"password failure": 2,
+			"password success": 0,
+		},
+	}
+
+	actual := map[string]map[string]float64{}
+	for _, family := range families {
+		series := map[string]float64{}
+		for _, metric := range family.GetMetric() {
+			// Labels are sorted by name, so method comes before result
+			var values []string
+			for _, label := range metric.GetLabel() {
+				values = append(values, label.GetValue())
+			}
+			series[strings.Join(values, " ")] = metric.GetCounter().GetValue()
+		}
+		actual[family.GetName()] = series
+	}
+
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
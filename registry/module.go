@@ -1,27 +1,27 @@
 package registry
 
 import (
-	"context"
-	"net/http"
-
 	"emailaddress.horse/thousand/repository"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/fx"
 )
 
 var Module = fx.Options(
 	fx.Provide(fxNew),
+	fx.Provide(NewGame),
 )
 
 type Params struct {
 	fx.In
 
+	Game       *Game
 	Repository *repository.Repository
 }
 
-func fxNew(lc fx.Lifecycle, params Params) (*prometheus.Registry, error) {
+// fxNew creates the registry metrics are collected in. They are served by the
+// admin listener.
+func fxNew(params Params) (*prometheus.Registry, error) {
 	r := prometheus.NewRegistry()
 
 	if err := r.Register(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{})); err != nil {
@@ -36,18 +36,9 @@ func fxNew(lc fx.Lifecycle, params Params) (*prometheus.Registry, error) {
 		return nil, err
 	}
 
-	s := &http.Server{
-		Addr:    ":9091",
-		Handler: promhttp.HandlerFor(r, promhttp.HandlerOpts{}),
+	if err := r.Register(params.Game); err != nil {
+		return nil, err
 	}
 
-	lc.Append(fx.Hook{
-		OnStart: func(_ context.Context) error {
-			go func() { _ = s.ListenAndServe() }()
-			return nil
-		},
-		OnStop: s.Shutdown,
-	})
-
 	return r, nil
 }
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"emailaddress.horse/thousand/models"
+	"emailaddress.horse/thousand/repository/queries"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+// GetAdminStats counts users, vampires and recent activity across everyone.
+func (m *Repository) GetAdminStats(ctx context.Context) (models.AdminStats, error) {
+	ctx, span := m.startSpan(ctx, "GetAdminStats")
+	defer span.End()
+
+	row, err := m.queries.GetAdminStats(ctx, queries.GetAdminStatsParams{
+		RecentSeconds:     int32(models.AdminStatsPeriod.Seconds()),
+		LoginAction:       string(models.AuditActionLogin),
+		LoginFailedAction: string(models.AuditActionLoginFailed),
+	})
+	if err != nil {
+		return models.AdminStats{}, err
+	}
+
+	return models.AdminStats{
+		Users:              row.Users,
+		Admins:             row.Admins,
+		DisabledUsers:      row.DisabledUsers,
+		Vampires:           row.Vampires,
+		TrashedVampires:    row.TrashedVampires,
+		Experiences:        row.Experiences,
+		RecentUsers:        row.RecentUsers,
+		RecentLogins:       row.RecentLogins,
+		RecentFailedLogins: row.RecentFailedLogins,
+	}, nil
+}
+
+// SearchUsers retrieves the most recently created users whose email contains
+// the search or whose ID is the search. A blank search matches everyone.
+func (m *Repository) SearchUsers(ctx context.Context, search string) ([]models.AdminUser, error) {
+	ctx, span := m.startSpan(ctx, "SearchUsers")
+	defer span.End()
+
+	rows, err := m.queries.SearchUsers(ctx, queries.SearchUsersParams{
+		Search:   search,
+		PageSize: models.AdminSearchLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]models.AdminUser, len(rows))
+	for i, row := range rows {
+		users[i] = models.AdminUser{
+			User: models.User{
+				ID:         row.ID,
+				Email:      row.Email,
+				IsAdmin:    row.IsAdmin,
+				DisabledAt: row.DisabledAt.Time,
+			},
+			CreatedAt:    row.CreatedAt,
+			VampireCount: row.VampireCount,
+		}
+	}
+
+	return users, nil
+}
+
+// SearchVampires retrieves the most recently created vampires, including those
+// in the trash, whose name matches the search, whose owner's email contains it
+// or whose ID is it. A blank search matches every vampire.
+func (m *Repository) SearchVampires(ctx context.Context, search string) ([]models.AdminVampire, error) {
+	ctx, span := m.startSpan(ctx, "SearchVampires")
+	defer span.End()
+
+	rows, err := m.queries.SearchVampires(ctx, queries.SearchVampiresParams{
+		Search:   search,
+		PageSize: models.AdminSearchLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	vampires := make([]models.AdminVampire, len(rows))
+	for i, row := range rows {
+		vampires[i] = models.AdminVampire{
+			ID:         row.ID,
+			Name:       row.Name,
+			OwnerID:    row.UserID.UUID,
+			OwnerEmail: row.OwnerEmail,
+			CreatedAt:  row.CreatedAt,
+			DeletedAt:  row.DeletedAt.Time,
+		}
+	}
+
+	return vampires, nil
+}
+
+// GrantAdmin makes the user with the email an admin. Granting it to someone
+// who is already an admin changes nothing.
+func (m *Repository) GrantAdmin(ctx context.Context, email string) (models.User, error) {
+	ctx, span := m.startSpan(ctx, "GrantAdmin")
+	defer span.End()
+
+	txRepo, tx, err := m.WithTx(ctx)
+	if err != nil {
+		return models.User{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	before, err := txRepo.queries.GetUserByEmail(ctx, email)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.User{}, models.ErrNotFound.Cause(err)
+	} else if err != nil {
+		return models.User{}, err
+	}
+
+	if before.IsAdmin {
+		return newUser(before), nil
+	}
+
+	after, err := txRepo.queries.SetUserAdmin(ctx, queries.SetUserAdminParams{
+		ID:      before.ID,
+		IsAdmin: true,
+	})
+	if err != nil {
+		return models.User{}, err
+	}
+
+	changes := models.DiffAuditValues(
+		map[string]interface{}{"is_admin": before.IsAdmin},
+		map[string]interface{}{"is_admin": after.IsAdmin},
+	)
+	if err := txRepo.audit(ctx, models.AuditActionAdminGranted, models.AuditTargetUser, after.ID, changes); err != nil {
+		return models.User{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.User{}, err
+	}
+
+	return newUser(after), nil
+}
+
+// SetUserDisabled disables the user, stopping them from logging in, or
+// enables them again. Disabling someone already disabled changes nothing.
+func (m *Repository) SetUserDisabled(ctx context.Context, id uuid.UUID, disabled bool) (models.User, error) {
+	ctx, span := m.startSpan(ctx, "SetUserDisabled")
+	defer span.End()
+
+	txRepo, tx, err := m.WithTx(ctx)
+	if err != nil {
+		return models.User{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	before, err := txRepo.queries.GetUser(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.User{}, models.ErrNotFound.Cause(err)
+	} else if err != nil {
+		return models.User{}, err
+	}
+
+	if before.DisabledAt.Valid == disabled {
+		return newUser(before), nil
+	}
+
+	after, err := txRepo.queries.SetUserDisabled(ctx, queries.SetUserDisabledParams{
+		ID:       id,
+		Disabled: disabled,
+	})
+	if err != nil {
+		return models.User{}, err
+	}
+
+	action := models.AuditActionUserEnabled
+	if disabled {
+		action = models.AuditActionUserDisabled
+	}
+
+	changes := models.DiffAuditValues(
+		map[string]interface{}{"disabled_at": auditTime(before.DisabledAt)},
+		map[string]interface{}{"disabled_at": auditTime(after.DisabledAt)},
+	)
+	if err := txRepo.audit(ctx, action, models.AuditTargetUser, id, changes); err != nil {
+		return models.User{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.User{}, err
+	}
+
+	return newUser(after), nil
+}
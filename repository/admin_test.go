@@ -0,0 +1,192 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"emailaddress.horse/thousand/form"
+	"emailaddress.horse/thousand/models"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func TestGetAdminStats(t *testing.T) {
+	m := newTestRepository(t)
+
+	if _, err := m.CreateVampire(context.Background(), m.UserID(), "Gruffudd"); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := m.GetAdminStats(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Other tests share the database, so only a lower bound is known
+	if stats.Users < 1 || stats.RecentUsers < 1 || stats.Vampires < 1 {
+		t.Errorf("expected the test user and vampire to be counted; got %+v", stats)
+	}
+}
+
+func TestSearchUsers(t *testing.T) {
+	m := newTestRepository(t)
+
+	email := fmt.Sprintf("%s@example.com", uuid.New())
+	user, err := m.CreateUser(context.Background(), form.NewUser(email, "password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.CreateVampire(context.Background(), user.ID, "Gruffudd"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, search := range []string{email[:20], user.ID.String()} {
+		users, err := m.SearchUsers(context.Background(), search)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(users) != 1 {
+			t.Fatalf("expected one user searching for %q; got %d", search, len(users))
+		}
+
+		if users[0].ID != user.ID || users[0].VampireCount != 1 {
+			t.Errorf("expected %q with a vampire; got %+v", user.ID, users[0])
+		}
+	}
+}
+
+func TestSearchVampires(t *testing.T) {
+	m := newTestRepository(t)
+
+	email := fmt.Sprintf("%s@example.com", uuid.New())
+	user, err := m.CreateUser(context.Background(), form.NewUser(email, "password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vampire, err := m.CreateVampire(context.Background(), user.ID, "Gruffudd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.TrashVampire(context.Background(), vampire.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, search := range []string{email, vampire.ID.String()} {
+		vampires, err := m.SearchVampires(context.Background(), search)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(vampires) != 1 {
+			t.Fatalf("expected one vampire searching for %q; got %d", search, len(vampires))
+		}
+
+		actual := vampires[0]
+		if actual.ID != vampire.ID || actual.OwnerEmail != email || actual.DeletedAt.IsZero() {
+			t.Errorf("expected trashed %q owned by %q; got %+v", vampire.ID, email, actual)
+		}
+	}
+}
+
+func TestGrantAdmin(t *testing.T) {
+	m := newTestRepository(t)
+
+	email := fmt.Sprintf("%s@example.com", uuid.New())
+	user, err := m.CreateUser(context.Background(), form.NewUser(email, "password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Granting it twice only changes anything once
+	for i := 0; i < 2; i++ {
+		admin, err := m.GrantAdmin(context.Background(), email)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !admin.IsAdmin {
+			t.Errorf("expected user to be an admin")
+		}
+	}
+
+	actual, err := m.GetUser(context.Background(), user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !actual.IsAdmin {
+		t.Errorf("expected user to be an admin once retrieved")
+	}
+
+	events, err := m.GetAuditEvents(context.Background(), models.AuditEventQuery{TargetID: user.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]string{"admin.granted"}, auditActions(events)); diff != "" {
+		t.Error(diff)
+	}
+
+	_, err = m.GrantAdmin(context.Background(), fmt.Sprintf("%s@example.com", uuid.New()))
+	if !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("expected not found for an unknown email; got %v", err)
+	}
+}
+
+func TestSetUserDisabled(t *testing.T) {
+	m := newTestRepository(t)
+
+	email := fmt.Sprintf("%s@example.com", uuid.New())
+	user, err := m.CreateUser(context.Background(), form.NewUser(email, "password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	disabled, err := m.SetUserDisabled(context.Background(), user.ID, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !disabled.Disabled() {
+		t.Errorf("expected user to be disabled")
+	}
+
+	// Disabling them again keeps when they were first disabled
+	again, err := m.SetUserDisabled(context.Background(), user.ID, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !again.DisabledAt.Equal(disabled.DisabledAt) {
+		t.Errorf("expected disabled at %s; got %s", disabled.DisabledAt, again.DisabledAt)
+	}
+
+	enabled, err := m.SetUserDisabled(context.Background(), user.ID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if enabled.Disabled() {
+		t.Errorf("expected user to be enabled")
+	}
+
+	events, err := m.GetAuditEvents(context.Background(), models.AuditEventQuery{TargetID: user.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]string{"admin.user_disabled", "admin.user_enabled"}, auditActions(events)); diff != "" {
+		t.Error(diff)
+	}
+
+	_, err = m.SetUserDisabled(context.Background(), uuid.New(), true)
+	if !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("expected not found for an unknown user; got %v", err)
+	}
+}
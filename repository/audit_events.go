@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"emailaddress.horse/thousand/audit"
+	"emailaddress.horse/thousand/models"
+	"emailaddress.horse/thousand/repository/queries"
+	"github.com/google/uuid"
+	"github.com/jackc/pgtype"
+)
+
+// RecordAuditEvent appends the event to the audit log. Whoever is acting and
+// from where is taken from the context for anything the event leaves blank.
+func (m *Repository) RecordAuditEvent(ctx context.Context, event models.AuditEvent) error {
+	ctx, span := m.startSpan(ctx, "RecordAuditEvent")
+	defer span.End()
+
+	actor := audit.ActorFromContext(ctx)
+	if event.ActorID == (uuid.UUID{}) && event.ActorEmail == "" {
+		event.ActorID = actor.UserID
+		event.ActorEmail = actor.Email
+	}
+	if event.ImpersonatorID == (uuid.UUID{}) {
+		event.ImpersonatorID = actor.ImpersonatorID
+	}
+	if event.IP == "" {
+		event.IP = actor.IP
+	}
+	if event.UserAgent == "" {
+		event.UserAgent = actor.UserAgent
+	}
+
+	if event.Changes == nil {
+		event.Changes = models.AuditChanges{}
+	}
+
+	changes, err := json.Marshal(event.Changes)
+	if err != nil {
+		return fmt.Errorf("error encoding audit changes: %w", err)
+	}
+
+	_, err = m.queries.CreateAuditEvent(ctx, queries.CreateAuditEventParams{
+		ActorID:        nullUUID(event.ActorID),
+		ActorEmail:     event.ActorEmail,
+		ImpersonatorID: nullUUID(event.ImpersonatorID),
+		Ip:             event.IP,
+		UserAgent:      event.UserAgent,
+		Action:         string(event.Action),
+		TargetType:     event.TargetType,
+		TargetID:       nullUUID(event.TargetID),
+		Changes:        pgtype.JSONB{Bytes: changes, Status: pgtype.Present},
+	})
+
+	return err
+}
+
+// audit records that the action was taken on the target, for use within the
+// transaction making the change so that one is never kept without the other.
+func (m *Repository) audit(ctx context.Context, action models.AuditAction, targetType string, targetID uuid.UUID, changes models.AuditChanges) error {
+	err := m.RecordAuditEvent(ctx, models.AuditEvent{
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Changes:    changes,
+	})
+	if err != nil {
+		return fmt.Errorf("error recording %s: %w", action, err)
+	}
+
+	return nil
+}
+
+// GetAuditEventsForUser retrieves the most recent events the user took part
+// in, whether by acting, being acted on or having their email address used to
+// try to log in.
+func (m *Repository) GetAuditEventsForUser(ctx context.Context, userID uuid.UUID) ([]models.AuditEvent, error) {
+	ctx, span := m.startSpan(ctx, "GetAuditEventsForUser")
+	defer span.End()
+
+	dbEvents, err := m.queries.GetAuditEventsForUser(ctx, queries.GetAuditEventsForUserParams{
+		UserID:   userID,
+		PageSize: models.DefaultAuditEventLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newAuditEvents(dbEvents)
+}
+
+// GetAuditEvents retrieves the most recent events matching the query.
+func (m *Repository) GetAuditEvents(ctx context.Context, q models.AuditEventQuery) ([]models.AuditEvent, error) {
+	ctx, span := m.startSpan(ctx, "GetAuditEvents")
+	defer span.End()
+
+	q = q.Normalize()
+
+	dbEvents, err := m.queries.GetAuditEvents(ctx, queries.GetAuditEventsParams{
+		ActorEmail:   q.ActorEmail,
+		Action:       string(q.Action),
+		HasTarget:    q.TargetID != (uuid.UUID{}),
+		TargetID:     q.TargetID,
+		SinceSeconds: int32(q.Since.Seconds()),
+		PageSize:     int32(q.Limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newAuditEvents(dbEvents)
+}
+
+func newAuditEvents(dbEvents []queries.AuditEvent) ([]models.AuditEvent, error) {
+	events := make([]models.AuditEvent, len(dbEvents))
+	for i, dbEvent := range dbEvents {
+		event, err := newAuditEvent(dbEvent)
+		if err != nil {
+			return nil, err
+		}
+		events[i] = event
+	}
+
+	return events, nil
+}
+
+func nullUUID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{UUID: id, Valid: id != (uuid.UUID{})}
+}
+
+// auditTime is the value of a nullable time as recorded in audit changes, so
+// that a time which isn't set is null rather than the zero time.
+func auditTime(t sql.NullTime) interface{} {
+	if !t.Valid {
+		return nil
+	}
+
+	return t.Time
+}
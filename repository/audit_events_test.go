@@ -0,0 +1,181 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"emailaddress.horse/thousand/audit"
+	"emailaddress.horse/thousand/form"
+	"emailaddress.horse/thousand/models"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+// auditActions returns the actions of the events in a stable order, since
+// events recorded in the same transaction share a time.
+func auditActions(events []models.AuditEvent) []string {
+	actions := make([]string, 0, len(events))
+	for _, event := range events {
+		actions = append(actions, string(event.Action))
+	}
+	sort.Strings(actions)
+	return actions
+}
+
+func TestRecordAuditEvent(t *testing.T) {
+	m := newTestRepository(t)
+
+	email := fmt.Sprintf("%s@example.com", uuid.New())
+	user, err := m.CreateUser(context.Background(), form.NewUser(email, "password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := audit.WithActor(context.Background(), audit.Actor{IP: "192.0.2.1", UserAgent: "test"})
+
+	events := []models.AuditEvent{
+		{
+			ActorID:    user.ID,
+			ActorEmail: user.Email,
+			Action:     models.AuditActionLogin,
+			TargetType: models.AuditTargetUser,
+			TargetID:   user.ID,
+		},
+		// A failed login is attributed to the email address it tried
+		{ActorEmail: email, Action: models.AuditActionLoginFailed},
+		// Someone else's login is not
+		{ActorID: m.UserID(), Action: models.AuditActionLogin},
+	}
+	for _, event := range events {
+		if err := m.RecordAuditEvent(ctx, event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	actual, err := m.GetAuditEventsForUser(context.Background(), user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{string(models.AuditActionLogin), string(models.AuditActionLoginFailed)}
+	if diff := cmp.Diff(expected, auditActions(actual)); diff != "" {
+		t.Error(diff)
+	}
+
+	for _, event := range actual {
+		if event.IP != "192.0.2.1" || event.UserAgent != "test" {
+			t.Errorf("expected actor from context; got IP %q and user agent %q", event.IP, event.UserAgent)
+		}
+	}
+}
+
+func TestGetAuditEvents(t *testing.T) {
+	m := newTestRepository(t)
+	userID := m.UserID()
+
+	vampire, err := m.CreateVampire(context.Background(), userID, "Gruffudd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	email := fmt.Sprintf("%s@example.com", uuid.New())
+	ctx := audit.WithUser(context.Background(), userID, email)
+
+	if err := m.TrashVampire(ctx, vampire.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.RestoreTrashedVampire(ctx, userID, vampire.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.RecordAuditEvent(context.Background(), models.AuditEvent{ActorEmail: email, Action: models.AuditActionLoginFailed}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		query    models.AuditEventQuery
+		expected []string
+	}{
+		{
+			name:     "by actor",
+			query:    models.AuditEventQuery{ActorEmail: email},
+			expected: []string{"session.login_failed", "vampire.restored", "vampire.trashed"},
+		},
+		{
+			name:     "by action",
+			query:    models.AuditEventQuery{ActorEmail: email, Action: models.AuditActionVampireTrashed},
+			expected: []string{"vampire.trashed"},
+		},
+		{
+			name:     "by target",
+			query:    models.AuditEventQuery{TargetID: vampire.ID},
+			expected: []string{"vampire.restored", "vampire.trashed"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := m.GetAuditEvents(context.Background(), tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tt.expected, auditActions(actual)); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+
+	limited, err := m.GetAuditEvents(context.Background(), models.AuditEventQuery{ActorEmail: email, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(limited) != 1 {
+		t.Errorf("expected the limit to be kept to; got %d events", len(limited))
+	}
+
+	restored, err := m.GetAuditEvents(context.Background(), models.AuditEventQuery{
+		TargetID: vampire.ID,
+		Action:   models.AuditActionVampireRestored,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(restored) != 1 {
+		t.Fatalf("expected one restore; got %d", len(restored))
+	}
+	if _, ok := restored[0].Changes["deleted_at"]; !ok {
+		t.Errorf("expected restore to record deleted_at changing; got %v", restored[0].Changes)
+	}
+}
+
+func TestAuditEventsAppendOnly(t *testing.T) {
+	m := newTestRepository(t)
+
+	if err := m.RecordAuditEvent(context.Background(), models.AuditEvent{Action: models.AuditActionLogin}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, statement := range []string{
+		"UPDATE audit_events SET action = 'changed'",
+		"DELETE FROM audit_events",
+		"TRUNCATE audit_events",
+	} {
+		sp, err := m.tx.Begin(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := sp.Exec(context.Background(), statement); err == nil {
+			t.Errorf("expected %q to fail", statement)
+		}
+
+		if err := sp.Rollback(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
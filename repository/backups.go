@@ -0,0 +1,422 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"emailaddress.horse/thousand/models"
+	"emailaddress.horse/thousand/repository/queries"
+	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v4"
+)
+
+// UserBackup is every row belonging to a user, as stored, so that they can be
+// restored exactly. It includes the vampires the user owns and everything on
+// them, along with the user's memberships of and invitations to other users'
+// vampires. The email of each member and inviter is kept so that they can be
+// matched to users in another database.
+type UserBackup struct {
+	User        queries.User                             `json:"user"`
+	Identities  []queries.UserIdentity                   `json:"user_identities"`
+	Vampires    []queries.Vampire                        `json:"vampires"`
+	Memories    []queries.Memory                         `json:"memories"`
+	Experiences []queries.Experience                     `json:"experiences"`
+	Skills      []queries.Skill                          `json:"skills"`
+	Resources   []queries.Resource                       `json:"resources"`
+	Characters  []queries.Character                      `json:"characters"`
+	Marks       []queries.Mark                           `json:"marks"`
+	ShareLinks  []queries.ShareLink                      `json:"share_links"`
+	Members     []queries.GetBackupVampireMembersRow     `json:"vampire_members"`
+	Invitations []queries.GetBackupVampireInvitationsRow `json:"vampire_invitations"`
+}
+
+// RestoreOptions changes how a backup is restored.
+type RestoreOptions struct {
+	// Replace deletes everything belonging to a user with the same email
+	// before restoring, rolling them back to the backup. Without it restoring
+	// a user who already exists fails.
+	Replace bool
+}
+
+// RestoreResult describes what restoring did.
+type RestoreResult struct {
+	UserID uuid.UUID
+	// Remapped counts the rows given new IDs because theirs were taken.
+	Remapped int
+	// Skipped counts memberships and invitations which weren't restored
+	// because their vampire or user doesn't exist.
+	Skipped int
+}
+
+// BackupUser reads every row belonging to the user with the email. Reading
+// happens in a repeatable read transaction so the rows are consistent with
+// each other.
+func (m *Repository) BackupUser(ctx context.Context, email string) (UserBackup, error) {
+	ctx, span := m.startSpan(ctx, "BackupUser")
+	defer span.End()
+
+	txRepo, tx, err := m.WithTx(ctx)
+	if err != nil {
+		return UserBackup{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Within a transaction the repository is already using, the isolation
+	// can't be changed and it's up to whoever began it
+	if m.pool != nil {
+		if _, err := tx.Exec(ctx, "SET TRANSACTION ISOLATION LEVEL REPEATABLE READ READ ONLY"); err != nil {
+			return UserBackup{}, err
+		}
+	}
+
+	q := txRepo.queries
+
+	var backup UserBackup
+
+	backup.User, err = q.GetBackupUser(ctx, email)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return UserBackup{}, models.ErrNotFound.Cause(err)
+	} else if err != nil {
+		return UserBackup{}, err
+	}
+
+	id := backup.User.ID
+
+	for _, read := range []func() error{
+		func() (err error) { backup.Identities, err = q.GetBackupUserIdentities(ctx, id); return },
+		func() (err error) { backup.Vampires, err = q.GetBackupVampires(ctx, id); return },
+		func() (err error) { backup.Memories, err = q.GetBackupMemories(ctx, id); return },
+		func() (err error) { backup.Experiences, err = q.GetBackupExperiences(ctx, id); return },
+		func() (err error) { backup.Skills, err = q.GetBackupSkills(ctx, id); return },
+		func() (err error) { backup.Resources, err = q.GetBackupResources(ctx, id); return },
+		func() (err error) { backup.Characters, err = q.GetBackupCharacters(ctx, id); return },
+		func() (err error) { backup.Marks, err = q.GetBackupMarks(ctx, id); return },
+		func() (err error) { backup.ShareLinks, err = q.GetBackupShareLinks(ctx, id); return },
+		func() (err error) { backup.Members, err = q.GetBackupVampireMembers(ctx, id); return },
+		func() (err error) { backup.Invitations, err = q.GetBackupVampireInvitations(ctx, id); return },
+	} {
+		if err := read(); err != nil {
+			return UserBackup{}, err
+		}
+	}
+
+	return backup, nil
+}
+
+// RestoreUser writes the rows in the backup in a single transaction, so
+// either all of them are restored or none are. Rows keep their IDs unless
+// they're taken, in which case they're given new ones and the rows referring
+// to them follow.
+func (m *Repository) RestoreUser(ctx context.Context, backup UserBackup, opts RestoreOptions) (RestoreResult, error) {
+	ctx, span := m.startSpan(ctx, "RestoreUser")
+	defer span.End()
+
+	txRepo, tx, err := m.WithTx(ctx)
+	if err != nil {
+		return RestoreResult{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	r := restorer{ctx: ctx, q: txRepo.queries, ids: map[uuid.UUID]uuid.UUID{}}
+
+	result, err := r.restore(backup, opts)
+	if err != nil {
+		return RestoreResult{}, err
+	}
+
+	changes := models.DiffAuditValues(nil, map[string]interface{}{
+		"email":    backup.User.Email,
+		"vampires": len(backup.Vampires),
+		"remapped": result.Remapped,
+	})
+	if err := txRepo.audit(ctx, models.AuditActionUserRestored, models.AuditTargetUser, result.UserID, changes); err != nil {
+		return RestoreResult{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return RestoreResult{}, err
+	}
+
+	return result, nil
+}
+
+type restorer struct {
+	ctx    context.Context
+	q      *queries.Queries
+	ids    map[uuid.UUID]uuid.UUID
+	result RestoreResult
+}
+
+func (r *restorer) restore(backup UserBackup, opts RestoreOptions) (RestoreResult, error) {
+	ctx, q := r.ctx, r.q
+
+	existing, err := q.GetBackupUser(ctx, backup.User.Email)
+	if err == nil {
+		if !opts.Replace {
+			return RestoreResult{}, models.ErrEmailAlreadyInUse.Cause(
+				fmt.Errorf("user %s already exists", backup.User.Email),
+			)
+		}
+
+		if err := r.deleteUser(existing.ID); err != nil {
+			return RestoreResult{}, fmt.Errorf("error deleting existing user: %w", err)
+		}
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return RestoreResult{}, err
+	}
+
+	u := backup.User
+	r.result.UserID, err = r.insert(u.ID, func(id uuid.UUID) (uuid.UUID, error) {
+		return q.RestoreUser(ctx, queries.RestoreUserParams{
+			ID: id, Email: u.Email, PasswordHash: u.PasswordHash, CreatedAt: u.CreatedAt, UpdatedAt: u.UpdatedAt,
+			IsAdmin: u.IsAdmin, DisabledAt: u.DisabledAt,
+		})
+	})
+	if err != nil {
+		return RestoreResult{}, fmt.Errorf("error restoring user: %w", err)
+	}
+
+	for _, i := range backup.Identities {
+		i := i
+		_, err := r.insert(i.ID, func(id uuid.UUID) (uuid.UUID, error) {
+			return q.RestoreUserIdentity(ctx, queries.RestoreUserIdentityParams{
+				ID: id, UserID: r.id(i.UserID), Issuer: i.Issuer, Subject: i.Subject, Email: i.Email,
+				CreatedAt: i.CreatedAt, UpdatedAt: i.UpdatedAt,
+			})
+		})
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return RestoreResult{}, models.ErrIdentityAlreadyLinked.Cause(err)
+		} else if err != nil {
+			return RestoreResult{}, fmt.Errorf("error restoring identity: %w", err)
+		}
+	}
+
+	for _, v := range backup.Vampires {
+		v := v
+		_, err := r.insert(v.ID, func(id uuid.UUID) (uuid.UUID, error) {
+			return q.RestoreVampire(ctx, queries.RestoreVampireParams{
+				ID: id, UserID: uuid.NullUUID{UUID: r.result.UserID, Valid: true}, Name: v.Name,
+				CreatedAt: v.CreatedAt, UpdatedAt: v.UpdatedAt, DeletedAt: v.DeletedAt, ArchivedAt: v.ArchivedAt,
+			})
+		})
+		if err != nil {
+			return RestoreResult{}, fmt.Errorf("error restoring vampire: %w", err)
+		}
+	}
+
+	for _, mem := range backup.Memories {
+		mem := mem
+		_, err := r.insert(mem.ID, func(id uuid.UUID) (uuid.UUID, error) {
+			return q.RestoreMemory(ctx, queries.RestoreMemoryParams{
+				ID: id, VampireID: r.id(mem.VampireID), CreatedAt: mem.CreatedAt, UpdatedAt: mem.UpdatedAt,
+			})
+		})
+		if err != nil {
+			return RestoreResult{}, fmt.Errorf("error restoring memory: %w", err)
+		}
+	}
+
+	for _, e := range backup.Experiences {
+		e := e
+		_, err := r.insert(e.ID, func(id uuid.UUID) (uuid.UUID, error) {
+			return q.RestoreExperience(ctx, queries.RestoreExperienceParams{
+				ID: id, MemoryID: r.id(e.MemoryID), Description: e.Description,
+				CreatedAt: e.CreatedAt, UpdatedAt: e.UpdatedAt,
+			})
+		})
+		if err != nil {
+			return RestoreResult{}, fmt.Errorf("error restoring experience: %w", err)
+		}
+	}
+
+	for _, s := range backup.Skills {
+		s := s
+		_, err := r.insert(s.ID, func(id uuid.UUID) (uuid.UUID, error) {
+			return q.RestoreSkill(ctx, queries.RestoreSkillParams{
+				ID: id, VampireID: r.id(s.VampireID), Description: s.Description,
+				CreatedAt: s.CreatedAt, UpdatedAt: s.UpdatedAt,
+			})
+		})
+		if err != nil {
+			return RestoreResult{}, fmt.Errorf("error restoring skill: %w", err)
+		}
+	}
+
+	for _, res := range backup.Resources {
+		res := res
+		_, err := r.insert(res.ID, func(id uuid.UUID) (uuid.UUID, error) {
+			return q.RestoreResource(ctx, queries.RestoreResourceParams{
+				ID: id, VampireID: r.id(res.VampireID), Description: res.Description, Stationary: res.Stationary,
+				CreatedAt: res.CreatedAt, UpdatedAt: res.UpdatedAt,
+			})
+		})
+		if err != nil {
+			return RestoreResult{}, fmt.Errorf("error restoring resource: %w", err)
+		}
+	}
+
+	for _, c := range backup.Characters {
+		c := c
+		_, err := r.insert(c.ID, func(id uuid.UUID) (uuid.UUID, error) {
+			return q.RestoreCharacter(ctx, queries.RestoreCharacterParams{
+				ID: id, VampireID: r.id(c.VampireID), Name: c.Name, Type: c.Type,
+				CreatedAt: c.CreatedAt, UpdatedAt: c.UpdatedAt,
+			})
+		})
+		if err != nil {
+			return RestoreResult{}, fmt.Errorf("error restoring character: %w", err)
+		}
+	}
+
+	for _, mark := range backup.Marks {
+		mark := mark
+		_, err := r.insert(mark.ID, func(id uuid.UUID) (uuid.UUID, error) {
+			return q.RestoreMark(ctx, queries.RestoreMarkParams{
+				ID: id, VampireID: r.id(mark.VampireID), Description: mark.Description,
+				CreatedAt: mark.CreatedAt, UpdatedAt: mark.UpdatedAt,
+			})
+		})
+		if err != nil {
+			return RestoreResult{}, fmt.Errorf("error restoring mark: %w", err)
+		}
+	}
+
+	for _, l := range backup.ShareLinks {
+		l := l
+		_, err := r.insert(l.ID, func(id uuid.UUID) (uuid.UUID, error) {
+			return q.RestoreShareLink(ctx, queries.RestoreShareLinkParams{
+				ID: id, VampireID: r.id(l.VampireID), Token: l.Token, ExpiresAt: l.ExpiresAt, RevokedAt: l.RevokedAt,
+				CreatedAt: l.CreatedAt, UpdatedAt: l.UpdatedAt,
+			})
+		})
+		if err != nil {
+			return RestoreResult{}, fmt.Errorf("error restoring share link: %w", err)
+		}
+	}
+
+	for _, member := range backup.Members {
+		member := member
+
+		vampireID, userID, ok, err := r.references(member.VampireID, member.UserID, member.Email)
+		if err != nil {
+			return RestoreResult{}, fmt.Errorf("error restoring member: %w", err)
+		} else if !ok {
+			r.result.Skipped++
+			continue
+		}
+
+		_, err = r.insert(member.ID, func(id uuid.UUID) (uuid.UUID, error) {
+			return q.RestoreVampireMember(ctx, queries.RestoreVampireMemberParams{
+				ID: id, VampireID: vampireID, UserID: userID, Role: member.Role,
+				CreatedAt: member.CreatedAt, UpdatedAt: member.UpdatedAt,
+			})
+		})
+		if err != nil {
+			return RestoreResult{}, fmt.Errorf("error restoring member: %w", err)
+		}
+	}
+
+	for _, invitation := range backup.Invitations {
+		invitation := invitation
+
+		vampireID, invitedBy, ok, err := r.references(invitation.VampireID, invitation.InvitedBy, invitation.InvitedByEmail)
+		if err != nil {
+			return RestoreResult{}, fmt.Errorf("error restoring invitation: %w", err)
+		} else if !ok {
+			r.result.Skipped++
+			continue
+		}
+
+		_, err = r.insert(invitation.ID, func(id uuid.UUID) (uuid.UUID, error) {
+			return q.RestoreVampireInvitation(ctx, queries.RestoreVampireInvitationParams{
+				ID: id, VampireID: vampireID, InvitedBy: invitedBy, Email: invitation.Email, Role: invitation.Role,
+				CreatedAt: invitation.CreatedAt, UpdatedAt: invitation.UpdatedAt,
+			})
+		})
+		if err != nil {
+			return RestoreResult{}, fmt.Errorf("error restoring invitation: %w", err)
+		}
+	}
+
+	return r.result, nil
+}
+
+// insert inserts a row with its ID or, if that's taken, a new one, recording
+// the ID it was given for the rows which refer to it.
+func (r *restorer) insert(id uuid.UUID, insert func(uuid.UUID) (uuid.UUID, error)) (uuid.UUID, error) {
+	newID, err := insert(id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		r.result.Remapped++
+		newID, err = insert(uuid.New())
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	r.ids[id] = newID
+	return newID, nil
+}
+
+// id returns the ID a restored row was given.
+func (r *restorer) id(id uuid.UUID) uuid.UUID {
+	if newID, ok := r.ids[id]; ok {
+		return newID
+	}
+	return id
+}
+
+// references finds the vampire and user a membership or invitation refers
+// to. Those which weren't restored must already exist, with users matched by
+// email as their IDs differ between databases.
+func (r *restorer) references(vampireID, userID uuid.UUID, email string) (uuid.UUID, uuid.UUID, bool, error) {
+	if _, ok := r.ids[vampireID]; !ok {
+		exists, err := r.q.VampireExists(r.ctx, vampireID)
+		if err != nil || !exists {
+			return uuid.Nil, uuid.Nil, false, err
+		}
+	}
+
+	if _, ok := r.ids[userID]; ok {
+		return r.id(vampireID), r.id(userID), true, nil
+	}
+
+	user, err := r.q.GetBackupUser(r.ctx, email)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, uuid.Nil, false, nil
+	} else if err != nil {
+		return uuid.Nil, uuid.Nil, false, err
+	}
+
+	return r.id(vampireID), user.ID, true, nil
+}
+
+// deleteUser deletes the user and everything which would be backed up with
+// them, children first.
+func (r *restorer) deleteUser(userID uuid.UUID) error {
+	ctx, q := r.ctx, r.q
+
+	for _, del := range []func(context.Context, uuid.UUID) error{
+		q.DeleteBackupExperiences,
+		q.DeleteBackupMemories,
+		q.DeleteBackupSkills,
+		q.DeleteBackupResources,
+		q.DeleteBackupCharacters,
+		q.DeleteBackupMarks,
+		q.DeleteBackupShareLinks,
+		q.DeleteBackupVampireMembers,
+		q.DeleteBackupVampireInvitations,
+		q.DeleteBackupVampires,
+		q.DeleteBackupUserIdentities,
+		q.DeleteBackupUser,
+	} {
+		if err := del(ctx, userID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
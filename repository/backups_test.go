@@ -0,0 +1,253 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"emailaddress.horse/thousand/form"
+	"emailaddress.horse/thousand/models"
+	"emailaddress.horse/thousand/repository"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+// createBackupFixture creates a user with a row in every table backed up, and
+// a friend who is a member of their vampire and whose vampire they're a member
+// of. It returns the user's email.
+func createBackupFixture(t *testing.T, m testRepository) string {
+	t.Helper()
+
+	ctx := context.Background()
+
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	email := fmt.Sprintf("%s@example.com", uuid.New())
+	user, err := m.CreateUser(ctx, form.NewUser(email, "password"))
+	must(err)
+	user.Email = email
+
+	friendEmail := fmt.Sprintf("%s@example.com", uuid.New())
+	friend, err := m.CreateUser(ctx, form.NewUser(friendEmail, "password"))
+	must(err)
+	friend.Email = friendEmail
+
+	_, err = m.LinkUserIdentity(ctx, user.ID, models.UserIdentity{
+		Issuer:  "https://issuer.example.com",
+		Subject: uuid.New().String(),
+		Email:   email,
+	})
+	must(err)
+
+	vampire, err := m.CreateVampire(ctx, user.ID, "Agnes the Pale")
+	must(err)
+
+	_, err = m.CreateExperience(ctx, vampire.ID, vampire.Memories[0].ID, "I was turned in a burning abbey.")
+	must(err)
+	_, err = m.CreateSkill(ctx, vampire.ID, "Reading Latin")
+	must(err)
+	_, err = m.CreateResource(ctx, vampire.ID, models.CreateResourceParams{Description: "A hidden crypt", Stationary: true})
+	must(err)
+	_, err = m.CreateCharacter(ctx, vampire.ID, models.CreateCharacterParams{Name: "Osric", Type: "mortal"})
+	must(err)
+	_, err = m.CreateMark(ctx, vampire.ID, "Flowers wilt when I pass.")
+	must(err)
+	_, err = m.CreateShareLink(ctx, vampire.ID, 0)
+	must(err)
+
+	invitation, err := m.InviteMember(ctx, vampire.ID, user.ID, friendEmail, models.RoleEditor)
+	must(err)
+	_, err = m.AcceptInvitation(ctx, friend, invitation.ID)
+	must(err)
+	_, err = m.InviteMember(ctx, vampire.ID, user.ID, fmt.Sprintf("%s@example.com", uuid.New()), models.RoleViewer)
+	must(err)
+
+	friendVampire, err := m.CreateVampire(ctx, friend.ID, "Godric Blackwood")
+	must(err)
+	invitation, err = m.InviteMember(ctx, friendVampire.ID, friend.ID, email, models.RoleViewer)
+	must(err)
+	_, err = m.AcceptInvitation(ctx, user, invitation.ID)
+	must(err)
+
+	return email
+}
+
+func TestBackupUser(t *testing.T) {
+	m := newTestRepository(t)
+	email := createBackupFixture(t, m)
+
+	backup, err := m.BackupUser(context.Background(), email)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := map[string][2]int{
+		"identities":  {len(backup.Identities), 1},
+		"vampires":    {len(backup.Vampires), 1},
+		"memories":    {len(backup.Memories), models.VampireMemorySize},
+		"experiences": {len(backup.Experiences), 1},
+		"skills":      {len(backup.Skills), 1},
+		"resources":   {len(backup.Resources), 1},
+		"characters":  {len(backup.Characters), 1},
+		"marks":       {len(backup.Marks), 1},
+		"share links": {len(backup.ShareLinks), 1},
+		// Owning their vampire, the friend's membership of it, and their
+		// membership of the friend's vampire
+		"members": {len(backup.Members), 3},
+		// The pending invitation to their vampire
+		"invitations": {len(backup.Invitations), 1},
+	}
+
+	for name, count := range counts {
+		if count[0] != count[1] {
+			t.Errorf("expected %d %s; got %d", count[1], name, count[0])
+		}
+	}
+}
+
+func TestBackupUser_NotFound(t *testing.T) {
+	m := newTestRepository(t)
+
+	_, err := m.BackupUser(context.Background(), "nobody@example.com")
+	if !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("expected %q; received %q", models.ErrNotFound, err)
+	}
+}
+
+func TestRestoreUser_Replace(t *testing.T) {
+	ctx := context.Background()
+	m := newTestRepository(t)
+	email := createBackupFixture(t, m)
+
+	backup, err := m.BackupUser(ctx, email)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Change things after the backup, which restoring should undo
+	vampire, err := m.CreateVampire(ctx, backup.User.ID, "Made by mistake")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.CreateSkill(ctx, vampire.ID, "Regret"); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := m.RestoreUser(ctx, backup, repository.RestoreOptions{Replace: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := (repository.RestoreResult{UserID: backup.User.ID}); result != expected {
+		t.Errorf("expected %+v; got %+v", expected, result)
+	}
+
+	restored, err := m.BackupUser(ctx, email)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(backup, restored); diff != "" {
+		t.Errorf("restored rows mismatch (-backup +restored):\n%s", diff)
+	}
+}
+
+func TestRestoreUser_AlreadyExists(t *testing.T) {
+	ctx := context.Background()
+	m := newTestRepository(t)
+	email := createBackupFixture(t, m)
+
+	backup, err := m.BackupUser(ctx, email)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = m.RestoreUser(ctx, backup, repository.RestoreOptions{})
+	if !errors.Is(err, models.ErrEmailAlreadyInUse) {
+		t.Errorf("expected %q; received %q", models.ErrEmailAlreadyInUse, err)
+	}
+}
+
+func TestRestoreUser_Remap(t *testing.T) {
+	ctx := context.Background()
+	m := newTestRepository(t)
+	email := createBackupFixture(t, m)
+
+	backup, err := m.BackupUser(ctx, email)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Restoring as another user, every ID is taken by the original
+	backup.User.Email = fmt.Sprintf("%s@example.com", uuid.New())
+
+	// An identity can only be linked to one user
+	_, err = m.RestoreUser(ctx, backup, repository.RestoreOptions{})
+	if !errors.Is(err, models.ErrIdentityAlreadyLinked) {
+		t.Fatalf("expected %q; received %q", models.ErrIdentityAlreadyLinked, err)
+	}
+
+	backup.Identities = nil
+
+	// A member who doesn't exist is skipped
+	for i := range backup.Members {
+		if backup.Members[i].Role == "editor" {
+			backup.Members[i].Email = "nobody@example.com"
+		}
+	}
+
+	result, err := m.RestoreUser(ctx, backup, repository.RestoreOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.UserID == backup.User.ID {
+		t.Error("expected the user to be given a new ID")
+	}
+
+	if expected := 1; result.Skipped != expected {
+		t.Errorf("expected %d skipped; got %d", expected, result.Skipped)
+	}
+
+	restored, err := m.BackupUser(ctx, backup.User.Email)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(restored.Vampires) != 1 {
+		t.Fatalf("expected 1 vampire; got %d", len(restored.Vampires))
+	}
+
+	if restored.Vampires[0].ID == backup.Vampires[0].ID {
+		t.Error("expected the vampire to be given a new ID")
+	}
+
+	if restored.Vampires[0].Name != backup.Vampires[0].Name {
+		t.Errorf("expected vampire %q; got %q", backup.Vampires[0].Name, restored.Vampires[0].Name)
+	}
+
+	if len(restored.Experiences) != 1 || restored.Experiences[0].Description != backup.Experiences[0].Description {
+		t.Errorf("expected experience to be restored; got %+v", restored.Experiences)
+	}
+
+	if restored.ShareLinks[0].Token == backup.ShareLinks[0].Token {
+		t.Error("expected the share link to be given a new token")
+	}
+
+	if len(restored.Members) != 2 {
+		t.Errorf("expected 2 members; got %d", len(restored.Members))
+	}
+
+	// The user, vampire, memories, the experience, skill, resource, character,
+	// mark and share link, and the members and invitation restored
+	expected := 2 + models.VampireMemorySize + 6 + len(restored.Members) + len(restored.Invitations)
+	if result.Remapped != expected {
+		t.Errorf("expected %d remapped; got %d", expected, result.Remapped)
+	}
+}
@@ -13,6 +13,9 @@ import (
 )
 
 func (m *Repository) CreateCharacter(ctx context.Context, vampireID uuid.UUID, params models.CreateCharacterParams) (models.Character, error) {
+	ctx, span := m.startSpan(ctx, "CreateCharacter")
+	defer span.End()
+
 	var characterType queries.CharacterType
 	switch params.Type {
 	case "mortal":
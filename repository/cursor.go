@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"emailaddress.horse/thousand/models"
+	"github.com/google/uuid"
+)
+
+// vampireCursor marks the last vampire on a page so that the next page can
+// start after it. Only the field the page is sorted by is set, alongside the
+// ID which breaks ties.
+type vampireCursor struct {
+	Sort models.VampireSort `json:"s"`
+	Name string             `json:"n,omitempty"`
+	Time time.Time          `json:"t,omitempty"`
+	ID   uuid.UUID          `json:"i"`
+}
+
+// encode turns the cursor into an opaque string which is safe to put in a
+// URL.
+func (c vampireCursor) encode() string {
+	data, err := json.Marshal(c)
+	if err != nil {
+		// Marshalling a struct of strings, times and UUIDs cannot fail
+		panic(err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeVampireCursor reads a cursor given out with a previous page. Cursors
+// for pages in a different order are rejected as they cannot be continued.
+func decodeVampireCursor(s string, sort models.VampireSort) (vampireCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return vampireCursor{}, models.ErrInvalidCursor.Cause(err)
+	}
+
+	var c vampireCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return vampireCursor{}, models.ErrInvalidCursor.Cause(err)
+	}
+
+	if c.Sort != sort {
+		return vampireCursor{}, models.ErrInvalidCursor
+	}
+
+	return c, nil
+}
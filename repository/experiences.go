@@ -14,6 +14,9 @@ import (
 // CreateExperience attempts to add a new experience to the DB for the provided
 // memory.
 func (m *Repository) CreateExperience(ctx context.Context, vampireID, memoryID uuid.UUID, description string) (models.Experience, error) {
+	ctx, span := m.startSpan(ctx, "CreateExperience")
+	defer span.End()
+
 	params := queries.CreateExperienceParams{
 		VampireID:   vampireID,
 		MemoryID:    memoryID,
@@ -42,6 +45,9 @@ func (m *Repository) CreateExperience(ctx context.Context, vampireID, memoryID u
 // GetExperiences attempts to retrieve all the experiences from the DB for the
 // provided vampire.
 func (m *Repository) GetExperiences(ctx context.Context, vampireID uuid.UUID) ([]models.Experience, error) {
+	ctx, span := m.startSpan(ctx, "GetExperiences")
+	defer span.End()
+
 	dbExperiences, err := m.queries.GetExperiencesForVampire(ctx, vampireID)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,23 @@
+package repository
+
+import "time"
+
+// GetVampireSequentially exposes getVampireSequentially so that benchmarks can
+// compare it with GetVampire.
+var GetVampireSequentially = (*Repository).getVampireSequentially
+
+// WithRequestComment exposes withRequestComment so that it can be tested
+// without a DB.
+var WithRequestComment = withRequestComment
+
+// ParseSnippet exposes parseSnippet so that it can be tested without a DB.
+var ParseSnippet = parseSnippet
+
+// WithTrashRetention returns a copy of the repository which keeps trashed
+// vampires for the duration. A negative duration purges vampires as soon as
+// they are trashed.
+func WithTrashRetention(m *Repository, retention time.Duration) *Repository {
+	withRetention := *m
+	withRetention.trashRetention = retention
+	return &withRetention
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/jackc/pgx/v4"
+)
+
+// applicationName identifies the app's connections in pg_stat_activity and
+// the Postgres logs.
+const applicationName = "thousand"
+
+// requestLogger adds the ID of the request a query was made for to its log
+// entry so that queries can be tied to the request which caused them.
+type requestLogger struct {
+	pgx.Logger
+}
+
+func (l requestLogger) Log(ctx context.Context, level pgx.LogLevel, msg string, data map[string]interface{}) {
+	if id := middleware.GetReqID(ctx); id != "" {
+		if data == nil {
+			data = map[string]interface{}{}
+		}
+		data["requestID"] = id
+	}
+
+	l.Logger.Log(ctx, level, msg, data)
+}
+
+// withRequestComment adds a comment with the ID of the request a query is made
+// for to the end of its SQL. Postgres logs the comment along with the query,
+// and shows it in pg_stat_activity, so the query can be tied to the request
+// there too. Connections are shared between requests, so application_name
+// can't be used for this. Since pgx caches prepared statements by their SQL,
+// each request prepares its queries afresh.
+//
+// IDs which could end the comment early are left out, as are the comments of
+// queries made outside of a request.
+func withRequestComment(ctx context.Context, sql string) string {
+	id := middleware.GetReqID(ctx)
+	if id == "" || strings.ContainsAny(id, "*\x00") {
+		return sql
+	}
+
+	return sql + "\n/* request_id=" + id + " */"
+}
@@ -0,0 +1,49 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"emailaddress.horse/thousand/repository"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+func TestWithRequestComment(t *testing.T) {
+	const sql = "-- name: GetVampire :one\nSELECT * FROM vampires WHERE id = $1"
+
+	tests := []struct {
+		name      string
+		requestID string
+		expected  string
+	}{
+		{
+			name:      "request ID",
+			requestID: "host/abc-000001",
+			expected:  sql + "\n/* request_id=host/abc-000001 */",
+		},
+		{
+			name:     "outside a request",
+			expected: sql,
+		},
+		{
+			name:      "ID which would end the comment",
+			requestID: "abc */ DROP TABLE vampires; /*",
+			expected:  sql,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.requestID != "" {
+				ctx = context.WithValue(ctx, middleware.RequestIDKey, tt.requestID)
+			}
+
+			if actual := repository.WithRequestComment(ctx, sql); tt.expected != actual {
+				t.Errorf("expected %q; got %q", tt.expected, actual)
+			}
+		})
+	}
+}
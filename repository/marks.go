@@ -12,6 +12,9 @@ import (
 )
 
 func (m *Repository) CreateMark(ctx context.Context, vampireID uuid.UUID, description string) (models.Mark, error) {
+	ctx, span := m.startSpan(ctx, "CreateMark")
+	defer span.End()
+
 	params := queries.CreateMarkParams{
 		VampireID:   vampireID,
 		Description: description,
@@ -11,6 +11,9 @@ import (
 )
 
 func (m *Repository) GetMemory(ctx context.Context, vampireID, id uuid.UUID) (models.Memory, error) {
+	ctx, span := m.startSpan(ctx, "GetMemory")
+	defer span.End()
+
 	params := queries.GetMemoryParams{
 		VampireID: vampireID,
 		MemoryID:  id,
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"emailaddress.horse/thousand/db"
+)
+
+// PendingMigrations returns the versions of embedded migrations which haven't
+// been applied to the database.
+func (r *Repository) PendingMigrations(ctx context.Context) ([]int64, error) {
+	if r.pool == nil {
+		return nil, errors.New("cannot check migrations in a transaction")
+	}
+
+	versions, err := db.Versions()
+	if err != nil {
+		return nil, fmt.Errorf("error reading migrations: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, db.AppliedVersionsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("error querying applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := map[int64]bool{}
+	for rows.Next() {
+		var version int64
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("error scanning applied migration: %w", err)
+		}
+		applied[version] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error querying applied migrations: %w", err)
+	}
+
+	pending := []int64{}
+	for _, version := range versions {
+		if !applied[version] {
+			pending = append(pending, version)
+		}
+	}
+
+	return pending, nil
+}
+
+// CheckMigrations fails if any embedded migrations haven't been applied.
+func (r *Repository) CheckMigrations(ctx context.Context) error {
+	pending, err := r.PendingMigrations(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(pending) > 0 {
+		return fmt.Errorf("%d pending migrations, starting with %d", len(pending), pending[0])
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"fmt"
 	"strings"
 
 	"emailaddress.horse/thousand/models"
@@ -23,6 +24,16 @@ func newExperience(experience queries.Experience) models.Experience {
 	}
 }
 
+func newInvitation(dbInvitation queries.VampireInvitation, vampireName string) models.Invitation {
+	return models.Invitation{
+		ID:          dbInvitation.ID,
+		VampireID:   dbInvitation.VampireID,
+		VampireName: vampireName,
+		Email:       dbInvitation.Email,
+		Role:        models.MemberRole(dbInvitation.Role),
+	}
+}
+
 func newMark(dbMark queries.Mark) models.Mark {
 	return models.Mark{
 		ID:          dbMark.ID,
@@ -30,6 +41,16 @@ func newMark(dbMark queries.Mark) models.Mark {
 	}
 }
 
+func newMember(dbMember queries.GetVampireMembersRow) models.Member {
+	return models.Member{
+		ID:        dbMember.ID,
+		VampireID: dbMember.VampireID,
+		UserID:    dbMember.UserID,
+		Email:     dbMember.Email,
+		Role:      models.MemberRole(dbMember.Role),
+	}
+}
+
 func newMemory(dbMemory queries.Memory, dbExperiences []queries.Experience) models.Memory {
 	var experiences = make([]models.Experience, len(dbExperiences))
 	for i, dbExperience := range dbExperiences {
@@ -52,6 +73,24 @@ func newResource(dbResource queries.Resource) models.Resource {
 	}
 }
 
+func newShareLink(dbShareLink queries.ShareLink) models.ShareLink {
+	shareLink := models.ShareLink{
+		ID:        dbShareLink.ID,
+		VampireID: dbShareLink.VampireID,
+		Token:     dbShareLink.Token,
+	}
+
+	if dbShareLink.ExpiresAt.Valid {
+		shareLink.ExpiresAt = dbShareLink.ExpiresAt.Time
+	}
+
+	if dbShareLink.RevokedAt.Valid {
+		shareLink.RevokedAt = dbShareLink.RevokedAt.Time
+	}
+
+	return shareLink
+}
+
 func newSkill(dbSkill queries.Skill) models.Skill {
 	return models.Skill{
 		ID:          dbSkill.ID,
@@ -60,6 +99,16 @@ func newSkill(dbSkill queries.Skill) models.Skill {
 	}
 }
 
+func newUserIdentity(dbIdentity queries.UserIdentity) models.UserIdentity {
+	return models.UserIdentity{
+		ID:      dbIdentity.ID,
+		UserID:  dbIdentity.UserID,
+		Issuer:  dbIdentity.Issuer,
+		Subject: dbIdentity.Subject,
+		Email:   dbIdentity.Email,
+	}
+}
+
 func newVampire(dbVampire queries.Vampire, memories []models.Memory, skills []models.Skill, resources []models.Resource, characters []models.Character, marks []models.Mark) models.Vampire {
 	return models.Vampire{
 		ID:         dbVampire.ID,
@@ -69,5 +118,45 @@ func newVampire(dbVampire queries.Vampire, memories []models.Memory, skills []mo
 		Resources:  resources,
 		Characters: characters,
 		Marks:      marks,
+		ArchivedAt: dbVampire.ArchivedAt.Time,
+	}
+}
+
+func newAuditEvent(dbEvent queries.AuditEvent) (models.AuditEvent, error) {
+	changes := models.AuditChanges{}
+	if err := dbEvent.Changes.AssignTo(&changes); err != nil {
+		return models.AuditEvent{}, fmt.Errorf("error decoding audit changes: %w", err)
+	}
+
+	return models.AuditEvent{
+		ID:             dbEvent.ID,
+		ActorID:        dbEvent.ActorID.UUID,
+		ActorEmail:     dbEvent.ActorEmail,
+		ImpersonatorID: dbEvent.ImpersonatorID.UUID,
+		IP:             dbEvent.Ip,
+		UserAgent:      dbEvent.UserAgent,
+		Action:         models.AuditAction(dbEvent.Action),
+		TargetType:     dbEvent.TargetType,
+		TargetID:       dbEvent.TargetID.UUID,
+		Changes:        changes,
+		CreatedAt:      dbEvent.CreatedAt,
+	}, nil
+}
+
+func newUser(dbUser queries.User) models.User {
+	return models.User{
+		ID:         dbUser.ID,
+		Email:      dbUser.Email,
+		IsAdmin:    dbUser.IsAdmin,
+		DisabledAt: dbUser.DisabledAt.Time,
+	}
+}
+
+func newPasswordReset(dbReset queries.PasswordReset) models.PasswordReset {
+	return models.PasswordReset{
+		ID:        dbReset.ID,
+		UserID:    dbReset.UserID,
+		Token:     dbReset.Token,
+		ExpiresAt: dbReset.ExpiresAt,
 	}
 }
@@ -1,7 +1,11 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"emailaddress.horse/thousand/health"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 )
@@ -13,16 +17,20 @@ var Module = fx.Options(
 type Params struct {
 	fx.In
 
-	DatabaseURL string `name:"databaseURL"`
+	DatabaseURL    string        `name:"databaseURL"`
+	TrashRetention time.Duration `name:"trashRetention" optional:"true"`
 
-	Health *health.Health
-	Logger *zap.Logger
+	Health         *health.Health
+	Logger         *zap.Logger
+	TracerProvider trace.TracerProvider `optional:"true"`
 }
 
-func fxNew(params Params) (*Repository, error) {
+func fxNew(lc fx.Lifecycle, params Params) (*Repository, error) {
 	opts := Options{
-		DatabaseURL: params.DatabaseURL,
-		Logger:      params.Logger.Named("repository"),
+		DatabaseURL:    params.DatabaseURL,
+		Logger:         params.Logger.Named("repository"),
+		TracerProvider: params.TracerProvider,
+		TrashRetention: params.TrashRetention,
 	}
 
 	repo, err := New(opts)
@@ -31,8 +39,24 @@ func fxNew(params Params) (*Repository, error) {
 	}
 
 	params.Health.Register(&health.Component{
-		Name:  "repository",
-		Check: repo.Ping,
+		Name:     "database",
+		Check:    repo.Ping,
+		Critical: true,
+	})
+
+	params.Health.Register(&health.Component{
+		Name:  "migrations",
+		Check: repo.CheckMigrations,
+	})
+
+	// The repository is built before anything that uses it so, as hooks stop
+	// in reverse, the pool is closed once the servers have shut down.
+	lc.Append(fx.Hook{
+		OnStop: func(_ context.Context) error {
+			params.Logger.Named("repository").Info("closing connection pool")
+			repo.Close()
+			return nil
+		},
 	})
 
 	return repo, nil
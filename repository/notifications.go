@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"emailaddress.horse/thousand/models"
+	"emailaddress.horse/thousand/repository/queries"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+// streamMessageRetention is how long a stored stream message is kept for every
+// instance to load it after being notified.
+const streamMessageRetention = 5 * time.Minute
+
+// Notify sends the payload to every connection listening on the channel. When
+// called inside a transaction the notification is only sent once it commits.
+func (r *Repository) Notify(ctx context.Context, channel, payload string) error {
+	ctx, span := r.startSpan(ctx, "Notify")
+	defer span.End()
+
+	return r.queries.Notify(ctx, queries.NotifyParams{
+		Channel: channel,
+		Payload: payload,
+	})
+}
+
+// Listen holds a connection from the pool open and calls fn with the payload
+// of each notification sent to the channel. It blocks until the context is
+// cancelled or the connection fails.
+func (r *Repository) Listen(ctx context.Context, channel string, fn func(payload string)) error {
+	if r.pool == nil {
+		return errors.New("cannot listen within a transaction")
+	}
+
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("error acquiring connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return fmt.Errorf("error listening to %q: %w", channel, err)
+	}
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			// Don't return a connection which may still be listening to the pool
+			_ = conn.Conn().Close(context.Background())
+			return err
+		}
+
+		fn(notification.Payload)
+	}
+}
+
+// StoreStreamMessage stores a message too large to send as a notification's
+// payload, so that its ID can be sent instead. Messages stored long enough ago
+// that every instance has loaded them are removed.
+func (r *Repository) StoreStreamMessage(ctx context.Context, data string) (uuid.UUID, error) {
+	ctx, span := r.startSpan(ctx, "StoreStreamMessage")
+	defer span.End()
+
+	err := r.queries.DeleteStreamMessagesBefore(ctx, int32(streamMessageRetention.Seconds()))
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	return r.queries.CreateStreamMessage(ctx, data)
+}
+
+// GetStreamMessage attempts to retrieve a message stored by StoreStreamMessage.
+func (r *Repository) GetStreamMessage(ctx context.Context, id uuid.UUID) (string, error) {
+	ctx, span := r.startSpan(ctx, "GetStreamMessage")
+	defer span.End()
+
+	data, err := r.queries.GetStreamMessage(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", models.ErrNotFound.Cause(err)
+	}
+
+	return data, err
+}
@@ -0,0 +1,33 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"emailaddress.horse/thousand/models"
+	"github.com/google/uuid"
+)
+
+func TestStoreStreamMessage(t *testing.T) {
+	m := newTestRepository(t)
+
+	id, err := m.StoreStreamMessage(context.Background(), "a message")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := m.GetStreamMessage(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if data != "a message" {
+		t.Errorf("expected %q; got %q", "a message", data)
+	}
+
+	_, err = m.GetStreamMessage(context.Background(), uuid.New())
+	if !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("expected not found for an unknown message; got %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"emailaddress.horse/thousand/audit"
+	"emailaddress.horse/thousand/models"
+	"emailaddress.horse/thousand/repository/queries"
+	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v4"
+)
+
+// CreatePasswordReset creates a reset with a random token which can be used
+// once, until it expires, to choose a new password for the user.
+func (m *Repository) CreatePasswordReset(ctx context.Context, userID uuid.UUID) (models.PasswordReset, error) {
+	ctx, span := m.startSpan(ctx, "CreatePasswordReset")
+	defer span.End()
+
+	txRepo, tx, err := m.WithTx(ctx)
+	if err != nil {
+		return models.PasswordReset{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	dbReset, err := txRepo.queries.CreatePasswordReset(ctx, queries.CreatePasswordResetParams{
+		UserID:           userID,
+		ExpiresInSeconds: int32(models.PasswordResetExpiry.Seconds()),
+	})
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.ForeignKeyViolation {
+		return models.PasswordReset{}, models.ErrNotFound.Cause(err)
+	} else if err != nil {
+		return models.PasswordReset{}, err
+	}
+
+	// The token is left out so that reading the audit log doesn't give a way
+	// to log in as the user
+	changes := models.DiffAuditValues(nil, map[string]interface{}{
+		"expires_at": dbReset.ExpiresAt,
+	})
+	if err := txRepo.audit(ctx, models.AuditActionPasswordResetCreated, models.AuditTargetUser, userID, changes); err != nil {
+		return models.PasswordReset{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.PasswordReset{}, err
+	}
+
+	return newPasswordReset(dbReset), nil
+}
+
+// GetPasswordReset attempts to retrieve the reset with the token if it can
+// still be used.
+func (m *Repository) GetPasswordReset(ctx context.Context, token string) (models.PasswordReset, error) {
+	ctx, span := m.startSpan(ctx, "GetPasswordReset")
+	defer span.End()
+
+	dbReset, err := m.queries.GetActivePasswordReset(ctx, token)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.PasswordReset{}, models.ErrNotFound.Cause(err)
+	} else if err != nil {
+		return models.PasswordReset{}, err
+	}
+
+	return newPasswordReset(dbReset), nil
+}
+
+// ResetPassword uses the reset with the token to change its user's password.
+// Using it also stops any other resets for the user from being used.
+func (m *Repository) ResetPassword(ctx context.Context, token, password string) (models.User, error) {
+	ctx, span := m.startSpan(ctx, "ResetPassword")
+	defer span.End()
+
+	txRepo, tx, err := m.WithTx(ctx)
+	if err != nil {
+		return models.User{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	dbReset, err := txRepo.queries.UsePasswordReset(ctx, token)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.User{}, models.ErrNotFound.Cause(err)
+	} else if err != nil {
+		return models.User{}, err
+	}
+
+	err = txRepo.queries.SetUserPassword(ctx, queries.SetUserPasswordParams{
+		ID:       dbReset.UserID,
+		Password: password,
+	})
+	if err != nil {
+		return models.User{}, err
+	}
+
+	if err := txRepo.queries.ExpirePasswordResetsForUser(ctx, dbReset.UserID); err != nil {
+		return models.User{}, err
+	}
+
+	dbUser, err := txRepo.queries.GetUser(ctx, dbReset.UserID)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	// Nobody is logged in, so it's the user whose password it is who acted
+	ctx = audit.WithUser(ctx, dbUser.ID, dbUser.Email)
+	if err := txRepo.audit(ctx, models.AuditActionPasswordReset, models.AuditTargetUser, dbUser.ID, nil); err != nil {
+		return models.User{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.User{}, err
+	}
+
+	return newUser(dbUser), nil
+}
@@ -0,0 +1,91 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"emailaddress.horse/thousand/form"
+	"emailaddress.horse/thousand/models"
+	"github.com/google/uuid"
+)
+
+func TestCreatePasswordReset(t *testing.T) {
+	m := newTestRepository(t)
+
+	reset, err := m.CreatePasswordReset(context.Background(), m.UserID())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reset.Token == "" {
+		t.Errorf("expected a token")
+	}
+
+	expected := time.Now().Add(models.PasswordResetExpiry)
+	if reset.ExpiresAt.Before(expected.Add(-time.Minute)) || reset.ExpiresAt.After(expected.Add(time.Minute)) {
+		t.Errorf("expected reset to expire around %s; got %s", expected, reset.ExpiresAt)
+	}
+
+	actual, err := m.GetPasswordReset(context.Background(), reset.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if actual.ID != reset.ID {
+		t.Errorf("expected %q; got %q", reset.ID, actual.ID)
+	}
+
+	_, err = m.CreatePasswordReset(context.Background(), uuid.New())
+	if !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("expected not found for an unknown user; got %v", err)
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	m := newTestRepository(t)
+
+	email := fmt.Sprintf("%s@example.com", uuid.New())
+	user, err := m.CreateUser(context.Background(), form.NewUser(email, "password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reset, err := m.CreatePasswordReset(context.Background(), user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := m.CreatePasswordReset(context.Background(), user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := m.ResetPassword(context.Background(), reset.Token, "new password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if actual.ID != user.ID {
+		t.Errorf("expected %q; got %q", user.ID, actual.ID)
+	}
+
+	authenticated, err := m.AuthenticateUser(context.Background(), form.NewSession(email, "new password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if authenticated.ID != user.ID {
+		t.Errorf("expected to log in with the new password")
+	}
+
+	// Neither the used reset nor any other for the user can be used again
+	for _, token := range []string{reset.Token, other.Token} {
+		_, err := m.ResetPassword(context.Background(), token, "another password")
+		if !errors.Is(err, models.ErrNotFound) {
+			t.Errorf("expected not found for a used reset; got %v", err)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+-- name: SearchUsers :many
+SELECT
+    users.id,
+    users.email,
+    users.is_admin,
+    users.disabled_at,
+    users.created_at,
+    (
+        SELECT
+            count(*)
+        FROM
+            vampires
+        WHERE
+            vampires.user_id = users.id
+            AND vampires.deleted_at IS NULL) AS vampire_count
+FROM
+    users
+WHERE
+    @search::text = ''
+    OR users.email ILIKE '%' || @search::text || '%'
+    OR users.id::text = @search::text
+ORDER BY
+    users.created_at DESC,
+    users.id DESC
+LIMIT @page_size;
+
+-- name: SearchVampires :many
+SELECT
+    vampires.id,
+    vampires.name,
+    vampires.user_id,
+    COALESCE(users.email, '')::text AS owner_email,
+    vampires.created_at,
+    vampires.deleted_at
+FROM
+    vampires
+    LEFT JOIN users ON users.id = vampires.user_id
+WHERE
+    @search::text = ''
+    OR to_tsvector('english', vampires.name) @@ websearch_to_tsquery('english', @search::text)
+    OR users.email ILIKE '%' || @search::text || '%'
+    OR vampires.id::text = @search::text
+ORDER BY
+    vampires.created_at DESC,
+    vampires.id DESC
+LIMIT @page_size;
+
+-- name: GetAdminStats :one
+SELECT
+    (
+        SELECT
+            count(*)
+        FROM
+            users) AS users,
+    (
+        SELECT
+            count(*)
+        FROM
+            users
+        WHERE
+            is_admin) AS admins,
+    (
+        SELECT
+            count(*)
+        FROM
+            users
+        WHERE
+            disabled_at IS NOT NULL) AS disabled_users,
+    (
+        SELECT
+            count(*)
+        FROM
+            users
+        WHERE
+            created_at > NOW() - make_interval(secs => @recent_seconds::int)) AS recent_users,
+    (
+        SELECT
+            count(*)
+        FROM
+            vampires
+        WHERE
+            deleted_at IS NULL) AS vampires,
+    (
+        SELECT
+            count(*)
+        FROM
+            vampires
+        WHERE
+            deleted_at IS NOT NULL) AS trashed_vampires,
+    (
+        SELECT
+            count(*)
+        FROM
+            experiences) AS experiences,
+    (
+        SELECT
+            count(*)
+        FROM
+            audit_events
+        WHERE
+            action = @login_action::text
+            AND created_at > NOW() - make_interval(secs => @recent_seconds::int)) AS recent_logins,
+    (
+        SELECT
+            count(*)
+        FROM
+            audit_events
+        WHERE
+            action = @login_failed_action::text
+            AND created_at > NOW() - make_interval(secs => @recent_seconds::int)) AS recent_failed_logins;
+
+-- name: SetUserAdmin :one
+UPDATE
+    users
+SET
+    is_admin = @is_admin,
+    updated_at = NOW()
+WHERE
+    id = @id
+RETURNING
+    *;
+
+-- name: SetUserDisabled :one
+UPDATE
+    users
+SET
+    disabled_at = CASE WHEN @disabled::boolean THEN
+        COALESCE(disabled_at, NOW())
+    END,
+    updated_at = NOW()
+WHERE
+    id = @id
+RETURNING
+    *;
@@ -0,0 +1,309 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: admin.sql
+
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const getAdminStats = `-- name: GetAdminStats :one
+SELECT
+    (
+        SELECT
+            count(*)
+        FROM
+            users) AS users,
+    (
+        SELECT
+            count(*)
+        FROM
+            users
+        WHERE
+            is_admin) AS admins,
+    (
+        SELECT
+            count(*)
+        FROM
+            users
+        WHERE
+            disabled_at IS NOT NULL) AS disabled_users,
+    (
+        SELECT
+            count(*)
+        FROM
+            users
+        WHERE
+            created_at > NOW() - make_interval(secs => $1::int)) AS recent_users,
+    (
+        SELECT
+            count(*)
+        FROM
+            vampires
+        WHERE
+            deleted_at IS NULL) AS vampires,
+    (
+        SELECT
+            count(*)
+        FROM
+            vampires
+        WHERE
+            deleted_at IS NOT NULL) AS trashed_vampires,
+    (
+        SELECT
+            count(*)
+        FROM
+            experiences) AS experiences,
+    (
+        SELECT
+            count(*)
+        FROM
+            audit_events
+        WHERE
+            action = $2::text
+            AND created_at > NOW() - make_interval(secs => $1::int)) AS recent_logins,
+    (
+        SELECT
+            count(*)
+        FROM
+            audit_events
+        WHERE
+            action = $3::text
+            AND created_at > NOW() - make_interval(secs => $1::int)) AS recent_failed_logins
+`
+
+type GetAdminStatsParams struct {
+	RecentSeconds     int32
+	LoginAction       string
+	LoginFailedAction string
+}
+
+type GetAdminStatsRow struct {
+	Users              int64
+	Admins             int64
+	DisabledUsers      int64
+	RecentUsers        int64
+	Vampires           int64
+	TrashedVampires    int64
+	Experiences        int64
+	RecentLogins       int64
+	RecentFailedLogins int64
+}
+
+func (q *Queries) GetAdminStats(ctx context.Context, arg GetAdminStatsParams) (GetAdminStatsRow, error) {
+	row := q.db.QueryRow(ctx, getAdminStats, arg.RecentSeconds, arg.LoginAction, arg.LoginFailedAction)
+	var i GetAdminStatsRow
+	err := row.Scan(
+		&i.Users,
+		&i.Admins,
+		&i.DisabledUsers,
+		&i.RecentUsers,
+		&i.Vampires,
+		&i.TrashedVampires,
+		&i.Experiences,
+		&i.RecentLogins,
+		&i.RecentFailedLogins,
+	)
+	return i, err
+}
+
+const searchUsers = `-- name: SearchUsers :many
+SELECT
+    users.id,
+    users.email,
+    users.is_admin,
+    users.disabled_at,
+    users.created_at,
+    (
+        SELECT
+            count(*)
+        FROM
+            vampires
+        WHERE
+            vampires.user_id = users.id
+            AND vampires.deleted_at IS NULL) AS vampire_count
+FROM
+    users
+WHERE
+    $1::text = ''
+    OR users.email ILIKE '%' || $1::text || '%'
+    OR users.id::text = $1::text
+ORDER BY
+    users.created_at DESC,
+    users.id DESC
+LIMIT $2
+`
+
+type SearchUsersParams struct {
+	Search   string
+	PageSize int32
+}
+
+type SearchUsersRow struct {
+	ID           uuid.UUID
+	Email        string
+	IsAdmin      bool
+	DisabledAt   sql.NullTime
+	CreatedAt    time.Time
+	VampireCount int64
+}
+
+func (q *Queries) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]SearchUsersRow, error) {
+	rows, err := q.db.Query(ctx, searchUsers, arg.Search, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchUsersRow
+	for rows.Next() {
+		var i SearchUsersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.IsAdmin,
+			&i.DisabledAt,
+			&i.CreatedAt,
+			&i.VampireCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchVampires = `-- name: SearchVampires :many
+SELECT
+    vampires.id,
+    vampires.name,
+    vampires.user_id,
+    COALESCE(users.email, '')::text AS owner_email,
+    vampires.created_at,
+    vampires.deleted_at
+FROM
+    vampires
+    LEFT JOIN users ON users.id = vampires.user_id
+WHERE
+    $1::text = ''
+    OR to_tsvector('english', vampires.name) @@ websearch_to_tsquery('english', $1::text)
+    OR users.email ILIKE '%' || $1::text || '%'
+    OR vampires.id::text = $1::text
+ORDER BY
+    vampires.created_at DESC,
+    vampires.id DESC
+LIMIT $2
+`
+
+type SearchVampiresParams struct {
+	Search   string
+	PageSize int32
+}
+
+type SearchVampiresRow struct {
+	ID         uuid.UUID
+	Name       string
+	UserID     uuid.NullUUID
+	OwnerEmail string
+	CreatedAt  time.Time
+	DeletedAt  sql.NullTime
+}
+
+func (q *Queries) SearchVampires(ctx context.Context, arg SearchVampiresParams) ([]SearchVampiresRow, error) {
+	rows, err := q.db.Query(ctx, searchVampires, arg.Search, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchVampiresRow
+	for rows.Next() {
+		var i SearchVampiresRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.UserID,
+			&i.OwnerEmail,
+			&i.CreatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setUserAdmin = `-- name: SetUserAdmin :one
+UPDATE
+    users
+SET
+    is_admin = $1,
+    updated_at = NOW()
+WHERE
+    id = $2
+RETURNING
+    id, email, password_hash, created_at, updated_at, is_admin, disabled_at
+`
+
+type SetUserAdminParams struct {
+	IsAdmin bool
+	ID      uuid.UUID
+}
+
+func (q *Queries) SetUserAdmin(ctx context.Context, arg SetUserAdminParams) (User, error) {
+	row := q.db.QueryRow(ctx, setUserAdmin, arg.IsAdmin, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.PasswordHash,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.IsAdmin,
+		&i.DisabledAt,
+	)
+	return i, err
+}
+
+const setUserDisabled = `-- name: SetUserDisabled :one
+UPDATE
+    users
+SET
+    disabled_at = CASE WHEN $1::boolean THEN
+        COALESCE(disabled_at, NOW())
+    END,
+    updated_at = NOW()
+WHERE
+    id = $2
+RETURNING
+    id, email, password_hash, created_at, updated_at, is_admin, disabled_at
+`
+
+type SetUserDisabledParams struct {
+	Disabled bool
+	ID       uuid.UUID
+}
+
+func (q *Queries) SetUserDisabled(ctx context.Context, arg SetUserDisabledParams) (User, error) {
+	row := q.db.QueryRow(ctx, setUserDisabled, arg.Disabled, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.PasswordHash,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.IsAdmin,
+		&i.DisabledAt,
+	)
+	return i, err
+}
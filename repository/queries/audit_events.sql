@@ -0,0 +1,46 @@
+-- name: CreateAuditEvent :one
+INSERT INTO audit_events (actor_id, actor_email, impersonator_id, ip, user_agent, action, target_type, target_id, changes)
+    VALUES (@actor_id, lower(@actor_email), @impersonator_id, @ip, @user_agent, @action, @target_type, @target_id, @changes)
+RETURNING
+    *;
+
+-- name: GetAuditEventsForUser :many
+SELECT
+    *
+FROM
+    audit_events
+WHERE
+    actor_id = @user_id::uuid
+    OR (target_type = 'user'
+        AND target_id = @user_id::uuid)
+    -- Failed logins have no actor, only the email address which was tried
+    OR (actor_id IS NULL
+        AND actor_email = (
+            SELECT
+                email
+            FROM
+                users
+            WHERE
+                id = @user_id::uuid))
+ORDER BY
+    created_at DESC,
+    id DESC
+LIMIT @page_size;
+
+-- name: GetAuditEvents :many
+SELECT
+    *
+FROM
+    audit_events
+WHERE (@actor_email::text = ''
+    OR actor_email = lower(@actor_email::text))
+AND (@action::text = ''
+    OR action = @action::text)
+AND (NOT @has_target::boolean
+    OR target_id = @target_id::uuid)
+AND (@since_seconds::int = 0
+    OR created_at >= NOW() - make_interval(secs => @since_seconds::int))
+ORDER BY
+    created_at DESC,
+    id DESC
+LIMIT @page_size;
@@ -0,0 +1,187 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: audit_events.sql
+
+package queries
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgtype"
+)
+
+const createAuditEvent = `-- name: CreateAuditEvent :one
+INSERT INTO audit_events (actor_id, actor_email, impersonator_id, ip, user_agent, action, target_type, target_id, changes)
+    VALUES ($1, lower($2), $3, $4, $5, $6, $7, $8, $9)
+RETURNING
+    id, actor_id, actor_email, ip, user_agent, action, target_type, target_id, changes, created_at, impersonator_id
+`
+
+type CreateAuditEventParams struct {
+	ActorID        uuid.NullUUID
+	ActorEmail     string
+	ImpersonatorID uuid.NullUUID
+	Ip             string
+	UserAgent      string
+	Action         string
+	TargetType     string
+	TargetID       uuid.NullUUID
+	Changes        pgtype.JSONB
+}
+
+func (q *Queries) CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) (AuditEvent, error) {
+	row := q.db.QueryRow(ctx, createAuditEvent,
+		arg.ActorID,
+		arg.ActorEmail,
+		arg.ImpersonatorID,
+		arg.Ip,
+		arg.UserAgent,
+		arg.Action,
+		arg.TargetType,
+		arg.TargetID,
+		arg.Changes,
+	)
+	var i AuditEvent
+	err := row.Scan(
+		&i.ID,
+		&i.ActorID,
+		&i.ActorEmail,
+		&i.Ip,
+		&i.UserAgent,
+		&i.Action,
+		&i.TargetType,
+		&i.TargetID,
+		&i.Changes,
+		&i.CreatedAt,
+		&i.ImpersonatorID,
+	)
+	return i, err
+}
+
+const getAuditEvents = `-- name: GetAuditEvents :many
+SELECT
+    id, actor_id, actor_email, ip, user_agent, action, target_type, target_id, changes, created_at, impersonator_id
+FROM
+    audit_events
+WHERE ($1::text = ''
+    OR actor_email = lower($1::text))
+AND ($2::text = ''
+    OR action = $2::text)
+AND (NOT $3::boolean
+    OR target_id = $4::uuid)
+AND ($5::int = 0
+    OR created_at >= NOW() - make_interval(secs => $5::int))
+ORDER BY
+    created_at DESC,
+    id DESC
+LIMIT $6
+`
+
+type GetAuditEventsParams struct {
+	ActorEmail   string
+	Action       string
+	HasTarget    bool
+	TargetID     uuid.UUID
+	SinceSeconds int32
+	PageSize     int32
+}
+
+func (q *Queries) GetAuditEvents(ctx context.Context, arg GetAuditEventsParams) ([]AuditEvent, error) {
+	rows, err := q.db.Query(ctx, getAuditEvents,
+		arg.ActorEmail,
+		arg.Action,
+		arg.HasTarget,
+		arg.TargetID,
+		arg.SinceSeconds,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditEvent
+	for rows.Next() {
+		var i AuditEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.ActorID,
+			&i.ActorEmail,
+			&i.Ip,
+			&i.UserAgent,
+			&i.Action,
+			&i.TargetType,
+			&i.TargetID,
+			&i.Changes,
+			&i.CreatedAt,
+			&i.ImpersonatorID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAuditEventsForUser = `-- name: GetAuditEventsForUser :many
+SELECT
+    id, actor_id, actor_email, ip, user_agent, action, target_type, target_id, changes, created_at, impersonator_id
+FROM
+    audit_events
+WHERE
+    actor_id = $1::uuid
+    OR (target_type = 'user'
+        AND target_id = $1::uuid)
+    -- Failed logins have no actor, only the email address which was tried
+    OR (actor_id IS NULL
+        AND actor_email = (
+            SELECT
+                email
+            FROM
+                users
+            WHERE
+                id = $1::uuid))
+ORDER BY
+    created_at DESC,
+    id DESC
+LIMIT $2
+`
+
+type GetAuditEventsForUserParams struct {
+	UserID   uuid.UUID
+	PageSize int32
+}
+
+func (q *Queries) GetAuditEventsForUser(ctx context.Context, arg GetAuditEventsForUserParams) ([]AuditEvent, error) {
+	rows, err := q.db.Query(ctx, getAuditEventsForUser, arg.UserID, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditEvent
+	for rows.Next() {
+		var i AuditEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.ActorID,
+			&i.ActorEmail,
+			&i.Ip,
+			&i.UserAgent,
+			&i.Action,
+			&i.TargetType,
+			&i.TargetID,
+			&i.Changes,
+			&i.CreatedAt,
+			&i.ImpersonatorID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,323 @@
+-- name: GetBackupUser :one
+SELECT
+    *
+FROM
+    users
+WHERE
+    email = lower(@email)
+LIMIT 1;
+
+-- name: GetBackupUserIdentities :many
+SELECT
+    *
+FROM
+    user_identities
+WHERE
+    user_id = @user_id
+ORDER BY
+    created_at,
+    id;
+
+-- name: GetBackupVampires :many
+SELECT
+    *
+FROM
+    vampires
+WHERE
+    user_id = @user_id::uuid
+ORDER BY
+    created_at,
+    id;
+
+-- name: GetBackupMemories :many
+SELECT
+    memories.*
+FROM
+    memories
+    INNER JOIN vampires ON vampires.id = memories.vampire_id
+WHERE
+    vampires.user_id = @user_id::uuid
+ORDER BY
+    memories.created_at,
+    memories.id;
+
+-- name: GetBackupExperiences :many
+SELECT
+    experiences.*
+FROM
+    experiences
+    INNER JOIN memories ON memories.id = experiences.memory_id
+    INNER JOIN vampires ON vampires.id = memories.vampire_id
+WHERE
+    vampires.user_id = @user_id::uuid
+ORDER BY
+    experiences.created_at,
+    experiences.id;
+
+-- name: GetBackupSkills :many
+SELECT
+    skills.*
+FROM
+    skills
+    INNER JOIN vampires ON vampires.id = skills.vampire_id
+WHERE
+    vampires.user_id = @user_id::uuid
+ORDER BY
+    skills.created_at,
+    skills.id;
+
+-- name: GetBackupResources :many
+SELECT
+    resources.*
+FROM
+    resources
+    INNER JOIN vampires ON vampires.id = resources.vampire_id
+WHERE
+    vampires.user_id = @user_id::uuid
+ORDER BY
+    resources.created_at,
+    resources.id;
+
+-- name: GetBackupCharacters :many
+SELECT
+    characters.*
+FROM
+    characters
+    INNER JOIN vampires ON vampires.id = characters.vampire_id
+WHERE
+    vampires.user_id = @user_id::uuid
+ORDER BY
+    characters.created_at,
+    characters.id;
+
+-- name: GetBackupMarks :many
+SELECT
+    marks.*
+FROM
+    marks
+    INNER JOIN vampires ON vampires.id = marks.vampire_id
+WHERE
+    vampires.user_id = @user_id::uuid
+ORDER BY
+    marks.created_at,
+    marks.id;
+
+-- name: GetBackupShareLinks :many
+SELECT
+    share_links.*
+FROM
+    share_links
+    INNER JOIN vampires ON vampires.id = share_links.vampire_id
+WHERE
+    vampires.user_id = @user_id::uuid
+ORDER BY
+    share_links.created_at,
+    share_links.id;
+
+-- name: GetBackupVampireMembers :many
+SELECT
+    vampire_members.*,
+    users.email
+FROM
+    vampire_members
+    INNER JOIN vampires ON vampires.id = vampire_members.vampire_id
+    INNER JOIN users ON users.id = vampire_members.user_id
+WHERE
+    vampires.user_id = @user_id::uuid
+    OR vampire_members.user_id = @user_id
+ORDER BY
+    vampire_members.created_at,
+    vampire_members.id;
+
+-- name: GetBackupVampireInvitations :many
+SELECT
+    vampire_invitations.*,
+    users.email AS invited_by_email
+FROM
+    vampire_invitations
+    INNER JOIN vampires ON vampires.id = vampire_invitations.vampire_id
+    INNER JOIN users ON users.id = vampire_invitations.invited_by
+WHERE
+    vampires.user_id = @user_id::uuid
+    OR vampire_invitations.invited_by = @user_id
+ORDER BY
+    vampire_invitations.created_at,
+    vampire_invitations.id;
+
+-- name: RestoreUser :one
+INSERT INTO users (id, email, password_hash, created_at, updated_at, is_admin, disabled_at)
+    VALUES (@id, @email, @password_hash, @created_at, @updated_at, @is_admin, @disabled_at)
+ON CONFLICT (id)
+    DO NOTHING
+RETURNING
+    id;
+
+-- name: RestoreUserIdentity :one
+INSERT INTO user_identities (id, user_id, issuer, subject, email, created_at, updated_at)
+    VALUES (@id, @user_id, @issuer, @subject, @email, @created_at, @updated_at)
+ON CONFLICT (id)
+    DO NOTHING
+RETURNING
+    id;
+
+-- name: RestoreVampire :one
+INSERT INTO vampires (id, user_id, name, created_at, updated_at, deleted_at, archived_at)
+    VALUES (@id, @user_id, @name, @created_at, @updated_at, @deleted_at, @archived_at)
+ON CONFLICT (id)
+    DO NOTHING
+RETURNING
+    id;
+
+-- name: RestoreMemory :one
+INSERT INTO memories (id, vampire_id, created_at, updated_at)
+    VALUES (@id, @vampire_id, @created_at, @updated_at)
+ON CONFLICT (id)
+    DO NOTHING
+RETURNING
+    id;
+
+-- name: RestoreExperience :one
+INSERT INTO experiences (id, memory_id, description, created_at, updated_at)
+    VALUES (@id, @memory_id, @description, @created_at, @updated_at)
+ON CONFLICT (id)
+    DO NOTHING
+RETURNING
+    id;
+
+-- name: RestoreSkill :one
+INSERT INTO skills (id, vampire_id, description, created_at, updated_at)
+    VALUES (@id, @vampire_id, @description, @created_at, @updated_at)
+ON CONFLICT (id)
+    DO NOTHING
+RETURNING
+    id;
+
+-- name: RestoreResource :one
+INSERT INTO resources (id, vampire_id, description, stationary, created_at, updated_at)
+    VALUES (@id, @vampire_id, @description, @stationary, @created_at, @updated_at)
+ON CONFLICT (id)
+    DO NOTHING
+RETURNING
+    id;
+
+-- name: RestoreCharacter :one
+INSERT INTO characters (id, vampire_id, name, type, created_at, updated_at)
+    VALUES (@id, @vampire_id, @name, @type, @created_at, @updated_at)
+ON CONFLICT (id)
+    DO NOTHING
+RETURNING
+    id;
+
+-- name: RestoreMark :one
+INSERT INTO marks (id, vampire_id, description, created_at, updated_at)
+    VALUES (@id, @vampire_id, @description, @created_at, @updated_at)
+ON CONFLICT (id)
+    DO NOTHING
+RETURNING
+    id;
+
+-- name: RestoreShareLink :one
+-- A token already in use is replaced so the restored link doesn't take over
+-- another.
+INSERT INTO share_links (id, vampire_id, token, expires_at, revoked_at, created_at, updated_at)
+    VALUES (@id, @vampire_id, CASE WHEN EXISTS (
+            SELECT
+                1
+            FROM
+                share_links AS existing
+            WHERE
+                existing.token = @token) THEN
+            translate(encode(gen_random_bytes(24), 'base64'), '+/', '-_')
+        ELSE
+            @token
+        END, @expires_at, @revoked_at, @created_at, @updated_at)
+ON CONFLICT (id)
+    DO NOTHING
+RETURNING
+    id;
+
+-- name: RestoreVampireMember :one
+INSERT INTO vampire_members (id, vampire_id, user_id, ROLE, created_at, updated_at)
+    VALUES (@id, @vampire_id, @user_id, @role, @created_at, @updated_at)
+ON CONFLICT (id)
+    DO NOTHING
+RETURNING
+    id;
+
+-- name: RestoreVampireInvitation :one
+INSERT INTO vampire_invitations (id, vampire_id, invited_by, email, ROLE, created_at, updated_at)
+    VALUES (@id, @vampire_id, @invited_by, @email, @role, @created_at, @updated_at)
+ON CONFLICT (id)
+    DO NOTHING
+RETURNING
+    id;
+
+-- name: VampireExists :one
+SELECT
+    EXISTS (
+        SELECT
+            1
+        FROM
+            vampires
+        WHERE
+            id = @id);
+
+-- name: DeleteBackupExperiences :exec
+DELETE FROM experiences USING memories, vampires
+WHERE memories.id = experiences.memory_id
+    AND vampires.id = memories.vampire_id
+    AND vampires.user_id = @user_id::uuid;
+
+-- name: DeleteBackupMemories :exec
+DELETE FROM memories USING vampires
+WHERE vampires.id = memories.vampire_id
+    AND vampires.user_id = @user_id::uuid;
+
+-- name: DeleteBackupSkills :exec
+DELETE FROM skills USING vampires
+WHERE vampires.id = skills.vampire_id
+    AND vampires.user_id = @user_id::uuid;
+
+-- name: DeleteBackupResources :exec
+DELETE FROM resources USING vampires
+WHERE vampires.id = resources.vampire_id
+    AND vampires.user_id = @user_id::uuid;
+
+-- name: DeleteBackupCharacters :exec
+DELETE FROM characters USING vampires
+WHERE vampires.id = characters.vampire_id
+    AND vampires.user_id = @user_id::uuid;
+
+-- name: DeleteBackupMarks :exec
+DELETE FROM marks USING vampires
+WHERE vampires.id = marks.vampire_id
+    AND vampires.user_id = @user_id::uuid;
+
+-- name: DeleteBackupShareLinks :exec
+DELETE FROM share_links USING vampires
+WHERE vampires.id = share_links.vampire_id
+    AND vampires.user_id = @user_id::uuid;
+
+-- name: DeleteBackupVampireMembers :exec
+DELETE FROM vampire_members USING vampires
+WHERE vampires.id = vampire_members.vampire_id
+    AND (vampires.user_id = @user_id::uuid
+        OR vampire_members.user_id = @user_id);
+
+-- name: DeleteBackupVampireInvitations :exec
+DELETE FROM vampire_invitations USING vampires
+WHERE vampires.id = vampire_invitations.vampire_id
+    AND (vampires.user_id = @user_id::uuid
+        OR vampire_invitations.invited_by = @user_id);
+
+-- name: DeleteBackupVampires :exec
+DELETE FROM vampires
+WHERE user_id = @user_id::uuid;
+
+-- name: DeleteBackupUserIdentities :exec
+DELETE FROM user_identities
+WHERE user_id = @user_id;
+
+-- name: DeleteBackupUser :exec
+DELETE FROM users
+WHERE id = @id;
@@ -0,0 +1,1050 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: backups.sql
+
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const deleteBackupCharacters = `-- name: DeleteBackupCharacters :exec
+DELETE FROM characters USING vampires
+WHERE vampires.id = characters.vampire_id
+    AND vampires.user_id = $1::uuid
+`
+
+func (q *Queries) DeleteBackupCharacters(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteBackupCharacters, userID)
+	return err
+}
+
+const deleteBackupExperiences = `-- name: DeleteBackupExperiences :exec
+DELETE FROM experiences USING memories, vampires
+WHERE memories.id = experiences.memory_id
+    AND vampires.id = memories.vampire_id
+    AND vampires.user_id = $1::uuid
+`
+
+func (q *Queries) DeleteBackupExperiences(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteBackupExperiences, userID)
+	return err
+}
+
+const deleteBackupMarks = `-- name: DeleteBackupMarks :exec
+DELETE FROM marks USING vampires
+WHERE vampires.id = marks.vampire_id
+    AND vampires.user_id = $1::uuid
+`
+
+func (q *Queries) DeleteBackupMarks(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteBackupMarks, userID)
+	return err
+}
+
+const deleteBackupMemories = `-- name: DeleteBackupMemories :exec
+DELETE FROM memories USING vampires
+WHERE vampires.id = memories.vampire_id
+    AND vampires.user_id = $1::uuid
+`
+
+func (q *Queries) DeleteBackupMemories(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteBackupMemories, userID)
+	return err
+}
+
+const deleteBackupResources = `-- name: DeleteBackupResources :exec
+DELETE FROM resources USING vampires
+WHERE vampires.id = resources.vampire_id
+    AND vampires.user_id = $1::uuid
+`
+
+func (q *Queries) DeleteBackupResources(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteBackupResources, userID)
+	return err
+}
+
+const deleteBackupShareLinks = `-- name: DeleteBackupShareLinks :exec
+DELETE FROM share_links USING vampires
+WHERE vampires.id = share_links.vampire_id
+    AND vampires.user_id = $1::uuid
+`
+
+func (q *Queries) DeleteBackupShareLinks(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteBackupShareLinks, userID)
+	return err
+}
+
+const deleteBackupSkills = `-- name: DeleteBackupSkills :exec
+DELETE FROM skills USING vampires
+WHERE vampires.id = skills.vampire_id
+    AND vampires.user_id = $1::uuid
+`
+
+func (q *Queries) DeleteBackupSkills(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteBackupSkills, userID)
+	return err
+}
+
+const deleteBackupUser = `-- name: DeleteBackupUser :exec
+DELETE FROM users
+WHERE id = $1
+`
+
+func (q *Queries) DeleteBackupUser(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteBackupUser, id)
+	return err
+}
+
+const deleteBackupUserIdentities = `-- name: DeleteBackupUserIdentities :exec
+DELETE FROM user_identities
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteBackupUserIdentities(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteBackupUserIdentities, userID)
+	return err
+}
+
+const deleteBackupVampireInvitations = `-- name: DeleteBackupVampireInvitations :exec
+DELETE FROM vampire_invitations USING vampires
+WHERE vampires.id = vampire_invitations.vampire_id
+    AND (vampires.user_id = $1::uuid
+        OR vampire_invitations.invited_by = $1)
+`
+
+func (q *Queries) DeleteBackupVampireInvitations(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteBackupVampireInvitations, userID)
+	return err
+}
+
+const deleteBackupVampireMembers = `-- name: DeleteBackupVampireMembers :exec
+DELETE FROM vampire_members USING vampires
+WHERE vampires.id = vampire_members.vampire_id
+    AND (vampires.user_id = $1::uuid
+        OR vampire_members.user_id = $1)
+`
+
+func (q *Queries) DeleteBackupVampireMembers(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteBackupVampireMembers, userID)
+	return err
+}
+
+const deleteBackupVampires = `-- name: DeleteBackupVampires :exec
+DELETE FROM vampires
+WHERE user_id = $1::uuid
+`
+
+func (q *Queries) DeleteBackupVampires(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteBackupVampires, userID)
+	return err
+}
+
+const getBackupCharacters = `-- name: GetBackupCharacters :many
+SELECT
+    characters.id, characters.vampire_id, characters.name, characters.type, characters.created_at, characters.updated_at
+FROM
+    characters
+    INNER JOIN vampires ON vampires.id = characters.vampire_id
+WHERE
+    vampires.user_id = $1::uuid
+ORDER BY
+    characters.created_at,
+    characters.id
+`
+
+func (q *Queries) GetBackupCharacters(ctx context.Context, userID uuid.UUID) ([]Character, error) {
+	rows, err := q.db.Query(ctx, getBackupCharacters, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Character
+	for rows.Next() {
+		var i Character
+		if err := rows.Scan(
+			&i.ID,
+			&i.VampireID,
+			&i.Name,
+			&i.Type,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBackupExperiences = `-- name: GetBackupExperiences :many
+SELECT
+    experiences.id, experiences.memory_id, experiences.description, experiences.created_at, experiences.updated_at
+FROM
+    experiences
+    INNER JOIN memories ON memories.id = experiences.memory_id
+    INNER JOIN vampires ON vampires.id = memories.vampire_id
+WHERE
+    vampires.user_id = $1::uuid
+ORDER BY
+    experiences.created_at,
+    experiences.id
+`
+
+func (q *Queries) GetBackupExperiences(ctx context.Context, userID uuid.UUID) ([]Experience, error) {
+	rows, err := q.db.Query(ctx, getBackupExperiences, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Experience
+	for rows.Next() {
+		var i Experience
+		if err := rows.Scan(
+			&i.ID,
+			&i.MemoryID,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBackupMarks = `-- name: GetBackupMarks :many
+SELECT
+    marks.id, marks.vampire_id, marks.description, marks.created_at, marks.updated_at
+FROM
+    marks
+    INNER JOIN vampires ON vampires.id = marks.vampire_id
+WHERE
+    vampires.user_id = $1::uuid
+ORDER BY
+    marks.created_at,
+    marks.id
+`
+
+func (q *Queries) GetBackupMarks(ctx context.Context, userID uuid.UUID) ([]Mark, error) {
+	rows, err := q.db.Query(ctx, getBackupMarks, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Mark
+	for rows.Next() {
+		var i Mark
+		if err := rows.Scan(
+			&i.ID,
+			&i.VampireID,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBackupMemories = `-- name: GetBackupMemories :many
+SELECT
+    memories.id, memories.vampire_id, memories.created_at, memories.updated_at
+FROM
+    memories
+    INNER JOIN vampires ON vampires.id = memories.vampire_id
+WHERE
+    vampires.user_id = $1::uuid
+ORDER BY
+    memories.created_at,
+    memories.id
+`
+
+func (q *Queries) GetBackupMemories(ctx context.Context, userID uuid.UUID) ([]Memory, error) {
+	rows, err := q.db.Query(ctx, getBackupMemories, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Memory
+	for rows.Next() {
+		var i Memory
+		if err := rows.Scan(
+			&i.ID,
+			&i.VampireID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBackupResources = `-- name: GetBackupResources :many
+SELECT
+    resources.id, resources.vampire_id, resources.description, resources.stationary, resources.created_at, resources.updated_at
+FROM
+    resources
+    INNER JOIN vampires ON vampires.id = resources.vampire_id
+WHERE
+    vampires.user_id = $1::uuid
+ORDER BY
+    resources.created_at,
+    resources.id
+`
+
+func (q *Queries) GetBackupResources(ctx context.Context, userID uuid.UUID) ([]Resource, error) {
+	rows, err := q.db.Query(ctx, getBackupResources, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Resource
+	for rows.Next() {
+		var i Resource
+		if err := rows.Scan(
+			&i.ID,
+			&i.VampireID,
+			&i.Description,
+			&i.Stationary,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBackupShareLinks = `-- name: GetBackupShareLinks :many
+SELECT
+    share_links.id, share_links.vampire_id, share_links.token, share_links.expires_at, share_links.revoked_at, share_links.created_at, share_links.updated_at
+FROM
+    share_links
+    INNER JOIN vampires ON vampires.id = share_links.vampire_id
+WHERE
+    vampires.user_id = $1::uuid
+ORDER BY
+    share_links.created_at,
+    share_links.id
+`
+
+func (q *Queries) GetBackupShareLinks(ctx context.Context, userID uuid.UUID) ([]ShareLink, error) {
+	rows, err := q.db.Query(ctx, getBackupShareLinks, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ShareLink
+	for rows.Next() {
+		var i ShareLink
+		if err := rows.Scan(
+			&i.ID,
+			&i.VampireID,
+			&i.Token,
+			&i.ExpiresAt,
+			&i.RevokedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBackupSkills = `-- name: GetBackupSkills :many
+SELECT
+    skills.id, skills.vampire_id, skills.description, skills.created_at, skills.updated_at
+FROM
+    skills
+    INNER JOIN vampires ON vampires.id = skills.vampire_id
+WHERE
+    vampires.user_id = $1::uuid
+ORDER BY
+    skills.created_at,
+    skills.id
+`
+
+func (q *Queries) GetBackupSkills(ctx context.Context, userID uuid.UUID) ([]Skill, error) {
+	rows, err := q.db.Query(ctx, getBackupSkills, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Skill
+	for rows.Next() {
+		var i Skill
+		if err := rows.Scan(
+			&i.ID,
+			&i.VampireID,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBackupUser = `-- name: GetBackupUser :one
+SELECT
+    id, email, password_hash, created_at, updated_at, is_admin, disabled_at
+FROM
+    users
+WHERE
+    email = lower($1)
+LIMIT 1
+`
+
+func (q *Queries) GetBackupUser(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRow(ctx, getBackupUser, email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.PasswordHash,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.IsAdmin,
+		&i.DisabledAt,
+	)
+	return i, err
+}
+
+const getBackupUserIdentities = `-- name: GetBackupUserIdentities :many
+SELECT
+    id, user_id, issuer, subject, email, created_at, updated_at
+FROM
+    user_identities
+WHERE
+    user_id = $1
+ORDER BY
+    created_at,
+    id
+`
+
+func (q *Queries) GetBackupUserIdentities(ctx context.Context, userID uuid.UUID) ([]UserIdentity, error) {
+	rows, err := q.db.Query(ctx, getBackupUserIdentities, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserIdentity
+	for rows.Next() {
+		var i UserIdentity
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Issuer,
+			&i.Subject,
+			&i.Email,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBackupVampireInvitations = `-- name: GetBackupVampireInvitations :many
+SELECT
+    vampire_invitations.id, vampire_invitations.vampire_id, vampire_invitations.invited_by, vampire_invitations.email, vampire_invitations.role, vampire_invitations.created_at, vampire_invitations.updated_at,
+    users.email AS invited_by_email
+FROM
+    vampire_invitations
+    INNER JOIN vampires ON vampires.id = vampire_invitations.vampire_id
+    INNER JOIN users ON users.id = vampire_invitations.invited_by
+WHERE
+    vampires.user_id = $1::uuid
+    OR vampire_invitations.invited_by = $1
+ORDER BY
+    vampire_invitations.created_at,
+    vampire_invitations.id
+`
+
+type GetBackupVampireInvitationsRow struct {
+	ID             uuid.UUID
+	VampireID      uuid.UUID
+	InvitedBy      uuid.UUID
+	Email          string
+	Role           MemberRole
+	CreatedAt      time.Time
+	UpdatedAt      sql.NullTime
+	InvitedByEmail string
+}
+
+func (q *Queries) GetBackupVampireInvitations(ctx context.Context, userID uuid.UUID) ([]GetBackupVampireInvitationsRow, error) {
+	rows, err := q.db.Query(ctx, getBackupVampireInvitations, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetBackupVampireInvitationsRow
+	for rows.Next() {
+		var i GetBackupVampireInvitationsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.VampireID,
+			&i.InvitedBy,
+			&i.Email,
+			&i.Role,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.InvitedByEmail,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBackupVampireMembers = `-- name: GetBackupVampireMembers :many
+SELECT
+    vampire_members.id, vampire_members.vampire_id, vampire_members.user_id, vampire_members.role, vampire_members.created_at, vampire_members.updated_at,
+    users.email
+FROM
+    vampire_members
+    INNER JOIN vampires ON vampires.id = vampire_members.vampire_id
+    INNER JOIN users ON users.id = vampire_members.user_id
+WHERE
+    vampires.user_id = $1::uuid
+    OR vampire_members.user_id = $1
+ORDER BY
+    vampire_members.created_at,
+    vampire_members.id
+`
+
+type GetBackupVampireMembersRow struct {
+	ID        uuid.UUID
+	VampireID uuid.UUID
+	UserID    uuid.UUID
+	Role      MemberRole
+	CreatedAt time.Time
+	UpdatedAt sql.NullTime
+	Email     string
+}
+
+func (q *Queries) GetBackupVampireMembers(ctx context.Context, userID uuid.UUID) ([]GetBackupVampireMembersRow, error) {
+	rows, err := q.db.Query(ctx, getBackupVampireMembers, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetBackupVampireMembersRow
+	for rows.Next() {
+		var i GetBackupVampireMembersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.VampireID,
+			&i.UserID,
+			&i.Role,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Email,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBackupVampires = `-- name: GetBackupVampires :many
+SELECT
+    id, name, created_at, updated_at, user_id, deleted_at, archived_at
+FROM
+    vampires
+WHERE
+    user_id = $1::uuid
+ORDER BY
+    created_at,
+    id
+`
+
+func (q *Queries) GetBackupVampires(ctx context.Context, userID uuid.UUID) ([]Vampire, error) {
+	rows, err := q.db.Query(ctx, getBackupVampires, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Vampire
+	for rows.Next() {
+		var i Vampire
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreCharacter = `-- name: RestoreCharacter :one
+INSERT INTO characters (id, vampire_id, name, type, created_at, updated_at)
+    VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (id)
+    DO NOTHING
+RETURNING
+    id
+`
+
+type RestoreCharacterParams struct {
+	ID        uuid.UUID
+	VampireID uuid.UUID
+	Name      string
+	Type      CharacterType
+	CreatedAt time.Time
+	UpdatedAt sql.NullTime
+}
+
+func (q *Queries) RestoreCharacter(ctx context.Context, arg RestoreCharacterParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, restoreCharacter,
+		arg.ID,
+		arg.VampireID,
+		arg.Name,
+		arg.Type,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const restoreExperience = `-- name: RestoreExperience :one
+INSERT INTO experiences (id, memory_id, description, created_at, updated_at)
+    VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (id)
+    DO NOTHING
+RETURNING
+    id
+`
+
+type RestoreExperienceParams struct {
+	ID          uuid.UUID
+	MemoryID    uuid.UUID
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   sql.NullTime
+}
+
+func (q *Queries) RestoreExperience(ctx context.Context, arg RestoreExperienceParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, restoreExperience,
+		arg.ID,
+		arg.MemoryID,
+		arg.Description,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const restoreMark = `-- name: RestoreMark :one
+INSERT INTO marks (id, vampire_id, description, created_at, updated_at)
+    VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (id)
+    DO NOTHING
+RETURNING
+    id
+`
+
+type RestoreMarkParams struct {
+	ID          uuid.UUID
+	VampireID   uuid.UUID
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   sql.NullTime
+}
+
+func (q *Queries) RestoreMark(ctx context.Context, arg RestoreMarkParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, restoreMark,
+		arg.ID,
+		arg.VampireID,
+		arg.Description,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const restoreMemory = `-- name: RestoreMemory :one
+INSERT INTO memories (id, vampire_id, created_at, updated_at)
+    VALUES ($1, $2, $3, $4)
+ON CONFLICT (id)
+    DO NOTHING
+RETURNING
+    id
+`
+
+type RestoreMemoryParams struct {
+	ID        uuid.UUID
+	VampireID uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt sql.NullTime
+}
+
+func (q *Queries) RestoreMemory(ctx context.Context, arg RestoreMemoryParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, restoreMemory,
+		arg.ID,
+		arg.VampireID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const restoreResource = `-- name: RestoreResource :one
+INSERT INTO resources (id, vampire_id, description, stationary, created_at, updated_at)
+    VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (id)
+    DO NOTHING
+RETURNING
+    id
+`
+
+type RestoreResourceParams struct {
+	ID          uuid.UUID
+	VampireID   uuid.UUID
+	Description string
+	Stationary  bool
+	CreatedAt   time.Time
+	UpdatedAt   sql.NullTime
+}
+
+func (q *Queries) RestoreResource(ctx context.Context, arg RestoreResourceParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, restoreResource,
+		arg.ID,
+		arg.VampireID,
+		arg.Description,
+		arg.Stationary,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const restoreShareLink = `-- name: RestoreShareLink :one
+INSERT INTO share_links (id, vampire_id, token, expires_at, revoked_at, created_at, updated_at)
+    VALUES ($1, $2, CASE WHEN EXISTS (
+            SELECT
+                1
+            FROM
+                share_links AS existing
+            WHERE
+                existing.token = $3) THEN
+            translate(encode(gen_random_bytes(24), 'base64'), '+/', '-_')
+        ELSE
+            $3
+        END, $4, $5, $6, $7)
+ON CONFLICT (id)
+    DO NOTHING
+RETURNING
+    id
+`
+
+type RestoreShareLinkParams struct {
+	ID        uuid.UUID
+	VampireID uuid.UUID
+	Token     string
+	ExpiresAt sql.NullTime
+	RevokedAt sql.NullTime
+	CreatedAt time.Time
+	UpdatedAt sql.NullTime
+}
+
+// A token already in use is replaced so the restored link doesn't take over
+// another.
+func (q *Queries) RestoreShareLink(ctx context.Context, arg RestoreShareLinkParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, restoreShareLink,
+		arg.ID,
+		arg.VampireID,
+		arg.Token,
+		arg.ExpiresAt,
+		arg.RevokedAt,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const restoreSkill = `-- name: RestoreSkill :one
+INSERT INTO skills (id, vampire_id, description, created_at, updated_at)
+    VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (id)
+    DO NOTHING
+RETURNING
+    id
+`
+
+type RestoreSkillParams struct {
+	ID          uuid.UUID
+	VampireID   uuid.UUID
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   sql.NullTime
+}
+
+func (q *Queries) RestoreSkill(ctx context.Context, arg RestoreSkillParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, restoreSkill,
+		arg.ID,
+		arg.VampireID,
+		arg.Description,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const restoreUser = `-- name: RestoreUser :one
+INSERT INTO users (id, email, password_hash, created_at, updated_at, is_admin, disabled_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (id)
+    DO NOTHING
+RETURNING
+    id
+`
+
+type RestoreUserParams struct {
+	ID           uuid.UUID
+	Email        string
+	PasswordHash sql.NullString
+	CreatedAt    time.Time
+	UpdatedAt    sql.NullTime
+	IsAdmin      bool
+	DisabledAt   sql.NullTime
+}
+
+func (q *Queries) RestoreUser(ctx context.Context, arg RestoreUserParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, restoreUser,
+		arg.ID,
+		arg.Email,
+		arg.PasswordHash,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.IsAdmin,
+		arg.DisabledAt,
+	)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const restoreUserIdentity = `-- name: RestoreUserIdentity :one
+INSERT INTO user_identities (id, user_id, issuer, subject, email, created_at, updated_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (id)
+    DO NOTHING
+RETURNING
+    id
+`
+
+type RestoreUserIdentityParams struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Issuer    string
+	Subject   string
+	Email     string
+	CreatedAt time.Time
+	UpdatedAt sql.NullTime
+}
+
+func (q *Queries) RestoreUserIdentity(ctx context.Context, arg RestoreUserIdentityParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, restoreUserIdentity,
+		arg.ID,
+		arg.UserID,
+		arg.Issuer,
+		arg.Subject,
+		arg.Email,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const restoreVampire = `-- name: RestoreVampire :one
+INSERT INTO vampires (id, user_id, name, created_at, updated_at, deleted_at, archived_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (id)
+    DO NOTHING
+RETURNING
+    id
+`
+
+type RestoreVampireParams struct {
+	ID         uuid.UUID
+	UserID     uuid.NullUUID
+	Name       string
+	CreatedAt  time.Time
+	UpdatedAt  sql.NullTime
+	DeletedAt  sql.NullTime
+	ArchivedAt sql.NullTime
+}
+
+func (q *Queries) RestoreVampire(ctx context.Context, arg RestoreVampireParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, restoreVampire,
+		arg.ID,
+		arg.UserID,
+		arg.Name,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.DeletedAt,
+		arg.ArchivedAt,
+	)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const restoreVampireInvitation = `-- name: RestoreVampireInvitation :one
+INSERT INTO vampire_invitations (id, vampire_id, invited_by, email, ROLE, created_at, updated_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (id)
+    DO NOTHING
+RETURNING
+    id
+`
+
+type RestoreVampireInvitationParams struct {
+	ID        uuid.UUID
+	VampireID uuid.UUID
+	InvitedBy uuid.UUID
+	Email     string
+	Role      MemberRole
+	CreatedAt time.Time
+	UpdatedAt sql.NullTime
+}
+
+func (q *Queries) RestoreVampireInvitation(ctx context.Context, arg RestoreVampireInvitationParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, restoreVampireInvitation,
+		arg.ID,
+		arg.VampireID,
+		arg.InvitedBy,
+		arg.Email,
+		arg.Role,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const restoreVampireMember = `-- name: RestoreVampireMember :one
+INSERT INTO vampire_members (id, vampire_id, user_id, ROLE, created_at, updated_at)
+    VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (id)
+    DO NOTHING
+RETURNING
+    id
+`
+
+type RestoreVampireMemberParams struct {
+	ID        uuid.UUID
+	VampireID uuid.UUID
+	UserID    uuid.UUID
+	Role      MemberRole
+	CreatedAt time.Time
+	UpdatedAt sql.NullTime
+}
+
+func (q *Queries) RestoreVampireMember(ctx context.Context, arg RestoreVampireMemberParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, restoreVampireMember,
+		arg.ID,
+		arg.VampireID,
+		arg.UserID,
+		arg.Role,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const vampireExists = `-- name: VampireExists :one
+SELECT
+    EXISTS (
+        SELECT
+            1
+        FROM
+            vampires
+        WHERE
+            id = $1)
+`
+
+func (q *Queries) VampireExists(ctx context.Context, id uuid.UUID) (bool, error) {
+	row := q.db.QueryRow(ctx, vampireExists, id)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
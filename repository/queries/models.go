@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgtype"
 )
 
 type CharacterType string
@@ -29,6 +30,40 @@ func (e *CharacterType) Scan(src interface{}) error {
 	return nil
 }
 
+type MemberRole string
+
+const (
+	MemberRoleOwner  MemberRole = "owner"
+	MemberRoleEditor MemberRole = "editor"
+	MemberRoleViewer MemberRole = "viewer"
+)
+
+func (e *MemberRole) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = MemberRole(s)
+	case string:
+		*e = MemberRole(s)
+	default:
+		return fmt.Errorf("unsupported scan type for MemberRole: %T", src)
+	}
+	return nil
+}
+
+type AuditEvent struct {
+	ID             uuid.UUID
+	ActorID        uuid.NullUUID
+	ActorEmail     string
+	Ip             string
+	UserAgent      string
+	Action         string
+	TargetType     string
+	TargetID       uuid.NullUUID
+	Changes        pgtype.JSONB
+	CreatedAt      time.Time
+	ImpersonatorID uuid.NullUUID
+}
+
 type Character struct {
 	ID        uuid.UUID
 	VampireID uuid.UUID
@@ -61,6 +96,15 @@ type Memory struct {
 	UpdatedAt sql.NullTime
 }
 
+type PasswordReset struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Token     string
+	ExpiresAt time.Time
+	UsedAt    sql.NullTime
+	CreatedAt time.Time
+}
+
 type Resource struct {
 	ID          uuid.UUID
 	VampireID   uuid.UUID
@@ -70,6 +114,16 @@ type Resource struct {
 	UpdatedAt   sql.NullTime
 }
 
+type ShareLink struct {
+	ID        uuid.UUID
+	VampireID uuid.UUID
+	Token     string
+	ExpiresAt sql.NullTime
+	RevokedAt sql.NullTime
+	CreatedAt time.Time
+	UpdatedAt sql.NullTime
+}
+
 type Skill struct {
 	ID          uuid.UUID
 	VampireID   uuid.UUID
@@ -78,18 +132,57 @@ type Skill struct {
 	UpdatedAt   sql.NullTime
 }
 
+type StreamMessage struct {
+	ID        uuid.UUID
+	Data      string
+	CreatedAt time.Time
+}
+
 type User struct {
 	ID           uuid.UUID
 	Email        string
-	PasswordHash string
+	PasswordHash sql.NullString
 	CreatedAt    time.Time
 	UpdatedAt    sql.NullTime
+	IsAdmin      bool
+	DisabledAt   sql.NullTime
+}
+
+type UserIdentity struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Issuer    string
+	Subject   string
+	Email     string
+	CreatedAt time.Time
+	UpdatedAt sql.NullTime
 }
 
 type Vampire struct {
+	ID         uuid.UUID
+	Name       string
+	CreatedAt  time.Time
+	UpdatedAt  sql.NullTime
+	UserID     uuid.NullUUID
+	DeletedAt  sql.NullTime
+	ArchivedAt sql.NullTime
+}
+
+type VampireInvitation struct {
 	ID        uuid.UUID
-	Name      string
+	VampireID uuid.UUID
+	InvitedBy uuid.UUID
+	Email     string
+	Role      MemberRole
+	CreatedAt time.Time
+	UpdatedAt sql.NullTime
+}
+
+type VampireMember struct {
+	ID        uuid.UUID
+	VampireID uuid.UUID
+	UserID    uuid.UUID
+	Role      MemberRole
 	CreatedAt time.Time
 	UpdatedAt sql.NullTime
-	UserID    uuid.NullUUID
 }
@@ -0,0 +1,21 @@
+-- name: Notify :exec
+SELECT
+    pg_notify(@channel::text, @payload::text);
+
+-- name: CreateStreamMessage :one
+INSERT INTO stream_messages (data)
+    VALUES (@data)
+RETURNING
+    id;
+
+-- name: GetStreamMessage :one
+SELECT
+    data
+FROM
+    stream_messages
+WHERE
+    id = @id;
+
+-- name: DeleteStreamMessagesBefore :exec
+DELETE FROM stream_messages
+WHERE created_at < NOW() - make_interval(secs => @older_than_seconds::int);
@@ -0,0 +1,65 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: notifications.sql
+
+package queries
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const createStreamMessage = `-- name: CreateStreamMessage :one
+INSERT INTO stream_messages (data)
+    VALUES ($1)
+RETURNING
+    id
+`
+
+func (q *Queries) CreateStreamMessage(ctx context.Context, data string) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, createStreamMessage, data)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const deleteStreamMessagesBefore = `-- name: DeleteStreamMessagesBefore :exec
+DELETE FROM stream_messages
+WHERE created_at < NOW() - make_interval(secs => $1::int)
+`
+
+func (q *Queries) DeleteStreamMessagesBefore(ctx context.Context, olderThanSeconds int32) error {
+	_, err := q.db.Exec(ctx, deleteStreamMessagesBefore, olderThanSeconds)
+	return err
+}
+
+const getStreamMessage = `-- name: GetStreamMessage :one
+SELECT
+    data
+FROM
+    stream_messages
+WHERE
+    id = $1
+`
+
+func (q *Queries) GetStreamMessage(ctx context.Context, id uuid.UUID) (string, error) {
+	row := q.db.QueryRow(ctx, getStreamMessage, id)
+	var data string
+	err := row.Scan(&data)
+	return data, err
+}
+
+const notify = `-- name: Notify :exec
+SELECT
+    pg_notify($1::text, $2::text)
+`
+
+type NotifyParams struct {
+	Channel string
+	Payload string
+}
+
+func (q *Queries) Notify(ctx context.Context, arg NotifyParams) error {
+	_, err := q.db.Exec(ctx, notify, arg.Channel, arg.Payload)
+	return err
+}
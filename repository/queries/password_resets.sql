@@ -0,0 +1,37 @@
+-- name: CreatePasswordReset :one
+INSERT INTO password_resets (user_id, expires_at)
+    VALUES (@user_id, NOW() + make_interval(secs => @expires_in_seconds::int))
+RETURNING
+    *;
+
+-- name: GetActivePasswordReset :one
+SELECT
+    *
+FROM
+    password_resets
+WHERE
+    token = @token
+    AND used_at IS NULL
+    AND expires_at > NOW()
+LIMIT 1;
+
+-- name: UsePasswordReset :one
+UPDATE
+    password_resets
+SET
+    used_at = NOW()
+WHERE
+    token = @token
+    AND used_at IS NULL
+    AND expires_at > NOW()
+RETURNING
+    *;
+
+-- name: ExpirePasswordResetsForUser :exec
+UPDATE
+    password_resets
+SET
+    used_at = NOW()
+WHERE
+    user_id = @user_id
+    AND used_at IS NULL;
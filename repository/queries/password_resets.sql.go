@@ -0,0 +1,104 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: password_resets.sql
+
+package queries
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const createPasswordReset = `-- name: CreatePasswordReset :one
+INSERT INTO password_resets (user_id, expires_at)
+    VALUES ($1, NOW() + make_interval(secs => $2::int))
+RETURNING
+    id, user_id, token, expires_at, used_at, created_at
+`
+
+type CreatePasswordResetParams struct {
+	UserID           uuid.UUID
+	ExpiresInSeconds int32
+}
+
+func (q *Queries) CreatePasswordReset(ctx context.Context, arg CreatePasswordResetParams) (PasswordReset, error) {
+	row := q.db.QueryRow(ctx, createPasswordReset, arg.UserID, arg.ExpiresInSeconds)
+	var i PasswordReset
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Token,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const expirePasswordResetsForUser = `-- name: ExpirePasswordResetsForUser :exec
+UPDATE
+    password_resets
+SET
+    used_at = NOW()
+WHERE
+    user_id = $1
+    AND used_at IS NULL
+`
+
+func (q *Queries) ExpirePasswordResetsForUser(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, expirePasswordResetsForUser, userID)
+	return err
+}
+
+const getActivePasswordReset = `-- name: GetActivePasswordReset :one
+SELECT
+    id, user_id, token, expires_at, used_at, created_at
+FROM
+    password_resets
+WHERE
+    token = $1
+    AND used_at IS NULL
+    AND expires_at > NOW()
+LIMIT 1
+`
+
+func (q *Queries) GetActivePasswordReset(ctx context.Context, token string) (PasswordReset, error) {
+	row := q.db.QueryRow(ctx, getActivePasswordReset, token)
+	var i PasswordReset
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Token,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const usePasswordReset = `-- name: UsePasswordReset :one
+UPDATE
+    password_resets
+SET
+    used_at = NOW()
+WHERE
+    token = $1
+    AND used_at IS NULL
+    AND expires_at > NOW()
+RETURNING
+    id, user_id, token, expires_at, used_at, created_at
+`
+
+func (q *Queries) UsePasswordReset(ctx context.Context, token string) (PasswordReset, error) {
+	row := q.db.QueryRow(ctx, usePasswordReset, token)
+	var i PasswordReset
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Token,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,72 @@
+-- name: SearchVampire :many
+WITH matches AS (
+    SELECT
+        'experience'::text AS kind,
+        experiences.id,
+        experiences.description AS body,
+        experiences.created_at
+    FROM
+        experiences
+        INNER JOIN memories ON memories.id = experiences.memory_id
+    WHERE
+        memories.vampire_id = @vampire_id
+        AND to_tsvector('english', experiences.description) @@ websearch_to_tsquery('english', @search::text)
+    UNION ALL
+    SELECT
+        'skill'::text AS kind,
+        skills.id,
+        skills.description AS body,
+        skills.created_at
+    FROM
+        skills
+    WHERE
+        skills.vampire_id = @vampire_id
+        AND to_tsvector('english', skills.description) @@ websearch_to_tsquery('english', @search::text)
+    UNION ALL
+    SELECT
+        'resource'::text AS kind,
+        resources.id,
+        resources.description AS body,
+        resources.created_at
+    FROM
+        resources
+    WHERE
+        resources.vampire_id = @vampire_id
+        AND to_tsvector('english', resources.description) @@ websearch_to_tsquery('english', @search::text)
+    UNION ALL
+    SELECT
+        'character'::text AS kind,
+        characters.id,
+        characters.name AS body,
+        characters.created_at
+    FROM
+        characters
+    WHERE
+        characters.vampire_id = @vampire_id
+        AND to_tsvector('english', characters.name) @@ websearch_to_tsquery('english', @search::text)
+    UNION ALL
+    SELECT
+        'mark'::text AS kind,
+        marks.id,
+        marks.description AS body,
+        marks.created_at
+    FROM
+        marks
+    WHERE
+        marks.vampire_id = @vampire_id
+        AND to_tsvector('english', marks.description) @@ websearch_to_tsquery('english', @search::text))
+SELECT
+    matches.kind,
+    matches.id,
+    experiences.memory_id,
+    ts_headline('english', matches.body, websearch_to_tsquery('english', @search::text), @headline_options::text)::text AS snippet,
+    ts_rank(to_tsvector('english', matches.body), websearch_to_tsquery('english', @search::text))::real AS rank
+FROM
+    matches
+    LEFT JOIN experiences ON matches.kind = 'experience'
+        AND experiences.id = matches.id
+ORDER BY
+    rank DESC,
+    matches.created_at DESC,
+    matches.id
+LIMIT @result_limit;
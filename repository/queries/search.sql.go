@@ -0,0 +1,130 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: search.sql
+
+package queries
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const searchVampire = `-- name: SearchVampire :many
+WITH matches AS (
+    SELECT
+        'experience'::text AS kind,
+        experiences.id,
+        experiences.description AS body,
+        experiences.created_at
+    FROM
+        experiences
+        INNER JOIN memories ON memories.id = experiences.memory_id
+    WHERE
+        memories.vampire_id = $4
+        AND to_tsvector('english', experiences.description) @@ websearch_to_tsquery('english', $1::text)
+    UNION ALL
+    SELECT
+        'skill'::text AS kind,
+        skills.id,
+        skills.description AS body,
+        skills.created_at
+    FROM
+        skills
+    WHERE
+        skills.vampire_id = $4
+        AND to_tsvector('english', skills.description) @@ websearch_to_tsquery('english', $1::text)
+    UNION ALL
+    SELECT
+        'resource'::text AS kind,
+        resources.id,
+        resources.description AS body,
+        resources.created_at
+    FROM
+        resources
+    WHERE
+        resources.vampire_id = $4
+        AND to_tsvector('english', resources.description) @@ websearch_to_tsquery('english', $1::text)
+    UNION ALL
+    SELECT
+        'character'::text AS kind,
+        characters.id,
+        characters.name AS body,
+        characters.created_at
+    FROM
+        characters
+    WHERE
+        characters.vampire_id = $4
+        AND to_tsvector('english', characters.name) @@ websearch_to_tsquery('english', $1::text)
+    UNION ALL
+    SELECT
+        'mark'::text AS kind,
+        marks.id,
+        marks.description AS body,
+        marks.created_at
+    FROM
+        marks
+    WHERE
+        marks.vampire_id = $4
+        AND to_tsvector('english', marks.description) @@ websearch_to_tsquery('english', $1::text))
+SELECT
+    matches.kind,
+    matches.id,
+    experiences.memory_id,
+    ts_headline('english', matches.body, websearch_to_tsquery('english', $1::text), $2::text)::text AS snippet,
+    ts_rank(to_tsvector('english', matches.body), websearch_to_tsquery('english', $1::text))::real AS rank
+FROM
+    matches
+    LEFT JOIN experiences ON matches.kind = 'experience'
+        AND experiences.id = matches.id
+ORDER BY
+    rank DESC,
+    matches.created_at DESC,
+    matches.id
+LIMIT $3
+`
+
+type SearchVampireParams struct {
+	Search          string
+	HeadlineOptions string
+	ResultLimit     int32
+	VampireID       uuid.UUID
+}
+
+type SearchVampireRow struct {
+	Kind     string
+	ID       uuid.UUID
+	MemoryID uuid.NullUUID
+	Snippet  string
+	Rank     float32
+}
+
+func (q *Queries) SearchVampire(ctx context.Context, arg SearchVampireParams) ([]SearchVampireRow, error) {
+	rows, err := q.db.Query(ctx, searchVampire,
+		arg.Search,
+		arg.HeadlineOptions,
+		arg.ResultLimit,
+		arg.VampireID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchVampireRow
+	for rows.Next() {
+		var i SearchVampireRow
+		if err := rows.Scan(
+			&i.Kind,
+			&i.ID,
+			&i.MemoryID,
+			&i.Snippet,
+			&i.Rank,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,44 @@
+-- name: CreateShareLink :one
+INSERT INTO share_links (vampire_id, expires_at)
+    VALUES (@vampire_id, CASE WHEN @expires_in_seconds::int > 0 THEN
+            NOW() + make_interval(secs => @expires_in_seconds::int)
+        END)
+RETURNING
+    *;
+
+-- name: GetShareLinksForVampire :many
+SELECT
+    share_links.*
+FROM
+    share_links
+WHERE
+    share_links.vampire_id = @vampire_id
+ORDER BY
+    share_links.created_at;
+
+-- name: GetActiveShareLink :one
+SELECT
+    share_links.*
+FROM
+    share_links
+    INNER JOIN vampires ON vampires.id = share_links.vampire_id
+WHERE
+    share_links.token = @token
+    AND share_links.revoked_at IS NULL
+    AND vampires.deleted_at IS NULL
+    AND (share_links.expires_at IS NULL
+        OR share_links.expires_at > NOW())
+LIMIT 1;
+
+-- name: RevokeShareLink :one
+UPDATE
+    share_links
+SET
+    revoked_at = NOW(),
+    updated_at = NOW()
+WHERE
+    share_links.id = @id
+    AND share_links.vampire_id = @vampire_id
+    AND share_links.revoked_at IS NULL
+RETURNING
+    *;
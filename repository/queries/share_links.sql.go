@@ -0,0 +1,142 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: share_links.sql
+
+package queries
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const createShareLink = `-- name: CreateShareLink :one
+INSERT INTO share_links (vampire_id, expires_at)
+    VALUES ($1, CASE WHEN $2::int > 0 THEN
+            NOW() + make_interval(secs => $2::int)
+        END)
+RETURNING
+    id, vampire_id, token, expires_at, revoked_at, created_at, updated_at
+`
+
+type CreateShareLinkParams struct {
+	VampireID        uuid.UUID
+	ExpiresInSeconds int32
+}
+
+func (q *Queries) CreateShareLink(ctx context.Context, arg CreateShareLinkParams) (ShareLink, error) {
+	row := q.db.QueryRow(ctx, createShareLink, arg.VampireID, arg.ExpiresInSeconds)
+	var i ShareLink
+	err := row.Scan(
+		&i.ID,
+		&i.VampireID,
+		&i.Token,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getActiveShareLink = `-- name: GetActiveShareLink :one
+SELECT
+    share_links.id, share_links.vampire_id, share_links.token, share_links.expires_at, share_links.revoked_at, share_links.created_at, share_links.updated_at
+FROM
+    share_links
+    INNER JOIN vampires ON vampires.id = share_links.vampire_id
+WHERE
+    share_links.token = $1
+    AND share_links.revoked_at IS NULL
+    AND vampires.deleted_at IS NULL
+    AND (share_links.expires_at IS NULL
+        OR share_links.expires_at > NOW())
+LIMIT 1
+`
+
+func (q *Queries) GetActiveShareLink(ctx context.Context, token string) (ShareLink, error) {
+	row := q.db.QueryRow(ctx, getActiveShareLink, token)
+	var i ShareLink
+	err := row.Scan(
+		&i.ID,
+		&i.VampireID,
+		&i.Token,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getShareLinksForVampire = `-- name: GetShareLinksForVampire :many
+SELECT
+    share_links.id, share_links.vampire_id, share_links.token, share_links.expires_at, share_links.revoked_at, share_links.created_at, share_links.updated_at
+FROM
+    share_links
+WHERE
+    share_links.vampire_id = $1
+ORDER BY
+    share_links.created_at
+`
+
+func (q *Queries) GetShareLinksForVampire(ctx context.Context, vampireID uuid.UUID) ([]ShareLink, error) {
+	rows, err := q.db.Query(ctx, getShareLinksForVampire, vampireID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ShareLink
+	for rows.Next() {
+		var i ShareLink
+		if err := rows.Scan(
+			&i.ID,
+			&i.VampireID,
+			&i.Token,
+			&i.ExpiresAt,
+			&i.RevokedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const revokeShareLink = `-- name: RevokeShareLink :one
+UPDATE
+    share_links
+SET
+    revoked_at = NOW(),
+    updated_at = NOW()
+WHERE
+    share_links.id = $1
+    AND share_links.vampire_id = $2
+    AND share_links.revoked_at IS NULL
+RETURNING
+    id, vampire_id, token, expires_at, revoked_at, created_at, updated_at
+`
+
+type RevokeShareLinkParams struct {
+	ID        uuid.UUID
+	VampireID uuid.UUID
+}
+
+func (q *Queries) RevokeShareLink(ctx context.Context, arg RevokeShareLinkParams) (ShareLink, error) {
+	row := q.db.QueryRow(ctx, revokeShareLink, arg.ID, arg.VampireID)
+	var i ShareLink
+	err := row.Scan(
+		&i.ID,
+		&i.VampireID,
+		&i.Token,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
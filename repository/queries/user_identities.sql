@@ -0,0 +1,33 @@
+-- name: CreateUserIdentity :one
+INSERT INTO user_identities (user_id, issuer, subject, email)
+    VALUES (@user_id, @issuer, @subject, @email)
+RETURNING
+    *;
+
+-- name: GetUserIdentitiesForUser :many
+SELECT
+    *
+FROM
+    user_identities
+WHERE
+    user_id = @user_id
+ORDER BY
+    created_at;
+
+-- name: GetUserByIdentity :one
+SELECT
+    users.*
+FROM
+    users
+    INNER JOIN user_identities ON user_identities.user_id = users.id
+WHERE
+    user_identities.issuer = @issuer
+    AND user_identities.subject = @subject
+LIMIT 1;
+
+-- name: DeleteUserIdentity :one
+DELETE FROM user_identities
+WHERE id = @id
+    AND user_id = @user_id
+RETURNING
+    *;
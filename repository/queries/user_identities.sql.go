@@ -0,0 +1,143 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: user_identities.sql
+
+package queries
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const createUserIdentity = `-- name: CreateUserIdentity :one
+INSERT INTO user_identities (user_id, issuer, subject, email)
+    VALUES ($1, $2, $3, $4)
+RETURNING
+    id, user_id, issuer, subject, email, created_at, updated_at
+`
+
+type CreateUserIdentityParams struct {
+	UserID  uuid.UUID
+	Issuer  string
+	Subject string
+	Email   string
+}
+
+func (q *Queries) CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) (UserIdentity, error) {
+	row := q.db.QueryRow(ctx, createUserIdentity,
+		arg.UserID,
+		arg.Issuer,
+		arg.Subject,
+		arg.Email,
+	)
+	var i UserIdentity
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Issuer,
+		&i.Subject,
+		&i.Email,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteUserIdentity = `-- name: DeleteUserIdentity :one
+DELETE FROM user_identities
+WHERE id = $1
+    AND user_id = $2
+RETURNING
+    id, user_id, issuer, subject, email, created_at, updated_at
+`
+
+type DeleteUserIdentityParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) DeleteUserIdentity(ctx context.Context, arg DeleteUserIdentityParams) (UserIdentity, error) {
+	row := q.db.QueryRow(ctx, deleteUserIdentity, arg.ID, arg.UserID)
+	var i UserIdentity
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Issuer,
+		&i.Subject,
+		&i.Email,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getUserByIdentity = `-- name: GetUserByIdentity :one
+SELECT
+    users.id, users.email, users.password_hash, users.created_at, users.updated_at, users.is_admin, users.disabled_at
+FROM
+    users
+    INNER JOIN user_identities ON user_identities.user_id = users.id
+WHERE
+    user_identities.issuer = $1
+    AND user_identities.subject = $2
+LIMIT 1
+`
+
+type GetUserByIdentityParams struct {
+	Issuer  string
+	Subject string
+}
+
+func (q *Queries) GetUserByIdentity(ctx context.Context, arg GetUserByIdentityParams) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByIdentity, arg.Issuer, arg.Subject)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.PasswordHash,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.IsAdmin,
+		&i.DisabledAt,
+	)
+	return i, err
+}
+
+const getUserIdentitiesForUser = `-- name: GetUserIdentitiesForUser :many
+SELECT
+    id, user_id, issuer, subject, email, created_at, updated_at
+FROM
+    user_identities
+WHERE
+    user_id = $1
+ORDER BY
+    created_at
+`
+
+func (q *Queries) GetUserIdentitiesForUser(ctx context.Context, userID uuid.UUID) ([]UserIdentity, error) {
+	rows, err := q.db.Query(ctx, getUserIdentitiesForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserIdentity
+	for rows.Next() {
+		var i UserIdentity
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Issuer,
+			&i.Subject,
+			&i.Email,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
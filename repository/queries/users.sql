@@ -23,3 +23,27 @@ WHERE
     AND password_hash = crypt(@password::text, password_hash)
 LIMIT 1;
 
+
+-- name: CreateUserWithoutPassword :one
+INSERT INTO users (email)
+    VALUES (lower(@email))
+RETURNING
+    id, email;
+
+-- name: GetUserByEmail :one
+SELECT
+    *
+FROM
+    users
+WHERE
+    email = lower(@email)
+LIMIT 1;
+
+-- name: SetUserPassword :exec
+UPDATE
+    users
+SET
+    password_hash = crypt(@password::text, gen_salt('bf', 8)),
+    updated_at = NOW()
+WHERE
+    id = @id;
@@ -11,7 +11,7 @@ import (
 
 const authenticateUser = `-- name: AuthenticateUser :one
 SELECT
-    id, email, password_hash, created_at, updated_at
+    id, email, password_hash, created_at, updated_at, is_admin, disabled_at
 FROM
     users
 WHERE
@@ -34,6 +34,8 @@ func (q *Queries) AuthenticateUser(ctx context.Context, arg AuthenticateUserPara
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.IsAdmin,
+		&i.DisabledAt,
 	)
 	return i, err
 }
@@ -62,9 +64,28 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (CreateU
 	return i, err
 }
 
+const createUserWithoutPassword = `-- name: CreateUserWithoutPassword :one
+INSERT INTO users (email)
+    VALUES (lower($1))
+RETURNING
+    id, email
+`
+
+type CreateUserWithoutPasswordRow struct {
+	ID    uuid.UUID
+	Email string
+}
+
+func (q *Queries) CreateUserWithoutPassword(ctx context.Context, email string) (CreateUserWithoutPasswordRow, error) {
+	row := q.db.QueryRow(ctx, createUserWithoutPassword, email)
+	var i CreateUserWithoutPasswordRow
+	err := row.Scan(&i.ID, &i.Email)
+	return i, err
+}
+
 const getUser = `-- name: GetUser :one
 SELECT
-    id, email, password_hash, created_at, updated_at
+    id, email, password_hash, created_at, updated_at, is_admin, disabled_at
 FROM
     users
 WHERE
@@ -81,6 +102,53 @@ func (q *Queries) GetUser(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.IsAdmin,
+		&i.DisabledAt,
 	)
 	return i, err
 }
+
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT
+    id, email, password_hash, created_at, updated_at, is_admin, disabled_at
+FROM
+    users
+WHERE
+    email = lower($1)
+LIMIT 1
+`
+
+func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByEmail, email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.PasswordHash,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.IsAdmin,
+		&i.DisabledAt,
+	)
+	return i, err
+}
+
+const setUserPassword = `-- name: SetUserPassword :exec
+UPDATE
+    users
+SET
+    password_hash = crypt($1::text, gen_salt('bf', 8)),
+    updated_at = NOW()
+WHERE
+    id = $2
+`
+
+type SetUserPasswordParams struct {
+	Password string
+	ID       uuid.UUID
+}
+
+func (q *Queries) SetUserPassword(ctx context.Context, arg SetUserPasswordParams) error {
+	_, err := q.db.Exec(ctx, setUserPassword, arg.Password, arg.ID)
+	return err
+}
@@ -0,0 +1,80 @@
+-- name: CreateVampireMember :one
+INSERT INTO vampire_members (vampire_id, user_id, ROLE)
+    VALUES (@vampire_id, @user_id, @role)
+RETURNING
+    *;
+
+-- name: GetVampireMemberRole :one
+SELECT
+    vampire_members.role
+FROM
+    vampire_members
+    INNER JOIN vampires ON vampires.id = vampire_members.vampire_id
+WHERE
+    vampire_members.vampire_id = @vampire_id
+    AND vampire_members.user_id = @user_id
+    AND vampires.deleted_at IS NULL
+LIMIT 1;
+
+-- name: GetVampireMembers :many
+SELECT
+    vampire_members.*,
+    users.email
+FROM
+    vampire_members
+    INNER JOIN users ON users.id = vampire_members.user_id
+WHERE
+    vampire_members.vampire_id = @vampire_id
+ORDER BY
+    vampire_members.created_at;
+
+-- name: DeleteVampireMember :one
+DELETE FROM vampire_members
+WHERE id = @id
+    AND vampire_id = @vampire_id
+    AND ROLE <> 'owner'
+RETURNING
+    *;
+
+-- name: CreateVampireInvitation :one
+INSERT INTO vampire_invitations (vampire_id, invited_by, email, ROLE)
+    VALUES (@vampire_id, @invited_by, LOWER(@email), @role)
+RETURNING
+    *;
+
+-- name: GetVampireInvitations :many
+SELECT
+    *
+FROM
+    vampire_invitations
+WHERE
+    vampire_id = @vampire_id
+ORDER BY
+    created_at;
+
+-- name: GetInvitationsForEmail :many
+SELECT
+    vampire_invitations.*,
+    vampires.name AS vampire_name
+FROM
+    vampire_invitations
+    INNER JOIN vampires ON vampires.id = vampire_invitations.vampire_id
+WHERE
+    vampire_invitations.email = LOWER(@email)
+    AND vampires.deleted_at IS NULL
+ORDER BY
+    vampire_invitations.created_at;
+
+-- name: DeleteInvitationForEmail :one
+DELETE FROM vampire_invitations
+WHERE id = @id
+    AND email = LOWER(@email)
+RETURNING
+    *;
+
+-- name: DeleteVampireInvitation :one
+DELETE FROM vampire_invitations
+WHERE id = @id
+    AND vampire_id = @vampire_id
+RETURNING
+    *;
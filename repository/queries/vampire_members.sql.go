@@ -0,0 +1,326 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: vampire_members.sql
+
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createVampireInvitation = `-- name: CreateVampireInvitation :one
+INSERT INTO vampire_invitations (vampire_id, invited_by, email, ROLE)
+    VALUES ($1, $2, LOWER($3), $4)
+RETURNING
+    id, vampire_id, invited_by, email, role, created_at, updated_at
+`
+
+type CreateVampireInvitationParams struct {
+	VampireID uuid.UUID
+	InvitedBy uuid.UUID
+	Email     string
+	Role      MemberRole
+}
+
+func (q *Queries) CreateVampireInvitation(ctx context.Context, arg CreateVampireInvitationParams) (VampireInvitation, error) {
+	row := q.db.QueryRow(ctx, createVampireInvitation,
+		arg.VampireID,
+		arg.InvitedBy,
+		arg.Email,
+		arg.Role,
+	)
+	var i VampireInvitation
+	err := row.Scan(
+		&i.ID,
+		&i.VampireID,
+		&i.InvitedBy,
+		&i.Email,
+		&i.Role,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createVampireMember = `-- name: CreateVampireMember :one
+INSERT INTO vampire_members (vampire_id, user_id, ROLE)
+    VALUES ($1, $2, $3)
+RETURNING
+    id, vampire_id, user_id, role, created_at, updated_at
+`
+
+type CreateVampireMemberParams struct {
+	VampireID uuid.UUID
+	UserID    uuid.UUID
+	Role      MemberRole
+}
+
+func (q *Queries) CreateVampireMember(ctx context.Context, arg CreateVampireMemberParams) (VampireMember, error) {
+	row := q.db.QueryRow(ctx, createVampireMember, arg.VampireID, arg.UserID, arg.Role)
+	var i VampireMember
+	err := row.Scan(
+		&i.ID,
+		&i.VampireID,
+		&i.UserID,
+		&i.Role,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteInvitationForEmail = `-- name: DeleteInvitationForEmail :one
+DELETE FROM vampire_invitations
+WHERE id = $1
+    AND email = LOWER($2)
+RETURNING
+    id, vampire_id, invited_by, email, role, created_at, updated_at
+`
+
+type DeleteInvitationForEmailParams struct {
+	ID    uuid.UUID
+	Email string
+}
+
+func (q *Queries) DeleteInvitationForEmail(ctx context.Context, arg DeleteInvitationForEmailParams) (VampireInvitation, error) {
+	row := q.db.QueryRow(ctx, deleteInvitationForEmail, arg.ID, arg.Email)
+	var i VampireInvitation
+	err := row.Scan(
+		&i.ID,
+		&i.VampireID,
+		&i.InvitedBy,
+		&i.Email,
+		&i.Role,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteVampireInvitation = `-- name: DeleteVampireInvitation :one
+DELETE FROM vampire_invitations
+WHERE id = $1
+    AND vampire_id = $2
+RETURNING
+    id, vampire_id, invited_by, email, role, created_at, updated_at
+`
+
+type DeleteVampireInvitationParams struct {
+	ID        uuid.UUID
+	VampireID uuid.UUID
+}
+
+func (q *Queries) DeleteVampireInvitation(ctx context.Context, arg DeleteVampireInvitationParams) (VampireInvitation, error) {
+	row := q.db.QueryRow(ctx, deleteVampireInvitation, arg.ID, arg.VampireID)
+	var i VampireInvitation
+	err := row.Scan(
+		&i.ID,
+		&i.VampireID,
+		&i.InvitedBy,
+		&i.Email,
+		&i.Role,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteVampireMember = `-- name: DeleteVampireMember :one
+DELETE FROM vampire_members
+WHERE id = $1
+    AND vampire_id = $2
+    AND ROLE <> 'owner'
+RETURNING
+    id, vampire_id, user_id, role, created_at, updated_at
+`
+
+type DeleteVampireMemberParams struct {
+	ID        uuid.UUID
+	VampireID uuid.UUID
+}
+
+func (q *Queries) DeleteVampireMember(ctx context.Context, arg DeleteVampireMemberParams) (VampireMember, error) {
+	row := q.db.QueryRow(ctx, deleteVampireMember, arg.ID, arg.VampireID)
+	var i VampireMember
+	err := row.Scan(
+		&i.ID,
+		&i.VampireID,
+		&i.UserID,
+		&i.Role,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getInvitationsForEmail = `-- name: GetInvitationsForEmail :many
+SELECT
+    vampire_invitations.id, vampire_invitations.vampire_id, vampire_invitations.invited_by, vampire_invitations.email, vampire_invitations.role, vampire_invitations.created_at, vampire_invitations.updated_at,
+    vampires.name AS vampire_name
+FROM
+    vampire_invitations
+    INNER JOIN vampires ON vampires.id = vampire_invitations.vampire_id
+WHERE
+    vampire_invitations.email = LOWER($1)
+    AND vampires.deleted_at IS NULL
+ORDER BY
+    vampire_invitations.created_at
+`
+
+type GetInvitationsForEmailRow struct {
+	ID          uuid.UUID
+	VampireID   uuid.UUID
+	InvitedBy   uuid.UUID
+	Email       string
+	Role        MemberRole
+	CreatedAt   time.Time
+	UpdatedAt   sql.NullTime
+	VampireName string
+}
+
+func (q *Queries) GetInvitationsForEmail(ctx context.Context, email string) ([]GetInvitationsForEmailRow, error) {
+	rows, err := q.db.Query(ctx, getInvitationsForEmail, email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetInvitationsForEmailRow
+	for rows.Next() {
+		var i GetInvitationsForEmailRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.VampireID,
+			&i.InvitedBy,
+			&i.Email,
+			&i.Role,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.VampireName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVampireInvitations = `-- name: GetVampireInvitations :many
+SELECT
+    id, vampire_id, invited_by, email, role, created_at, updated_at
+FROM
+    vampire_invitations
+WHERE
+    vampire_id = $1
+ORDER BY
+    created_at
+`
+
+func (q *Queries) GetVampireInvitations(ctx context.Context, vampireID uuid.UUID) ([]VampireInvitation, error) {
+	rows, err := q.db.Query(ctx, getVampireInvitations, vampireID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []VampireInvitation
+	for rows.Next() {
+		var i VampireInvitation
+		if err := rows.Scan(
+			&i.ID,
+			&i.VampireID,
+			&i.InvitedBy,
+			&i.Email,
+			&i.Role,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVampireMemberRole = `-- name: GetVampireMemberRole :one
+SELECT
+    vampire_members.role
+FROM
+    vampire_members
+    INNER JOIN vampires ON vampires.id = vampire_members.vampire_id
+WHERE
+    vampire_members.vampire_id = $1
+    AND vampire_members.user_id = $2
+    AND vampires.deleted_at IS NULL
+LIMIT 1
+`
+
+type GetVampireMemberRoleParams struct {
+	VampireID uuid.UUID
+	UserID    uuid.UUID
+}
+
+func (q *Queries) GetVampireMemberRole(ctx context.Context, arg GetVampireMemberRoleParams) (MemberRole, error) {
+	row := q.db.QueryRow(ctx, getVampireMemberRole, arg.VampireID, arg.UserID)
+	var role MemberRole
+	err := row.Scan(&role)
+	return role, err
+}
+
+const getVampireMembers = `-- name: GetVampireMembers :many
+SELECT
+    vampire_members.id, vampire_members.vampire_id, vampire_members.user_id, vampire_members.role, vampire_members.created_at, vampire_members.updated_at,
+    users.email
+FROM
+    vampire_members
+    INNER JOIN users ON users.id = vampire_members.user_id
+WHERE
+    vampire_members.vampire_id = $1
+ORDER BY
+    vampire_members.created_at
+`
+
+type GetVampireMembersRow struct {
+	ID        uuid.UUID
+	VampireID uuid.UUID
+	UserID    uuid.UUID
+	Role      MemberRole
+	CreatedAt time.Time
+	UpdatedAt sql.NullTime
+	Email     string
+}
+
+func (q *Queries) GetVampireMembers(ctx context.Context, vampireID uuid.UUID) ([]GetVampireMembersRow, error) {
+	rows, err := q.db.Query(ctx, getVampireMembers, vampireID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetVampireMembersRow
+	for rows.Next() {
+		var i GetVampireMembersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.VampireID,
+			&i.UserID,
+			&i.Role,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Email,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
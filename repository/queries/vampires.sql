@@ -13,9 +13,242 @@ INSERT INTO vampires (name, user_id)
 RETURNING
     *;
 
--- name: GetVampires :many
+
+-- name: GetVampiresForMemberByName :many
 SELECT
-    *
+    vampires.*
+FROM
+    vampires
+    INNER JOIN vampire_members ON vampire_members.vampire_id = vampires.id
+WHERE
+    vampire_members.user_id = @user_id
+    AND vampire_members.role::text = ANY (@roles::text[])
+    AND vampires.deleted_at IS NULL
+    AND vampires.archived_at IS NULL
+    AND (NOT @paginate::boolean
+        OR (vampires.name, vampires.id) > (@after_name::text, @after_id::uuid))
+    AND (@search::text = ''
+        OR to_tsvector('english', vampires.name) @@ websearch_to_tsquery('english', @search::text)
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                experiences
+                INNER JOIN memories ON experiences.memory_id = memories.id
+            WHERE
+                memories.vampire_id = vampires.id
+                AND to_tsvector('english', experiences.description) @@ websearch_to_tsquery('english', @search::text)))
+ORDER BY
+    vampires.name,
+    vampires.id
+LIMIT @page_size;
+
+-- name: GetVampiresForMemberByCreated :many
+SELECT
+    vampires.*
+FROM
+    vampires
+    INNER JOIN vampire_members ON vampire_members.vampire_id = vampires.id
+WHERE
+    vampire_members.user_id = @user_id
+    AND vampire_members.role::text = ANY (@roles::text[])
+    AND vampires.deleted_at IS NULL
+    AND vampires.archived_at IS NULL
+    AND (NOT @paginate::boolean
+        OR (vampires.created_at, vampires.id) < (@after_created_at::timestamp, @after_id::uuid))
+    AND (@search::text = ''
+        OR to_tsvector('english', vampires.name) @@ websearch_to_tsquery('english', @search::text)
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                experiences
+                INNER JOIN memories ON experiences.memory_id = memories.id
+            WHERE
+                memories.vampire_id = vampires.id
+                AND to_tsvector('english', experiences.description) @@ websearch_to_tsquery('english', @search::text)))
+ORDER BY
+    vampires.created_at DESC,
+    vampires.id DESC
+LIMIT @page_size;
+
+-- name: GetVampiresForMemberByPlayed :many
+WITH played AS (
+    SELECT
+        memories.vampire_id,
+        max(experiences.created_at) AS played_at
+    FROM
+        experiences
+        INNER JOIN memories ON experiences.memory_id = memories.id
+    GROUP BY
+        memories.vampire_id
+)
+SELECT
+    vampires.*,
+    COALESCE(played.played_at, vampires.created_at)::timestamp AS played_at
+FROM
+    vampires
+    INNER JOIN vampire_members ON vampire_members.vampire_id = vampires.id
+    LEFT JOIN played ON played.vampire_id = vampires.id
+WHERE
+    vampire_members.user_id = @user_id
+    AND vampire_members.role::text = ANY (@roles::text[])
+    AND vampires.deleted_at IS NULL
+    AND vampires.archived_at IS NULL
+    AND (NOT @paginate::boolean
+        OR (COALESCE(played.played_at, vampires.created_at), vampires.id) < (@after_played_at::timestamp, @after_id::uuid))
+    AND (@search::text = ''
+        OR to_tsvector('english', vampires.name) @@ websearch_to_tsquery('english', @search::text)
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                experiences
+                INNER JOIN memories ON experiences.memory_id = memories.id
+            WHERE
+                memories.vampire_id = vampires.id
+                AND to_tsvector('english', experiences.description) @@ websearch_to_tsquery('english', @search::text)))
+ORDER BY
+    COALESCE(played.played_at, vampires.created_at) DESC,
+    vampires.id DESC
+LIMIT @page_size;
+
+-- name: GetVampireDetails :one
+SELECT
+    vampires.id,
+    vampires.name,
+    vampires.archived_at,
+    COALESCE((
+        SELECT
+            json_agg(json_build_object('id', memories.id, 'vampireID', memories.vampire_id, 'experiences', COALESCE((
+                    SELECT
+                        json_agg(json_build_object('id', experiences.id, 'memoryID', experiences.memory_id, 'description', experiences.description))
+                    FROM experiences
+                    WHERE
+                        experiences.memory_id = memories.id), '[]')))
+        FROM memories
+        WHERE
+            memories.vampire_id = vampires.id), '[]')::json AS memories,
+    COALESCE((
+        SELECT
+            json_agg(json_build_object('id', skills.id, 'vampireID', skills.vampire_id, 'description', skills.description))
+        FROM skills
+        WHERE
+            skills.vampire_id = vampires.id), '[]')::json AS skills,
+    COALESCE((
+        SELECT
+            json_agg(json_build_object('id', resources.id, 'vampireID', resources.vampire_id, 'description', resources.description, 'stationary', resources.stationary))
+        FROM resources
+        WHERE
+            resources.vampire_id = vampires.id), '[]')::json AS resources,
+    COALESCE((
+        SELECT
+            json_agg(json_build_object('id', characters.id, 'vampireID', characters.vampire_id, 'name', characters.name, 'type', characters.type))
+        FROM characters
+        WHERE
+            characters.vampire_id = vampires.id), '[]')::json AS characters,
+    COALESCE((
+        SELECT
+            json_agg(json_build_object('id', marks.id, 'vampireID', marks.vampire_id, 'description', marks.description))
+        FROM marks
+        WHERE
+            marks.vampire_id = vampires.id), '[]')::json AS marks
 FROM
-    vampires;
+    vampires
+WHERE
+    vampires.id = $1
+    AND vampires.deleted_at IS NULL;
 
+-- name: TrashVampire :one
+UPDATE
+    vampires
+SET
+    deleted_at = NOW(),
+    updated_at = NOW()
+WHERE
+    id = @id
+    AND deleted_at IS NULL
+RETURNING
+    *;
+
+-- name: ArchiveVampire :one
+UPDATE
+    vampires
+SET
+    archived_at = NOW(),
+    updated_at = NOW()
+WHERE
+    id = @id
+    AND deleted_at IS NULL
+    AND archived_at IS NULL
+RETURNING
+    *;
+
+-- name: UnarchiveVampire :one
+UPDATE
+    vampires
+SET
+    archived_at = NULL,
+    updated_at = NOW()
+WHERE
+    id = @id
+    AND deleted_at IS NULL
+    AND archived_at IS NOT NULL
+RETURNING
+    *;
+
+-- name: GetArchivedVampires :many
+SELECT
+    vampires.*
+FROM
+    vampires
+    INNER JOIN vampire_members ON vampire_members.vampire_id = vampires.id
+WHERE
+    vampire_members.user_id = @user_id
+    AND vampire_members.role = 'owner'
+    AND vampires.deleted_at IS NULL
+    AND vampires.archived_at IS NOT NULL
+ORDER BY
+    vampires.archived_at DESC,
+    vampires.id DESC;
+
+-- name: GetTrashedVampires :many
+SELECT
+    vampires.*
+FROM
+    vampires
+    INNER JOIN vampire_members ON vampire_members.vampire_id = vampires.id
+WHERE
+    vampire_members.user_id = @user_id
+    AND vampire_members.role = 'owner'
+    AND vampires.deleted_at > NOW() - make_interval(secs => @retention_seconds::int)
+ORDER BY
+    vampires.deleted_at DESC,
+    vampires.id DESC;
+
+-- name: RestoreTrashedVampire :one
+UPDATE
+    vampires
+SET
+    deleted_at = NULL,
+    updated_at = NOW()
+WHERE
+    vampires.id = @id
+    AND vampires.deleted_at > NOW() - make_interval(secs => @retention_seconds::int)
+    AND EXISTS (
+        SELECT
+            1
+        FROM
+            vampire_members
+        WHERE
+            vampire_members.vampire_id = vampires.id
+            AND vampire_members.user_id = @user_id
+            AND vampire_members.role = 'owner')
+RETURNING
+    *;
+
+-- name: PurgeTrashedVampires :many
+DELETE FROM vampires
+WHERE deleted_at <= NOW() - make_interval(secs => @retention_seconds::int)
+RETURNING
+    *;
@@ -5,15 +5,47 @@ package queries
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgtype"
 )
 
+const archiveVampire = `-- name: ArchiveVampire :one
+UPDATE
+    vampires
+SET
+    archived_at = NOW(),
+    updated_at = NOW()
+WHERE
+    id = $1
+    AND deleted_at IS NULL
+    AND archived_at IS NULL
+RETURNING
+    id, name, created_at, updated_at, user_id, deleted_at, archived_at
+`
+
+func (q *Queries) ArchiveVampire(ctx context.Context, id uuid.UUID) (Vampire, error) {
+	row := q.db.QueryRow(ctx, archiveVampire, id)
+	var i Vampire
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.DeletedAt,
+		&i.ArchivedAt,
+	)
+	return i, err
+}
+
 const createVampire = `-- name: CreateVampire :one
 INSERT INTO vampires (name, user_id)
     VALUES ($1, $2::uuid)
 RETURNING
-    id, name, created_at, updated_at, user_id
+    id, name, created_at, updated_at, user_id, deleted_at, archived_at
 `
 
 type CreateVampireParams struct {
@@ -30,13 +62,107 @@ func (q *Queries) CreateVampire(ctx context.Context, arg CreateVampireParams) (V
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.UserID,
+		&i.DeletedAt,
+		&i.ArchivedAt,
 	)
 	return i, err
 }
 
+const getArchivedVampires = `-- name: GetArchivedVampires :many
+SELECT
+    vampires.id, vampires.name, vampires.created_at, vampires.updated_at, vampires.user_id, vampires.deleted_at, vampires.archived_at
+FROM
+    vampires
+    INNER JOIN vampire_members ON vampire_members.vampire_id = vampires.id
+WHERE
+    vampire_members.user_id = $1
+    AND vampire_members.role = 'owner'
+    AND vampires.deleted_at IS NULL
+    AND vampires.archived_at IS NOT NULL
+ORDER BY
+    vampires.archived_at DESC,
+    vampires.id DESC
+`
+
+func (q *Queries) GetArchivedVampires(ctx context.Context, userID uuid.UUID) ([]Vampire, error) {
+	rows, err := q.db.Query(ctx, getArchivedVampires, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Vampire
+	for rows.Next() {
+		var i Vampire
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTrashedVampires = `-- name: GetTrashedVampires :many
+SELECT
+    vampires.id, vampires.name, vampires.created_at, vampires.updated_at, vampires.user_id, vampires.deleted_at, vampires.archived_at
+FROM
+    vampires
+    INNER JOIN vampire_members ON vampire_members.vampire_id = vampires.id
+WHERE
+    vampire_members.user_id = $1
+    AND vampire_members.role = 'owner'
+    AND vampires.deleted_at > NOW() - make_interval(secs => $2::int)
+ORDER BY
+    vampires.deleted_at DESC,
+    vampires.id DESC
+`
+
+type GetTrashedVampiresParams struct {
+	UserID           uuid.UUID
+	RetentionSeconds int32
+}
+
+func (q *Queries) GetTrashedVampires(ctx context.Context, arg GetTrashedVampiresParams) ([]Vampire, error) {
+	rows, err := q.db.Query(ctx, getTrashedVampires, arg.UserID, arg.RetentionSeconds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Vampire
+	for rows.Next() {
+		var i Vampire
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getVampire = `-- name: GetVampire :one
 SELECT
-    id, name, created_at, updated_at, user_id
+    id, name, created_at, updated_at, user_id, deleted_at, archived_at
 FROM
     vampires
 WHERE
@@ -53,19 +179,347 @@ func (q *Queries) GetVampire(ctx context.Context, id uuid.UUID) (Vampire, error)
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.UserID,
+		&i.DeletedAt,
+		&i.ArchivedAt,
 	)
 	return i, err
 }
 
-const getVampires = `-- name: GetVampires :many
+const getVampireDetails = `-- name: GetVampireDetails :one
+SELECT
+    vampires.id,
+    vampires.name,
+    vampires.archived_at,
+    COALESCE((
+        SELECT
+            json_agg(json_build_object('id', memories.id, 'vampireID', memories.vampire_id, 'experiences', COALESCE((
+                    SELECT
+                        json_agg(json_build_object('id', experiences.id, 'memoryID', experiences.memory_id, 'description', experiences.description))
+                    FROM experiences
+                    WHERE
+                        experiences.memory_id = memories.id), '[]')))
+        FROM memories
+        WHERE
+            memories.vampire_id = vampires.id), '[]')::json AS memories,
+    COALESCE((
+        SELECT
+            json_agg(json_build_object('id', skills.id, 'vampireID', skills.vampire_id, 'description', skills.description))
+        FROM skills
+        WHERE
+            skills.vampire_id = vampires.id), '[]')::json AS skills,
+    COALESCE((
+        SELECT
+            json_agg(json_build_object('id', resources.id, 'vampireID', resources.vampire_id, 'description', resources.description, 'stationary', resources.stationary))
+        FROM resources
+        WHERE
+            resources.vampire_id = vampires.id), '[]')::json AS resources,
+    COALESCE((
+        SELECT
+            json_agg(json_build_object('id', characters.id, 'vampireID', characters.vampire_id, 'name', characters.name, 'type', characters.type))
+        FROM characters
+        WHERE
+            characters.vampire_id = vampires.id), '[]')::json AS characters,
+    COALESCE((
+        SELECT
+            json_agg(json_build_object('id', marks.id, 'vampireID', marks.vampire_id, 'description', marks.description))
+        FROM marks
+        WHERE
+            marks.vampire_id = vampires.id), '[]')::json AS marks
+FROM
+    vampires
+WHERE
+    vampires.id = $1
+    AND vampires.deleted_at IS NULL
+`
+
+type GetVampireDetailsRow struct {
+	ID         uuid.UUID
+	Name       string
+	ArchivedAt sql.NullTime
+	Memories   pgtype.JSON
+	Skills     pgtype.JSON
+	Resources  pgtype.JSON
+	Characters pgtype.JSON
+	Marks      pgtype.JSON
+}
+
+func (q *Queries) GetVampireDetails(ctx context.Context, id uuid.UUID) (GetVampireDetailsRow, error) {
+	row := q.db.QueryRow(ctx, getVampireDetails, id)
+	var i GetVampireDetailsRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.ArchivedAt,
+		&i.Memories,
+		&i.Skills,
+		&i.Resources,
+		&i.Characters,
+		&i.Marks,
+	)
+	return i, err
+}
+
+const getVampiresForMemberByCreated = `-- name: GetVampiresForMemberByCreated :many
+SELECT
+    vampires.id, vampires.name, vampires.created_at, vampires.updated_at, vampires.user_id, vampires.deleted_at, vampires.archived_at
+FROM
+    vampires
+    INNER JOIN vampire_members ON vampire_members.vampire_id = vampires.id
+WHERE
+    vampire_members.user_id = $1
+    AND vampire_members.role::text = ANY ($2::text[])
+    AND vampires.deleted_at IS NULL
+    AND vampires.archived_at IS NULL
+    AND (NOT $3::boolean
+        OR (vampires.created_at, vampires.id) < ($4::timestamp, $5::uuid))
+    AND ($6::text = ''
+        OR to_tsvector('english', vampires.name) @@ websearch_to_tsquery('english', $6::text)
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                experiences
+                INNER JOIN memories ON experiences.memory_id = memories.id
+            WHERE
+                memories.vampire_id = vampires.id
+                AND to_tsvector('english', experiences.description) @@ websearch_to_tsquery('english', $6::text)))
+ORDER BY
+    vampires.created_at DESC,
+    vampires.id DESC
+LIMIT $7
+`
+
+type GetVampiresForMemberByCreatedParams struct {
+	UserID         uuid.UUID
+	Roles          []string
+	Paginate       bool
+	AfterCreatedAt time.Time
+	AfterID        uuid.UUID
+	Search         string
+	PageSize       int32
+}
+
+func (q *Queries) GetVampiresForMemberByCreated(ctx context.Context, arg GetVampiresForMemberByCreatedParams) ([]Vampire, error) {
+	rows, err := q.db.Query(ctx, getVampiresForMemberByCreated,
+		arg.UserID,
+		arg.Roles,
+		arg.Paginate,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.Search,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Vampire
+	for rows.Next() {
+		var i Vampire
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVampiresForMemberByName = `-- name: GetVampiresForMemberByName :many
 SELECT
-    id, name, created_at, updated_at, user_id
+    vampires.id, vampires.name, vampires.created_at, vampires.updated_at, vampires.user_id, vampires.deleted_at, vampires.archived_at
 FROM
     vampires
+    INNER JOIN vampire_members ON vampire_members.vampire_id = vampires.id
+WHERE
+    vampire_members.user_id = $1
+    AND vampire_members.role::text = ANY ($2::text[])
+    AND vampires.deleted_at IS NULL
+    AND vampires.archived_at IS NULL
+    AND (NOT $3::boolean
+        OR (vampires.name, vampires.id) > ($4::text, $5::uuid))
+    AND ($6::text = ''
+        OR to_tsvector('english', vampires.name) @@ websearch_to_tsquery('english', $6::text)
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                experiences
+                INNER JOIN memories ON experiences.memory_id = memories.id
+            WHERE
+                memories.vampire_id = vampires.id
+                AND to_tsvector('english', experiences.description) @@ websearch_to_tsquery('english', $6::text)))
+ORDER BY
+    vampires.name,
+    vampires.id
+LIMIT $7
+`
+
+type GetVampiresForMemberByNameParams struct {
+	UserID    uuid.UUID
+	Roles     []string
+	Paginate  bool
+	AfterName string
+	AfterID   uuid.UUID
+	Search    string
+	PageSize  int32
+}
+
+func (q *Queries) GetVampiresForMemberByName(ctx context.Context, arg GetVampiresForMemberByNameParams) ([]Vampire, error) {
+	rows, err := q.db.Query(ctx, getVampiresForMemberByName,
+		arg.UserID,
+		arg.Roles,
+		arg.Paginate,
+		arg.AfterName,
+		arg.AfterID,
+		arg.Search,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Vampire
+	for rows.Next() {
+		var i Vampire
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVampiresForMemberByPlayed = `-- name: GetVampiresForMemberByPlayed :many
+WITH played AS (
+    SELECT
+        memories.vampire_id,
+        max(experiences.created_at) AS played_at
+    FROM
+        experiences
+        INNER JOIN memories ON experiences.memory_id = memories.id
+    GROUP BY
+        memories.vampire_id
+)
+SELECT
+    vampires.id, vampires.name, vampires.created_at, vampires.updated_at, vampires.user_id, vampires.deleted_at, vampires.archived_at,
+    COALESCE(played.played_at, vampires.created_at)::timestamp AS played_at
+FROM
+    vampires
+    INNER JOIN vampire_members ON vampire_members.vampire_id = vampires.id
+    LEFT JOIN played ON played.vampire_id = vampires.id
+WHERE
+    vampire_members.user_id = $1
+    AND vampire_members.role::text = ANY ($2::text[])
+    AND vampires.deleted_at IS NULL
+    AND vampires.archived_at IS NULL
+    AND (NOT $3::boolean
+        OR (COALESCE(played.played_at, vampires.created_at), vampires.id) < ($4::timestamp, $5::uuid))
+    AND ($6::text = ''
+        OR to_tsvector('english', vampires.name) @@ websearch_to_tsquery('english', $6::text)
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                experiences
+                INNER JOIN memories ON experiences.memory_id = memories.id
+            WHERE
+                memories.vampire_id = vampires.id
+                AND to_tsvector('english', experiences.description) @@ websearch_to_tsquery('english', $6::text)))
+ORDER BY
+    COALESCE(played.played_at, vampires.created_at) DESC,
+    vampires.id DESC
+LIMIT $7
+`
+
+type GetVampiresForMemberByPlayedParams struct {
+	UserID        uuid.UUID
+	Roles         []string
+	Paginate      bool
+	AfterPlayedAt time.Time
+	AfterID       uuid.UUID
+	Search        string
+	PageSize      int32
+}
+
+type GetVampiresForMemberByPlayedRow struct {
+	ID         uuid.UUID
+	Name       string
+	CreatedAt  time.Time
+	UpdatedAt  sql.NullTime
+	UserID     uuid.NullUUID
+	DeletedAt  sql.NullTime
+	ArchivedAt sql.NullTime
+	PlayedAt   time.Time
+}
+
+func (q *Queries) GetVampiresForMemberByPlayed(ctx context.Context, arg GetVampiresForMemberByPlayedParams) ([]GetVampiresForMemberByPlayedRow, error) {
+	rows, err := q.db.Query(ctx, getVampiresForMemberByPlayed,
+		arg.UserID,
+		arg.Roles,
+		arg.Paginate,
+		arg.AfterPlayedAt,
+		arg.AfterID,
+		arg.Search,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetVampiresForMemberByPlayedRow
+	for rows.Next() {
+		var i GetVampiresForMemberByPlayedRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+			&i.PlayedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeTrashedVampires = `-- name: PurgeTrashedVampires :many
+DELETE FROM vampires
+WHERE deleted_at <= NOW() - make_interval(secs => $1::int)
+RETURNING
+    id, name, created_at, updated_at, user_id, deleted_at, archived_at
 `
 
-func (q *Queries) GetVampires(ctx context.Context) ([]Vampire, error) {
-	rows, err := q.db.Query(ctx, getVampires)
+func (q *Queries) PurgeTrashedVampires(ctx context.Context, retentionSeconds int32) ([]Vampire, error) {
+	rows, err := q.db.Query(ctx, purgeTrashedVampires, retentionSeconds)
 	if err != nil {
 		return nil, err
 	}
@@ -79,6 +533,8 @@ func (q *Queries) GetVampires(ctx context.Context) ([]Vampire, error) {
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.UserID,
+			&i.DeletedAt,
+			&i.ArchivedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -89,3 +545,103 @@ func (q *Queries) GetVampires(ctx context.Context) ([]Vampire, error) {
 	}
 	return items, nil
 }
+
+const restoreTrashedVampire = `-- name: RestoreTrashedVampire :one
+UPDATE
+    vampires
+SET
+    deleted_at = NULL,
+    updated_at = NOW()
+WHERE
+    vampires.id = $1
+    AND vampires.deleted_at > NOW() - make_interval(secs => $2::int)
+    AND EXISTS (
+        SELECT
+            1
+        FROM
+            vampire_members
+        WHERE
+            vampire_members.vampire_id = vampires.id
+            AND vampire_members.user_id = $3
+            AND vampire_members.role = 'owner')
+RETURNING
+    id, name, created_at, updated_at, user_id, deleted_at, archived_at
+`
+
+type RestoreTrashedVampireParams struct {
+	ID               uuid.UUID
+	RetentionSeconds int32
+	UserID           uuid.UUID
+}
+
+func (q *Queries) RestoreTrashedVampire(ctx context.Context, arg RestoreTrashedVampireParams) (Vampire, error) {
+	row := q.db.QueryRow(ctx, restoreTrashedVampire, arg.ID, arg.RetentionSeconds, arg.UserID)
+	var i Vampire
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.DeletedAt,
+		&i.ArchivedAt,
+	)
+	return i, err
+}
+
+const trashVampire = `-- name: TrashVampire :one
+UPDATE
+    vampires
+SET
+    deleted_at = NOW(),
+    updated_at = NOW()
+WHERE
+    id = $1
+    AND deleted_at IS NULL
+RETURNING
+    id, name, created_at, updated_at, user_id, deleted_at, archived_at
+`
+
+func (q *Queries) TrashVampire(ctx context.Context, id uuid.UUID) (Vampire, error) {
+	row := q.db.QueryRow(ctx, trashVampire, id)
+	var i Vampire
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.DeletedAt,
+		&i.ArchivedAt,
+	)
+	return i, err
+}
+
+const unarchiveVampire = `-- name: UnarchiveVampire :one
+UPDATE
+    vampires
+SET
+    archived_at = NULL,
+    updated_at = NOW()
+WHERE
+    id = $1
+    AND deleted_at IS NULL
+    AND archived_at IS NOT NULL
+RETURNING
+    id, name, created_at, updated_at, user_id, deleted_at, archived_at
+`
+
+func (q *Queries) UnarchiveVampire(ctx context.Context, id uuid.UUID) (Vampire, error) {
+	row := q.db.QueryRow(ctx, unarchiveVampire, id)
+	var i Vampire
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.DeletedAt,
+		&i.ArchivedAt,
+	)
+	return i, err
+}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"emailaddress.horse/thousand/models"
 	"emailaddress.horse/thousand/repository/queries"
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/log/zapadapter"
 	"github.com/jackc/pgx/v4/pgxpool"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -16,6 +19,9 @@ type Repository struct {
 	pool    *pgxpool.Pool
 	txConn  txConnector
 	queries *queries.Queries
+	tracer  trace.Tracer
+
+	trashRetention time.Duration
 }
 
 type txConnector interface {
@@ -23,8 +29,12 @@ type txConnector interface {
 }
 
 type Options struct {
-	DatabaseURL string
-	Logger      *zap.Logger
+	DatabaseURL    string
+	Logger         *zap.Logger
+	TracerProvider trace.TracerProvider
+	// TrashRetention is how long trashed vampires can be restored for before
+	// they are purged. It defaults to models.DefaultTrashRetention.
+	TrashRetention time.Duration
 }
 
 func New(opts Options) (*Repository, error) {
@@ -32,12 +42,25 @@ func New(opts Options) (*Repository, error) {
 		opts.Logger = zap.NewNop()
 	}
 
+	if opts.TracerProvider == nil {
+		opts.TracerProvider = trace.NewNoopTracerProvider()
+	}
+
+	if opts.TrashRetention == 0 {
+		opts.TrashRetention = models.DefaultTrashRetention
+	} else if opts.TrashRetention < 0 || opts.TrashRetention > models.MaxTrashRetention {
+		return nil, fmt.Errorf("trash retention must be between 0 and %s; got %s", models.MaxTrashRetention, opts.TrashRetention)
+	}
+
 	config, err := pgxpool.ParseConfig(opts.DatabaseURL)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing database URL: %w", err)
 	}
 
-	config.ConnConfig.Logger = zapadapter.NewLogger(opts.Logger)
+	config.ConnConfig.Logger = requestLogger{zapadapter.NewLogger(opts.Logger)}
+	if _, ok := config.ConnConfig.RuntimeParams["application_name"]; !ok {
+		config.ConnConfig.RuntimeParams["application_name"] = applicationName
+	}
 	config.LazyConnect = true
 
 	pool, err := pgxpool.ConnectConfig(context.Background(), config)
@@ -45,10 +68,15 @@ func New(opts Options) (*Repository, error) {
 		return nil, fmt.Errorf("error connecting to database: %w", err)
 	}
 
+	tracer := opts.TracerProvider.Tracer(tracerName)
+
 	return &Repository{
 		pool:    pool,
 		txConn:  pool,
-		queries: queries.New(pool),
+		queries: queries.New(tracedDB{db: pool, tracer: tracer}),
+		tracer:  tracer,
+
+		trashRetention: opts.TrashRetention,
 	}, nil
 }
 
@@ -59,6 +87,15 @@ func (r *Repository) Ping(ctx context.Context) error {
 	return r.pool.Ping(ctx)
 }
 
+// Close closes every connection in the pool, waiting for any in use to be
+// released. Transactions don't own the pool so closing one does nothing.
+func (r *Repository) Close() {
+	if r.pool == nil {
+		return
+	}
+	r.pool.Close()
+}
+
 func (r *Repository) Stat() *pgxpool.Stat {
 	return r.pool.Stat()
 }
@@ -71,7 +108,10 @@ func (r *Repository) WithTx(ctx context.Context) (*Repository, pgx.Tx, error) {
 
 	return &Repository{
 		txConn:  tx,
-		queries: queries.New(tx),
+		queries: queries.New(tracedDB{db: tx, tracer: r.tracer}),
+		tracer:  r.tracer,
+
+		trashRetention: r.trashRetention,
 	}, tx, nil
 }
 
@@ -88,6 +128,9 @@ func (r *Repository) WithSavepoint(ctx context.Context) (*Repository, pgx.Tx, er
 
 	return &Repository{
 		txConn:  spTx,
-		queries: queries.New(spTx),
+		queries: queries.New(tracedDB{db: spTx, tracer: r.tracer}),
+		tracer:  r.tracer,
+
+		trashRetention: r.trashRetention,
 	}, spTx, nil
 }
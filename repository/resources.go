@@ -14,6 +14,9 @@ import (
 // CreateResource attempts to add a new resource to the DB for the provided
 // vampire.
 func (m *Repository) CreateResource(ctx context.Context, vampireID uuid.UUID, params models.CreateResourceParams) (models.Resource, error) {
+	ctx, span := m.startSpan(ctx, "CreateResource")
+	defer span.End()
+
 	dbParams := queries.CreateResourceParams{
 		VampireID:   vampireID,
 		Description: params.Description,
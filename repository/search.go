@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"emailaddress.horse/thousand/models"
+	"emailaddress.horse/thousand/repository/queries"
+	"github.com/google/uuid"
+)
+
+// The matches in a snippet are wrapped in control characters, which won't
+// appear in anything a player writes, rather than markup so that the rest of
+// the snippet can still be escaped when it is rendered.
+const (
+	snippetStart = "\x02"
+	snippetStop  = "\x03"
+)
+
+var headlineOptions = fmt.Sprintf(
+	`StartSel="%s", StopSel="%s", MinWords=10, MaxWords=30, MaxFragments=2, FragmentDelimiter=" … "`,
+	snippetStart,
+	snippetStop,
+)
+
+// SearchVampire attempts to find the experiences, skills, resources,
+// characters and marks of the vampire which match the search, best matches
+// first. The search is in the same format as web search engines accept, with
+// quoted phrases, "or" and negation using "-".
+func (m *Repository) SearchVampire(ctx context.Context, vampireID uuid.UUID, search string) ([]models.SearchResult, error) {
+	ctx, span := m.startSpan(ctx, "SearchVampire")
+	defer span.End()
+
+	rows, err := m.queries.SearchVampire(ctx, queries.SearchVampireParams{
+		VampireID:       vampireID,
+		Search:          search,
+		HeadlineOptions: headlineOptions,
+		ResultLimit:     models.MaxSearchResults,
+	})
+	if err != nil {
+		return []models.SearchResult{}, err
+	}
+
+	results := make([]models.SearchResult, len(rows))
+	for i, row := range rows {
+		results[i] = models.SearchResult{
+			Kind:     models.SearchResultKind(row.Kind),
+			ID:       row.ID,
+			MemoryID: row.MemoryID.UUID,
+			Snippet:  parseSnippet(row.Snippet),
+			Rank:     row.Rank,
+		}
+	}
+
+	return results, nil
+}
+
+// parseSnippet splits a headline from Postgres into the parts which matched
+// the search and the text between them.
+func parseSnippet(headline string) []models.SnippetPart {
+	var parts []models.SnippetPart
+
+	for headline != "" {
+		start := strings.Index(headline, snippetStart)
+		if start == -1 {
+			parts = append(parts, models.SnippetPart{Text: headline})
+			break
+		}
+
+		if start > 0 {
+			parts = append(parts, models.SnippetPart{Text: headline[:start]})
+		}
+		headline = headline[start+len(snippetStart):]
+
+		stop := strings.Index(headline, snippetStop)
+		if stop == -1 {
+			stop = len(headline)
+		}
+
+		parts = append(parts, models.SnippetPart{Text: headline[:stop], Match: true})
+		headline = strings.TrimPrefix(headline[stop:], snippetStop)
+	}
+
+	return parts
+}
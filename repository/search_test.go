@@ -0,0 +1,151 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"emailaddress.horse/thousand/models"
+	"emailaddress.horse/thousand/repository"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSearchVampire(t *testing.T) {
+	m := newTestRepository(t)
+
+	vampire, err := m.CreateVampire(context.Background(), m.UserID(), "Countess")
+	if err != nil {
+		t.Fatal(err)
+	}
+	memory := vampire.Memories[0]
+
+	if _, err := m.CreateExperience(context.Background(), vampire.ID, memory.ID, "Elisabeth taught me to fear the sun. Elisabeth fed me. Elisabeth left me."); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.CreateSkill(context.Background(), vampire.ID, "Elisabeth showed me how to hunt, as Elisabeth always had"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.CreateCharacter(context.Background(), vampire.ID, models.CreateCharacterParams{Name: "Elisabeth Báthory", Type: "immortal"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.CreateMark(context.Background(), vampire.ID, "My eyes shine in the dark"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another vampire's chronicle is never searched
+	other, err := m.CreateVampire(context.Background(), m.UserID(), "Other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.CreateSkill(context.Background(), other.ID, "Elisabeth's old tricks"); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := m.SearchVampire(context.Background(), vampire.ID, "elisabeth")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kinds := make([]models.SearchResultKind, len(results))
+	for i, result := range results {
+		kinds[i] = result.Kind
+	}
+
+	// Results mentioning the search more often rank higher
+	expectedKinds := []models.SearchResultKind{
+		models.SearchResultExperience,
+		models.SearchResultSkill,
+		models.SearchResultCharacter,
+	}
+	if diff := cmp.Diff(expectedKinds, kinds); diff != "" {
+		t.Fatal(diff)
+	}
+
+	if results[0].MemoryID != memory.ID {
+		t.Errorf("expected experience to be in memory %s; got %s", memory.ID, results[0].MemoryID)
+	}
+
+	expectedSnippet := []models.SnippetPart{
+		{Text: "Elisabeth", Match: true},
+		{Text: " Báthory"},
+	}
+	if diff := cmp.Diff(expectedSnippet, results[2].Snippet); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestSearchVampire_NoMatches(t *testing.T) {
+	m := newTestRepository(t)
+
+	vampire, err := m.CreateVampire(context.Background(), m.UserID(), "Countess")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := m.SearchVampire(context.Background(), vampire.ID, "elisabeth")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 0 {
+		t.Errorf("expected no results; got %v", results)
+	}
+}
+
+func TestParseSnippet(t *testing.T) {
+	tests := []struct {
+		name     string
+		headline string
+		expected []models.SnippetPart
+	}{
+		{
+			name:     "no matches",
+			headline: "nothing to see",
+			expected: []models.SnippetPart{{Text: "nothing to see"}},
+		},
+		{
+			name:     "match in the middle",
+			headline: "I met \x02Elisabeth\x03 at dusk",
+			expected: []models.SnippetPart{
+				{Text: "I met "},
+				{Text: "Elisabeth", Match: true},
+				{Text: " at dusk"},
+			},
+		},
+		{
+			name:     "matches at either end",
+			headline: "\x02Elisabeth\x03 and \x02Elisabeth\x03",
+			expected: []models.SnippetPart{
+				{Text: "Elisabeth", Match: true},
+				{Text: " and "},
+				{Text: "Elisabeth", Match: true},
+			},
+		},
+		{
+			name:     "unterminated match",
+			headline: "I met \x02Elisabeth",
+			expected: []models.SnippetPart{
+				{Text: "I met "},
+				{Text: "Elisabeth", Match: true},
+			},
+		},
+		{
+			name:     "markup is left alone",
+			headline: "<b>\x02Elisabeth\x03</b>",
+			expected: []models.SnippetPart{
+				{Text: "<b>"},
+				{Text: "Elisabeth", Match: true},
+				{Text: "</b>"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.expected, repository.ParseSnippet(tt.headline)); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
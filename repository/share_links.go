@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"emailaddress.horse/thousand/models"
+	"emailaddress.horse/thousand/repository/queries"
+	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v4"
+)
+
+// CreateShareLink attempts to create a new share link with a random token for
+// the provided vampire. A link created with an expiresIn of zero never expires.
+// The expiry must be no more than models.MaxShareLinkExpiry.
+func (m *Repository) CreateShareLink(ctx context.Context, vampireID uuid.UUID, expiresIn time.Duration) (models.ShareLink, error) {
+	ctx, span := m.startSpan(ctx, "CreateShareLink")
+	defer span.End()
+
+	// Anything longer would overflow the seconds, which could make the link
+	// never expire
+	if expiresIn > models.MaxShareLinkExpiry {
+		return models.ShareLink{}, fmt.Errorf("share link expiry %s is longer than %s", expiresIn, models.MaxShareLinkExpiry)
+	}
+
+	params := queries.CreateShareLinkParams{
+		VampireID:        vampireID,
+		ExpiresInSeconds: int32(expiresIn.Seconds()),
+	}
+
+	dbShareLink, err := m.queries.CreateShareLink(ctx, params)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		if pgErr.Code == pgerrcode.ForeignKeyViolation && pgErr.ConstraintName == "share_links_vampire_id_fkey" {
+			return models.ShareLink{}, models.ErrNotFound.Cause(err)
+		}
+
+		return models.ShareLink{}, err
+	} else if err != nil {
+		return models.ShareLink{}, err
+	}
+
+	return newShareLink(dbShareLink), nil
+}
+
+// GetShareLinks attempts to retrieve all the share links, including expired
+// and revoked links, for the provided vampire.
+func (m *Repository) GetShareLinks(ctx context.Context, vampireID uuid.UUID) ([]models.ShareLink, error) {
+	ctx, span := m.startSpan(ctx, "GetShareLinks")
+	defer span.End()
+
+	dbShareLinks, err := m.queries.GetShareLinksForVampire(ctx, vampireID)
+	if err != nil {
+		return nil, err
+	}
+
+	shareLinks := make([]models.ShareLink, len(dbShareLinks))
+	for i, dbShareLink := range dbShareLinks {
+		shareLinks[i] = newShareLink(dbShareLink)
+	}
+
+	return shareLinks, nil
+}
+
+// GetSharedVampire attempts to retrieve the vampire shared by the active share
+// link with the provided token.
+func (m *Repository) GetSharedVampire(ctx context.Context, token string) (models.Vampire, error) {
+	ctx, span := m.startSpan(ctx, "GetSharedVampire")
+	defer span.End()
+
+	dbShareLink, err := m.queries.GetActiveShareLink(ctx, token)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Vampire{}, models.ErrNotFound.Cause(err)
+	} else if err != nil {
+		return models.Vampire{}, err
+	}
+
+	return m.GetVampire(ctx, dbShareLink.VampireID)
+}
+
+// RevokeShareLink attempts to revoke the share link so that its token can no
+// longer be used.
+func (m *Repository) RevokeShareLink(ctx context.Context, vampireID, id uuid.UUID) (models.ShareLink, error) {
+	ctx, span := m.startSpan(ctx, "RevokeShareLink")
+	defer span.End()
+
+	params := queries.RevokeShareLinkParams{
+		ID:        id,
+		VampireID: vampireID,
+	}
+
+	txRepo, tx, err := m.WithTx(ctx)
+	if err != nil {
+		return models.ShareLink{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	dbShareLink, err := txRepo.queries.RevokeShareLink(ctx, params)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.ShareLink{}, models.ErrNotFound.Cause(err)
+	} else if err != nil {
+		return models.ShareLink{}, err
+	}
+
+	changes := models.DiffAuditValues(
+		map[string]interface{}{"revoked_at": nil},
+		map[string]interface{}{"revoked_at": dbShareLink.RevokedAt.Time},
+	)
+	if err := txRepo.audit(ctx, models.AuditActionShareLinkRevoked, models.AuditTargetShareLink, id, changes); err != nil {
+		return models.ShareLink{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.ShareLink{}, err
+	}
+
+	return newShareLink(dbShareLink), nil
+}
@@ -0,0 +1,196 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"emailaddress.horse/thousand/models"
+	"emailaddress.horse/thousand/repository"
+	"github.com/google/uuid"
+)
+
+func TestCreateShareLink(t *testing.T) {
+	tests := []struct {
+		name          string
+		id            func(models.Vampire) uuid.UUID
+		expiresIn     time.Duration
+		expectExpiry  bool
+		expectedError error
+	}{
+		{
+			name:          "successful",
+			id:            func(v models.Vampire) uuid.UUID { return v.ID },
+			expectedError: nil,
+		},
+		{
+			name:          "successful with expiry",
+			id:            func(v models.Vampire) uuid.UUID { return v.ID },
+			expiresIn:     24 * time.Hour,
+			expectExpiry:  true,
+			expectedError: nil,
+		},
+		{
+			name:          "vampire not found",
+			id:            func(v models.Vampire) uuid.UUID { return uuid.New() },
+			expectedError: models.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestRepository(t)
+
+			vampire, err := m.CreateVampire(context.Background(), m.UserID(), "test vampire")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			link, err := m.CreateShareLink(context.Background(), tt.id(vampire), tt.expiresIn)
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected %q; received %q", tt.expectedError, err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if link.Token == "" {
+				t.Error("expected link to have a token")
+			}
+
+			if tt.expectExpiry == link.ExpiresAt.IsZero() {
+				t.Errorf("expected expiry %t; got %q", tt.expectExpiry, link.ExpiresAt)
+			}
+		})
+	}
+}
+
+func TestGetSharedVampire(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         func(models.ShareLink) string
+		revoke        bool
+		expectedError error
+	}{
+		{
+			name:          "successful",
+			token:         func(l models.ShareLink) string { return l.Token },
+			expectedError: nil,
+		},
+		{
+			name:          "revoked",
+			token:         func(l models.ShareLink) string { return l.Token },
+			revoke:        true,
+			expectedError: models.ErrNotFound,
+		},
+		{
+			name:          "unknown token",
+			token:         func(l models.ShareLink) string { return "unknown" },
+			expectedError: models.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestRepository(t)
+
+			vampire, err := m.CreateVampire(context.Background(), m.UserID(), "test vampire")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			link, err := m.CreateShareLink(context.Background(), vampire.ID, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.revoke {
+				if _, err := m.RevokeShareLink(context.Background(), vampire.ID, link.ID); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			sharedVampire, err := m.GetSharedVampire(context.Background(), tt.token(link))
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected %q; received %q", tt.expectedError, err)
+			}
+
+			if err == nil && sharedVampire.ID != vampire.ID {
+				t.Errorf("expected vampire %q; got %q", vampire.ID, sharedVampire.ID)
+			}
+		})
+	}
+}
+
+func TestRevokeShareLink(t *testing.T) {
+	tests := []struct {
+		name          string
+		vampireID     func(models.Vampire) uuid.UUID
+		id            func(models.ShareLink) uuid.UUID
+		expectedError error
+	}{
+		{
+			name:          "successful",
+			vampireID:     func(v models.Vampire) uuid.UUID { return v.ID },
+			id:            func(l models.ShareLink) uuid.UUID { return l.ID },
+			expectedError: nil,
+		},
+		{
+			name:          "link not found",
+			vampireID:     func(v models.Vampire) uuid.UUID { return v.ID },
+			id:            func(l models.ShareLink) uuid.UUID { return uuid.New() },
+			expectedError: models.ErrNotFound,
+		},
+		{
+			name:          "link for another vampire",
+			vampireID:     func(v models.Vampire) uuid.UUID { return uuid.New() },
+			id:            func(l models.ShareLink) uuid.UUID { return l.ID },
+			expectedError: models.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestRepository(t)
+
+			vampire, err := m.CreateVampire(context.Background(), m.UserID(), "test vampire")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			link, err := m.CreateShareLink(context.Background(), vampire.ID, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = m.WithSavepoint(func(m *repository.Repository) error {
+				_, err := m.RevokeShareLink(context.Background(), tt.vampireID(vampire), tt.id(link))
+				return err
+			})
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected %q; received %q", tt.expectedError, err)
+			}
+
+			links, err := m.GetShareLinks(context.Background(), vampire.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(links) != 1 {
+				t.Fatalf("expected 1 link; found %d", len(links))
+			}
+
+			if revoked := !links[0].RevokedAt.IsZero(); revoked != (tt.expectedError == nil) {
+				t.Errorf("expected revoked %t; got %t", tt.expectedError == nil, revoked)
+			}
+		})
+	}
+}
@@ -13,6 +13,9 @@ import (
 
 // CreateSkill attempts to add a new skill to the DB for the provided vampire.
 func (m *Repository) CreateSkill(ctx context.Context, vampireID uuid.UUID, description string) (models.Skill, error) {
+	ctx, span := m.startSpan(ctx, "CreateSkill")
+	defer span.End()
+
 	params := queries.CreateSkillParams{
 		VampireID:   vampireID,
 		Description: description,
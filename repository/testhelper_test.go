@@ -14,10 +14,10 @@ import (
 type testRepository struct {
 	*repository.Repository
 	tx pgx.Tx
-	t  *testing.T
+	t  testing.TB
 }
 
-func newTestRepository(t *testing.T) testRepository {
+func newTestRepository(t testing.TB) testRepository {
 	// TODO: Derive DB connection from App config to prevent duplication - cannot
 	// be done until config is extracted from app package due to circular
 	// dependencies.
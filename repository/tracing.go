@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"emailaddress.horse/thousand/repository/queries"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "emailaddress.horse/thousand/repository"
+
+// startSpan starts a span covering a call to one of the repository's methods,
+// which the spans of its queries are nested within.
+func (m *Repository) startSpan(ctx context.Context, method string) (context.Context, trace.Span) {
+	return m.tracer.Start(ctx, "repository."+method)
+}
+
+// tracedDB starts a span for each query made through it, and tags the query
+// with the request it was made for.
+type tracedDB struct {
+	db     queries.DBTX
+	tracer trace.Tracer
+}
+
+var _ queries.DBTX = tracedDB{}
+
+func (t tracedDB) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	ctx, span := t.start(ctx, sql)
+	defer span.End()
+
+	tag, err := t.db.Exec(ctx, withRequestComment(ctx, sql), args...)
+	recordError(span, err)
+
+	return tag, err
+}
+
+func (t tracedDB) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	ctx, span := t.start(ctx, sql)
+
+	rows, err := t.db.Query(ctx, withRequestComment(ctx, sql), args...)
+	if err != nil {
+		recordError(span, err)
+		span.End()
+		return rows, err
+	}
+
+	return tracedRows{Rows: rows, span: span}, nil
+}
+
+func (t tracedDB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	ctx, span := t.start(ctx, sql)
+
+	return tracedRow{row: t.db.QueryRow(ctx, withRequestComment(ctx, sql), args...), span: span}
+}
+
+func (t tracedDB) start(ctx context.Context, sql string) (context.Context, trace.Span) {
+	return t.tracer.Start(ctx, queryName(sql),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBStatementKey.String(sql),
+		),
+	)
+}
+
+// tracedRows ends the span for a query once its rows have been read.
+type tracedRows struct {
+	pgx.Rows
+	span trace.Span
+}
+
+func (r tracedRows) Close() {
+	r.Rows.Close()
+	recordError(r.span, r.Rows.Err())
+	r.span.End()
+}
+
+// tracedRow ends the span for a query once its row has been read.
+type tracedRow struct {
+	row  pgx.Row
+	span trace.Span
+}
+
+func (r tracedRow) Scan(dest ...interface{}) error {
+	err := r.row.Scan(dest...)
+	if !errors.Is(err, pgx.ErrNoRows) {
+		recordError(r.span, err)
+	}
+	r.span.End()
+
+	return err
+}
+
+func recordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// queryName names a span after the query's name as given to sqlc, falling
+// back to a generic name for other queries.
+func queryName(sql string) string {
+	const prefix = "-- name: "
+
+	if strings.HasPrefix(sql, prefix) {
+		fields := strings.Fields(strings.TrimPrefix(sql, prefix))
+		if len(fields) > 0 {
+			return "queries." + fields[0]
+		}
+	}
+
+	return "query"
+}
@@ -0,0 +1,77 @@
+package repository_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"emailaddress.horse/thousand/repository"
+	"emailaddress.horse/thousand/tracing"
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracing(t *testing.T) {
+	var databaseURL = "postgres://localhost:5432/thousand_test?sslmode=disable"
+
+	if os.Getenv("DATABASE_URL") != "" {
+		databaseURL = os.Getenv("DATABASE_URL")
+	}
+
+	recorder := tracetest.NewSpanRecorder()
+
+	repo, err := repository.New(repository.Options{
+		DatabaseURL:    databaseURL,
+		TracerProvider: tracing.NewWithProcessor(recorder),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo, tx, err := repo.WithTx(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := tx.Rollback(context.Background()); err != nil {
+			t.Fatalf("Error attempting to rollback - DB may have unexpected contents: %s", err)
+		}
+	})
+
+	m := testRepository{repo, tx, t}
+
+	vampire, err := m.CreateVampire(context.Background(), m.UserID(), "traced vampire")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before := len(recorder.Ended())
+
+	if _, err := m.GetVampire(context.Background(), vampire.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := recorder.Ended()[before:]
+
+	method := spans[len(spans)-1]
+	if method.Name() != "repository.GetVampire" {
+		t.Fatalf("expected the repository method to end last; got %q", method.Name())
+	}
+
+	var names []string
+	for _, span := range spans[:len(spans)-1] {
+		if span.Parent().SpanID() != method.SpanContext().SpanID() {
+			t.Errorf("expected %q to be nested within the repository method", span.Name())
+		}
+
+		names = append(names, span.Name())
+	}
+
+	expectedNames := []string{
+		"queries.GetVampireDetails",
+	}
+
+	if diff := cmp.Diff(expectedNames, names); diff != "" {
+		t.Error(diff)
+	}
+}
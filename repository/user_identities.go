@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"emailaddress.horse/thousand/models"
+	"emailaddress.horse/thousand/repository/queries"
+	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v4"
+)
+
+// GetUserByIdentity attempts to find the user which has linked the external
+// identity with the provided issuer and subject.
+func (m *Repository) GetUserByIdentity(ctx context.Context, issuer, subject string) (models.User, error) {
+	ctx, span := m.startSpan(ctx, "GetUserByIdentity")
+	defer span.End()
+
+	dbUser, err := m.queries.GetUserByIdentity(ctx, queries.GetUserByIdentityParams{
+		Issuer:  issuer,
+		Subject: subject,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.User{}, models.ErrNotFound.Cause(err)
+	} else if err != nil {
+		return models.User{}, err
+	}
+
+	return newUser(dbUser), nil
+}
+
+// CreateUserFromIdentity attempts to create a new user without a password
+// which can only log in with the provided external identity.
+func (m *Repository) CreateUserFromIdentity(ctx context.Context, identity models.UserIdentity) (models.User, error) {
+	ctx, span := m.startSpan(ctx, "CreateUserFromIdentity")
+	defer span.End()
+
+	txRepo, tx, err := m.WithTx(ctx)
+	if err != nil {
+		return models.User{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	dbUser, err := txRepo.queries.CreateUserWithoutPassword(ctx, identity.Email)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			err = models.ErrEmailAlreadyInUse.Cause(err)
+		}
+
+		return models.User{}, err
+	}
+
+	if _, err := txRepo.LinkUserIdentity(ctx, dbUser.ID, identity); err != nil {
+		return models.User{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.User{}, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return models.User{
+		ID:    dbUser.ID,
+		Email: dbUser.Email,
+	}, nil
+}
+
+// LinkUserIdentity attempts to link the provided external identity to the user
+// so that they can use it to log in.
+func (m *Repository) LinkUserIdentity(ctx context.Context, userID uuid.UUID, identity models.UserIdentity) (models.UserIdentity, error) {
+	ctx, span := m.startSpan(ctx, "LinkUserIdentity")
+	defer span.End()
+
+	txRepo, tx, err := m.WithTx(ctx)
+	if err != nil {
+		return models.UserIdentity{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	dbIdentity, err := txRepo.queries.CreateUserIdentity(ctx, queries.CreateUserIdentityParams{
+		UserID:  userID,
+		Issuer:  identity.Issuer,
+		Subject: identity.Subject,
+		Email:   identity.Email,
+	})
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		if pgErr.Code == pgerrcode.UniqueViolation {
+			return models.UserIdentity{}, models.ErrIdentityAlreadyLinked.Cause(err)
+		}
+
+		if pgErr.Code == pgerrcode.ForeignKeyViolation && pgErr.ConstraintName == "user_identities_user_id_fkey" {
+			return models.UserIdentity{}, models.ErrNotFound.Cause(err)
+		}
+
+		return models.UserIdentity{}, err
+	} else if err != nil {
+		return models.UserIdentity{}, err
+	}
+
+	changes := models.DiffAuditValues(nil, map[string]interface{}{
+		"user_id": dbIdentity.UserID,
+		"issuer":  dbIdentity.Issuer,
+		"subject": dbIdentity.Subject,
+	})
+	if err := txRepo.audit(ctx, models.AuditActionIdentityLink, models.AuditTargetIdentity, dbIdentity.ID, changes); err != nil {
+		return models.UserIdentity{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.UserIdentity{}, err
+	}
+
+	return newUserIdentity(dbIdentity), nil
+}
+
+// GetUserIdentities attempts to retrieve all the external identities linked to
+// the user.
+func (m *Repository) GetUserIdentities(ctx context.Context, userID uuid.UUID) ([]models.UserIdentity, error) {
+	ctx, span := m.startSpan(ctx, "GetUserIdentities")
+	defer span.End()
+
+	dbIdentities, err := m.queries.GetUserIdentitiesForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	identities := make([]models.UserIdentity, len(dbIdentities))
+	for i, dbIdentity := range dbIdentities {
+		identities[i] = newUserIdentity(dbIdentity)
+	}
+
+	return identities, nil
+}
+
+// UnlinkUserIdentity attempts to remove the external identity from the user. A
+// user without a password must keep at least one identity so that they can
+// still log in.
+func (m *Repository) UnlinkUserIdentity(ctx context.Context, userID, id uuid.UUID) error {
+	ctx, span := m.startSpan(ctx, "UnlinkUserIdentity")
+	defer span.End()
+
+	dbUser, err := m.queries.GetUser(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.ErrNotFound.Cause(err)
+	} else if err != nil {
+		return err
+	}
+
+	dbIdentities, err := m.queries.GetUserIdentitiesForUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	linked := false
+	for _, dbIdentity := range dbIdentities {
+		if dbIdentity.ID == id {
+			linked = true
+		}
+	}
+
+	if !linked {
+		return models.ErrNotFound
+	}
+
+	if !dbUser.PasswordHash.Valid && len(dbIdentities) <= 1 {
+		return models.ErrLastLoginMethod
+	}
+
+	txRepo, tx, err := m.WithTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	dbIdentity, err := txRepo.queries.DeleteUserIdentity(ctx, queries.DeleteUserIdentityParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.ErrNotFound.Cause(err)
+	} else if err != nil {
+		return err
+	}
+
+	changes := models.DiffAuditValues(map[string]interface{}{
+		"user_id": dbIdentity.UserID,
+		"issuer":  dbIdentity.Issuer,
+		"subject": dbIdentity.Subject,
+	}, nil)
+	if err := txRepo.audit(ctx, models.AuditActionIdentityUnlink, models.AuditTargetIdentity, id, changes); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,257 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"emailaddress.horse/thousand/form"
+	"emailaddress.horse/thousand/models"
+	"emailaddress.horse/thousand/repository"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/uuid"
+)
+
+func TestLinkUserIdentity(t *testing.T) {
+	tests := []struct {
+		name          string
+		userID        func(models.User) uuid.UUID
+		preLinked     bool
+		expectedError error
+	}{
+		{
+			name:          "successful",
+			userID:        func(u models.User) uuid.UUID { return u.ID },
+			expectedError: nil,
+		},
+		{
+			name:          "already linked",
+			userID:        func(u models.User) uuid.UUID { return u.ID },
+			preLinked:     true,
+			expectedError: models.ErrIdentityAlreadyLinked,
+		},
+		{
+			name:          "user not found",
+			userID:        func(u models.User) uuid.UUID { return uuid.New() },
+			expectedError: models.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestRepository(t)
+
+			user, err := m.CreateUser(context.Background(), form.NewUser("john@bannister.com", "password"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			identity := models.UserIdentity{
+				Issuer:  "https://provider.test",
+				Subject: "12345",
+				Email:   "john@bannister.com",
+			}
+
+			if tt.preLinked {
+				if _, err := m.LinkUserIdentity(context.Background(), user.ID, identity); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err = m.WithSavepoint(func(m *repository.Repository) error {
+				_, err := m.LinkUserIdentity(context.Background(), tt.userID(user), identity)
+				return err
+			})
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected %q; received %q", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestGetUserByIdentity(t *testing.T) {
+	m := newTestRepository(t)
+
+	user, err := m.CreateUser(context.Background(), form.NewUser("john@bannister.com", "password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = m.LinkUserIdentity(context.Background(), user.ID, models.UserIdentity{
+		Issuer:  "https://provider.test",
+		Subject: "12345",
+		Email:   "john@bannister.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	foundUser, err := m.GetUserByIdentity(context.Background(), "https://provider.test", "12345")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(user, foundUser); diff != "" {
+		t.Error(diff)
+	}
+
+	_, err = m.GetUserByIdentity(context.Background(), "https://another.test", "12345")
+	if !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("expected %q; received %q", models.ErrNotFound, err)
+	}
+}
+
+func TestCreateUserFromIdentity(t *testing.T) {
+	m := newTestRepository(t)
+
+	identity := models.UserIdentity{
+		Issuer:  "https://provider.test",
+		Subject: "12345",
+		Email:   "John@Bannister.com",
+	}
+
+	user, err := m.CreateUserFromIdentity(context.Background(), identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if user.Email != "john@bannister.com" {
+		t.Errorf("expected email %q; got %q", "john@bannister.com", user.Email)
+	}
+
+	identities, err := m.GetUserIdentities(context.Background(), user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedIdentities := []models.UserIdentity{
+		{
+			UserID:  user.ID,
+			Issuer:  "https://provider.test",
+			Subject: "12345",
+			Email:   "John@Bannister.com",
+		},
+	}
+
+	if diff := cmp.Diff(expectedIdentities, identities, cmpopts.IgnoreFields(models.UserIdentity{}, "ID")); diff != "" {
+		t.Error(diff)
+	}
+
+	// A user without a password cannot log in with one
+	authUser, err := m.AuthenticateUser(context.Background(), form.NewSession("john@bannister.com", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if authUser != (models.User{}) {
+		t.Error("user without a password should not authenticate")
+	}
+
+	identity.Subject = "67890"
+	err = m.WithSavepoint(func(m *repository.Repository) error {
+		_, err := m.CreateUserFromIdentity(context.Background(), identity)
+		return err
+	})
+	if !errors.Is(err, models.ErrEmailAlreadyInUse) {
+		t.Errorf("expected %q; received %q", models.ErrEmailAlreadyInUse, err)
+	}
+}
+
+func TestUnlinkUserIdentity(t *testing.T) {
+	tests := []struct {
+		name          string
+		withPassword  bool
+		identities    int
+		id            func([]models.UserIdentity) uuid.UUID
+		expectedError error
+		expectedCount int
+	}{
+		{
+			name:          "successful with password",
+			withPassword:  true,
+			identities:    1,
+			id:            func(is []models.UserIdentity) uuid.UUID { return is[0].ID },
+			expectedError: nil,
+			expectedCount: 0,
+		},
+		{
+			name:          "successful with another identity",
+			identities:    2,
+			id:            func(is []models.UserIdentity) uuid.UUID { return is[0].ID },
+			expectedError: nil,
+			expectedCount: 1,
+		},
+		{
+			name:          "last login method",
+			identities:    1,
+			id:            func(is []models.UserIdentity) uuid.UUID { return is[0].ID },
+			expectedError: models.ErrLastLoginMethod,
+			expectedCount: 1,
+		},
+		{
+			name:          "not found",
+			withPassword:  true,
+			identities:    1,
+			id:            func(is []models.UserIdentity) uuid.UUID { return uuid.New() },
+			expectedError: models.ErrNotFound,
+			expectedCount: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestRepository(t)
+
+			identity := models.UserIdentity{
+				Issuer:  "https://provider.test",
+				Subject: "0",
+				Email:   "john@bannister.com",
+			}
+
+			var user models.User
+			var err error
+			if tt.withPassword {
+				user, err = m.CreateUser(context.Background(), form.NewUser(identity.Email, "password"))
+				if err == nil {
+					_, err = m.LinkUserIdentity(context.Background(), user.ID, identity)
+				}
+			} else {
+				user, err = m.CreateUserFromIdentity(context.Background(), identity)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i := 1; i < tt.identities; i++ {
+				identity.Subject = uuid.NewString()
+				if _, err := m.LinkUserIdentity(context.Background(), user.ID, identity); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			identities, err := m.GetUserIdentities(context.Background(), user.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = m.UnlinkUserIdentity(context.Background(), user.ID, tt.id(identities))
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected %q; received %q", tt.expectedError, err)
+			}
+
+			identities, err = m.GetUserIdentities(context.Background(), user.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(identities) != tt.expectedCount {
+				t.Errorf("expected %d identities; found %d", tt.expectedCount, len(identities))
+			}
+		})
+	}
+}
@@ -14,6 +14,9 @@ import (
 )
 
 func (m *Repository) CreateUser(ctx context.Context, form *form.NewUserForm) (models.User, error) {
+	ctx, span := m.startSpan(ctx, "CreateUser")
+	defer span.End()
+
 	dbUser, err := m.queries.CreateUser(ctx, queries.CreateUserParams{
 		Email:    form.Email.Value,
 		Password: form.Password.Value,
@@ -34,18 +37,23 @@ func (m *Repository) CreateUser(ctx context.Context, form *form.NewUserForm) (mo
 }
 
 func (m *Repository) GetUser(ctx context.Context, id uuid.UUID) (models.User, error) {
+	ctx, span := m.startSpan(ctx, "GetUser")
+	defer span.End()
+
 	dbUser, err := m.queries.GetUser(ctx, id)
-	if err != nil {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.User{}, models.ErrNotFound.Cause(err)
+	} else if err != nil {
 		return models.User{}, err
 	}
 
-	return models.User{
-		ID:    dbUser.ID,
-		Email: dbUser.Email,
-	}, nil
+	return newUser(dbUser), nil
 }
 
 func (m *Repository) AuthenticateUser(ctx context.Context, form *form.NewSessionForm) (models.User, error) {
+	ctx, span := m.startSpan(ctx, "AuthenticateUser")
+	defer span.End()
+
 	dbUser, err := m.queries.AuthenticateUser(ctx, queries.AuthenticateUserParams{
 		Email:    form.Email.Value,
 		Password: form.Password.Value,
@@ -58,8 +66,5 @@ func (m *Repository) AuthenticateUser(ctx context.Context, form *form.NewSession
 		return models.User{}, err
 	}
 
-	return models.User{
-		ID:    dbUser.ID,
-		Email: dbUser.Email,
-	}, nil
+	return newUser(dbUser), nil
 }
@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"emailaddress.horse/thousand/models"
+	"emailaddress.horse/thousand/repository/queries"
+	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v4"
+)
+
+// GetVampireRole attempts to retrieve the role the user has as a member of the
+// vampire. Users who are not members receive models.ErrNotFound.
+func (m *Repository) GetVampireRole(ctx context.Context, vampireID, userID uuid.UUID) (models.MemberRole, error) {
+	ctx, span := m.startSpan(ctx, "GetVampireRole")
+	defer span.End()
+
+	role, err := m.queries.GetVampireMemberRole(ctx, queries.GetVampireMemberRoleParams{
+		VampireID: vampireID,
+		UserID:    userID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", models.ErrNotFound.Cause(err)
+	} else if err != nil {
+		return "", err
+	}
+
+	return models.MemberRole(role), nil
+}
+
+// GetMembers attempts to retrieve all the members of the vampire, including its
+// owner.
+func (m *Repository) GetMembers(ctx context.Context, vampireID uuid.UUID) ([]models.Member, error) {
+	ctx, span := m.startSpan(ctx, "GetMembers")
+	defer span.End()
+
+	dbMembers, err := m.queries.GetVampireMembers(ctx, vampireID)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]models.Member, len(dbMembers))
+	for i, dbMember := range dbMembers {
+		members[i] = newMember(dbMember)
+	}
+
+	return members, nil
+}
+
+// RemoveMember attempts to remove the member from the vampire, returning who
+// they were without their email. The owner of a vampire cannot be removed.
+func (m *Repository) RemoveMember(ctx context.Context, vampireID, id uuid.UUID) (models.Member, error) {
+	ctx, span := m.startSpan(ctx, "RemoveMember")
+	defer span.End()
+
+	txRepo, tx, err := m.WithTx(ctx)
+	if err != nil {
+		return models.Member{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	dbMember, err := txRepo.queries.DeleteVampireMember(ctx, queries.DeleteVampireMemberParams{
+		ID:        id,
+		VampireID: vampireID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Member{}, models.ErrNotFound.Cause(err)
+	} else if err != nil {
+		return models.Member{}, err
+	}
+
+	changes := models.DiffAuditValues(map[string]interface{}{
+		"vampire_id": dbMember.VampireID,
+		"user_id":    dbMember.UserID,
+		"role":       dbMember.Role,
+	}, nil)
+	if err := txRepo.audit(ctx, models.AuditActionMemberRemoved, models.AuditTargetMember, id, changes); err != nil {
+		return models.Member{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.Member{}, err
+	}
+
+	return models.Member{
+		ID:        dbMember.ID,
+		VampireID: dbMember.VampireID,
+		UserID:    dbMember.UserID,
+		Role:      models.MemberRole(dbMember.Role),
+	}, nil
+}
+
+// InviteMember attempts to invite the user with the email address to become a
+// member of the vampire with the provided role.
+func (m *Repository) InviteMember(ctx context.Context, vampireID, invitedBy uuid.UUID, email string, role models.MemberRole) (models.Invitation, error) {
+	ctx, span := m.startSpan(ctx, "InviteMember")
+	defer span.End()
+
+	dbInvitation, err := m.queries.CreateVampireInvitation(ctx, queries.CreateVampireInvitationParams{
+		VampireID: vampireID,
+		InvitedBy: invitedBy,
+		Email:     email,
+		Role:      queries.MemberRole(role),
+	})
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgerrcode.UniqueViolation:
+			return models.Invitation{}, models.ErrAlreadyInvited.Cause(err)
+		case pgerrcode.CheckViolation, pgerrcode.InvalidTextRepresentation:
+			return models.Invitation{}, models.ErrInvalidRole.Cause(err)
+		case pgerrcode.ForeignKeyViolation:
+			return models.Invitation{}, models.ErrNotFound.Cause(err)
+		}
+
+		return models.Invitation{}, err
+	} else if err != nil {
+		return models.Invitation{}, err
+	}
+
+	return newInvitation(dbInvitation, ""), nil
+}
+
+// GetInvitations attempts to retrieve the pending invitations to the vampire.
+func (m *Repository) GetInvitations(ctx context.Context, vampireID uuid.UUID) ([]models.Invitation, error) {
+	ctx, span := m.startSpan(ctx, "GetInvitations")
+	defer span.End()
+
+	dbInvitations, err := m.queries.GetVampireInvitations(ctx, vampireID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations := make([]models.Invitation, len(dbInvitations))
+	for i, dbInvitation := range dbInvitations {
+		invitations[i] = newInvitation(dbInvitation, "")
+	}
+
+	return invitations, nil
+}
+
+// GetInvitationsForEmail attempts to retrieve the pending invitations sent to
+// the email address, including the name of each vampire.
+func (m *Repository) GetInvitationsForEmail(ctx context.Context, email string) ([]models.Invitation, error) {
+	ctx, span := m.startSpan(ctx, "GetInvitationsForEmail")
+	defer span.End()
+
+	dbInvitations, err := m.queries.GetInvitationsForEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations := make([]models.Invitation, len(dbInvitations))
+	for i, row := range dbInvitations {
+		invitations[i] = newInvitation(queries.VampireInvitation{
+			ID:        row.ID,
+			VampireID: row.VampireID,
+			Email:     row.Email,
+			Role:      row.Role,
+		}, row.VampireName)
+	}
+
+	return invitations, nil
+}
+
+// AcceptInvitation attempts to make the user a member of the vampire they were
+// invited to. The invitation must have been sent to the user's email address.
+// A user who is already a member keeps their existing role.
+func (m *Repository) AcceptInvitation(ctx context.Context, user models.User, id uuid.UUID) (models.Invitation, error) {
+	ctx, span := m.startSpan(ctx, "AcceptInvitation")
+	defer span.End()
+
+	txRepo, tx, err := m.WithTx(ctx)
+	if err != nil {
+		return models.Invitation{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	dbInvitation, err := txRepo.queries.DeleteInvitationForEmail(ctx, queries.DeleteInvitationForEmailParams{
+		ID:    id,
+		Email: user.Email,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Invitation{}, models.ErrNotFound.Cause(err)
+	} else if err != nil {
+		return models.Invitation{}, err
+	}
+
+	_, err = txRepo.queries.GetVampireMemberRole(ctx, queries.GetVampireMemberRoleParams{
+		VampireID: dbInvitation.VampireID,
+		UserID:    user.ID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		_, err = txRepo.queries.CreateVampireMember(ctx, queries.CreateVampireMemberParams{
+			VampireID: dbInvitation.VampireID,
+			UserID:    user.ID,
+			Role:      dbInvitation.Role,
+		})
+	}
+	if err != nil {
+		return models.Invitation{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.Invitation{}, err
+	}
+
+	return newInvitation(dbInvitation, ""), nil
+}
+
+// DeclineInvitation attempts to remove the invitation sent to the email
+// address without making the user a member.
+func (m *Repository) DeclineInvitation(ctx context.Context, email string, id uuid.UUID) error {
+	ctx, span := m.startSpan(ctx, "DeclineInvitation")
+	defer span.End()
+
+	_, err := m.queries.DeleteInvitationForEmail(ctx, queries.DeleteInvitationForEmailParams{
+		ID:    id,
+		Email: email,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.ErrNotFound.Cause(err)
+	}
+
+	return err
+}
+
+// RevokeInvitation attempts to remove a pending invitation to the vampire.
+func (m *Repository) RevokeInvitation(ctx context.Context, vampireID, id uuid.UUID) error {
+	ctx, span := m.startSpan(ctx, "RevokeInvitation")
+	defer span.End()
+
+	txRepo, tx, err := m.WithTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	dbInvitation, err := txRepo.queries.DeleteVampireInvitation(ctx, queries.DeleteVampireInvitationParams{
+		ID:        id,
+		VampireID: vampireID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.ErrNotFound.Cause(err)
+	} else if err != nil {
+		return err
+	}
+
+	changes := models.DiffAuditValues(map[string]interface{}{
+		"vampire_id": dbInvitation.VampireID,
+		"email":      dbInvitation.Email,
+		"role":       dbInvitation.Role,
+	}, nil)
+	if err := txRepo.audit(ctx, models.AuditActionInvitationRevoked, models.AuditTargetInvitation, id, changes); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
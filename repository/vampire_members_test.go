@@ -0,0 +1,377 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"emailaddress.horse/thousand/form"
+	"emailaddress.horse/thousand/models"
+	"emailaddress.horse/thousand/repository"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/uuid"
+)
+
+func TestVampireRolePermissions(t *testing.T) {
+	tests := []struct {
+		name        string
+		role        models.MemberRole
+		member      bool
+		canView     bool
+		canEdit     bool
+		canManage   bool
+		expectedErr error
+	}{
+		{
+			name:      "owner",
+			role:      models.RoleOwner,
+			member:    true,
+			canView:   true,
+			canEdit:   true,
+			canManage: true,
+		},
+		{
+			name:      "editor",
+			role:      models.RoleEditor,
+			member:    true,
+			canView:   true,
+			canEdit:   true,
+			canManage: false,
+		},
+		{
+			name:      "viewer",
+			role:      models.RoleViewer,
+			member:    true,
+			canView:   true,
+			canEdit:   false,
+			canManage: false,
+		},
+		{
+			name:        "not a member",
+			member:      false,
+			expectedErr: models.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestRepository(t)
+
+			owner, err := m.CreateUser(context.Background(), form.NewUser("owner@bannister.com", "password"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			vampire, err := m.CreateVampire(context.Background(), owner.ID, "test vampire")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			user := owner
+			if tt.role != models.RoleOwner {
+				user, err = m.CreateUser(context.Background(), form.NewUser("member@bannister.com", "password"))
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if tt.member && tt.role != models.RoleOwner {
+				invitation, err := m.InviteMember(context.Background(), vampire.ID, owner.ID, user.Email, tt.role)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if _, err := m.AcceptInvitation(context.Background(), user, invitation.ID); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			role, err := m.GetVampireRole(context.Background(), vampire.ID, user.ID)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected %q; received %q", tt.expectedErr, err)
+			}
+
+			if tt.member && role != tt.role {
+				t.Errorf("expected role %q; got %q", tt.role, role)
+			}
+
+			if canView := role.Allows(models.RoleViewer); canView != tt.canView {
+				t.Errorf("expected can view %t; got %t", tt.canView, canView)
+			}
+
+			if canEdit := role.CanEdit(); canEdit != tt.canEdit {
+				t.Errorf("expected can edit %t; got %t", tt.canEdit, canEdit)
+			}
+
+			if canManage := role.CanManage(); canManage != tt.canManage {
+				t.Errorf("expected can manage %t; got %t", tt.canManage, canManage)
+			}
+		})
+	}
+}
+
+func TestGetSharedVampires(t *testing.T) {
+	m := newTestRepository(t)
+
+	owner, err := m.CreateUser(context.Background(), form.NewUser("owner@bannister.com", "password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	member, err := m.CreateUser(context.Background(), form.NewUser("member@bannister.com", "password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ownedVampire, err := m.CreateVampire(context.Background(), member.ID, "owned vampire")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sharedVampire, err := m.CreateVampire(context.Background(), owner.ID, "shared vampire")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.CreateVampire(context.Background(), owner.ID, "private vampire"); err != nil {
+		t.Fatal(err)
+	}
+
+	invitation, err := m.InviteMember(context.Background(), sharedVampire.ID, owner.ID, "Member@Bannister.com", models.RoleViewer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	invitations, err := m.GetInvitationsForEmail(context.Background(), member.Email)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedInvitations := []models.Invitation{
+		{
+			ID:          invitation.ID,
+			VampireID:   sharedVampire.ID,
+			VampireName: "shared vampire",
+			Email:       "member@bannister.com",
+			Role:        models.RoleViewer,
+		},
+	}
+
+	if diff := cmp.Diff(expectedInvitations, invitations); diff != "" {
+		t.Error(diff)
+	}
+
+	if _, err := m.AcceptInvitation(context.Background(), member, invitation.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	vampires, err := m.GetVampires(context.Background(), member.ID, models.VampireQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(vampires.Vampires) != 1 || vampires.Vampires[0].ID != ownedVampire.ID {
+		t.Errorf("expected only %q to be owned; got %v", ownedVampire.ID, vampires.Vampires)
+	}
+
+	sharedVampires, err := m.GetSharedVampires(context.Background(), member.ID, models.VampireQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sharedVampires.Vampires) != 1 || sharedVampires.Vampires[0].ID != sharedVampire.ID {
+		t.Errorf("expected only %q to be shared; got %v", sharedVampire.ID, sharedVampires.Vampires)
+	}
+}
+
+func TestInviteMember(t *testing.T) {
+	tests := []struct {
+		name          string
+		role          models.MemberRole
+		preInvited    bool
+		expectedError error
+	}{
+		{
+			name:          "successful",
+			role:          models.RoleEditor,
+			expectedError: nil,
+		},
+		{
+			name:          "already invited",
+			role:          models.RoleEditor,
+			preInvited:    true,
+			expectedError: models.ErrAlreadyInvited,
+		},
+		{
+			name:          "owner role",
+			role:          models.RoleOwner,
+			expectedError: models.ErrInvalidRole,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestRepository(t)
+			userID := m.UserID()
+
+			vampire, err := m.CreateVampire(context.Background(), userID, "test vampire")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.preInvited {
+				if _, err := m.InviteMember(context.Background(), vampire.ID, userID, "jane@bannister.com", models.RoleViewer); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err = m.WithSavepoint(func(m *repository.Repository) error {
+				_, err := m.InviteMember(context.Background(), vampire.ID, userID, "jane@bannister.com", tt.role)
+				return err
+			})
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected %q; received %q", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestAcceptInvitation(t *testing.T) {
+	tests := []struct {
+		name          string
+		email         string
+		expectedError error
+	}{
+		{
+			name:          "successful",
+			email:         "jane@bannister.com",
+			expectedError: nil,
+		},
+		{
+			name:          "invitation for another email",
+			email:         "john@bannister.com",
+			expectedError: models.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestRepository(t)
+			ownerID := m.UserID()
+
+			vampire, err := m.CreateVampire(context.Background(), ownerID, "test vampire")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			invitation, err := m.InviteMember(context.Background(), vampire.ID, ownerID, "jane@bannister.com", models.RoleEditor)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			user, err := m.CreateUser(context.Background(), form.NewUser(tt.email, "password"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = m.AcceptInvitation(context.Background(), user, invitation.ID)
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected %q; received %q", tt.expectedError, err)
+			}
+
+			members, err := m.GetMembers(context.Background(), vampire.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expectedMembers := []models.Member{
+				{VampireID: vampire.ID, UserID: ownerID, Role: models.RoleOwner},
+			}
+			if tt.expectedError == nil {
+				expectedMembers = append(expectedMembers, models.Member{
+					VampireID: vampire.ID,
+					UserID:    user.ID,
+					Email:     user.Email,
+					Role:      models.RoleEditor,
+				})
+			}
+
+			if diff := cmp.Diff(expectedMembers, members, cmpopts.IgnoreFields(models.Member{}, "ID")); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestRemoveMember(t *testing.T) {
+	tests := []struct {
+		name          string
+		id            func(owner, editor models.Member) uuid.UUID
+		expectedError error
+	}{
+		{
+			name:          "successful",
+			id:            func(owner, editor models.Member) uuid.UUID { return editor.ID },
+			expectedError: nil,
+		},
+		{
+			name:          "owner cannot be removed",
+			id:            func(owner, editor models.Member) uuid.UUID { return owner.ID },
+			expectedError: models.ErrNotFound,
+		},
+		{
+			name:          "not found",
+			id:            func(owner, editor models.Member) uuid.UUID { return uuid.New() },
+			expectedError: models.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestRepository(t)
+			ownerID := m.UserID()
+
+			vampire, err := m.CreateVampire(context.Background(), ownerID, "test vampire")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			editor, err := m.CreateUser(context.Background(), form.NewUser("jane@bannister.com", "password"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			invitation, err := m.InviteMember(context.Background(), vampire.ID, ownerID, editor.Email, models.RoleEditor)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := m.AcceptInvitation(context.Background(), editor, invitation.ID); err != nil {
+				t.Fatal(err)
+			}
+
+			members, err := m.GetMembers(context.Background(), vampire.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			member, err := m.RemoveMember(context.Background(), vampire.ID, tt.id(members[0], members[1]))
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected %q; received %q", tt.expectedError, err)
+			}
+
+			if err == nil && member.UserID != editor.ID {
+				t.Errorf("expected removed member to be %q; got %q", editor.ID, member.UserID)
+			}
+		})
+	}
+}
@@ -3,22 +3,42 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"emailaddress.horse/thousand/models"
 	"emailaddress.horse/thousand/repository/queries"
 	"github.com/google/uuid"
+	"github.com/jackc/pgtype"
 	"github.com/jackc/pgx/v4"
 )
 
 // CreateVampire attempts to create a new vampire in the DB with the provided
-// name.
+// name, owned by the user.
 func (m *Repository) CreateVampire(ctx context.Context, userID uuid.UUID, name string) (models.Vampire, error) {
+	ctx, span := m.startSpan(ctx, "CreateVampire")
+	defer span.End()
+
+	txRepo, tx, err := m.WithTx(ctx)
+	if err != nil {
+		return models.Vampire{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
 	params := queries.CreateVampireParams{
 		Name:   name,
 		UserID: userID,
 	}
 
-	v, err := m.queries.CreateVampire(ctx, params)
+	v, err := txRepo.queries.CreateVampire(ctx, params)
+	if err != nil {
+		return models.Vampire{}, err
+	}
+
+	_, err = txRepo.queries.CreateVampireMember(ctx, queries.CreateVampireMemberParams{
+		VampireID: v.ID,
+		UserID:    userID,
+		Role:      queries.MemberRoleOwner,
+	})
 	if err != nil {
 		return models.Vampire{}, err
 	}
@@ -28,11 +48,15 @@ func (m *Repository) CreateVampire(ctx context.Context, userID uuid.UUID, name s
 		createMemoriesParams[i] = v.ID
 	}
 
-	dbMemories, err := m.queries.CreateMemories(ctx, createMemoriesParams)
+	dbMemories, err := txRepo.queries.CreateMemories(ctx, createMemoriesParams)
 	if err != nil {
 		return models.Vampire{}, err
 	}
 
+	if err := tx.Commit(ctx); err != nil {
+		return models.Vampire{}, err
+	}
+
 	memories := make([]models.Memory, len(dbMemories))
 	for i, dbMemory := range dbMemories {
 		memories[i] = newMemory(dbMemory, make([]queries.Experience, 0, 3))
@@ -42,7 +66,86 @@ func (m *Repository) CreateVampire(ctx context.Context, userID uuid.UUID, name s
 }
 
 // GetVampire attempts to retrieve a vampire from the DB with the provided ID.
+// Everything belonging to the vampire is aggregated into JSON by the database
+// so that it can be loaded in a single query.
 func (m *Repository) GetVampire(ctx context.Context, id uuid.UUID) (models.Vampire, error) {
+	ctx, span := m.startSpan(ctx, "GetVampire")
+	defer span.End()
+
+	row, err := m.queries.GetVampireDetails(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Vampire{}, models.ErrNotFound.Cause(err)
+	} else if err != nil {
+		return models.Vampire{}, err
+	}
+
+	var details struct {
+		memories   []memoryDetails
+		skills     []queries.Skill
+		resources  []queries.Resource
+		characters []queries.Character
+		marks      []queries.Mark
+	}
+
+	for _, column := range []struct {
+		json pgtype.JSON
+		dst  interface{}
+	}{
+		{row.Memories, &details.memories},
+		{row.Skills, &details.skills},
+		{row.Resources, &details.resources},
+		{row.Characters, &details.characters},
+		{row.Marks, &details.marks},
+	} {
+		if err := column.json.AssignTo(column.dst); err != nil {
+			return models.Vampire{}, fmt.Errorf("error decoding vampire details: %w", err)
+		}
+	}
+
+	memories := make([]models.Memory, len(details.memories))
+	for i, dbMemory := range details.memories {
+		memories[i] = newMemory(dbMemory.Memory, dbMemory.Experiences)
+	}
+
+	skills := make([]models.Skill, len(details.skills))
+	for i, dbSkill := range details.skills {
+		skills[i] = newSkill(dbSkill)
+	}
+
+	resources := make([]models.Resource, len(details.resources))
+	for i, dbResource := range details.resources {
+		resources[i] = newResource(dbResource)
+	}
+
+	characters := make([]models.Character, len(details.characters))
+	for i, dbCharacter := range details.characters {
+		characters[i] = newCharacter(dbCharacter)
+	}
+
+	marks := make([]models.Mark, len(details.marks))
+	for i, dbMark := range details.marks {
+		marks[i] = newMark(dbMark)
+	}
+
+	vampire := queries.Vampire{
+		ID:         row.ID,
+		Name:       row.Name,
+		ArchivedAt: row.ArchivedAt,
+	}
+
+	return newVampire(vampire, memories, skills, resources, characters, marks), nil
+}
+
+// memoryDetails is a memory as aggregated by GetVampireDetails, with its
+// experiences nested within it.
+type memoryDetails struct {
+	queries.Memory
+	Experiences []queries.Experience
+}
+
+// getVampireSequentially retrieves a vampire with a query for each of the
+// things belonging to it. It is kept to benchmark GetVampire against.
+func (m *Repository) getVampireSequentially(ctx context.Context, id uuid.UUID) (models.Vampire, error) {
 	v, err := m.queries.GetVampire(ctx, id)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return models.Vampire{}, models.ErrNotFound.Cause(err)
@@ -60,17 +163,14 @@ func (m *Repository) GetVampire(ctx context.Context, id uuid.UUID) (models.Vampi
 		return models.Vampire{}, err
 	}
 
+	experiencesByMemory := make(map[uuid.UUID][]queries.Experience, len(dbMemories))
+	for _, experience := range dbExperiences {
+		experiencesByMemory[experience.MemoryID] = append(experiencesByMemory[experience.MemoryID], experience)
+	}
+
 	memories := make([]models.Memory, len(dbMemories))
 	for i, dbMemory := range dbMemories {
-		experiences := make([]queries.Experience, 0, 3)
-
-		for _, experience := range dbExperiences {
-			if experience.MemoryID == dbMemory.ID {
-				experiences = append(experiences, experience)
-			}
-		}
-
-		memories[i] = newMemory(dbMemory, experiences)
+		memories[i] = newMemory(dbMemory, experiencesByMemory[dbMemory.ID])
 	}
 
 	dbSkills, err := m.queries.GetSkillsForVampire(ctx, id)
@@ -116,17 +216,343 @@ func (m *Repository) GetVampire(ctx context.Context, id uuid.UUID) (models.Vampi
 	return newVampire(v, memories, skills, resources, characters, marks), nil
 }
 
-// GetVampires attempts to retrieve all the vampires from the DB.
-func (m *Repository) GetVampires(ctx context.Context) ([]models.Vampire, error) {
-	vs, err := m.queries.GetVampires(ctx)
-	if err != nil {
-		return []models.Vampire{}, err
+// GetVampires attempts to retrieve a page of the vampires owned by the user.
+func (m *Repository) GetVampires(ctx context.Context, userID uuid.UUID, q models.VampireQuery) (models.VampirePage, error) {
+	ctx, span := m.startSpan(ctx, "GetVampires")
+	defer span.End()
+
+	return m.getVampirePage(ctx, userID, []queries.MemberRole{queries.MemberRoleOwner}, q)
+}
+
+// GetSharedVampires attempts to retrieve a page of the vampires the user is
+// an editor or viewer of.
+func (m *Repository) GetSharedVampires(ctx context.Context, userID uuid.UUID, q models.VampireQuery) (models.VampirePage, error) {
+	ctx, span := m.startSpan(ctx, "GetSharedVampires")
+	defer span.End()
+
+	return m.getVampirePage(ctx, userID, []queries.MemberRole{queries.MemberRoleEditor, queries.MemberRoleViewer}, q)
+}
+
+// getVampirePage lists the vampires the user has one of the roles for. One
+// more vampire than fits on the page is fetched to find out whether there is a
+// next page.
+func (m *Repository) getVampirePage(ctx context.Context, userID uuid.UUID, roles []queries.MemberRole, q models.VampireQuery) (models.VampirePage, error) {
+	q = q.Normalize()
+
+	var after vampireCursor
+	if q.After != "" {
+		var err error
+		after, err = decodeVampireCursor(q.After, q.Sort)
+		if err != nil {
+			return models.VampirePage{}, err
+		}
+	}
+
+	roleNames := make([]string, len(roles))
+	for i, role := range roles {
+		roleNames[i] = string(role)
 	}
 
+	var vs []queries.Vampire
+	var cursors []vampireCursor
+
+	switch q.Sort {
+	case models.VampireSortName:
+		rows, err := m.queries.GetVampiresForMemberByName(ctx, queries.GetVampiresForMemberByNameParams{
+			UserID:    userID,
+			Roles:     roleNames,
+			Paginate:  q.After != "",
+			AfterName: after.Name,
+			AfterID:   after.ID,
+			Search:    q.Search,
+			PageSize:  int32(q.Limit + 1),
+		})
+		if err != nil {
+			return models.VampirePage{}, err
+		}
+
+		for _, row := range rows {
+			vs = append(vs, row)
+			cursors = append(cursors, vampireCursor{Sort: q.Sort, Name: row.Name, ID: row.ID})
+		}
+	case models.VampireSortCreated:
+		rows, err := m.queries.GetVampiresForMemberByCreated(ctx, queries.GetVampiresForMemberByCreatedParams{
+			UserID:         userID,
+			Roles:          roleNames,
+			Paginate:       q.After != "",
+			AfterCreatedAt: after.Time,
+			AfterID:        after.ID,
+			Search:         q.Search,
+			PageSize:       int32(q.Limit + 1),
+		})
+		if err != nil {
+			return models.VampirePage{}, err
+		}
+
+		for _, row := range rows {
+			vs = append(vs, row)
+			cursors = append(cursors, vampireCursor{Sort: q.Sort, Time: row.CreatedAt, ID: row.ID})
+		}
+	case models.VampireSortPlayed:
+		rows, err := m.queries.GetVampiresForMemberByPlayed(ctx, queries.GetVampiresForMemberByPlayedParams{
+			UserID:        userID,
+			Roles:         roleNames,
+			Paginate:      q.After != "",
+			AfterPlayedAt: after.Time,
+			AfterID:       after.ID,
+			Search:        q.Search,
+			PageSize:      int32(q.Limit + 1),
+		})
+		if err != nil {
+			return models.VampirePage{}, err
+		}
+
+		for _, row := range rows {
+			vs = append(vs, queries.Vampire{
+				ID:        row.ID,
+				Name:      row.Name,
+				CreatedAt: row.CreatedAt,
+				UpdatedAt: row.UpdatedAt,
+				UserID:    row.UserID,
+			})
+			cursors = append(cursors, vampireCursor{Sort: q.Sort, Time: row.PlayedAt, ID: row.ID})
+		}
+	default:
+		return models.VampirePage{}, fmt.Errorf("unknown sort order: %q", q.Sort)
+	}
+
+	page := models.VampirePage{}
+	if len(vs) > q.Limit {
+		vs = vs[:q.Limit]
+		page.Next = cursors[q.Limit-1].encode()
+	}
+	page.Vampires = newVampireSummaries(vs)
+
+	return page, nil
+}
+
+func newVampireSummaries(vs []queries.Vampire) []models.Vampire {
 	nvs := make([]models.Vampire, len(vs))
 	for i, v := range vs {
 		nvs[i] = newVampire(v, []models.Memory{}, []models.Skill{}, []models.Resource{}, []models.Character{}, []models.Mark{})
 	}
 
-	return nvs, nil
+	return nvs
+}
+
+// TrashVampire moves the vampire to the trash, hiding it until it is restored
+// or purged.
+func (m *Repository) TrashVampire(ctx context.Context, id uuid.UUID) error {
+	ctx, span := m.startSpan(ctx, "TrashVampire")
+	defer span.End()
+
+	txRepo, tx, err := m.WithTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	v, err := txRepo.queries.TrashVampire(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.ErrNotFound.Cause(err)
+	} else if err != nil {
+		return err
+	}
+
+	changes := models.DiffAuditValues(
+		map[string]interface{}{"deleted_at": nil},
+		map[string]interface{}{"deleted_at": v.DeletedAt.Time},
+	)
+	if err := txRepo.audit(ctx, models.AuditActionVampireTrashed, models.AuditTargetVampire, id, changes); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetTrashedVampires retrieves the vampires owned by the user which are in the
+// trash and can still be restored, most recently trashed first.
+func (m *Repository) GetTrashedVampires(ctx context.Context, userID uuid.UUID) ([]models.TrashedVampire, error) {
+	ctx, span := m.startSpan(ctx, "GetTrashedVampires")
+	defer span.End()
+
+	vs, err := m.queries.GetTrashedVampires(ctx, queries.GetTrashedVampiresParams{
+		UserID:           userID,
+		RetentionSeconds: m.retentionSeconds(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	trashed := make([]models.TrashedVampire, len(vs))
+	for i, v := range vs {
+		trashed[i] = models.TrashedVampire{
+			Vampire:   newVampire(v, []models.Memory{}, []models.Skill{}, []models.Resource{}, []models.Character{}, []models.Mark{}),
+			DeletedAt: v.DeletedAt.Time,
+			PurgeAt:   v.DeletedAt.Time.Add(m.trashRetention),
+		}
+	}
+
+	return trashed, nil
+}
+
+// RestoreTrashedVampire takes the vampire out of the trash. Only its owner can
+// restore it, and only until it is due to be purged.
+func (m *Repository) RestoreTrashedVampire(ctx context.Context, userID, id uuid.UUID) error {
+	ctx, span := m.startSpan(ctx, "RestoreTrashedVampire")
+	defer span.End()
+
+	txRepo, tx, err := m.WithTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	before, err := txRepo.queries.GetVampire(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.ErrNotFound.Cause(err)
+	} else if err != nil {
+		return err
+	}
+
+	_, err = txRepo.queries.RestoreTrashedVampire(ctx, queries.RestoreTrashedVampireParams{
+		ID:               id,
+		UserID:           userID,
+		RetentionSeconds: m.retentionSeconds(),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.ErrNotFound.Cause(err)
+	} else if err != nil {
+		return err
+	}
+
+	changes := models.DiffAuditValues(
+		map[string]interface{}{"deleted_at": before.DeletedAt.Time},
+		map[string]interface{}{"deleted_at": nil},
+	)
+	if err := txRepo.audit(ctx, models.AuditActionVampireRestored, models.AuditTargetVampire, id, changes); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ArchiveVampire puts the vampire away in the archive, hiding it from the
+// lists of vampires. Unlike the trash, archived vampires are never purged.
+func (m *Repository) ArchiveVampire(ctx context.Context, id uuid.UUID) error {
+	ctx, span := m.startSpan(ctx, "ArchiveVampire")
+	defer span.End()
+
+	txRepo, tx, err := m.WithTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	v, err := txRepo.queries.ArchiveVampire(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.ErrNotFound.Cause(err)
+	} else if err != nil {
+		return err
+	}
+
+	changes := models.DiffAuditValues(
+		map[string]interface{}{"archived_at": nil},
+		map[string]interface{}{"archived_at": v.ArchivedAt.Time},
+	)
+	if err := txRepo.audit(ctx, models.AuditActionVampireArchived, models.AuditTargetVampire, id, changes); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// UnarchiveVampire takes the vampire out of the archive, listing it again.
+func (m *Repository) UnarchiveVampire(ctx context.Context, id uuid.UUID) error {
+	ctx, span := m.startSpan(ctx, "UnarchiveVampire")
+	defer span.End()
+
+	txRepo, tx, err := m.WithTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	before, err := txRepo.queries.GetVampire(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.ErrNotFound.Cause(err)
+	} else if err != nil {
+		return err
+	}
+
+	_, err = txRepo.queries.UnarchiveVampire(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.ErrNotFound.Cause(err)
+	} else if err != nil {
+		return err
+	}
+
+	changes := models.DiffAuditValues(
+		map[string]interface{}{"archived_at": before.ArchivedAt.Time},
+		map[string]interface{}{"archived_at": nil},
+	)
+	if err := txRepo.audit(ctx, models.AuditActionVampireUnarchived, models.AuditTargetVampire, id, changes); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetArchivedVampires retrieves the vampires owned by the user which are in the
+// archive, most recently archived first.
+func (m *Repository) GetArchivedVampires(ctx context.Context, userID uuid.UUID) ([]models.Vampire, error) {
+	ctx, span := m.startSpan(ctx, "GetArchivedVampires")
+	defer span.End()
+
+	vs, err := m.queries.GetArchivedVampires(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newVampireSummaries(vs), nil
+}
+
+// PurgeTrashedVampires permanently deletes the vampires which have been in the
+// trash for longer than the retention period, along with everything belonging
+// to them. It returns how many vampires were deleted.
+func (m *Repository) PurgeTrashedVampires(ctx context.Context) (int64, error) {
+	ctx, span := m.startSpan(ctx, "PurgeTrashedVampires")
+	defer span.End()
+
+	txRepo, tx, err := m.WithTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	vs, err := txRepo.queries.PurgeTrashedVampires(ctx, m.retentionSeconds())
+	if err != nil {
+		return 0, err
+	}
+
+	for _, v := range vs {
+		changes := models.DiffAuditValues(map[string]interface{}{
+			"name":       v.Name,
+			"user_id":    v.UserID.UUID,
+			"deleted_at": v.DeletedAt.Time,
+		}, nil)
+		if err := txRepo.audit(ctx, models.AuditActionVampirePurged, models.AuditTargetVampire, v.ID, changes); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return int64(len(vs)), nil
+}
+
+func (m *Repository) retentionSeconds() int32 {
+	return int32(m.trashRetention.Seconds())
 }
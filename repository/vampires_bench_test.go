@@ -0,0 +1,117 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"emailaddress.horse/thousand/models"
+	"emailaddress.horse/thousand/repository"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+// seedVampire creates a vampire with full memories and size of each other
+// thing a vampire can have.
+func seedVampire(tb testing.TB, m testRepository, size int) models.Vampire {
+	tb.Helper()
+
+	ctx := context.Background()
+
+	vampire, err := m.CreateVampire(ctx, m.UserID(), "seeded vampire")
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	for _, memory := range vampire.Memories {
+		for i := 0; i < 3; i++ {
+			if _, err := m.CreateExperience(ctx, vampire.ID, memory.ID, fmt.Sprintf("experience %d", i)); err != nil {
+				tb.Fatal(err)
+			}
+		}
+	}
+
+	for i := 0; i < size; i++ {
+		if _, err := m.CreateSkill(ctx, vampire.ID, fmt.Sprintf("skill %d", i)); err != nil {
+			tb.Fatal(err)
+		}
+
+		if _, err := m.CreateResource(ctx, vampire.ID, models.CreateResourceParams{
+			Description: fmt.Sprintf("resource %d", i),
+			Stationary:  i%2 == 0,
+		}); err != nil {
+			tb.Fatal(err)
+		}
+
+		characterType := "mortal"
+		if i%2 == 0 {
+			characterType = "immortal"
+		}
+
+		if _, err := m.CreateCharacter(ctx, vampire.ID, models.CreateCharacterParams{
+			Name: fmt.Sprintf("character %d", i),
+			Type: characterType,
+		}); err != nil {
+			tb.Fatal(err)
+		}
+
+		if _, err := m.CreateMark(ctx, vampire.ID, fmt.Sprintf("mark %d", i)); err != nil {
+			tb.Fatal(err)
+		}
+	}
+
+	return vampire
+}
+
+func TestGetVampire_MatchesSequential(t *testing.T) {
+	m := newTestRepository(t)
+
+	vampire := seedVampire(t, m, 5)
+
+	expected, err := repository.GetVampireSequentially(m.Repository, context.Background(), vampire.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := m.GetVampire(context.Background(), vampire.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func BenchmarkGetVampire(b *testing.B) {
+	paths := []struct {
+		name       string
+		getVampire func(*repository.Repository, context.Context, uuid.UUID) (models.Vampire, error)
+	}{
+		{
+			name:       "single query",
+			getVampire: (*repository.Repository).GetVampire,
+		},
+		{
+			name:       "sequential",
+			getVampire: repository.GetVampireSequentially,
+		},
+	}
+
+	for _, size := range []int{10, 100, 1000} {
+		m := newTestRepository(b)
+		vampire := seedVampire(b, m, size)
+
+		for _, path := range paths {
+			path := path
+
+			b.Run(fmt.Sprintf("%s/size=%d", path.name, size), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, err := path.getVampire(m.Repository, context.Background(), vampire.ID); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"emailaddress.horse/thousand/models"
 	"emailaddress.horse/thousand/repository"
@@ -100,3 +101,353 @@ func TestGetVampire(t *testing.T) {
 		})
 	}
 }
+
+func TestGetVampires(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         models.VampireQuery
+		expectedPages [][]string
+		expectedError error
+	}{
+		{
+			name:  "by name",
+			query: models.VampireQuery{Sort: models.VampireSortName, Limit: 2},
+			expectedPages: [][]string{
+				{"Aurelia", "Bartholomew"},
+				{"Cassius", "Drusilla"},
+			},
+		},
+		{
+			name:  "by created",
+			query: models.VampireQuery{Sort: models.VampireSortCreated, Limit: 3},
+			expectedPages: [][]string{
+				{"Cassius", "Aurelia", "Drusilla"},
+				{"Bartholomew"},
+			},
+		},
+		{
+			name:  "by played",
+			query: models.VampireQuery{Sort: models.VampireSortPlayed, Limit: 2},
+			expectedPages: [][]string{
+				{"Bartholomew", "Cassius"},
+				{"Aurelia", "Drusilla"},
+			},
+		},
+		{
+			name:  "search by name",
+			query: models.VampireQuery{Sort: models.VampireSortName, Search: "drusilla"},
+			expectedPages: [][]string{
+				{"Drusilla"},
+			},
+		},
+		{
+			name:  "search by experience",
+			query: models.VampireQuery{Sort: models.VampireSortName, Search: "shipwrecked"},
+			expectedPages: [][]string{
+				{"Bartholomew"},
+			},
+		},
+		{
+			name:          "invalid cursor",
+			query:         models.VampireQuery{Sort: models.VampireSortName, After: "nonsense"},
+			expectedError: models.ErrInvalidCursor,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestRepository(t)
+			userID := m.UserID()
+
+			// Vampires are backdated a second apart, in this order, so that they
+			// can be told apart by when they were created
+			names := []string{"Bartholomew", "Drusilla", "Aurelia", "Cassius"}
+			var bartholomew models.Vampire
+			for i, name := range names {
+				vampire, err := m.CreateVampire(context.Background(), userID, name)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if name == "Bartholomew" {
+					bartholomew = vampire
+				}
+
+				if _, err := m.tx.Exec(context.Background(), "UPDATE vampires SET created_at = created_at - make_interval(secs => $1) WHERE id = $2", len(names)-i, vampire.ID); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if _, err := m.CreateExperience(context.Background(), bartholomew.ID, bartholomew.Memories[0].ID, "I was shipwrecked off the coast of Wales."); err != nil {
+				t.Fatal(err)
+			}
+
+			var pages [][]string
+			query := tt.query
+			for {
+				page, err := m.GetVampires(context.Background(), userID, query)
+				if !errors.Is(err, tt.expectedError) {
+					t.Fatalf("expected %q; received %q", tt.expectedError, err)
+				}
+
+				if err != nil {
+					return
+				}
+
+				names := make([]string, len(page.Vampires))
+				for i, vampire := range page.Vampires {
+					names[i] = vampire.Name
+				}
+				pages = append(pages, names)
+
+				if page.Next == "" {
+					break
+				}
+				query.After = page.Next
+			}
+
+			if diff := cmp.Diff(tt.expectedPages, pages); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestTrashVampire(t *testing.T) {
+	m := newTestRepository(t)
+	userID := m.UserID()
+
+	vampire, err := m.CreateVampire(context.Background(), userID, "Gruffudd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.TrashVampire(context.Background(), vampire.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.GetVampire(context.Background(), vampire.ID); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("expected %q getting trashed vampire; received %q", models.ErrNotFound, err)
+	}
+
+	page, err := m.GetVampires(context.Background(), userID, models.VampireQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Vampires) != 0 {
+		t.Errorf("expected trashed vampire to be hidden; got %d vampires", len(page.Vampires))
+	}
+
+	if err := m.TrashVampire(context.Background(), vampire.ID); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("expected %q trashing twice; received %q", models.ErrNotFound, err)
+	}
+
+	trashed, err := m.GetTrashedVampires(context.Background(), userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trashed) != 1 || trashed[0].ID != vampire.ID {
+		t.Fatalf("expected trash to hold %s; got %v", vampire.ID, trashed)
+	}
+	if got := trashed[0].PurgeAt.Sub(trashed[0].DeletedAt); got != models.DefaultTrashRetention {
+		t.Errorf("expected to be purged after %s; got %s", models.DefaultTrashRetention, got)
+	}
+}
+
+func TestArchiveVampire(t *testing.T) {
+	m := newTestRepository(t)
+	userID := m.UserID()
+
+	vampire, err := m.CreateVampire(context.Background(), userID, "Gruffudd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.ArchiveVampire(context.Background(), vampire.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	archived, err := m.GetVampire(context.Background(), vampire.ID)
+	if err != nil {
+		t.Fatalf("expected archived vampire to still be found; received %q", err)
+	}
+	if !archived.Archived() {
+		t.Error("expected vampire to be archived")
+	}
+
+	page, err := m.GetVampires(context.Background(), userID, models.VampireQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Vampires) != 0 {
+		t.Errorf("expected archived vampire to be hidden; got %d vampires", len(page.Vampires))
+	}
+
+	if err := m.ArchiveVampire(context.Background(), vampire.ID); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("expected %q archiving twice; received %q", models.ErrNotFound, err)
+	}
+
+	vampires, err := m.GetArchivedVampires(context.Background(), userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vampires) != 1 || vampires[0].ID != vampire.ID {
+		t.Fatalf("expected archive to hold %s; got %v", vampire.ID, vampires)
+	}
+
+	// Archived vampires are never purged, however long ago they were archived
+	purged, err := repository.WithTrashRetention(m.Repository, time.Nanosecond).PurgeTrashedVampires(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if purged != 0 {
+		t.Errorf("expected archived vampire not to be purged; purged %d", purged)
+	}
+
+	if err := m.UnarchiveVampire(context.Background(), vampire.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	page, err = m.GetVampires(context.Background(), userID, models.VampireQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Vampires) != 1 {
+		t.Errorf("expected unarchived vampire to be listed; got %d vampires", len(page.Vampires))
+	}
+
+	if err := m.UnarchiveVampire(context.Background(), vampire.ID); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("expected %q unarchiving twice; received %q", models.ErrNotFound, err)
+	}
+}
+
+func TestRestoreTrashedVampire(t *testing.T) {
+	tests := []struct {
+		name          string
+		userID        func(owner, other uuid.UUID) uuid.UUID
+		retention     time.Duration
+		expectedError error
+	}{
+		{
+			name:          "successful",
+			userID:        func(owner, _ uuid.UUID) uuid.UUID { return owner },
+			expectedError: nil,
+		},
+		{
+			name:          "not owner",
+			userID:        func(_, other uuid.UUID) uuid.UUID { return other },
+			expectedError: models.ErrNotFound,
+		},
+		{
+			name:          "retention passed",
+			userID:        func(owner, _ uuid.UUID) uuid.UUID { return owner },
+			retention:     -time.Second,
+			expectedError: models.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestRepository(t)
+			owner, other := m.UserID(), m.UserID()
+
+			vampire, err := m.CreateVampire(context.Background(), owner, "Gruffudd")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := m.TrashVampire(context.Background(), vampire.ID); err != nil {
+				t.Fatal(err)
+			}
+
+			repo := m.Repository
+			if tt.retention != 0 {
+				repo = repository.WithTrashRetention(repo, tt.retention)
+			}
+
+			err = repo.RestoreTrashedVampire(context.Background(), tt.userID(owner, other), vampire.ID)
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected %q; received %q", tt.expectedError, err)
+			}
+
+			_, err = m.GetVampire(context.Background(), vampire.ID)
+			if tt.expectedError == nil && err != nil {
+				t.Errorf("expected restored vampire; received %q", err)
+			} else if tt.expectedError != nil && !errors.Is(err, models.ErrNotFound) {
+				t.Errorf("expected vampire to stay trashed; received %v", err)
+			}
+		})
+	}
+}
+
+func TestPurgeTrashedVampires(t *testing.T) {
+	m := newTestRepository(t)
+	userID := m.UserID()
+
+	kept, err := m.CreateVampire(context.Background(), userID, "Kept")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	purged, err := m.CreateVampire(context.Background(), userID, "Purged")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Give the vampire something in every table which refers to it, so that
+	// purging fails unless each one cascades
+	if _, err := m.CreateExperience(context.Background(), purged.ID, purged.Memories[0].ID, "an experience"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.CreateSkill(context.Background(), purged.ID, "a skill"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.CreateResource(context.Background(), purged.ID, models.CreateResourceParams{Description: "a resource"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.CreateCharacter(context.Background(), purged.ID, models.CreateCharacterParams{Name: "a character", Type: "mortal"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.CreateMark(context.Background(), purged.ID, "a mark"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.CreateShareLink(context.Background(), purged.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.TrashVampire(context.Background(), purged.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := m.PurgeTrashedVampires(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("expected nothing to be purged within the retention period; purged %d", count)
+	}
+
+	count, err = repository.WithTrashRetention(m.Repository, -time.Second).PurgeTrashedVampires(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 vampire to be purged; purged %d", count)
+	}
+
+	trashed, err := m.GetTrashedVampires(context.Background(), userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trashed) != 0 {
+		t.Errorf("expected trash to be empty; got %d vampires", len(trashed))
+	}
+
+	if _, err := m.GetVampire(context.Background(), kept.ID); err != nil {
+		t.Errorf("expected untrashed vampire to be kept; received %q", err)
+	}
+}
@@ -0,0 +1,165 @@
+package seed
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+
+	"emailaddress.horse/thousand/models"
+)
+
+// experiencesPerMemory is how many experiences fit in a memory before it is
+// full. See models.Memory.Full.
+const experiencesPerMemory = 3
+
+// User is a user to seed along with their vampires.
+type User struct {
+	Email    string
+	Vampires []Vampire
+}
+
+// Vampire is a vampire to seed. Memories holds the descriptions of the
+// experiences in each memory, in order.
+type Vampire struct {
+	Name       string
+	Memories   [][]string
+	Skills     []string
+	Resources  []models.CreateResourceParams
+	Characters []models.CreateCharacterParams
+	Marks      []string
+}
+
+// Generate describes the users and vampires to seed. The same options always
+// generate the same data, and each user is generated independently of the
+// others so that skipping one doesn't change the rest.
+func Generate(opts Options) []User {
+	users := make([]User, opts.Users)
+
+	for i := range users {
+		rng := rand.New(rand.NewSource(userSeed(opts.Seed, i)))
+
+		users[i] = User{
+			Email:    Email(opts.Seed, i),
+			Vampires: make([]Vampire, opts.Vampires),
+		}
+
+		for j := range users[i].Vampires {
+			users[i].Vampires[j] = generateVampire(rng)
+		}
+	}
+
+	return users
+}
+
+// Email is the address of the nth seeded user. It marks the user as seeded so
+// that seeding again with the same seed skips them.
+func Email(seed int64, n int) string {
+	return fmt.Sprintf("seed-%d-%d@example.com", seed, n+1)
+}
+
+func userSeed(seed int64, n int) int64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d:%d", seed, n)
+	return int64(h.Sum64())
+}
+
+func generateVampire(rng *rand.Rand) Vampire {
+	v := Vampire{
+		Name:     fmt.Sprintf("%s %s", pick(rng, givenNames), pick(rng, epithets)),
+		Memories: make([][]string, models.VampireMemorySize),
+	}
+
+	for i := range v.Memories {
+		// The first memory is always full so that every vampire shows one
+		count := experiencesPerMemory
+		if i > 0 {
+			count = rng.Intn(experiencesPerMemory + 1)
+		}
+
+		for j := 0; j < count; j++ {
+			v.Memories[i] = append(v.Memories[i], experience(rng))
+		}
+	}
+
+	for i := rng.Intn(4) + 1; i > 0; i-- {
+		v.Skills = append(v.Skills, pick(rng, skills))
+	}
+
+	for i := rng.Intn(3) + 1; i > 0; i-- {
+		v.Resources = append(v.Resources, models.CreateResourceParams{
+			Description: pick(rng, resources),
+			Stationary:  rng.Intn(2) == 0,
+		})
+	}
+
+	for i := rng.Intn(4) + 1; i > 0; i-- {
+		characterType := "mortal"
+		if rng.Intn(3) == 0 {
+			characterType = "immortal"
+		}
+
+		v.Characters = append(v.Characters, models.CreateCharacterParams{
+			Name: fmt.Sprintf("%s %s", pick(rng, givenNames), pick(rng, epithets)),
+			Type: characterType,
+		})
+	}
+
+	for i := rng.Intn(3); i > 0; i-- {
+		v.Marks = append(v.Marks, pick(rng, marks))
+	}
+
+	return v
+}
+
+func experience(rng *rand.Rand) string {
+	return fmt.Sprintf(pick(rng, experienceTemplates), pick(rng, givenNames), pick(rng, places))
+}
+
+func pick(rng *rand.Rand, words []string) string {
+	return words[rng.Intn(len(words))]
+}
+
+var givenNames = []string{
+	"Agnes", "Aldric", "Beatrix", "Cassius", "Constance", "Edmund", "Elspeth",
+	"Godric", "Hild", "Isolde", "Leofric", "Matilda", "Osric", "Rowena",
+	"Sabine", "Theodric", "Ursula", "Wulfstan",
+}
+
+var epithets = []string{
+	"the Pale", "of the Marsh", "Ashborn", "the Quiet", "of Lindisfarne",
+	"the Hungry", "Blackwood", "the Pilgrim", "of the Fens", "Gravesend",
+}
+
+var places = []string{
+	"a burning abbey", "the salt marshes", "a plague village", "the river ford",
+	"a crusader camp", "the king's court", "a drowned church", "the high moors",
+	"a merchant's cellar", "the old Roman road",
+}
+
+var experienceTemplates = []string{
+	"I was turned by %s in %s and woke hungry.",
+	"I fed on %s in %s and told no one.",
+	"%s hid me from the sun in %s; I repaid them poorly.",
+	"I buried %s in %s and did not weep.",
+	"I swore an oath to %s in %s that I have since broken.",
+	"I watched %s grow old in %s while I stayed the same.",
+	"%s learned what I am in %s, and I let them live.",
+}
+
+var skills = []string{
+	"Hunting in darkness", "Reading Latin", "Lying to priests",
+	"Sword fighting", "Herbalism", "Moving unseen", "Bargaining",
+	"Commanding animals",
+}
+
+var resources = []string{
+	"A crumbling tower", "A chest of silver coins", "A forged letter of passage",
+	"A reliquary with a saint's finger", "A loyal hound", "A rented room above a tavern",
+	"A hidden crypt",
+}
+
+var marks = []string{
+	"My reflection is a second too slow.", "My eyes shine red in candlelight.",
+	"Flowers wilt when I pass.", "I smell faintly of grave soil.",
+	"My skin is cold as river stone.",
+}
@@ -0,0 +1,72 @@
+package seed_test
+
+import (
+	"testing"
+
+	"emailaddress.horse/thousand/models"
+	"emailaddress.horse/thousand/seed"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	opts := seed.Options{Users: 4, Vampires: 3, Seed: 42}
+
+	users := seed.Generate(opts)
+
+	if len(users) != 4 {
+		t.Fatalf("expected 4 users; got %d", len(users))
+	}
+
+	for i, user := range users {
+		if expected := seed.Email(42, i); user.Email != expected {
+			t.Errorf("expected user %d to have email %q; got %q", i, expected, user.Email)
+		}
+
+		if len(user.Vampires) != 3 {
+			t.Errorf("expected user %d to have 3 vampires; got %d", i, len(user.Vampires))
+		}
+
+		for _, vampire := range user.Vampires {
+			if len(vampire.Memories) != models.VampireMemorySize {
+				t.Errorf("expected %d memories; got %d", models.VampireMemorySize, len(vampire.Memories))
+			}
+
+			if len(vampire.Memories[0]) != 3 {
+				t.Errorf("expected the first memory to be full; got %d experiences", len(vampire.Memories[0]))
+			}
+
+			for _, memory := range vampire.Memories {
+				if len(memory) > 3 {
+					t.Errorf("expected at most 3 experiences in a memory; got %d", len(memory))
+				}
+			}
+
+			if len(vampire.Skills) == 0 || len(vampire.Resources) == 0 || len(vampire.Characters) == 0 {
+				t.Errorf("expected %q to have skills, resources and characters", vampire.Name)
+			}
+		}
+	}
+}
+
+func TestGenerate_Deterministic(t *testing.T) {
+	t.Parallel()
+
+	opts := seed.Options{Users: 3, Vampires: 2, Seed: 7}
+
+	if diff := cmp.Diff(seed.Generate(opts), seed.Generate(opts)); diff != "" {
+		t.Errorf("expected the same options to generate the same data:\n%s", diff)
+	}
+
+	// Generating more users doesn't change those already generated
+	more := seed.Generate(seed.Options{Users: 5, Vampires: 2, Seed: 7})
+	if diff := cmp.Diff(seed.Generate(opts), more[:3]); diff != "" {
+		t.Errorf("expected earlier users to be unchanged:\n%s", diff)
+	}
+
+	other := seed.Generate(seed.Options{Users: 3, Vampires: 2, Seed: 8})
+	if cmp.Equal(seed.Generate(opts), other) {
+		t.Error("expected a different seed to generate different data")
+	}
+}
@@ -0,0 +1,139 @@
+// Package seed fills a database with generated users and vampires so that
+// there is something to look at while developing.
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"emailaddress.horse/thousand/form"
+	"emailaddress.horse/thousand/models"
+	"emailaddress.horse/thousand/repository"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Password is the password of every seeded user.
+const Password = "password"
+
+type Options struct {
+	// Users is how many users to seed.
+	Users int
+	// Vampires is how many vampires each user has.
+	Vampires int
+	// Seed picks which data is generated. Seeding with a different seed adds
+	// different users rather than skipping the existing ones.
+	Seed   int64
+	Logger *zap.Logger
+}
+
+// Result counts the users which were created and those which were skipped
+// because they had already been seeded.
+type Result struct {
+	Created int
+	Skipped int
+}
+
+// Run seeds the users described by the options. Each user is created along
+// with their vampires in a transaction so that a user either exists with
+// everything or not at all, which lets users who already exist be skipped.
+func Run(ctx context.Context, repo *repository.Repository, opts Options) (Result, error) {
+	if opts.Logger == nil {
+		opts.Logger = zap.NewNop()
+	}
+
+	var result Result
+
+	for _, user := range Generate(opts) {
+		created, err := seedUser(ctx, repo, user)
+		if err != nil {
+			return result, fmt.Errorf("error seeding %s: %w", user.Email, err)
+		}
+
+		if created {
+			result.Created++
+			opts.Logger.Info("seeded user", zap.String("email", user.Email), zap.Int("vampires", len(user.Vampires)))
+		} else {
+			result.Skipped++
+			opts.Logger.Info("skipped user already seeded", zap.String("email", user.Email))
+		}
+	}
+
+	return result, nil
+}
+
+func seedUser(ctx context.Context, repo *repository.Repository, user User) (bool, error) {
+	txRepo, tx, err := repo.WithTx(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	u, err := txRepo.CreateUser(ctx, form.NewUser(user.Email, Password))
+	if errors.Is(err, models.ErrEmailAlreadyInUse) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	for _, vampire := range user.Vampires {
+		if err := seedVampire(ctx, txRepo, u.ID, vampire); err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func seedVampire(ctx context.Context, repo *repository.Repository, userID uuid.UUID, vampire Vampire) error {
+	v, err := repo.CreateVampire(ctx, userID, vampire.Name)
+	if err != nil {
+		return err
+	}
+
+	for i, memory := range v.Memories {
+		for _, description := range vampire.Memories[i] {
+			if memory.Full() {
+				break
+			}
+
+			experience, err := repo.CreateExperience(ctx, v.ID, memory.ID, description)
+			if err != nil {
+				return err
+			}
+
+			memory.Experiences = append(memory.Experiences, experience)
+		}
+	}
+
+	for _, description := range vampire.Skills {
+		if _, err := repo.CreateSkill(ctx, v.ID, description); err != nil {
+			return err
+		}
+	}
+
+	for _, params := range vampire.Resources {
+		if _, err := repo.CreateResource(ctx, v.ID, params); err != nil {
+			return err
+		}
+	}
+
+	for _, params := range vampire.Characters {
+		if _, err := repo.CreateCharacter(ctx, v.ID, params); err != nil {
+			return err
+		}
+	}
+
+	for _, description := range vampire.Marks {
+		if _, err := repo.CreateMark(ctx, v.ID, description); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package seed_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"emailaddress.horse/thousand/form"
+	"emailaddress.horse/thousand/models"
+	"emailaddress.horse/thousand/repository"
+	"emailaddress.horse/thousand/seed"
+)
+
+func newTestRepository(t *testing.T) *repository.Repository {
+	t.Helper()
+
+	var databaseURL = "postgres://localhost:5432/thousand_test?sslmode=disable"
+
+	if os.Getenv("DATABASE_URL") != "" {
+		databaseURL = os.Getenv("DATABASE_URL")
+	}
+
+	repo, err := repository.New(repository.Options{
+		DatabaseURL: databaseURL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo, tx, err := repo.WithTx(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		if err := tx.Rollback(context.Background()); err != nil {
+			t.Fatalf("Error attempting to rollback - DB may have unexpected contents: %s", err)
+		}
+	})
+
+	return repo
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	opts := seed.Options{Users: 2, Vampires: 2, Seed: 3}
+
+	result, err := seed.Run(ctx, repo, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result != (seed.Result{Created: 2}) {
+		t.Errorf("expected 2 users created; got %+v", result)
+	}
+
+	// Seeding again skips the users already seeded
+	result, err = seed.Run(ctx, repo, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result != (seed.Result{Skipped: 2}) {
+		t.Errorf("expected 2 users skipped; got %+v", result)
+	}
+
+	user, err := repo.AuthenticateUser(ctx, form.NewSession(seed.Email(3, 0), seed.Password))
+	if err != nil {
+		t.Fatalf("expected to log in as a seeded user: %s", err)
+	}
+
+	page, err := repo.GetVampires(ctx, user.ID, models.VampireQuery{}.Normalize())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(page.Vampires) != 2 {
+		t.Fatalf("expected 2 vampires; got %d", len(page.Vampires))
+	}
+
+	vampire, err := repo.GetVampire(ctx, page.Vampires[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	full := 0
+	for _, memory := range vampire.Memories {
+		if memory.Full() {
+			full++
+		}
+	}
+
+	if full == 0 {
+		t.Error("expected a seeded vampire to have a full memory")
+	}
+}
@@ -0,0 +1,18 @@
+package server
+
+import (
+	"net/http"
+	"os"
+)
+
+// HTTPServer exposes the underlying server so its configuration can be checked.
+func (s *Server) HTTPServer() *http.Server {
+	return s.server
+}
+
+// SetNewFile replaces how socket activation opens the descriptors it's passed.
+func SetNewFile(f func(uintptr, string) *os.File) func() {
+	previous := newFile
+	newFile = f
+	return func() { newFile = previous }
+}
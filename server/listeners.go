@@ -0,0 +1,64 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+)
+
+// listenFDsStart is the first file descriptor passed down by socket
+// activation.
+const listenFDsStart = 3
+
+// newFile opens a file descriptor. It's replaced in tests, which can't choose
+// the descriptors they're given.
+var newFile = os.NewFile
+
+// activatedListener uses the first socket passed down following the systemd
+// socket activation protocol. The environment variables describing them are
+// unset so that they aren't passed on to any child processes.
+func activatedListener() (net.Listener, error) {
+	defer os.Unsetenv("LISTEN_PID")
+	defer os.Unsetenv("LISTEN_FDS")
+	defer os.Unsetenv("LISTEN_FDNAMES")
+
+	if os.Getenv("LISTEN_PID") != strconv.Itoa(os.Getpid()) {
+		return nil, errors.New("no sockets were passed to this process")
+	}
+
+	fds, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || fds < 1 {
+		return nil, errors.New("no sockets were passed to this process")
+	}
+
+	f := newFile(uintptr(listenFDsStart), "LISTEN_FD_"+strconv.Itoa(listenFDsStart))
+	defer f.Close()
+
+	listener, err := net.FileListener(f)
+	if err != nil {
+		return nil, fmt.Errorf("error using passed socket: %w", err)
+	}
+
+	return listener, nil
+}
+
+// unixListener listens on a Unix domain socket at the path, replacing any
+// socket left behind by a previous run. Anything else at the path is left
+// alone. The socket is removed when the listener is closed.
+func unixListener(ctx context.Context, path string) (net.Listener, error) {
+	if info, err := os.Stat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", path)
+		}
+
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("error removing old socket: %w", err)
+		}
+	}
+
+	lc := net.ListenConfig{}
+	return lc.Listen(ctx, "unix", path)
+}
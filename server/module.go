@@ -1,6 +1,10 @@
 package server
 
 import (
+	"context"
+	"time"
+
+	"emailaddress.horse/thousand/health"
 	"emailaddress.horse/thousand/repository"
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/fx"
@@ -14,30 +18,92 @@ var Module = fx.Options(
 type Params struct {
 	fx.In
 
-	Host string `name:"host" optional:"true"`
-	Port int    `name:"port"`
+	Host             string `name:"host" optional:"true"`
+	Port             int    `name:"port"`
+	Socket           string `name:"socket" optional:"true"`
+	SocketActivation bool   `name:"socketActivation" optional:"true"`
+	TLSCertFile      string `name:"tlsCertFile" optional:"true"`
+	TLSKeyFile       string `name:"tlsKeyFile" optional:"true"`
+
+	DrainPeriod       time.Duration `name:"drainPeriod" optional:"true"`
+	IdleTimeout       time.Duration `name:"idleTimeout" optional:"true"`
+	ReadHeaderTimeout time.Duration `name:"readHeaderTimeout" optional:"true"`
+	WriteTimeout      time.Duration `name:"writeTimeout" optional:"true"`
 
-	Logger     *zap.Logger
+	Health *health.Health
+	Logger *zap.Logger
+	Router chi.Router
+
+	// Repository isn't used by the server, but depending on it makes fx
+	// register the repository's hooks first and so run them last: the
+	// connection pool is only closed once the server has drained and stopped,
+	// rather than under requests which are still running. Don't remove it.
 	Repository *repository.Repository
-	Router     chi.Router
 }
 
+// fxNew builds the server and gates readiness on it: the app isn't ready
+// until the server has started, and stops being ready for the drain period
+// before the server shuts down so load balancers can stop sending requests.
 func fxNew(lc fx.Lifecycle, params Params) *Server {
+	logger := params.Logger.Named("server")
+
 	server := New(Options{
-		Host:   params.Host,
-		Logger: params.Logger.Named("server"),
-		Port:   params.Port,
-		Router: params.Router,
+		Host:              params.Host,
+		IdleTimeout:       params.IdleTimeout,
+		Logger:            logger,
+		Port:              params.Port,
+		ReadHeaderTimeout: params.ReadHeaderTimeout,
+		Router:            params.Router,
+		Socket:            params.Socket,
+		SocketActivation:  params.SocketActivation,
+		TLSCertFile:       params.TLSCertFile,
+		TLSKeyFile:        params.TLSKeyFile,
+		WriteTimeout:      params.WriteTimeout,
 	})
 
+	params.Health.SetReady(false)
+
 	lc.Append(fx.Hook{
 		OnStart: server.Listen,
 	})
 
 	lc.Append(fx.Hook{
-		OnStart: server.Start,
-		OnStop:  server.Stop,
+		OnStart: func(ctx context.Context) error {
+			if err := server.Start(ctx); err != nil {
+				return err
+			}
+
+			params.Health.SetReady(true)
+
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			params.Health.SetReady(false)
+
+			drain(ctx, logger, params.DrainPeriod)
+
+			return server.Stop(ctx)
+		},
 	})
 
 	return server
 }
+
+// drain waits for the period so requests already routed to the server can
+// still be served, cutting it short if the context is done.
+func drain(ctx context.Context, logger *zap.Logger, period time.Duration) {
+	if period <= 0 {
+		return
+	}
+
+	logger.Info("draining", zap.Duration("period", period))
+
+	timer := time.NewTimer(period)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		logger.Warn("drain cut short", zap.Error(ctx.Err()))
+	}
+}
@@ -0,0 +1,138 @@
+package server_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"emailaddress.horse/thousand/health"
+	"emailaddress.horse/thousand/repository"
+	"emailaddress.horse/thousand/server"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+)
+
+type params struct {
+	fx.Out
+
+	DrainPeriod time.Duration `name:"drainPeriod"`
+	Host        string        `name:"host"`
+	Port        int           `name:"port"`
+}
+
+func TestModule_Shutdown(t *testing.T) {
+	t.Parallel()
+
+	h := health.New(health.Options{})
+
+	var s *server.Server
+
+	app := fxtest.New(t,
+		fx.Supply(params{
+			DrainPeriod: 200 * time.Millisecond,
+			Host:        "127.0.0.1",
+		}),
+		fx.Supply(h),
+		fx.Provide(func() *repository.Repository { return nil }),
+		fx.Provide(func() *zap.Logger { return zap.NewNop() }),
+		fx.Provide(func() chi.Router {
+			r := chi.NewMux()
+			r.Get("/", func(w http.ResponseWriter, r *http.Request) {})
+			return r
+		}),
+
+		server.Module,
+
+		fx.Populate(&s),
+	)
+
+	if _, ok := h.Check(context.Background()); ok {
+		t.Error("expected not to be ready before starting")
+	}
+
+	app.RequireStart()
+
+	if _, ok := h.Check(context.Background()); !ok {
+		t.Error("expected to be ready once started")
+	}
+
+	url := "http://" + s.URL() + "/"
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		app.RequireStop()
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+
+	if _, ok := h.Check(context.Background()); ok {
+		t.Error("expected not to be ready while draining")
+	}
+
+	res, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("expected requests to be served while draining; got %s", err)
+	}
+	res.Body.Close()
+
+	<-stopped
+
+	if _, err := http.Get(url); err == nil {
+		t.Error("expected requests to fail once stopped")
+	}
+}
+
+func TestNew_Timeouts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                      string
+		opts                      server.Options
+		expectedReadHeaderTimeout time.Duration
+		expectedIdleTimeout       time.Duration
+		expectedWriteTimeout      time.Duration
+	}{
+		{
+			name:                      "defaults",
+			expectedReadHeaderTimeout: server.DefaultReadHeaderTimeout,
+			expectedIdleTimeout:       server.DefaultIdleTimeout,
+		},
+		{
+			name: "configured",
+			opts: server.Options{
+				ReadHeaderTimeout: time.Second,
+				IdleTimeout:       time.Minute,
+				WriteTimeout:      30 * time.Second,
+			},
+			expectedReadHeaderTimeout: time.Second,
+			expectedIdleTimeout:       time.Minute,
+			expectedWriteTimeout:      30 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := server.New(tt.opts).HTTPServer()
+
+			if tt.expectedReadHeaderTimeout != s.ReadHeaderTimeout {
+				t.Errorf("expected read header timeout %s; got %s", tt.expectedReadHeaderTimeout, s.ReadHeaderTimeout)
+			}
+
+			if tt.expectedIdleTimeout != s.IdleTimeout {
+				t.Errorf("expected idle timeout %s; got %s", tt.expectedIdleTimeout, s.IdleTimeout)
+			}
+
+			if tt.expectedWriteTimeout != s.WriteTimeout {
+				t.Errorf("expected write timeout %s; got %s", tt.expectedWriteTimeout, s.WriteTimeout)
+			}
+		})
+	}
+}
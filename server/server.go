@@ -7,6 +7,7 @@ import (
 	"net"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
@@ -15,18 +16,56 @@ import (
 // Server is a configured instance of the application, ready to be served by a
 // server or interacted with by CLI commands.
 type Server struct {
-	address  string
-	listener net.Listener
-	logger   *zap.Logger
-	server   *http.Server
+	address          string
+	socket           string
+	socketActivation bool
+	tls              *certReloader
+	listener         net.Listener
+	logger           *zap.Logger
+	server           *http.Server
 }
 
+const (
+	// DefaultReadHeaderTimeout is how long clients have to send request
+	// headers if the options don't say.
+	DefaultReadHeaderTimeout = 10 * time.Second
+
+	// DefaultIdleTimeout is how long keep-alive connections are held open
+	// between requests if the options don't say.
+	DefaultIdleTimeout = 2 * time.Minute
+)
+
 type Options struct {
 	Host   string
 	Logger *zap.Logger
 	Router chi.Router
 	Port   int
+	// Server is used to serve requests instead of one built from the options.
+	// Its handler defaults to Router and its timeouts are left as they are.
 	Server *http.Server
+
+	// ReadHeaderTimeout is how long clients have to send request headers.
+	ReadHeaderTimeout time.Duration
+	// IdleTimeout is how long keep-alive connections are held open between
+	// requests.
+	IdleTimeout time.Duration
+	// WriteTimeout is how long a response may take to write, or no limit if
+	// zero. Streams stay open far longer than a page takes, so they're cut off
+	// by any limit.
+	WriteTimeout time.Duration
+
+	// Socket is the path of a Unix domain socket to listen on instead of the
+	// host and port.
+	Socket string
+	// SocketActivation listens on the socket passed down by systemd, or
+	// anything else following its socket activation protocol, instead of the
+	// host and port.
+	SocketActivation bool
+
+	// TLSCertFile and TLSKeyFile are the paths of a PEM encoded certificate and
+	// key to serve HTTPS with. Both are reloaded whenever either changes.
+	TLSCertFile string
+	TLSKeyFile  string
 }
 
 // New configures an instance of the application with helpful defaults.
@@ -35,44 +74,96 @@ func New(opts Options) *Server {
 		opts.Logger = zap.NewNop()
 	}
 
+	if opts.ReadHeaderTimeout <= 0 {
+		opts.ReadHeaderTimeout = DefaultReadHeaderTimeout
+	}
+
+	if opts.IdleTimeout <= 0 {
+		opts.IdleTimeout = DefaultIdleTimeout
+	}
+
 	address := net.JoinHostPort(opts.Host, strconv.Itoa(opts.Port))
 
 	if opts.Server == nil {
 		opts.Server = &http.Server{
-			Addr:    address,
-			Handler: opts.Router,
+			ReadHeaderTimeout: opts.ReadHeaderTimeout,
+			IdleTimeout:       opts.IdleTimeout,
+			WriteTimeout:      opts.WriteTimeout,
 		}
 	}
 
+	if opts.Server.Handler == nil {
+		opts.Server.Handler = opts.Router
+	}
+
+	if opts.Server.Addr != "" {
+		address = opts.Server.Addr
+	}
+
+	var tls *certReloader
+	if opts.TLSCertFile != "" || opts.TLSKeyFile != "" {
+		tls = newCertReloader(opts.TLSCertFile, opts.TLSKeyFile, opts.Logger)
+	}
+
 	return &Server{
-		address: address,
-		logger:  opts.Logger,
-		server: &http.Server{
-			Handler: opts.Router,
-		},
+		address:          address,
+		socket:           opts.Socket,
+		socketActivation: opts.SocketActivation,
+		tls:              tls,
+		logger:           opts.Logger,
+		server:           opts.Server,
 	}
 }
 
+// Listen creates the listener the server will serve on: an inherited socket,
+// a Unix domain socket or the host and port, in that order of preference.
 func (s *Server) Listen(ctx context.Context) error {
-	if s.address == "" {
-		s.address = ":http"
+	if s.tls != nil {
+		if err := s.tls.load(); err != nil {
+			return err
+		}
+
+		s.server.TLSConfig = s.tls.config(s.server.TLSConfig)
 	}
 
-	lc := net.ListenConfig{}
-	listener, err := lc.Listen(ctx, "tcp", s.address)
+	var (
+		listener net.Listener
+		err      error
+	)
+
+	switch {
+	case s.socketActivation:
+		listener, err = activatedListener()
+	case s.socket != "":
+		listener, err = unixListener(ctx, s.socket)
+	default:
+		lc := net.ListenConfig{}
+		listener, err = lc.Listen(ctx, "tcp", s.address)
+	}
 	if err != nil {
 		return fmt.Errorf("error creating listener: %w", err)
 	}
 
 	s.listener = listener
+	s.address = listener.Addr().String()
 
 	return nil
 }
 
 func (s *Server) Start(_ context.Context) error {
-	s.logger.Info("starting", zap.String("address", s.address))
+	s.logger.Info("starting",
+		zap.String("network", s.listener.Addr().Network()),
+		zap.String("address", s.address),
+		zap.Bool("tls", s.tls != nil),
+	)
+
+	serve := s.server.Serve
+	if s.tls != nil {
+		serve = func(l net.Listener) error { return s.server.ServeTLS(l, "", "") }
+	}
+
 	go func() {
-		if err := s.server.Serve(s.listener); !errors.Is(err, http.ErrServerClosed) {
+		if err := serve(s.listener); !errors.Is(err, http.ErrServerClosed) {
 			s.logger.Error("server closed unexpectedly", zap.Error(err))
 		}
 	}()
@@ -89,6 +180,13 @@ func (s *Server) Stop(ctx context.Context) error {
 	return nil
 }
 
+// RegisterOnShutdown registers a function to call when the server begins to
+// shut down, such as to close long-lived connections which would otherwise
+// prevent it from finishing.
+func (s *Server) RegisterOnShutdown(f func()) {
+	s.server.RegisterOnShutdown(f)
+}
+
 func (s *Server) URL() string {
 	if s.listener == nil {
 		return ""
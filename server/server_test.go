@@ -0,0 +1,315 @@
+package server_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"emailaddress.horse/thousand/server"
+	"github.com/go-chi/chi/v5"
+)
+
+func newRouter() chi.Router {
+	r := chi.NewMux()
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "hello")
+	})
+	return r
+}
+
+// serve starts the server, stopping it when the test finishes.
+func serve(t *testing.T, s *server.Server) {
+	t.Helper()
+
+	if err := s.Listen(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		if err := s.Stop(context.Background()); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+// expectHello requests the root path with the client and checks it was served
+// by the test router.
+func expectHello(t *testing.T, client *http.Client, url string) {
+	t.Helper()
+
+	res, err := client.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(body) != "hello" {
+		t.Errorf("expected body %q; got %q", "hello", body)
+	}
+}
+
+func TestServer_TCP(t *testing.T) {
+	t.Parallel()
+
+	s := server.New(server.Options{
+		Host:   "127.0.0.1",
+		Router: newRouter(),
+	})
+	serve(t, s)
+
+	expectHello(t, http.DefaultClient, "http://"+s.URL()+"/")
+}
+
+func TestServer_SuppliedServer(t *testing.T) {
+	t.Parallel()
+
+	supplied := &http.Server{
+		Addr:        "127.0.0.1:0",
+		ReadTimeout: 3 * time.Second,
+	}
+
+	s := server.New(server.Options{
+		Router: newRouter(),
+		Server: supplied,
+	})
+
+	if s.HTTPServer() != supplied {
+		t.Fatal("expected the supplied server to be used")
+	}
+
+	if supplied.ReadTimeout != 3*time.Second {
+		t.Errorf("expected supplied read timeout to be kept; got %s", supplied.ReadTimeout)
+	}
+
+	serve(t, s)
+
+	expectHello(t, http.DefaultClient, "http://"+s.URL()+"/")
+}
+
+func TestServer_Unix(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "thousand.sock")
+
+	// A socket left behind by a previous run shouldn't stop the server starting
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	s := server.New(server.Options{
+		Router: newRouter(),
+		Socket: path,
+	})
+	serve(t, s)
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", path)
+			},
+		},
+	}
+
+	expectHello(t, client, "http://thousand/")
+}
+
+func TestServer_UnixNotSocket(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "thousand.sock")
+	if err := os.WriteFile(path, []byte("not a socket"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	s := server.New(server.Options{Socket: path})
+
+	if err := s.Listen(context.Background()); err == nil {
+		t.Error("expected an error listening over a file")
+	}
+}
+
+// TestServer_SocketActivation can't run in parallel as socket activation is
+// configured through the process's environment.
+func TestServer_SocketActivation(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	f, err := l.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	t.Cleanup(server.SetNewFile(func(fd uintptr, name string) *os.File {
+		if fd != 3 {
+			t.Errorf("expected the first passed descriptor to be 3; got %d", fd)
+		}
+		return f
+	}))
+
+	os.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+	os.Setenv("LISTEN_FDS", "1")
+	t.Cleanup(func() {
+		os.Unsetenv("LISTEN_PID")
+		os.Unsetenv("LISTEN_FDS")
+	})
+
+	s := server.New(server.Options{
+		Router:           newRouter(),
+		SocketActivation: true,
+	})
+	serve(t, s)
+
+	if s.URL() != l.Addr().String() {
+		t.Errorf("expected to serve on %s; got %s", l.Addr(), s.URL())
+	}
+
+	if os.Getenv("LISTEN_FDS") != "" {
+		t.Error("expected socket activation environment to be unset")
+	}
+
+	expectHello(t, http.DefaultClient, "http://"+s.URL()+"/")
+}
+
+func TestServer_SocketActivationMissing(t *testing.T) {
+	s := server.New(server.Options{SocketActivation: true})
+
+	if err := s.Listen(context.Background()); err == nil {
+		t.Error("expected an error without a passed socket")
+	}
+}
+
+// writeCertificate writes a new self-signed certificate with the serial number
+// and its key.
+func writeCertificate(t *testing.T, certFile, keyFile string, serial int64) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServer_TLS(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	writeCertificate(t, certFile, keyFile, 1)
+
+	s := server.New(server.Options{
+		Host:        "127.0.0.1",
+		Router:      newRouter(),
+		TLSCertFile: certFile,
+		TLSKeyFile:  keyFile,
+	})
+	serve(t, s)
+
+	serialNumber := func() int64 {
+		t.Helper()
+
+		conn, err := tls.Dial("tcp", s.URL(), &tls.Config{InsecureSkipVerify: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64()
+	}
+
+	if serial := serialNumber(); serial != 1 {
+		t.Errorf("expected certificate 1; got %d", serial)
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+	expectHello(t, client, "https://"+s.URL()+"/")
+
+	// Make sure the modification time changes even on coarse filesystems
+	writeCertificate(t, certFile, keyFile, 2)
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(certFile, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	if serial := serialNumber(); serial != 2 {
+		t.Errorf("expected reloaded certificate 2; got %d", serial)
+	}
+}
+
+func TestServer_TLSMissingFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	s := server.New(server.Options{
+		Host:        "127.0.0.1",
+		TLSCertFile: filepath.Join(dir, "cert.pem"),
+		TLSKeyFile:  filepath.Join(dir, "key.pem"),
+	})
+
+	if err := s.Listen(context.Background()); err == nil {
+		t.Error("expected an error without a certificate")
+	}
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// certReloader serves a certificate and key from files, loading them again
+// whenever either file is modified so that renewed certificates are picked up
+// without a restart.
+type certReloader struct {
+	certFile string
+	keyFile  string
+	logger   *zap.Logger
+
+	mu       sync.Mutex
+	cert     *tls.Certificate
+	certTime time.Time
+	keyTime  time.Time
+}
+
+func newCertReloader(certFile, keyFile string, logger *zap.Logger) *certReloader {
+	return &certReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		logger:   logger,
+	}
+}
+
+// config returns a copy of base, which may be nil, that gets its certificate
+// from the reloader.
+func (c *certReloader) config(base *tls.Config) *tls.Config {
+	var config *tls.Config
+	if base != nil {
+		config = base.Clone()
+	} else {
+		config = &tls.Config{}
+	}
+
+	config.GetCertificate = c.getCertificate
+
+	return config
+}
+
+// load reads the certificate and key.
+func (c *certReloader) load() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.loadLocked()
+}
+
+func (c *certReloader) loadLocked() error {
+	certInfo, err := os.Stat(c.certFile)
+	if err != nil {
+		return fmt.Errorf("error reading TLS certificate: %w", err)
+	}
+
+	keyInfo, err := os.Stat(c.keyFile)
+	if err != nil {
+		return fmt.Errorf("error reading TLS key: %w", err)
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return fmt.Errorf("error loading TLS certificate: %w", err)
+	}
+
+	c.cert = &cert
+	c.certTime = certInfo.ModTime()
+	c.keyTime = keyInfo.ModTime()
+
+	return nil
+}
+
+// getCertificate reloads the certificate if either file has changed since it
+// was last loaded. If reloading fails, such as when only one file has been
+// replaced so far, the previous certificate is kept.
+func (c *certReloader) getCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	certInfo, certErr := os.Stat(c.certFile)
+	keyInfo, keyErr := os.Stat(c.keyFile)
+
+	if certErr == nil && keyErr == nil &&
+		(!certInfo.ModTime().Equal(c.certTime) || !keyInfo.ModTime().Equal(c.keyTime)) {
+		if err := c.loadLocked(); err != nil {
+			c.logger.Error("error reloading TLS certificate", zap.Error(err))
+		} else {
+			c.logger.Info("reloaded TLS certificate")
+		}
+	}
+
+	return c.cert, nil
+}
@@ -0,0 +1,72 @@
+package session
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/gob"
+	"net/http"
+
+	"emailaddress.horse/thousand/errors"
+)
+
+func init() {
+	gob.Register(AuthFlow{})
+}
+
+const authFlowKey = "authFlow"
+
+var (
+	ErrAuthFlowMissing = errors.New("no auth flow in progress")
+)
+
+// AuthFlow holds the values which must survive the round trip to an external
+// identity provider so that its response can be verified.
+type AuthFlow struct {
+	State        string
+	Nonce        string
+	CodeVerifier string
+
+	// Linking is true when the flow was started to link an identity to the
+	// current user rather than to log in.
+	Linking bool
+}
+
+// NewAuthFlow generates a flow with fresh random values.
+func NewAuthFlow(linking bool) (AuthFlow, error) {
+	values := make([]string, 3)
+	for i := range values {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return AuthFlow{}, err
+		}
+		values[i] = base64.RawURLEncoding.EncodeToString(b)
+	}
+
+	return AuthFlow{
+		State:        values[0],
+		Nonce:        values[1],
+		CodeVerifier: values[2],
+		Linking:      linking,
+	}, nil
+}
+
+func (s *Store) SetAuthFlow(r *http.Request, w http.ResponseWriter, flow AuthFlow) error {
+	session, _ := s.store.Get(r, sessionKey)
+
+	session.Values[authFlowKey] = flow
+	return session.Save(r, w)
+}
+
+// PopAuthFlow returns the flow in progress and removes it from the session so
+// that it cannot be used twice.
+func (s *Store) PopAuthFlow(r *http.Request, w http.ResponseWriter) (AuthFlow, error) {
+	session, _ := s.store.Get(r, sessionKey)
+
+	flow, ok := session.Values[authFlowKey].(AuthFlow)
+	if !ok {
+		return AuthFlow{}, ErrAuthFlowMissing
+	}
+
+	delete(session.Values, authFlowKey)
+	return flow, session.Save(r, w)
+}
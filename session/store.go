@@ -9,12 +9,14 @@ import (
 )
 
 const (
-	sessionKey       = "thousand"
-	currentUserIDKey = "currentUserID"
+	sessionKey        = "thousand"
+	currentUserIDKey  = "currentUserID"
+	impersonatorIDKey = "impersonatorID"
 )
 
 var (
-	ErrFlashInvalid = errors.New("failed to parse flash")
+	ErrFlashInvalid     = errors.New("failed to parse flash")
+	ErrNotImpersonating = errors.New("not impersonating anyone")
 )
 
 type Store struct {
@@ -35,13 +37,59 @@ func (s *Store) SetCurrentUserID(r *http.Request, w http.ResponseWriter, id uuid
 	session, _ := s.store.Get(r, sessionKey)
 
 	session.Values[currentUserIDKey] = id.String()
+	delete(session.Values, impersonatorIDKey)
 	return session.Save(r, w)
 }
 
 func (s *Store) GetCurrentUserID(r *http.Request) (uuid.UUID, bool) {
+	return s.getID(r, currentUserIDKey)
+}
+
+func (s *Store) ClearCurrentUserID(w http.ResponseWriter, r *http.Request) error {
+	session, _ := s.store.Get(r, sessionKey)
+
+	delete(session.Values, currentUserIDKey)
+	delete(session.Values, impersonatorIDKey)
+	return session.Save(r, w)
+}
+
+// StartImpersonating makes the user current, remembering the admin as their
+// impersonator so that the admin can go back to being themselves.
+func (s *Store) StartImpersonating(r *http.Request, w http.ResponseWriter, adminID, userID uuid.UUID) error {
 	session, _ := s.store.Get(r, sessionKey)
 
-	id, ok := session.Values[currentUserIDKey]
+	session.Values[currentUserIDKey] = userID.String()
+	session.Values[impersonatorIDKey] = adminID.String()
+	return session.Save(r, w)
+}
+
+// GetImpersonatorID returns the admin impersonating the current user, if there
+// is one.
+func (s *Store) GetImpersonatorID(r *http.Request) (uuid.UUID, bool) {
+	return s.getID(r, impersonatorIDKey)
+}
+
+// StopImpersonating makes the impersonator the current user again, returning
+// who was impersonated.
+func (s *Store) StopImpersonating(r *http.Request, w http.ResponseWriter) (uuid.UUID, error) {
+	adminID, ok := s.GetImpersonatorID(r)
+	if !ok {
+		return uuid.UUID{}, ErrNotImpersonating
+	}
+
+	userID, _ := s.GetCurrentUserID(r)
+
+	session, _ := s.store.Get(r, sessionKey)
+
+	session.Values[currentUserIDKey] = adminID.String()
+	delete(session.Values, impersonatorIDKey)
+	return userID, session.Save(r, w)
+}
+
+func (s *Store) getID(r *http.Request, key string) (uuid.UUID, bool) {
+	session, _ := s.store.Get(r, sessionKey)
+
+	id, ok := session.Values[key]
 	if !ok {
 		return uuid.UUID{}, false
 	}
@@ -58,10 +106,3 @@ func (s *Store) GetCurrentUserID(r *http.Request) (uuid.UUID, bool) {
 
 	return idAsUUID, true
 }
-
-func (s *Store) ClearCurrentUserID(w http.ResponseWriter, r *http.Request) error {
-	session, _ := s.store.Get(r, sessionKey)
-
-	delete(session.Values, currentUserIDKey)
-	return session.Save(r, w)
-}
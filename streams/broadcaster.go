@@ -0,0 +1,116 @@
+package streams
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"emailaddress.horse/thousand/models"
+	"github.com/google/uuid"
+)
+
+// VampireTopic is the topic Turbo Streams for changes to the vampire are
+// published to.
+func VampireTopic(vampireID uuid.UUID) string {
+	return "vampire:" + vampireID.String()
+}
+
+// SubscriberKey is the key the user's subscriptions are made with, so that
+// they can be disconnected.
+func SubscriberKey(userID uuid.UUID) string {
+	return "user:" + userID.String()
+}
+
+type streamRenderer interface {
+	CreateCharacterStream(io.Writer, models.Character) error
+	CreateExperienceStream(io.Writer, models.Memory, models.Experience) error
+	CreateMarkStream(io.Writer, models.Mark) error
+	CreateResourceStream(io.Writer, models.Resource) error
+	CreateSkillStream(io.Writer, models.Skill) error
+	DestroyVampireStream(io.Writer) error
+}
+
+type memoryGetter interface {
+	GetMemory(context.Context, uuid.UUID, uuid.UUID) (models.Memory, error)
+}
+
+// Broadcaster renders changes to a vampire as Turbo Streams and publishes them
+// to everyone viewing the vampire.
+type Broadcaster struct {
+	hub      *Hub
+	memories memoryGetter
+	renderer streamRenderer
+}
+
+func NewBroadcaster(hub *Hub, renderer streamRenderer, memories memoryGetter) *Broadcaster {
+	return &Broadcaster{
+		hub:      hub,
+		memories: memories,
+		renderer: renderer,
+	}
+}
+
+func (b *Broadcaster) CharacterCreated(ctx context.Context, vampireID uuid.UUID, c models.Character) error {
+	return b.publish(ctx, vampireID, func(w io.Writer) error {
+		return b.renderer.CreateCharacterStream(w, c)
+	})
+}
+
+// ExperienceCreated reloads the experience's memory so that viewers can be
+// told when it has become full.
+func (b *Broadcaster) ExperienceCreated(ctx context.Context, vampireID uuid.UUID, e models.Experience) error {
+	memory, err := b.memories.GetMemory(ctx, vampireID, e.MemoryID)
+	if err != nil {
+		return err
+	}
+
+	return b.publish(ctx, vampireID, func(w io.Writer) error {
+		return b.renderer.CreateExperienceStream(w, memory, e)
+	})
+}
+
+func (b *Broadcaster) MarkCreated(ctx context.Context, vampireID uuid.UUID, m models.Mark) error {
+	return b.publish(ctx, vampireID, func(w io.Writer) error {
+		return b.renderer.CreateMarkStream(w, m)
+	})
+}
+
+func (b *Broadcaster) ResourceCreated(ctx context.Context, vampireID uuid.UUID, r models.Resource) error {
+	return b.publish(ctx, vampireID, func(w io.Writer) error {
+		return b.renderer.CreateResourceStream(w, r)
+	})
+}
+
+func (b *Broadcaster) SkillCreated(ctx context.Context, vampireID uuid.UUID, s models.Skill) error {
+	return b.publish(ctx, vampireID, func(w io.Writer) error {
+		return b.renderer.CreateSkillStream(w, s)
+	})
+}
+
+// VampireTrashed tells viewers that the vampire has been moved to the trash and
+// then disconnects them, since they can no longer see it.
+func (b *Broadcaster) VampireTrashed(ctx context.Context, vampireID uuid.UUID) error {
+	err := b.publish(ctx, vampireID, func(w io.Writer) error {
+		return b.renderer.DestroyVampireStream(w)
+	})
+	if err != nil {
+		return err
+	}
+
+	return b.hub.Disconnect(ctx, VampireTopic(vampireID), "")
+}
+
+// MemberRemoved disconnects the user's streams of the vampire. Their browser
+// reconnects, and is refused since they're no longer a member.
+func (b *Broadcaster) MemberRemoved(ctx context.Context, vampireID, userID uuid.UUID) error {
+	return b.hub.Disconnect(ctx, VampireTopic(vampireID), SubscriberKey(userID))
+}
+
+func (b *Broadcaster) publish(ctx context.Context, vampireID uuid.UUID, render func(io.Writer) error) error {
+	var buf bytes.Buffer
+	if err := render(&buf); err != nil {
+		return err
+	}
+
+	return b.hub.Publish(ctx, VampireTopic(vampireID), buf.String())
+}
@@ -0,0 +1,259 @@
+package streams
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// notifyChannel is the Postgres channel messages are sent over so that every
+// running instance can deliver them to its own subscribers.
+const notifyChannel = "thousand_streams"
+
+// maxNotifyPayload is just under the limit Postgres places on the size of a
+// notification payload. Larger messages are stored and sent by reference.
+const maxNotifyPayload = 7900
+
+// subscriberBuffer is how many messages a subscriber can fall behind by before
+// further messages are dropped for them.
+const subscriberBuffer = 16
+
+type notifier interface {
+	Notify(context.Context, string, string) error
+	Listen(context.Context, string, func(string)) error
+	StoreStreamMessage(context.Context, string) (uuid.UUID, error)
+	GetStreamMessage(context.Context, uuid.UUID) (string, error)
+}
+
+// message is sent as a notification's payload. Its data is blank when it was
+// too large to send, in which case each instance loads it by its ref. A
+// disconnect message closes subscriptions rather than delivering data.
+type message struct {
+	Topic      string `json:"topic"`
+	Data       string `json:"data,omitempty"`
+	Ref        string `json:"ref,omitempty"`
+	Disconnect bool   `json:"disconnect,omitempty"`
+	Key        string `json:"key,omitempty"`
+}
+
+// Hub is an in-process publish/subscribe hub for Turbo Stream messages. When
+// configured with a Notifier, messages are fanned out through Postgres so that
+// subscribers connected to other instances receive them too.
+type Hub struct {
+	logger   *zap.Logger
+	notifier notifier
+
+	mu     sync.RWMutex
+	closed bool
+	// subscribers maps each topic's subscriptions to the key they were made
+	// with
+	subscribers map[string]map[chan string]string
+}
+
+type Options struct {
+	Logger   *zap.Logger
+	Notifier notifier
+}
+
+func NewHub(opts Options) *Hub {
+	if opts.Logger == nil {
+		opts.Logger = zap.NewNop()
+	}
+
+	return &Hub{
+		logger:      opts.Logger,
+		notifier:    opts.Notifier,
+		subscribers: map[string]map[chan string]string{},
+	}
+}
+
+// Subscribe returns a channel which receives every message published to the
+// topic, and a function to call once the subscriber is no longer interested.
+// The channel is closed when unsubscribing, when the hub is closed or when
+// subscribers with the key are disconnected.
+func (h *Hub) Subscribe(topic, key string) (<-chan string, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ch := make(chan string, subscriberBuffer)
+
+	if h.closed {
+		close(ch)
+		return ch, func() {}
+	}
+
+	if h.subscribers[topic] == nil {
+		h.subscribers[topic] = map[chan string]string{}
+	}
+	h.subscribers[topic][ch] = key
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+
+			if _, ok := h.subscribers[topic][ch]; !ok {
+				return
+			}
+
+			delete(h.subscribers[topic], ch)
+			if len(h.subscribers[topic]) == 0 {
+				delete(h.subscribers, topic)
+			}
+			close(ch)
+		})
+	}
+
+	return ch, unsubscribe
+}
+
+// Publish sends the data to every subscriber of the topic.
+func (h *Hub) Publish(ctx context.Context, topic, data string) error {
+	if h.notifier == nil {
+		h.deliver(topic, data)
+		return nil
+	}
+
+	payload, err := json.Marshal(message{Topic: topic, Data: data})
+	if err != nil {
+		return err
+	}
+
+	if len(payload) > maxNotifyPayload {
+		ref, err := h.notifier.StoreStreamMessage(ctx, data)
+		if err != nil {
+			return err
+		}
+
+		payload, err = json.Marshal(message{Topic: topic, Ref: ref.String()})
+		if err != nil {
+			return err
+		}
+	}
+
+	return h.notifier.Notify(ctx, notifyChannel, string(payload))
+}
+
+// Disconnect closes the subscriptions to the topic made with the key, or every
+// subscription to it when the key is blank, on every instance. Messages
+// published beforehand are still received.
+func (h *Hub) Disconnect(ctx context.Context, topic, key string) error {
+	if h.notifier == nil {
+		h.disconnect(topic, key)
+		return nil
+	}
+
+	payload, err := json.Marshal(message{Topic: topic, Disconnect: true, Key: key})
+	if err != nil {
+		return err
+	}
+
+	return h.notifier.Notify(ctx, notifyChannel, string(payload))
+}
+
+// Run listens for messages published by any instance and delivers them to
+// local subscribers until the context is cancelled. Lost connections are
+// retried after a short delay.
+func (h *Hub) Run(ctx context.Context) {
+	if h.notifier == nil {
+		return
+	}
+
+	for {
+		err := h.notifier.Listen(ctx, notifyChannel, h.receive)
+		if ctx.Err() != nil {
+			return
+		}
+
+		h.logger.Error("stopped listening for messages; retrying", zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// Close disconnects every subscriber. Subscribing after closing returns a
+// closed channel.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+
+	for topic, subscribers := range h.subscribers {
+		for ch := range subscribers {
+			close(ch)
+		}
+		delete(h.subscribers, topic)
+	}
+}
+
+func (h *Hub) receive(payload string) {
+	var msg message
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		h.logger.Error("failed to decode message", zap.Error(err))
+		return
+	}
+
+	if msg.Disconnect {
+		h.disconnect(msg.Topic, msg.Key)
+		return
+	}
+
+	if msg.Ref != "" {
+		data, err := h.load(msg.Ref)
+		if err != nil {
+			h.logger.Error("failed to load message", zap.String("topic", msg.Topic), zap.String("ref", msg.Ref), zap.Error(err))
+			return
+		}
+		msg.Data = data
+	}
+
+	h.deliver(msg.Topic, msg.Data)
+}
+
+func (h *Hub) load(ref string) (string, error) {
+	id, err := uuid.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+
+	return h.notifier.GetStreamMessage(context.Background(), id)
+}
+
+func (h *Hub) disconnect(topic, key string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch, k := range h.subscribers[topic] {
+		if key == "" || k == key {
+			delete(h.subscribers[topic], ch)
+			close(ch)
+		}
+	}
+
+	if len(h.subscribers[topic]) == 0 {
+		delete(h.subscribers, topic)
+	}
+}
+
+func (h *Hub) deliver(topic, data string) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for ch := range h.subscribers[topic] {
+		select {
+		case ch <- data:
+		default:
+			h.logger.Warn("subscriber is too slow; dropping message", zap.String("topic", topic))
+		}
+	}
+}
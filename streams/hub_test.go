@@ -0,0 +1,350 @@
+package streams_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"emailaddress.horse/thousand/streams"
+	"github.com/google/uuid"
+)
+
+func receive(t *testing.T, ch <-chan string) string {
+	t.Helper()
+
+	select {
+	case message, ok := <-ch:
+		if !ok {
+			t.Fatal("channel closed")
+		}
+		return message
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for message")
+		return ""
+	}
+}
+
+func TestHubPublishLocally(t *testing.T) {
+	t.Parallel()
+
+	hub := streams.NewHub(streams.Options{})
+
+	first, unsubscribeFirst := hub.Subscribe("vampire:1", "")
+	defer unsubscribeFirst()
+
+	second, unsubscribeSecond := hub.Subscribe("vampire:1", "")
+	defer unsubscribeSecond()
+
+	other, unsubscribeOther := hub.Subscribe("vampire:2", "")
+	defer unsubscribeOther()
+
+	if err := hub.Publish(context.Background(), "vampire:1", "a message"); err != nil {
+		t.Fatal(err)
+	}
+
+	if message := receive(t, first); message != "a message" {
+		t.Errorf("expected %q; got %q", "a message", message)
+	}
+
+	if message := receive(t, second); message != "a message" {
+		t.Errorf("expected %q; got %q", "a message", message)
+	}
+
+	select {
+	case message := <-other:
+		t.Errorf("expected no message on another topic; got %q", message)
+	default:
+	}
+}
+
+func TestHubUnsubscribe(t *testing.T) {
+	t.Parallel()
+
+	hub := streams.NewHub(streams.Options{})
+
+	ch, unsubscribe := hub.Subscribe("vampire:1", "")
+	unsubscribe()
+	unsubscribe()
+
+	if _, ok := <-ch; ok {
+		t.Error("expected channel to be closed")
+	}
+
+	if err := hub.Publish(context.Background(), "vampire:1", "a message"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHubClose(t *testing.T) {
+	t.Parallel()
+
+	hub := streams.NewHub(streams.Options{})
+
+	ch, unsubscribe := hub.Subscribe("vampire:1", "")
+	defer unsubscribe()
+
+	hub.Close()
+
+	if _, ok := <-ch; ok {
+		t.Error("expected channel to be closed")
+	}
+
+	late, unsubscribeLate := hub.Subscribe("vampire:1", "")
+	defer unsubscribeLate()
+
+	if _, ok := <-late; ok {
+		t.Error("expected channel subscribed after closing to be closed")
+	}
+}
+
+// closed reports whether the channel has been closed once any messages left in
+// it have been received.
+func closed(t *testing.T, ch <-chan string) bool {
+	t.Helper()
+
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return true
+			}
+		case <-time.After(100 * time.Millisecond):
+			return false
+		}
+	}
+}
+
+func TestHubDisconnect(t *testing.T) {
+	t.Parallel()
+
+	hub := streams.NewHub(streams.Options{})
+
+	jane, unsubscribeJane := hub.Subscribe("vampire:1", "jane")
+	defer unsubscribeJane()
+
+	john, unsubscribeJohn := hub.Subscribe("vampire:1", "john")
+	defer unsubscribeJohn()
+
+	other, unsubscribeOther := hub.Subscribe("vampire:2", "jane")
+	defer unsubscribeOther()
+
+	if err := hub.Disconnect(context.Background(), "vampire:1", "jane"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !closed(t, jane) {
+		t.Error("expected jane's subscription to be closed")
+	}
+
+	if closed(t, john) {
+		t.Error("expected john's subscription to stay open")
+	}
+
+	if closed(t, other) {
+		t.Error("expected jane's subscription to another topic to stay open")
+	}
+
+	if err := hub.Disconnect(context.Background(), "vampire:1", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if !closed(t, john) {
+		t.Error("expected every subscription to be closed")
+	}
+}
+
+type mockNotifier struct {
+	mu        sync.Mutex
+	listeners []func(string)
+	listening chan struct{}
+	payloads  []string
+	messages  map[uuid.UUID]string
+	err       error
+}
+
+func newMockNotifier() *mockNotifier {
+	return &mockNotifier{
+		listening: make(chan struct{}),
+		messages:  map[uuid.UUID]string{},
+	}
+}
+
+func (m *mockNotifier) Notify(_ context.Context, _ string, payload string) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	m.mu.Lock()
+	m.payloads = append(m.payloads, payload)
+	listeners := m.listeners
+	m.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(payload)
+	}
+
+	return nil
+}
+
+func (m *mockNotifier) StoreStreamMessage(_ context.Context, data string) (uuid.UUID, error) {
+	if m.err != nil {
+		return uuid.UUID{}, m.err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id := uuid.New()
+	m.messages[id] = data
+	return id, nil
+}
+
+func (m *mockNotifier) GetStreamMessage(_ context.Context, id uuid.UUID) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	data, ok := m.messages[id]
+	if !ok {
+		return "", errors.New("not found")
+	}
+
+	return data, nil
+}
+
+func (m *mockNotifier) Listen(ctx context.Context, _ string, fn func(string)) error {
+	m.mu.Lock()
+	m.listeners = append(m.listeners, fn)
+	m.mu.Unlock()
+
+	close(m.listening)
+
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestHubPublishThroughNotifier(t *testing.T) {
+	t.Parallel()
+
+	notifier := newMockNotifier()
+
+	publisher := streams.NewHub(streams.Options{Notifier: notifier})
+	subscriber := streams.NewHub(streams.Options{Notifier: notifier})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go subscriber.Run(ctx)
+	<-notifier.listening
+
+	ch, unsubscribe := subscriber.Subscribe("vampire:1", "")
+	defer unsubscribe()
+
+	if err := publisher.Publish(context.Background(), "vampire:1", "a message"); err != nil {
+		t.Fatal(err)
+	}
+
+	if message := receive(t, ch); message != "a message" {
+		t.Errorf("expected %q; got %q", "a message", message)
+	}
+}
+
+func TestHubDisconnectThroughNotifier(t *testing.T) {
+	t.Parallel()
+
+	notifier := newMockNotifier()
+
+	publisher := streams.NewHub(streams.Options{Notifier: notifier})
+	subscriber := streams.NewHub(streams.Options{Notifier: notifier})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go subscriber.Run(ctx)
+	<-notifier.listening
+
+	ch, unsubscribe := subscriber.Subscribe("vampire:1", "jane")
+	defer unsubscribe()
+
+	if err := publisher.Publish(context.Background(), "vampire:1", "a message"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := publisher.Disconnect(context.Background(), "vampire:1", "jane"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Messages published before disconnecting are still received
+	if message := receive(t, ch); message != "a message" {
+		t.Errorf("expected %q; got %q", "a message", message)
+	}
+
+	if !closed(t, ch) {
+		t.Error("expected subscription to be closed")
+	}
+}
+
+func TestHubPublishLargeMessage(t *testing.T) {
+	t.Parallel()
+
+	notifier := newMockNotifier()
+
+	publisher := streams.NewHub(streams.Options{Notifier: notifier})
+	subscriber := streams.NewHub(streams.Options{Notifier: notifier})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go subscriber.Run(ctx)
+	<-notifier.listening
+
+	ch, unsubscribe := subscriber.Subscribe("vampire:1", "")
+	defer unsubscribe()
+
+	data := strings.Repeat("a", 8000)
+
+	if err := publisher.Publish(context.Background(), "vampire:1", data); err != nil {
+		t.Fatal(err)
+	}
+
+	if message := receive(t, ch); message != data {
+		t.Errorf("expected message of length %d; got %d", len(data), len(message))
+	}
+
+	// The message is sent by reference rather than in the notification
+	for _, payload := range notifier.payloads {
+		if len(payload) > len(data) {
+			t.Errorf("expected a reference to the message; got payload of length %d", len(payload))
+		}
+	}
+}
+
+func TestHubPublishLargeMessageError(t *testing.T) {
+	t.Parallel()
+
+	notifier := newMockNotifier()
+	notifier.err = errors.New("mock error")
+
+	hub := streams.NewHub(streams.Options{Notifier: notifier})
+
+	data := strings.Repeat("a", 8000)
+
+	if err := hub.Publish(context.Background(), "vampire:1", data); !errors.Is(err, notifier.err) {
+		t.Errorf("expected %q; got %q", notifier.err, err)
+	}
+}
+
+func TestHubPublishError(t *testing.T) {
+	t.Parallel()
+
+	notifier := newMockNotifier()
+	notifier.err = errors.New("mock error")
+
+	hub := streams.NewHub(streams.Options{Notifier: notifier})
+
+	if err := hub.Publish(context.Background(), "vampire:1", "a message"); !errors.Is(err, notifier.err) {
+		t.Errorf("expected %q; got %q", notifier.err, err)
+	}
+}
@@ -0,0 +1,75 @@
+package streams
+
+import (
+	"context"
+
+	"emailaddress.horse/thousand/repository"
+	"emailaddress.horse/thousand/server"
+	"emailaddress.horse/thousand/templates"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var Module = fx.Options(
+	fx.Provide(fxNewHub),
+	fx.Provide(fxNewBroadcaster),
+	fx.Invoke(fxCloseOnShutdown),
+)
+
+type HubParams struct {
+	fx.In
+
+	Logger     *zap.Logger
+	Repository *repository.Repository
+}
+
+func fxNewHub(lc fx.Lifecycle, params HubParams) *Hub {
+	hub := NewHub(Options{
+		Logger:   params.Logger.Named("streams"),
+		Notifier: params.Repository,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go func() {
+				defer close(done)
+				hub.Run(ctx)
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			hub.Close()
+
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return stopCtx.Err()
+			}
+		},
+	})
+
+	return hub
+}
+
+type BroadcasterParams struct {
+	fx.In
+
+	Hub        *Hub
+	Renderer   *templates.Renderer
+	Repository *repository.Repository
+}
+
+func fxNewBroadcaster(params BroadcasterParams) *Broadcaster {
+	return NewBroadcaster(params.Hub, params.Renderer, params.Repository)
+}
+
+// fxCloseOnShutdown disconnects subscribers as soon as the server begins to
+// shut down, otherwise their open streams would hold the shutdown up.
+func fxCloseOnShutdown(s *server.Server, hub *Hub) {
+	s.RegisterOnShutdown(hub.Close)
+}
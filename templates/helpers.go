@@ -3,7 +3,10 @@ package templates
 import (
 	"fmt"
 	"html/template"
+	"net/url"
+	"strconv"
 
+	"emailaddress.horse/thousand/models"
 	"github.com/google/uuid"
 )
 
@@ -22,6 +25,25 @@ var helpers = template.FuncMap{
 		return fmt.Sprintf("/vampires/%s/memories/%s/experiences", vampireID, memoryID)
 	},
 
+	"vampireMembersPath": func(vampireID uuid.UUID) string {
+		return fmt.Sprintf("/vampires/%s/members", vampireID)
+	},
+	"vampireMemberPath": func(vampireID, id uuid.UUID) string {
+		return fmt.Sprintf("/vampires/%s/members/%s", vampireID, id)
+	},
+	"vampireInvitationsPath": func(vampireID uuid.UUID) string {
+		return fmt.Sprintf("/vampires/%s/invitations", vampireID)
+	},
+	"vampireInvitationPath": func(vampireID, id uuid.UUID) string {
+		return fmt.Sprintf("/vampires/%s/invitations/%s", vampireID, id)
+	},
+	"invitationPath": func(id uuid.UUID) string {
+		return fmt.Sprintf("/invitations/%s", id)
+	},
+	"acceptInvitationPath": func(id uuid.UUID) string {
+		return fmt.Sprintf("/invitations/%s/accept", id)
+	},
+
 	"newMarkPath": func(vampireID uuid.UUID) string {
 		return fmt.Sprintf("/vampires/%s/marks/new", vampireID)
 	},
@@ -39,10 +61,23 @@ var helpers = template.FuncMap{
 	"sessionPath": func() string {
 		return "/session"
 	},
+	"oidcSessionPath": func() string {
+		return "/session/oidc"
+	},
 	"newSessionPath": func() string {
 		return "/session/new"
 	},
 
+	"sharedVampirePath": func(token string) string {
+		return fmt.Sprintf("/shared/%s", token)
+	},
+	"shareLinksPath": func(vampireID uuid.UUID) string {
+		return fmt.Sprintf("/vampires/%s/share_links", vampireID)
+	},
+	"shareLinkPath": func(vampireID, id uuid.UUID) string {
+		return fmt.Sprintf("/vampires/%s/share_links/%s", vampireID, id)
+	},
+
 	"newSkillPath": func(vampireID uuid.UUID) string {
 		return fmt.Sprintf("/vampires/%s/skills/new", vampireID)
 	},
@@ -56,14 +91,98 @@ var helpers = template.FuncMap{
 	"newUserPath": func() string {
 		return "/user/new"
 	},
+	"userActivityPath": func() string {
+		return "/user/activity"
+	},
+
+	"userIdentitiesPath": func() string {
+		return "/user/identities"
+	},
+	"userIdentityPath": func(id uuid.UUID) string {
+		return fmt.Sprintf("/user/identities/%s", id)
+	},
 
 	"vampiresPath": func() string {
 		return "/vampires"
 	},
+	"vampiresPagePath": func(query models.VampireQuery, after string) string {
+		values := url.Values{}
+		values.Set("sort", string(query.Sort))
+		if query.Search != "" {
+			values.Set("q", query.Search)
+		}
+		if query.Limit != models.DefaultVampirePageSize {
+			values.Set("limit", strconv.Itoa(query.Limit))
+		}
+		values.Set("after", after)
+
+		return "/vampires?" + values.Encode()
+	},
 	"newVampirePath": func() string {
 		return "/vampires/new"
 	},
 	"vampirePath": func(vampireID uuid.UUID) string {
 		return fmt.Sprintf("/vampires/%s", vampireID)
 	},
+	"vampireSearchPath": func(vampireID uuid.UUID) string {
+		return fmt.Sprintf("/vampires/%s/search", vampireID)
+	},
+	"searchResultPath": func(vampireID uuid.UUID, result models.SearchResult) string {
+		// Experiences link to the memory they are in, which is where they're
+		// best read; everything else links to the item itself
+		if result.Kind == models.SearchResultExperience {
+			return fmt.Sprintf("/vampires/%s#memory-%s", vampireID, result.MemoryID)
+		}
+
+		return fmt.Sprintf("/vampires/%s#%s-%s", vampireID, result.Kind, result.ID)
+	},
+	"vampireStreamPath": func(vampireID uuid.UUID) string {
+		return fmt.Sprintf("/vampires/%s/stream", vampireID)
+	},
+
+	"trashPath": func() string {
+		return "/trash"
+	},
+	"restoreVampirePath": func(vampireID uuid.UUID) string {
+		return fmt.Sprintf("/trash/%s/restore", vampireID)
+	},
+	"archivePath": func() string {
+		return "/archive"
+	},
+	"vampireArchivePath": func(vampireID uuid.UUID) string {
+		return fmt.Sprintf("/vampires/%s/archive", vampireID)
+	},
+
+	"passwordResetPath": func(token string) string {
+		return fmt.Sprintf("/password_resets/%s", token)
+	},
+
+	"impersonationPath": func() string {
+		return "/impersonation"
+	},
+
+	"adminPath": func() string {
+		return "/admin"
+	},
+	"adminUsersPath": func() string {
+		return "/admin/users"
+	},
+	"adminUserPath": func(userID uuid.UUID) string {
+		return fmt.Sprintf("/admin/users/%s", userID)
+	},
+	"adminUserDisablePath": func(userID uuid.UUID) string {
+		return fmt.Sprintf("/admin/users/%s/disable", userID)
+	},
+	"adminUserEnablePath": func(userID uuid.UUID) string {
+		return fmt.Sprintf("/admin/users/%s/enable", userID)
+	},
+	"adminUserPasswordResetsPath": func(userID uuid.UUID) string {
+		return fmt.Sprintf("/admin/users/%s/password_resets", userID)
+	},
+	"adminUserImpersonationPath": func(userID uuid.UUID) string {
+		return fmt.Sprintf("/admin/users/%s/impersonation", userID)
+	},
+	"adminVampiresPath": func() string {
+		return "/admin/vampires"
+	},
 }
@@ -1,6 +1,8 @@
 package templates
 
 import (
+	"emailaddress.horse/thousand/health"
+	"emailaddress.horse/thousand/openid"
 	"emailaddress.horse/thousand/session"
 	"go.uber.org/fx"
 )
@@ -12,11 +14,27 @@ var Module = fx.Options(
 type RendererParams struct {
 	fx.In
 
-	Store *session.Store
+	Health   *health.Health
+	Provider *openid.Provider `optional:"true"`
+	Store    *session.Store
 }
 
 func fxNewRenderer(params RendererParams) *Renderer {
-	return NewRenderer(RendererOptions{
-		Store: params.Store,
+	var identityProvider string
+	if params.Provider != nil {
+		identityProvider = params.Provider.Name()
+	}
+
+	renderer := NewRenderer(RendererOptions{
+		IdentityProvider: identityProvider,
+		Store:            params.Store,
+	})
+
+	params.Health.Register(&health.Component{
+		Name:     "templates",
+		Check:    renderer.Check,
+		Critical: true,
 	})
+
+	return renderer
 }
@@ -2,51 +2,114 @@ package templates
 
 import (
 	"net/http"
+	"time"
 
 	"emailaddress.horse/thousand/form"
+	"emailaddress.horse/thousand/middleware"
 	"emailaddress.horse/thousand/models"
+	"github.com/google/uuid"
 )
 
-func (r *Renderer) NewCharacter(w http.ResponseWriter, req *http.Request, v models.Vampire) error {
+func (r *Renderer) NewCharacter(w http.ResponseWriter, req *http.Request, v models.Vampire, f *form.NewCharacterForm) error {
 	data := map[string]interface{}{
+		"form":    f,
 		"vampire": v,
 	}
 
 	return r.render(w, req, "characters/new", data)
 }
 
-func (r *Renderer) NewExperience(w http.ResponseWriter, req *http.Request, m models.Memory) error {
+func (r *Renderer) CreateCharacter(w http.ResponseWriter, req *http.Request, vampireID uuid.UUID, c models.Character) error {
 	data := map[string]interface{}{
+		"character": c,
+		"vampireID": vampireID,
+	}
+
+	return r.renderTurboStream(w, req, "characters/create", data)
+}
+
+func (r *Renderer) NewExperience(w http.ResponseWriter, req *http.Request, m models.Memory, f *form.NewExperienceForm) error {
+	data := map[string]interface{}{
+		"form":   f,
 		"memory": m,
 	}
 
 	return r.render(w, req, "experiences/new", data)
 }
 
-func (r *Renderer) NewMark(w http.ResponseWriter, req *http.Request, v models.Vampire) error {
+func (r *Renderer) CreateExperience(w http.ResponseWriter, req *http.Request, m models.Memory, e models.Experience) error {
 	data := map[string]interface{}{
+		"experience": e,
+		"memory":     m,
+	}
+
+	return r.renderTurboStream(w, req, "experiences/create", data)
+}
+
+func (r *Renderer) NewMark(w http.ResponseWriter, req *http.Request, v models.Vampire, f *form.NewMarkForm) error {
+	data := map[string]interface{}{
+		"form":    f,
 		"vampire": v,
 	}
 
 	return r.render(w, req, "marks/new", data)
 }
 
-func (r *Renderer) NewSkill(w http.ResponseWriter, req *http.Request, v models.Vampire) error {
+func (r *Renderer) CreateMark(w http.ResponseWriter, req *http.Request, vampireID uuid.UUID, m models.Mark) error {
 	data := map[string]interface{}{
+		"mark":      m,
+		"vampireID": vampireID,
+	}
+
+	return r.renderTurboStream(w, req, "marks/create", data)
+}
+
+func (r *Renderer) NewSkill(w http.ResponseWriter, req *http.Request, v models.Vampire, f *form.NewSkillForm) error {
+	data := map[string]interface{}{
+		"form":    f,
 		"vampire": v,
 	}
 
 	return r.render(w, req, "skills/new", data)
 }
 
-func (r *Renderer) NewResource(w http.ResponseWriter, req *http.Request, v models.Vampire) error {
+func (r *Renderer) CreateSkill(w http.ResponseWriter, req *http.Request, vampireID uuid.UUID, s models.Skill) error {
+	data := map[string]interface{}{
+		"skill":     s,
+		"vampireID": vampireID,
+	}
+
+	return r.renderTurboStream(w, req, "skills/create", data)
+}
+
+func (r *Renderer) ShowMembers(w http.ResponseWriter, req *http.Request, v models.Vampire, members []models.Member, invitations []models.Invitation) error {
+	data := map[string]interface{}{
+		"invitations": invitations,
+		"members":     members,
+		"vampire":     v,
+	}
+
+	return r.render(w, req, "members/index", data)
+}
+
+func (r *Renderer) NewResource(w http.ResponseWriter, req *http.Request, v models.Vampire, f *form.NewResourceForm) error {
 	data := map[string]interface{}{
+		"form":    f,
 		"vampire": v,
 	}
 
 	return r.render(w, req, "resources/new", data)
 }
 
+func (r *Renderer) CreateResource(w http.ResponseWriter, req *http.Request, vampireID uuid.UUID, res models.Resource) error {
+	data := map[string]interface{}{
+		"resource":  res,
+		"vampireID": vampireID,
+	}
+
+	return r.renderTurboStream(w, req, "resources/create", data)
+}
+
 func (r *Renderer) NewSession(w http.ResponseWriter, req *http.Request, f *form.NewSessionForm) error {
 	data := map[string]interface{}{
 		"form": f,
@@ -63,22 +126,146 @@ func (r *Renderer) NewUser(w http.ResponseWriter, req *http.Request, f *form.New
 	return r.render(w, req, "users/new", data)
 }
 
-func (r *Renderer) ShowVampires(w http.ResponseWriter, req *http.Request, v []models.Vampire) error {
+func (r *Renderer) ShowShareLinks(w http.ResponseWriter, req *http.Request, v models.Vampire, links []models.ShareLink) error {
+	data := map[string]interface{}{
+		"now":        time.Now(),
+		"shareLinks": links,
+		"vampire":    v,
+	}
+
+	return r.render(w, req, "share_links/index", data)
+}
+
+func (r *Renderer) ShowUser(w http.ResponseWriter, req *http.Request, identities []models.UserIdentity) error {
+	data := map[string]interface{}{
+		"identities": identities,
+	}
+
+	return r.render(w, req, "users/show", data)
+}
+
+func (r *Renderer) ShowSecurityActivity(w http.ResponseWriter, req *http.Request, events []models.AuditEvent) error {
+	data := map[string]interface{}{
+		"events": events,
+	}
+
+	return r.render(w, req, "users/activity", data)
+}
+
+func (r *Renderer) ShowVampires(w http.ResponseWriter, req *http.Request, query models.VampireQuery, page, shared models.VampirePage, invitations []models.Invitation) error {
 	data := map[string]interface{}{
-		"vampires": v,
+		"invitations":    invitations,
+		"next":           page.Next,
+		"query":          query,
+		"sharedNext":     shared.Next,
+		"sharedVampires": shared.Vampires,
+		"vampires":       page.Vampires,
 	}
 
 	return r.render(w, req, "vampires/index", data)
 }
 
+func (r *Renderer) ShowTrash(w http.ResponseWriter, req *http.Request, vampires []models.TrashedVampire) error {
+	data := map[string]interface{}{
+		"vampires": vampires,
+	}
+
+	return r.render(w, req, "vampires/trash", data)
+}
+
+func (r *Renderer) ShowArchive(w http.ResponseWriter, req *http.Request, vampires []models.Vampire) error {
+	data := map[string]interface{}{
+		"vampires": vampires,
+	}
+
+	return r.render(w, req, "vampires/archive", data)
+}
+
 func (r *Renderer) NewVampire(w http.ResponseWriter, req *http.Request) error {
 	return r.render(w, req, "vampires/new", map[string]interface{}{})
 }
 
 func (r *Renderer) ShowVampire(w http.ResponseWriter, req *http.Request, v models.Vampire) error {
+	role, _ := middleware.MaybeVampireRole(req.Context())
+
 	data := map[string]interface{}{
-		"vampire": v,
+		"readOnly": !role.CanEdit(),
+		"vampire":  v,
 	}
 
 	return r.render(w, req, "vampires/show", data)
 }
+
+func (r *Renderer) SearchVampire(w http.ResponseWriter, req *http.Request, v models.Vampire, search string, results []models.SearchResult) error {
+	data := map[string]interface{}{
+		"results": results,
+		"search":  search,
+		"vampire": v,
+	}
+
+	return r.render(w, req, "vampires/search", data)
+}
+
+func (r *Renderer) ShowSharedVampire(w http.ResponseWriter, req *http.Request, v models.Vampire) error {
+	data := map[string]interface{}{
+		"noindex":  true,
+		"readOnly": true,
+		"vampire":  v,
+	}
+
+	return r.render(w, req, "vampires/shared", data)
+}
+
+func (r *Renderer) ShowAdmin(w http.ResponseWriter, req *http.Request, stats models.AdminStats) error {
+	data := map[string]interface{}{
+		"stats": stats,
+	}
+
+	return r.render(w, req, "admin/index", data)
+}
+
+func (r *Renderer) ListAdminUsers(w http.ResponseWriter, req *http.Request, search string, users []models.AdminUser) error {
+	data := map[string]interface{}{
+		"search": search,
+		"users":  users,
+	}
+
+	return r.render(w, req, "admin/users", data)
+}
+
+func (r *Renderer) ShowAdminUser(w http.ResponseWriter, req *http.Request, user models.User, events []models.AuditEvent) error {
+	data := map[string]interface{}{
+		"events": events,
+		"user":   user,
+	}
+
+	return r.render(w, req, "admin/user", data)
+}
+
+func (r *Renderer) ListAdminVampires(w http.ResponseWriter, req *http.Request, search string, vampires []models.AdminVampire) error {
+	data := map[string]interface{}{
+		"search":   search,
+		"vampires": vampires,
+	}
+
+	return r.render(w, req, "admin/vampires", data)
+}
+
+func (r *Renderer) ShowPasswordReset(w http.ResponseWriter, req *http.Request, user models.User, reset models.PasswordReset) error {
+	data := map[string]interface{}{
+		"reset": reset,
+		"user":  user,
+	}
+
+	return r.render(w, req, "admin/password_reset", data)
+}
+
+func (r *Renderer) NewPasswordReset(w http.ResponseWriter, req *http.Request, token string, form *form.PasswordResetForm) error {
+	data := map[string]interface{}{
+		"form":    form,
+		"noindex": true,
+		"token":   token,
+	}
+
+	return r.render(w, req, "password_resets/new", data)
+}
@@ -1,9 +1,13 @@
 package templates
 
 import (
+	"bytes"
+	"context"
 	"embed"
+	"errors"
 	"fmt"
 	"html/template"
+	"io"
 	"io/fs"
 	"log"
 	"net/http"
@@ -19,20 +23,64 @@ var viewTemplates embed.FS
 //go:embed layouts/*.tmpl
 var layoutTemplates embed.FS
 
+//go:embed partials/*.tmpl
+var partialTemplates embed.FS
+
+//go:embed streams
+var streamTemplates embed.FS
+
+// TurboStreamMediaType is the media type of responses made up of Turbo Stream
+// actions rather than a whole page.
+const TurboStreamMediaType = "text/vnd.turbo-stream.html"
+
 type Renderer struct {
-	store       *session.Store
-	templateMap map[string]*template.Template
+	identityProvider string
+	store            *session.Store
+	templateMap      map[string]*template.Template
+	streamMap        map[string]*template.Template
 }
 
 type RendererOptions struct {
-	Store *session.Store
+	// IdentityProvider is the name of the external identity provider users can
+	// log in with, or blank if none is configured.
+	IdentityProvider string
+	Store            *session.Store
 }
 
 func NewRenderer(opts RendererOptions) *Renderer {
+	return &Renderer{
+		identityProvider: opts.IdentityProvider,
+		store:            opts.Store,
+		templateMap:      parseTemplates(viewTemplates, "views", true),
+		streamMap:        parseTemplates(streamTemplates, "streams", false),
+	}
+}
+
+// Check fails if the views or streams weren't loaded, such as when they were
+// missing from the build.
+func (r *Renderer) Check(_ context.Context) error {
+	if len(r.templateMap) == 0 {
+		return errors.New("no view templates loaded")
+	}
+
+	if len(r.streamMap) == 0 {
+		return errors.New("no stream templates loaded")
+	}
+
+	if _, ok := r.templateMap["errors/error"]; !ok {
+		return errors.New("error view template not loaded")
+	}
+
+	return nil
+}
+
+// parseTemplates builds a template for each file in dir, giving each access to
+// the partials and, if withLayouts is set, the layouts.
+func parseTemplates(fsys embed.FS, dir string, withLayouts bool) map[string]*template.Template {
 	templateMap := map[string]*template.Template{}
 
-	// Traverse the views directory, descending into each folder
-	err := fs.WalkDir(viewTemplates, "views", func(path string, d fs.DirEntry, err error) error {
+	// Traverse the directory, descending into each folder
+	err := fs.WalkDir(fsys, dir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -43,14 +91,14 @@ func NewRenderer(opts RendererOptions) *Renderer {
 		}
 
 		// Get the contents of the file
-		viewBytes, err := viewTemplates.ReadFile(path)
+		viewBytes, err := fsys.ReadFile(path)
 		if err != nil {
 			log.Fatal(err)
 		}
 
-		// Build the name we will use for the template by removing the views/ prefix
-		// and .tmpl suffix
-		name := strings.TrimPrefix(path, "views/")
+		// Build the name we will use for the template by removing the directory
+		// prefix and .tmpl suffix
+		name := strings.TrimPrefix(path, dir+"/")
 		name = strings.TrimSuffix(name, ".tmpl")
 
 		// Setup the new template for the view
@@ -67,7 +115,15 @@ func NewRenderer(opts RendererOptions) *Renderer {
 
 		// Also parse the contents of the layouts directory to make sure the view
 		// has access to all layouts
-		viewTemplate, err = viewTemplate.ParseFS(layoutTemplates, "layouts/*.tmpl")
+		if withLayouts {
+			viewTemplate, err = viewTemplate.ParseFS(layoutTemplates, "layouts/*.tmpl")
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+
+		// And the partials directory so views can share fragments of markup
+		viewTemplate, err = viewTemplate.ParseFS(partialTemplates, "partials/*.tmpl")
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -81,28 +137,100 @@ func NewRenderer(opts RendererOptions) *Renderer {
 		log.Fatal(err)
 	}
 
-	return &Renderer{
-		store:       opts.Store,
-		templateMap: templateMap,
-	}
+	return templateMap
 }
 
 func (r *Renderer) render(w http.ResponseWriter, req *http.Request, name string, data map[string]interface{}) error {
+	if err := r.addSessionData(w, req, data); err != nil {
+		return err
+	}
+
+	view, ok := r.templateMap[name]
+	if !ok {
+		return fmt.Errorf("No template found with name: %q", name)
+	}
+
+	return view.ExecuteTemplate(w, name, data)
+}
+
+// addSessionData adds what the layout needs to know about the session to the
+// data for a view.
+func (r *Renderer) addSessionData(w http.ResponseWriter, req *http.Request, data map[string]interface{}) error {
 	flashes, err := r.store.GetFlashes(req, w)
 	if err != nil {
 		return err
 	}
 	data["flashes"] = flashes
 
+	if r.identityProvider != "" {
+		data["identityProvider"] = r.identityProvider
+	}
+
 	currentUser, ok := middleware.MaybeCurrentUser(req.Context())
 	if ok {
 		data["currentUser"] = currentUser
 	}
 
+	impersonator, ok := middleware.MaybeImpersonator(req.Context())
+	if ok {
+		data["impersonator"] = impersonator
+	}
+
+	vampireRole, ok := middleware.MaybeVampireRole(req.Context())
+	if ok {
+		data["vampireRole"] = vampireRole
+	}
+
+	return nil
+}
+
+// Error renders the page for an error with the given status. The page is
+// rendered in full before anything is written so that, if it fails, the caller
+// is still free to respond some other way.
+func (r *Renderer) Error(w http.ResponseWriter, req *http.Request, status int, message, requestID string) error {
+	data := map[string]interface{}{
+		"message":   message,
+		"requestID": requestID,
+		"status":    status,
+	}
+
+	if err := r.addSessionData(w, req, data); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("errors/%d", status)
 	view, ok := r.templateMap[name]
 	if !ok {
-		return fmt.Errorf("No template found with name: %q", name)
+		name = "errors/error"
+		view = r.templateMap[name]
 	}
 
-	return view.ExecuteTemplate(w, name, data)
+	var buf bytes.Buffer
+	if err := view.ExecuteTemplate(&buf, name, data); err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// renderTurboStream renders a view made up of Turbo Stream actions in response
+// to a form submission.
+func (r *Renderer) renderTurboStream(w http.ResponseWriter, req *http.Request, name string, data map[string]interface{}) error {
+	w.Header().Set("Content-Type", TurboStreamMediaType)
+
+	return r.render(w, req, name, data)
+}
+
+// renderStream renders a Turbo Stream message. Streams are sent to every
+// viewer of a page so, unlike views, they have no access to the session.
+func (r *Renderer) renderStream(w io.Writer, name string, data map[string]interface{}) error {
+	stream, ok := r.streamMap[name]
+	if !ok {
+		return fmt.Errorf("No stream template found with name: %q", name)
+	}
+
+	return stream.ExecuteTemplate(w, name, data)
 }
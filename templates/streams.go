@@ -0,0 +1,46 @@
+package templates
+
+import (
+	"io"
+
+	"emailaddress.horse/thousand/models"
+)
+
+func (r *Renderer) CreateCharacterStream(w io.Writer, c models.Character) error {
+	return r.renderStream(w, "characters/create", map[string]interface{}{
+		"character": c,
+	})
+}
+
+// CreateExperienceStream also removes the link to add experiences to the
+// memory once it is full.
+func (r *Renderer) CreateExperienceStream(w io.Writer, m models.Memory, e models.Experience) error {
+	return r.renderStream(w, "experiences/create", map[string]interface{}{
+		"experience": e,
+		"memory":     m,
+	})
+}
+
+func (r *Renderer) CreateMarkStream(w io.Writer, m models.Mark) error {
+	return r.renderStream(w, "marks/create", map[string]interface{}{
+		"mark": m,
+	})
+}
+
+func (r *Renderer) CreateResourceStream(w io.Writer, res models.Resource) error {
+	return r.renderStream(w, "resources/create", map[string]interface{}{
+		"resource": res,
+	})
+}
+
+// DestroyVampireStream tells viewers that the vampire has been moved to the
+// trash.
+func (r *Renderer) DestroyVampireStream(w io.Writer) error {
+	return r.renderStream(w, "vampires/destroy", map[string]interface{}{})
+}
+
+func (r *Renderer) CreateSkillStream(w io.Writer, s models.Skill) error {
+	return r.renderStream(w, "skills/create", map[string]interface{}{
+		"skill": s,
+	})
+}
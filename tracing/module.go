@@ -0,0 +1,37 @@
+package tracing
+
+import (
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
+)
+
+var Module = fx.Options(
+	fx.Provide(fxNew),
+)
+
+type Params struct {
+	fx.In
+
+	Exporter     string `name:"traceExporter" optional:"true"`
+	File         string `name:"traceFile" optional:"true"`
+	OTLPEndpoint string `name:"otlpEndpoint" optional:"true"`
+	OTLPInsecure bool   `name:"otlpInsecure" optional:"true"`
+}
+
+func fxNew(lc fx.Lifecycle, params Params) (trace.TracerProvider, error) {
+	provider, err := New(Options{
+		Exporter:     params.Exporter,
+		File:         params.File,
+		OTLPEndpoint: params.OTLPEndpoint,
+		OTLPInsecure: params.OTLPInsecure,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	lc.Append(fx.Hook{
+		OnStop: provider.Shutdown,
+	})
+
+	return provider, nil
+}
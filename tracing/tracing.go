@@ -0,0 +1,155 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName identifies the app's spans among those of other services.
+const ServiceName = "thousand"
+
+const (
+	// ExporterNone disables tracing.
+	ExporterNone = ""
+	// ExporterOTLP sends spans to an OpenTelemetry collector over HTTP.
+	ExporterOTLP = "otlp"
+	// ExporterStdout writes spans to stdout for local use.
+	ExporterStdout = "stdout"
+	// ExporterFile writes spans to a file for local use.
+	ExporterFile = "file"
+)
+
+// ErrUnknownExporter is returned when configured with an exporter which is
+// not supported.
+var ErrUnknownExporter = errors.New("unknown trace exporter")
+
+type Options struct {
+	// Exporter is the name of the exporter spans are sent to. Tracing is
+	// disabled if blank.
+	Exporter string
+	// OTLPEndpoint is the host and port of the collector used by the OTLP
+	// exporter.
+	OTLPEndpoint string
+	// OTLPInsecure sends spans to the collector without TLS.
+	OTLPInsecure bool
+	// File is the path of the file the file exporter appends spans to.
+	File string
+}
+
+// Provider creates the tracers used throughout the app.
+type Provider struct {
+	trace.TracerProvider
+
+	shutdown func(context.Context) error
+}
+
+// New builds a provider which sends spans to the configured exporter, or one
+// which records nothing if tracing is disabled.
+func New(opts Options) (*Provider, error) {
+	if opts.Exporter == ExporterNone {
+		return &Provider{
+			TracerProvider: trace.NewNoopTracerProvider(),
+			shutdown:       func(context.Context) error { return nil },
+		}, nil
+	}
+
+	exporter, closer, err := newExporter(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWithExporter(exporter, closer), nil
+}
+
+// NewWithExporter builds a provider which batches spans to the exporter. The
+// closer, if given, is closed once the provider has been shut down.
+func NewWithExporter(exporter sdktrace.SpanExporter, closer io.Closer) *Provider {
+	return newProvider(sdktrace.WithBatcher(exporter), closer)
+}
+
+// NewWithProcessor builds a provider which hands spans straight to the
+// processor, such as a span recorder in tests.
+func NewWithProcessor(processor sdktrace.SpanProcessor) *Provider {
+	return newProvider(sdktrace.WithSpanProcessor(processor), nil)
+}
+
+func newProvider(opt sdktrace.TracerProviderOption, closer io.Closer) *Provider {
+	tp := sdktrace.NewTracerProvider(
+		opt,
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceNameKey.String(ServiceName),
+		)),
+	)
+
+	return &Provider{
+		TracerProvider: tp,
+		shutdown: func(ctx context.Context) error {
+			err := tp.Shutdown(ctx)
+			if closer != nil {
+				if closeErr := closer.Close(); err == nil {
+					err = closeErr
+				}
+			}
+			return err
+		},
+	}
+}
+
+// Shutdown flushes any spans which have not yet been exported and stops the
+// exporter.
+func (p *Provider) Shutdown(ctx context.Context) error {
+	return p.shutdown(ctx)
+}
+
+func newExporter(opts Options) (sdktrace.SpanExporter, io.Closer, error) {
+	switch opts.Exporter {
+	case ExporterOTLP:
+		clientOpts := []otlptracehttp.Option{}
+		if opts.OTLPEndpoint != "" {
+			clientOpts = append(clientOpts, otlptracehttp.WithEndpoint(opts.OTLPEndpoint))
+		}
+		if opts.OTLPInsecure {
+			clientOpts = append(clientOpts, otlptracehttp.WithInsecure())
+		}
+
+		exporter, err := otlptracehttp.New(context.Background(), clientOpts...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error creating OTLP exporter: %w", err)
+		}
+
+		return exporter, nil, nil
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, nil, fmt.Errorf("error creating stdout exporter: %w", err)
+		}
+
+		return exporter, nil, nil
+	case ExporterFile:
+		f, err := os.OpenFile(opts.File, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error opening trace file: %w", err)
+		}
+
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			_ = f.Close()
+			return nil, nil, fmt.Errorf("error creating file exporter: %w", err)
+		}
+
+		return exporter, f, nil
+	default:
+		return nil, nil, fmt.Errorf("%w: %q", ErrUnknownExporter, opts.Exporter)
+	}
+}
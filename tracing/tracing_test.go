@@ -0,0 +1,113 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"emailaddress.horse/thousand/tracing"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		exporter      string
+		recording     bool
+		expectedError error
+	}{
+		{
+			name:      "disabled",
+			exporter:  tracing.ExporterNone,
+			recording: false,
+		},
+		{
+			name:      "stdout",
+			exporter:  tracing.ExporterStdout,
+			recording: true,
+		},
+		{
+			name:          "unknown exporter",
+			exporter:      "carrier pigeon",
+			expectedError: tracing.ErrUnknownExporter,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := tracing.New(tracing.Options{Exporter: tt.exporter})
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected %q; received %q", tt.expectedError, err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			_, span := provider.Tracer("test").Start(context.Background(), "test")
+			if recording := span.IsRecording(); tt.recording != recording {
+				t.Errorf("expected recording %t; got %t", tt.recording, recording)
+			}
+			span.End()
+
+			if err := provider.Shutdown(context.Background()); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestNew_File(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "traces.json")
+
+	provider, err := tracing.New(tracing.Options{Exporter: tracing.ExporterFile, File: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, span := provider.Tracer("test").Start(context.Background(), "written to file")
+	span.End()
+
+	if err := provider.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(contents), "written to file") {
+		t.Errorf("expected span to be written to file; got %q", contents)
+	}
+}
+
+func TestNewWithProcessor(t *testing.T) {
+	t.Parallel()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := tracing.NewWithProcessor(recorder)
+
+	_, span := provider.Tracer("test").Start(context.Background(), "recorded")
+	span.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span; got %d", len(spans))
+	}
+
+	if service, ok := spans[0].Resource().Set().Value("service.name"); !ok || service.AsString() != tracing.ServiceName {
+		t.Errorf("expected service name %q; got %q", tracing.ServiceName, service.AsString())
+	}
+}